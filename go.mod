@@ -42,6 +42,7 @@ require (
 	github.com/wealdtech/go-eth2-types/v2 v2.7.0
 	github.com/wealdtech/go-eth2-util v1.7.0
 	github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4 v1.3.0
+	go.etcd.io/bbolt v1.3.5
 	go.uber.org/atomic v1.11.0 // indirect
 	golang.org/x/crypto v0.7.0
 	golang.org/x/sync v0.1.0
@@ -53,6 +53,11 @@ if [ ! -f "/validators/$FEE_RECIPIENT_FILE" ]; then
     exit 1
 fi
 
+# The operator's remote signer keys, written by the Stader node process
+if [ "$ENABLE_REMOTE_SIGNER" = "true" ] && [ -f "/validators/remote-signer-keys.txt" ]; then
+    REMOTE_SIGNER_KEYS=$(cat /validators/remote-signer-keys.txt)
+fi
+
 
 # Lighthouse startup
 if [ "$CC_CLIENT" = "lighthouse" ]; then
@@ -78,6 +83,11 @@ if [ "$CC_CLIENT" = "lighthouse" ]; then
         --suggested-fee-recipient $(cat /validators/$FEE_RECIPIENT_FILE) \
         $VC_ADDITIONAL_FLAGS"
 
+    # With a remote signer the keys come from the web3signer entries in validator_definitions.yml written by the node
+    if [ "$ENABLE_REMOTE_SIGNER" = "true" ]; then
+        CMD="$CMD --disable-auto-discover"
+    fi
+
     if [ "$DOPPELGANGER_DETECTION" = "true" ]; then
         CMD="$CMD --enable-doppelganger-protection"
     fi
@@ -116,13 +126,22 @@ if [ "$CC_CLIENT" = "lodestar" ]; then
         CC_URL_STRING="$CC_API_ENDPOINT,$FALLBACK_CC_API_ENDPOINT"
     fi
 
+    # Use the operator's remote signer keys written by the node instead of the local keystores if enabled
+    if [ "$ENABLE_REMOTE_SIGNER" = "true" ]; then
+        KEYS_ARG="--externalSigner.url $REMOTE_SIGNER_URL"
+        if [ -n "$REMOTE_SIGNER_KEYS" ]; then
+            KEYS_ARG="$KEYS_ARG --externalSigner.pubkeys $REMOTE_SIGNER_KEYS"
+        fi
+    else
+        KEYS_ARG="--keystoresDir /validators/lodestar/validators --secretsDir /validators/lodestar/secrets"
+    fi
+
     CMD="/usr/app/node_modules/.bin/lodestar validator \
         $LODESTAR_NETWORK_ARG \
         --dataDir /validators/lodestar \
         --beacon-nodes $CC_URL_STRING \
         $FALLBACK_CC_STRING \
-        --keystoresDir /validators/lodestar/validators \
-        --secretsDir /validators/lodestar/secrets \
+        $KEYS_ARG \
         --suggestedFeeRecipient $(cat /validators/$FEE_RECIPIENT_FILE) \
         $VC_ADDITIONAL_FLAGS"
 
@@ -197,11 +216,20 @@ if [ "$CC_CLIENT" = "prysm" ]; then
         CC_URL_STRING="$CC_RPC_ENDPOINT,$FALLBACK_CC_RPC_ENDPOINT"
     fi
 
+    # Use the operator's remote signer keys written by the node instead of the local wallet if enabled
+    if [ "$ENABLE_REMOTE_SIGNER" = "true" ]; then
+        KEYS_ARG="--validators-external-signer-url=$REMOTE_SIGNER_URL"
+        if [ -n "$REMOTE_SIGNER_KEYS" ]; then
+            KEYS_ARG="$KEYS_ARG --validators-external-signer-public-keys=$REMOTE_SIGNER_KEYS"
+        fi
+    else
+        KEYS_ARG="--wallet-dir /validators/prysm-non-hd --wallet-password-file /validators/prysm-non-hd/direct/accounts/secret"
+    fi
+
     CMD="/app/cmd/validator/validator \
         --accept-terms-of-use \
         $PRYSM_NETWORK \
-        --wallet-dir /validators/prysm-non-hd \
-        --wallet-password-file /validators/prysm-non-hd/direct/accounts/secret \
+        $KEYS_ARG \
         --beacon-rpc-provider $CC_URL_STRING \
         --suggested-fee-recipient $(cat /validators/$FEE_RECIPIENT_FILE) \
         $VC_ADDITIONAL_FLAGS"
@@ -243,10 +271,20 @@ if [ "$CC_CLIENT" = "teku" ]; then
         CC_URL_STRING="$CC_API_ENDPOINT,$FALLBACK_CC_API_ENDPOINT"
     fi
 
+    # Use the operator's remote signer keys written by the node instead of the local keystores if enabled
+    if [ "$ENABLE_REMOTE_SIGNER" = "true" ]; then
+        KEYS_ARG="--validators-external-signer-url=$REMOTE_SIGNER_URL"
+        if [ -n "$REMOTE_SIGNER_KEYS" ]; then
+            KEYS_ARG="$KEYS_ARG --validators-external-signer-public-keys=$REMOTE_SIGNER_KEYS"
+        fi
+    else
+        KEYS_ARG="--validator-keys=/validators/teku/keys:/validators/teku/passwords"
+    fi
+
     CMD="/opt/teku/bin/teku validator-client \
         --network=$TEKU_NETWORK \
         --data-path=/validators/teku \
-        --validator-keys=/validators/teku/keys:/validators/teku/passwords \
+        $KEYS_ARG \
         --beacon-node-api-endpoints=$CC_URL_STRING \
         --validators-keystore-locking-enabled=false \
         --log-destination=CONSOLE \
@@ -257,6 +295,10 @@ if [ "$CC_CLIENT" = "teku" ]; then
         CMD="$CMD --validators-builder-registration-default-enabled=true"
     fi
 
+    if [ "$DOPPELGANGER_DETECTION" = "true" ]; then
+        CMD="$CMD --doppelganger-detection-enabled=true"
+    fi
+
     if [ "$ENABLE_METRICS" = "true" ]; then
         CMD="$CMD --metrics-enabled=true --metrics-interface=0.0.0.0 --metrics-port=$VC_METRICS_PORT --metrics-host-allowlist=*"
     fi
@@ -20,8 +20,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package services
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
 	"github.com/fatih/color"
 	"github.com/stader-labs/stader-node/shared/services/beacon"
@@ -93,9 +93,9 @@ func NewBeaconClientManager(cfg *config.StaderConfig) (*BeaconClientManager, err
 	var primaryBc beacon.Client
 	var fallbackBc beacon.Client
 
-	primaryBc = client.NewStandardHttpClient(primaryProvider)
+	primaryBc = client.NewStandardHttpClient(primaryProvider, client.DefaultRequestTimeout)
 	if fallbackProvider != "" {
-		fallbackBc = client.NewStandardHttpClient(fallbackProvider)
+		fallbackBc = client.NewStandardHttpClient(fallbackProvider, client.DefaultRequestTimeout)
 	}
 
 	return &BeaconClientManager{
@@ -124,9 +124,9 @@ func (m *BeaconClientManager) GetClientType() (beacon.BeaconClientType, error) {
 }
 
 // Get the client's sync status
-func (m *BeaconClientManager) GetSyncStatus() (beacon.SyncStatus, error) {
+func (m *BeaconClientManager) GetSyncStatus(ctx context.Context) (beacon.SyncStatus, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetSyncStatus()
+		return client.GetSyncStatus(ctx)
 	})
 	if err != nil {
 		return beacon.SyncStatus{}, err
@@ -135,9 +135,9 @@ func (m *BeaconClientManager) GetSyncStatus() (beacon.SyncStatus, error) {
 }
 
 // Get the Beacon configuration
-func (m *BeaconClientManager) GetEth2Config() (beacon.Eth2Config, error) {
+func (m *BeaconClientManager) GetEth2Config(ctx context.Context) (beacon.Eth2Config, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetEth2Config()
+		return client.GetEth2Config(ctx)
 	})
 	if err != nil {
 		return beacon.Eth2Config{}, err
@@ -146,9 +146,9 @@ func (m *BeaconClientManager) GetEth2Config() (beacon.Eth2Config, error) {
 }
 
 // Get the Beacon configuration
-func (m *BeaconClientManager) GetEth2DepositContract() (beacon.Eth2DepositContract, error) {
+func (m *BeaconClientManager) GetEth2DepositContract(ctx context.Context) (beacon.Eth2DepositContract, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetEth2DepositContract()
+		return client.GetEth2DepositContract(ctx)
 	})
 	if err != nil {
 		return beacon.Eth2DepositContract{}, err
@@ -157,9 +157,9 @@ func (m *BeaconClientManager) GetEth2DepositContract() (beacon.Eth2DepositContra
 }
 
 // Get the attestations in a Beacon chain block
-func (m *BeaconClientManager) GetAttestations(blockId string) ([]beacon.AttestationInfo, bool, error) {
+func (m *BeaconClientManager) GetAttestations(ctx context.Context, blockId string) ([]beacon.AttestationInfo, bool, error) {
 	result1, result2, err := m.runFunction2(func(client beacon.Client) (interface{}, interface{}, error) {
-		return client.GetAttestations(blockId)
+		return client.GetAttestations(ctx, blockId)
 	})
 	if err != nil {
 		return nil, false, err
@@ -168,9 +168,9 @@ func (m *BeaconClientManager) GetAttestations(blockId string) ([]beacon.Attestat
 }
 
 // Get a Beacon chain block
-func (m *BeaconClientManager) GetBeaconBlock(blockId string) (beacon.BeaconBlock, bool, error) {
+func (m *BeaconClientManager) GetBeaconBlock(ctx context.Context, blockId string) (beacon.BeaconBlock, bool, error) {
 	result1, result2, err := m.runFunction2(func(client beacon.Client) (interface{}, interface{}, error) {
-		return client.GetBeaconBlock(blockId)
+		return client.GetBeaconBlock(ctx, blockId)
 	})
 	if err != nil {
 		return beacon.BeaconBlock{}, false, err
@@ -179,9 +179,9 @@ func (m *BeaconClientManager) GetBeaconBlock(blockId string) (beacon.BeaconBlock
 }
 
 // Get the Beacon chain's head information
-func (m *BeaconClientManager) GetBeaconHead() (beacon.BeaconHead, error) {
+func (m *BeaconClientManager) GetBeaconHead(ctx context.Context) (beacon.BeaconHead, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetBeaconHead()
+		return client.GetBeaconHead(ctx)
 	})
 	if err != nil {
 		return beacon.BeaconHead{}, err
@@ -190,9 +190,9 @@ func (m *BeaconClientManager) GetBeaconHead() (beacon.BeaconHead, error) {
 }
 
 // Get a validator's status by its index
-func (m *BeaconClientManager) GetValidatorStatusByIndex(index string, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+func (m *BeaconClientManager) GetValidatorStatusByIndex(ctx context.Context, index string, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetValidatorStatusByIndex(index, opts)
+		return client.GetValidatorStatusByIndex(ctx, index, opts)
 	})
 	if err != nil {
 		return beacon.ValidatorStatus{}, err
@@ -201,9 +201,9 @@ func (m *BeaconClientManager) GetValidatorStatusByIndex(index string, opts *beac
 }
 
 // Get a validator's status by its pubkey
-func (m *BeaconClientManager) GetValidatorStatus(pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+func (m *BeaconClientManager) GetValidatorStatus(ctx context.Context, pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetValidatorStatus(pubkey, opts)
+		return client.GetValidatorStatus(ctx, pubkey, opts)
 	})
 	if err != nil {
 		return beacon.ValidatorStatus{}, err
@@ -212,9 +212,9 @@ func (m *BeaconClientManager) GetValidatorStatus(pubkey types.ValidatorPubkey, o
 }
 
 // Get the statuses of multiple validators by their pubkeys
-func (m *BeaconClientManager) GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+func (m *BeaconClientManager) GetValidatorStatuses(ctx context.Context, pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetValidatorStatuses(pubkeys, opts)
+		return client.GetValidatorStatuses(ctx, pubkeys, opts)
 	})
 	if err != nil {
 		return nil, err
@@ -223,9 +223,9 @@ func (m *BeaconClientManager) GetValidatorStatuses(pubkeys []types.ValidatorPubk
 }
 
 // Get a validator's index
-func (m *BeaconClientManager) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error) {
+func (m *BeaconClientManager) GetValidatorIndex(ctx context.Context, pubkey types.ValidatorPubkey) (uint64, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetValidatorIndex(pubkey)
+		return client.GetValidatorIndex(ctx, pubkey)
 	})
 	if err != nil {
 		return 0, err
@@ -234,9 +234,9 @@ func (m *BeaconClientManager) GetValidatorIndex(pubkey types.ValidatorPubkey) (u
 }
 
 // Get a validator's sync duties
-func (m *BeaconClientManager) GetValidatorSyncDuties(indices []uint64, epoch uint64) (map[uint64]bool, error) {
+func (m *BeaconClientManager) GetValidatorSyncDuties(ctx context.Context, indices []uint64, epoch uint64) (map[uint64]bool, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetValidatorSyncDuties(indices, epoch)
+		return client.GetValidatorSyncDuties(ctx, indices, epoch)
 	})
 	if err != nil {
 		return nil, err
@@ -245,9 +245,9 @@ func (m *BeaconClientManager) GetValidatorSyncDuties(indices []uint64, epoch uin
 }
 
 // Get a validator's proposer duties
-func (m *BeaconClientManager) GetValidatorProposerDuties(indices []uint64, epoch uint64) (map[uint64]uint64, error) {
+func (m *BeaconClientManager) GetValidatorProposerDuties(ctx context.Context, indices []uint64, epoch uint64) (map[uint64]uint64, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetValidatorProposerDuties(indices, epoch)
+		return client.GetValidatorProposerDuties(ctx, indices, epoch)
 	})
 	if err != nil {
 		return nil, err
@@ -256,9 +256,9 @@ func (m *BeaconClientManager) GetValidatorProposerDuties(indices []uint64, epoch
 }
 
 // Get the Beacon chain's domain data
-func (m *BeaconClientManager) GetDomainData(domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error) {
+func (m *BeaconClientManager) GetDomainData(ctx context.Context, domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetDomainData(domainType, epoch, useGenesisFork)
+		return client.GetDomainData(ctx, domainType, epoch, useGenesisFork)
 	})
 	if err != nil {
 		return nil, err
@@ -266,10 +266,21 @@ func (m *BeaconClientManager) GetDomainData(domainType []byte, epoch uint64, use
 	return result.([]byte), nil
 }
 
+// Get the Beacon chain's current fork info
+func (m *BeaconClientManager) GetForkInfo(ctx context.Context) (beacon.ForkInfo, error) {
+	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
+		return client.GetForkInfo(ctx)
+	})
+	if err != nil {
+		return beacon.ForkInfo{}, err
+	}
+	return result.(beacon.ForkInfo), nil
+}
+
 // Voluntarily exit a validator
-func (m *BeaconClientManager) ExitValidator(validatorIndex, epoch uint64, signature types.ValidatorSignature) error {
+func (m *BeaconClientManager) ExitValidator(ctx context.Context, validatorIndex, epoch uint64, signature types.ValidatorSignature) error {
 	err := m.runFunction0(func(client beacon.Client) error {
-		return client.ExitValidator(validatorIndex, epoch, signature)
+		return client.ExitValidator(ctx, validatorIndex, epoch, signature)
 	})
 	return err
 }
@@ -283,9 +294,9 @@ func (m *BeaconClientManager) Close() error {
 }
 
 // Get the EL data for a CL block
-func (m *BeaconClientManager) GetEth1DataForEth2Block(blockId string) (beacon.Eth1Data, bool, error) {
+func (m *BeaconClientManager) GetEth1DataForEth2Block(ctx context.Context, blockId string) (beacon.Eth1Data, bool, error) {
 	result1, result2, err := m.runFunction2(func(client beacon.Client) (interface{}, interface{}, error) {
-		return client.GetEth1DataForEth2Block(blockId)
+		return client.GetEth1DataForEth2Block(ctx, blockId)
 	})
 	if err != nil {
 		return beacon.Eth1Data{}, false, err
@@ -293,10 +304,32 @@ func (m *BeaconClientManager) GetEth1DataForEth2Block(blockId string) (beacon.Et
 	return result1.(beacon.Eth1Data), result2.(bool), nil
 }
 
+// Get the validator assigned to propose each slot of an epoch, keyed by slot
+func (m *BeaconClientManager) GetEpochProposers(ctx context.Context, epoch uint64) (map[uint64]uint64, error) {
+	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
+		return client.GetEpochProposers(ctx, epoch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[uint64]uint64), nil
+}
+
+// Get the positions of validators in the sync committee for an epoch
+func (m *BeaconClientManager) GetSyncCommitteePositions(ctx context.Context, indices []uint64, epoch uint64) (map[uint64][]uint64, error) {
+	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
+		return client.GetSyncCommitteePositions(ctx, indices, epoch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[uint64][]uint64), nil
+}
+
 // Get the attestation committees for an epoch
-func (m *BeaconClientManager) GetCommitteesForEpoch(epoch *uint64) ([]beacon.Committee, error) {
+func (m *BeaconClientManager) GetCommitteesForEpoch(ctx context.Context, epoch *uint64) ([]beacon.Committee, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
-		return client.GetCommitteesForEpoch(epoch)
+		return client.GetCommitteesForEpoch(ctx, epoch)
 	})
 	if err != nil {
 		return nil, err
@@ -304,6 +337,15 @@ func (m *BeaconClientManager) GetCommitteesForEpoch(epoch *uint64) ([]beacon.Com
 	return result.([]beacon.Committee), nil
 }
 
+// Stream events from the beacon node until the stream drops or the context is cancelled.
+// If the primary client disconnects, the stream is reopened on the fallback.
+func (m *BeaconClientManager) StreamEvents(ctx context.Context, topics []beacon.EventTopic, events chan<- beacon.Event) error {
+	err := m.runFunction0(func(client beacon.Client) error {
+		return client.StreamEvents(ctx, topics, events)
+	})
+	return err
+}
+
 /// ==================
 /// Internal Functions
 /// ==================
@@ -347,7 +389,7 @@ func checkBcStatus(client beacon.Client) api.ClientStatus {
 	status := api.ClientStatus{}
 
 	// Get the fallback's sync progress
-	syncStatus, err := client.GetSyncStatus()
+	syncStatus, err := client.GetSyncStatus(context.Background())
 	if err != nil {
 		status.Error = fmt.Sprintf("Sync progress check failed with [%s]", err.Error())
 		status.IsSynced = false
@@ -495,7 +537,7 @@ func (m *BeaconClientManager) runFunction2(function bcFunction2) (interface{}, i
 
 }
 
-// Returns true if the error was a connection failure and a backup client is available
+// Returns true if the client is unreachable or failing, rather than rejecting the request with a validation error
 func (m *BeaconClientManager) isDisconnected(err error) bool {
-	return strings.Contains(err.Error(), "dial tcp")
+	return beacon.IsClientFailure(err)
 }
@@ -0,0 +1,81 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package beacontest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+)
+
+// In-memory stand-in for a beacon node, serving the committees, proposers, sync committee positions and blocks of a chain.
+// Any other beacon query panics.
+type MockClient struct {
+	beacon.Client
+	Committees    map[uint64][]beacon.Committee
+	Proposers     map[uint64]map[uint64]uint64
+	SyncPositions map[uint64]map[uint64][]uint64
+	Blocks        map[uint64]beacon.BeaconBlock
+}
+
+// Create a new mock beacon client with an empty chain
+func NewMockClient() *MockClient {
+	return &MockClient{
+		Committees:    map[uint64][]beacon.Committee{},
+		Proposers:     map[uint64]map[uint64]uint64{},
+		SyncPositions: map[uint64]map[uint64][]uint64{},
+		Blocks:        map[uint64]beacon.BeaconBlock{},
+	}
+}
+
+// Get the attestation committees of an epoch
+func (m *MockClient) GetCommitteesForEpoch(ctx context.Context, epoch *uint64) ([]beacon.Committee, error) {
+	if epoch == nil {
+		return nil, fmt.Errorf("The mock beacon client needs an epoch for committees")
+	}
+	return m.Committees[*epoch], nil
+}
+
+// Get the proposer of each slot of an epoch
+func (m *MockClient) GetEpochProposers(ctx context.Context, epoch uint64) (map[uint64]uint64, error) {
+	return m.Proposers[epoch], nil
+}
+
+// Get the sync committee positions of the given validators in an epoch
+func (m *MockClient) GetSyncCommitteePositions(ctx context.Context, indices []uint64, epoch uint64) (map[uint64][]uint64, error) {
+	positions := map[uint64][]uint64{}
+	for _, index := range indices {
+		if validatorPositions, exists := m.SyncPositions[epoch][index]; exists {
+			positions[index] = validatorPositions
+		}
+	}
+	return positions, nil
+}
+
+// Get the block at a slot, which must be given as a number
+func (m *MockClient) GetBeaconBlock(ctx context.Context, blockId string) (beacon.BeaconBlock, bool, error) {
+	var slot uint64
+	if _, err := fmt.Sscan(blockId, &slot); err != nil {
+		return beacon.BeaconBlock{}, false, fmt.Errorf("The mock beacon client only supports blocks by slot, not %s", blockId)
+	}
+	block, exists := m.Blocks[slot]
+	return block, exists, nil
+}
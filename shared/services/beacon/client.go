@@ -20,6 +20,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package beacon
 
 import (
+	"context"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/stader-labs/stader-node/stader-lib/types"
@@ -46,6 +48,12 @@ type Eth2Config struct {
 	SecondsPerEpoch              uint64
 	EpochsPerSyncCommitteePeriod uint64
 }
+type ForkInfo struct {
+	PreviousVersion       []byte
+	CurrentVersion        []byte
+	Epoch                 uint64
+	GenesisValidatorsRoot []byte
+}
 type Eth2DepositContract struct {
 	ChainID uint64
 	Address common.Address
@@ -78,8 +86,10 @@ type Eth1Data struct {
 type BeaconBlock struct {
 	Slot                 uint64
 	ProposerIndex        uint64
+	ParentRoot           common.Hash
 	HasExecutionPayload  bool
 	Attestations         []AttestationInfo
+	SyncCommitteeBits    bitfield.Bitvector512
 	FeeRecipient         common.Address
 	ExecutionBlockNumber uint64
 }
@@ -94,6 +104,12 @@ type AttestationInfo struct {
 	AggregationBits bitfield.Bitlist
 	SlotIndex       uint64
 	CommitteeIndex  uint64
+	BeaconBlockRoot common.Hash
+	TargetEpoch     uint64
+	TargetRoot      common.Hash
+	// Set for Electra attestations, whose aggregation bits cover each of these committees back to back in index order;
+	// CommitteeIndex is then always 0. Nil for earlier forks, where the bits only cover committee CommitteeIndex.
+	CommitteeBits bitfield.Bitvector64
 }
 
 // Beacon client type
@@ -131,21 +147,25 @@ const (
 // Beacon client interface
 type Client interface {
 	GetClientType() (BeaconClientType, error)
-	GetSyncStatus() (SyncStatus, error)
-	GetEth2Config() (Eth2Config, error)
-	GetEth2DepositContract() (Eth2DepositContract, error)
-	GetAttestations(blockId string) ([]AttestationInfo, bool, error)
-	GetBeaconBlock(blockId string) (BeaconBlock, bool, error)
-	GetBeaconHead() (BeaconHead, error)
-	GetValidatorStatusByIndex(index string, opts *ValidatorStatusOptions) (ValidatorStatus, error)
-	GetValidatorStatus(pubkey types.ValidatorPubkey, opts *ValidatorStatusOptions) (ValidatorStatus, error)
-	GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *ValidatorStatusOptions) (map[types.ValidatorPubkey]ValidatorStatus, error)
-	GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error)
-	GetValidatorSyncDuties(indices []uint64, epoch uint64) (map[uint64]bool, error)
-	GetValidatorProposerDuties(indices []uint64, epoch uint64) (map[uint64]uint64, error)
-	GetDomainData(domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error)
-	ExitValidator(validatorIndex, epoch uint64, signature types.ValidatorSignature) error
+	GetSyncStatus(ctx context.Context) (SyncStatus, error)
+	GetEth2Config(ctx context.Context) (Eth2Config, error)
+	GetEth2DepositContract(ctx context.Context) (Eth2DepositContract, error)
+	GetAttestations(ctx context.Context, blockId string) ([]AttestationInfo, bool, error)
+	GetBeaconBlock(ctx context.Context, blockId string) (BeaconBlock, bool, error)
+	GetBeaconHead(ctx context.Context) (BeaconHead, error)
+	GetValidatorStatusByIndex(ctx context.Context, index string, opts *ValidatorStatusOptions) (ValidatorStatus, error)
+	GetValidatorStatus(ctx context.Context, pubkey types.ValidatorPubkey, opts *ValidatorStatusOptions) (ValidatorStatus, error)
+	GetValidatorStatuses(ctx context.Context, pubkeys []types.ValidatorPubkey, opts *ValidatorStatusOptions) (map[types.ValidatorPubkey]ValidatorStatus, error)
+	GetValidatorIndex(ctx context.Context, pubkey types.ValidatorPubkey) (uint64, error)
+	GetValidatorSyncDuties(ctx context.Context, indices []uint64, epoch uint64) (map[uint64]bool, error)
+	GetValidatorProposerDuties(ctx context.Context, indices []uint64, epoch uint64) (map[uint64]uint64, error)
+	GetEpochProposers(ctx context.Context, epoch uint64) (map[uint64]uint64, error)
+	GetSyncCommitteePositions(ctx context.Context, indices []uint64, epoch uint64) (map[uint64][]uint64, error)
+	GetDomainData(ctx context.Context, domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error)
+	GetForkInfo(ctx context.Context) (ForkInfo, error)
+	ExitValidator(ctx context.Context, validatorIndex, epoch uint64, signature types.ValidatorSignature) error
 	Close() error
-	GetEth1DataForEth2Block(blockId string) (Eth1Data, bool, error)
-	GetCommitteesForEpoch(epoch *uint64) ([]Committee, error)
+	GetEth1DataForEth2Block(ctx context.Context, blockId string) (Eth1Data, bool, error)
+	GetCommitteesForEpoch(ctx context.Context, epoch *uint64) ([]Committee, error)
+	StreamEvents(ctx context.Context, topics []EventTopic, events chan<- Event) error
 }
@@ -20,23 +20,32 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package client
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/prysmaticlabs/prysm/v3/crypto/bls"
 	"github.com/stader-labs/stader-node/stader-lib/types"
 	eth2types "github.com/wealdtech/go-eth2-types/v2"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/stader-labs/stader-node/shared/services/beacon"
+	sszeth2 "github.com/stader-labs/stader-node/shared/types/eth2"
 	"github.com/stader-labs/stader-node/shared/utils/eth2"
 	hexutil "github.com/stader-labs/stader-node/shared/utils/hex"
 )
@@ -45,11 +54,16 @@ import (
 const (
 	RequestUrlFormat   = "%s%s"
 	RequestContentType = "application/json"
+	RequestSszType     = "application/octet-stream"
+
+	// Ask for SSZ but accept JSON from nodes that don't serve it
+	RequestAcceptSsz = "application/octet-stream;q=1.0,application/json;q=0.9"
 
 	RequestSyncStatusPath            = "/eth/v1/node/syncing"
 	RequestEth2ConfigPath            = "/eth/v1/config/spec"
 	RequestEth2DepositContractMethod = "/eth/v1/config/deposit_contract"
 	RequestGenesisPath               = "/eth/v1/beacon/genesis"
+	RequestBeaconHeaderPath          = "/eth/v1/beacon/headers/%s"
 	RequestCommitteePath             = "/eth/v1/beacon/states/%s/committees"
 	RequestFinalityCheckpointsPath   = "/eth/v1/beacon/states/%s/finality_checkpoints"
 	RequestForkPath                  = "/eth/v1/beacon/states/%s/fork"
@@ -59,20 +73,44 @@ const (
 	RequestBeaconBlockPath           = "/eth/v2/beacon/blocks/%s"
 	RequestValidatorSyncDuties       = "/eth/v1/validator/duties/sync/%s"
 	RequestValidatorProposerDuties   = "/eth/v1/validator/duties/proposer/%s"
+	RequestEventsPath                = "/eth/v1/events?topics=%s"
 
 	MaxRequestValidatorsCount     = 600
 	threadLimit               int = 6
+
+	DefaultRequestTimeout = 30 * time.Second
+	MaxRequestRetries     = 3
+	RequestRetryDelay     = 500 * time.Millisecond
+
+	// The event stream is dropped if the beacon node sends nothing for this long;
+	// it's over two epochs so streams of quiet topics like finalized_checkpoint aren't dropped
+	EventStreamIdleTimeout = 15 * time.Minute
+	maxEventStreamLineSize = 1024 * 1024
+
+	farFutureEpoch uint64 = math.MaxUint64
 )
 
 // Beacon client using the standard Beacon HTTP REST API (https://ethereum.github.io/beacon-APIs/)
 type StandardHttpClient struct {
 	providerAddress string
+	timeout         time.Duration
+	httpClient      *http.Client
+
+	// Set once the beacon node answers an SSZ request with JSON, so later requests skip SSZ (atomic)
+	sszUnsupported int32
+	// Cached from the node's config, since it never changes (atomic)
+	slotsPerEpoch uint64
+	// How long the event stream may go quiet before it's dropped
+	eventStreamIdleTimeout time.Duration
 }
 
-// Create a new client instance
-func NewStandardHttpClient(providerAddress string) *StandardHttpClient {
+// Create a new client instance; each request attempt is given up on after the timeout
+func NewStandardHttpClient(providerAddress string, timeout time.Duration) *StandardHttpClient {
 	return &StandardHttpClient{
-		providerAddress: providerAddress,
+		providerAddress:        providerAddress,
+		timeout:                timeout,
+		httpClient:             &http.Client{},
+		eventStreamIdleTimeout: EventStreamIdleTimeout,
 	}
 }
 
@@ -87,10 +125,10 @@ func (c *StandardHttpClient) GetClientType() (beacon.BeaconClientType, error) {
 }
 
 // Get the node's sync status
-func (c *StandardHttpClient) GetSyncStatus() (beacon.SyncStatus, error) {
+func (c *StandardHttpClient) GetSyncStatus(ctx context.Context) (beacon.SyncStatus, error) {
 
 	// Get sync status
-	syncStatus, err := c.getSyncStatus()
+	syncStatus, err := c.getSyncStatus(ctx)
 	if err != nil {
 		return beacon.SyncStatus{}, err
 	}
@@ -107,7 +145,7 @@ func (c *StandardHttpClient) GetSyncStatus() (beacon.SyncStatus, error) {
 }
 
 // Get the eth2 config
-func (c *StandardHttpClient) GetEth2Config() (beacon.Eth2Config, error) {
+func (c *StandardHttpClient) GetEth2Config(ctx context.Context) (beacon.Eth2Config, error) {
 
 	// Data
 	var wg errgroup.Group
@@ -117,14 +155,14 @@ func (c *StandardHttpClient) GetEth2Config() (beacon.Eth2Config, error) {
 	// Get eth2 config
 	wg.Go(func() error {
 		var err error
-		eth2Config, err = c.getEth2Config()
+		eth2Config, err = c.getEth2Config(ctx)
 		return err
 	})
 
 	// Get genesis
 	wg.Go(func() error {
 		var err error
-		genesis, err = c.getGenesis()
+		genesis, err = c.getGenesis(ctx)
 		return err
 	})
 
@@ -148,10 +186,10 @@ func (c *StandardHttpClient) GetEth2Config() (beacon.Eth2Config, error) {
 }
 
 // Get the eth2 deposit contract info
-func (c *StandardHttpClient) GetEth2DepositContract() (beacon.Eth2DepositContract, error) {
+func (c *StandardHttpClient) GetEth2DepositContract(ctx context.Context) (beacon.Eth2DepositContract, error) {
 
 	// Get the deposit contract
-	depositContract, err := c.getEth2DepositContract()
+	depositContract, err := c.getEth2DepositContract(ctx)
 	if err != nil {
 		return beacon.Eth2DepositContract{}, err
 	}
@@ -164,7 +202,7 @@ func (c *StandardHttpClient) GetEth2DepositContract() (beacon.Eth2DepositContrac
 }
 
 // Get the beacon head
-func (c *StandardHttpClient) GetBeaconHead() (beacon.BeaconHead, error) {
+func (c *StandardHttpClient) GetBeaconHead(ctx context.Context) (beacon.BeaconHead, error) {
 
 	// Data
 	var wg errgroup.Group
@@ -174,14 +212,14 @@ func (c *StandardHttpClient) GetBeaconHead() (beacon.BeaconHead, error) {
 	// Get eth2 config
 	wg.Go(func() error {
 		var err error
-		eth2Config, err = c.GetEth2Config()
+		eth2Config, err = c.GetEth2Config(ctx)
 		return err
 	})
 
 	// Get finality checkpoints
 	wg.Go(func() error {
 		var err error
-		finalityCheckpoints, err = c.getFinalityCheckpoints("head")
+		finalityCheckpoints, err = c.getFinalityCheckpoints(ctx, "head")
 		return err
 	})
 
@@ -201,18 +239,18 @@ func (c *StandardHttpClient) GetBeaconHead() (beacon.BeaconHead, error) {
 }
 
 // Get a validator's status
-func (c *StandardHttpClient) GetValidatorStatus(pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+func (c *StandardHttpClient) GetValidatorStatus(ctx context.Context, pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
 
-	return c.getValidatorStatus(hexutil.AddPrefix(pubkey.Hex()), opts)
+	return c.getValidatorStatus(ctx, hexutil.AddPrefix(pubkey.Hex()), opts)
 
 }
-func (c *StandardHttpClient) GetValidatorStatusByIndex(index string, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+func (c *StandardHttpClient) GetValidatorStatusByIndex(ctx context.Context, index string, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
 
-	return c.getValidatorStatus(index, opts)
+	return c.getValidatorStatus(ctx, index, opts)
 
 }
 
-func (c *StandardHttpClient) getValidatorStatus(pubkeyOrIndex string, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+func (c *StandardHttpClient) getValidatorStatus(ctx context.Context, pubkeyOrIndex string, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
 
 	// Return zero status for null pubkeyOrIndex
 	if pubkeyOrIndex == "" {
@@ -220,7 +258,7 @@ func (c *StandardHttpClient) getValidatorStatus(pubkeyOrIndex string, opts *beac
 	}
 
 	// Get validator
-	validators, err := c.getValidatorsByOpts([]string{pubkeyOrIndex}, opts)
+	validators, err := c.getValidatorsByOpts(ctx, []string{pubkeyOrIndex}, opts, false)
 	if err != nil {
 		return beacon.ValidatorStatus{}, err
 	}
@@ -248,7 +286,7 @@ func (c *StandardHttpClient) getValidatorStatus(pubkeyOrIndex string, opts *beac
 }
 
 // Get multiple validators' statuses
-func (c *StandardHttpClient) GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+func (c *StandardHttpClient) GetValidatorStatuses(ctx context.Context, pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
 
 	// The null validator pubkey
 	nullPubkey := types.ValidatorPubkey{}
@@ -272,7 +310,7 @@ func (c *StandardHttpClient) GetValidatorStatuses(pubkeys []types.ValidatorPubke
 	}
 
 	// Get validators
-	validators, err := c.getValidatorsByOpts(pubkeysHex, opts)
+	validators, err := c.getValidatorsByOpts(ctx, pubkeysHex, opts, true)
 	if err != nil {
 		return nil, err
 	}
@@ -315,7 +353,7 @@ func (c *StandardHttpClient) GetValidatorStatuses(pubkeys []types.ValidatorPubke
 }
 
 // Get whether validators have sync duties to perform at given epoch
-func (c *StandardHttpClient) GetValidatorSyncDuties(indices []uint64, epoch uint64) (map[uint64]bool, error) {
+func (c *StandardHttpClient) GetValidatorSyncDuties(ctx context.Context, indices []uint64, epoch uint64) (map[uint64]bool, error) {
 
 	// Convert incoming uint64 validator indices into an array of string for the request
 	indicesStrings := make([]string, len(indices))
@@ -325,14 +363,10 @@ func (c *StandardHttpClient) GetValidatorSyncDuties(indices []uint64, epoch uint
 	}
 
 	// Perform the post request
-	responseBody, status, err := c.postRequest(fmt.Sprintf(RequestValidatorSyncDuties, strconv.FormatUint(epoch, 10)), indicesStrings)
-
+	responseBody, err := c.postRequest(ctx, fmt.Sprintf(RequestValidatorSyncDuties, strconv.FormatUint(epoch, 10)), indicesStrings)
 	if err != nil {
 		return nil, fmt.Errorf("Could not get validator sync duties: %w", err)
 	}
-	if status != http.StatusOK {
-		return nil, fmt.Errorf("Could not get validator sync duties: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 
 	var response SyncDutiesResponse
 	if err := json.Unmarshal(responseBody, &response); err != nil {
@@ -356,17 +390,13 @@ func (c *StandardHttpClient) GetValidatorSyncDuties(indices []uint64, epoch uint
 }
 
 // Sums proposer duties per validators for a given epoch
-func (c *StandardHttpClient) GetValidatorProposerDuties(indices []uint64, epoch uint64) (map[uint64]uint64, error) {
+func (c *StandardHttpClient) GetValidatorProposerDuties(ctx context.Context, indices []uint64, epoch uint64) (map[uint64]uint64, error) {
 
 	// Perform the post request
-	responseBody, status, err := c.getRequest(fmt.Sprintf(RequestValidatorProposerDuties, strconv.FormatUint(epoch, 10)))
-
+	responseBody, err := c.getRequest(ctx, fmt.Sprintf(RequestValidatorProposerDuties, strconv.FormatUint(epoch, 10)))
 	if err != nil {
 		return nil, fmt.Errorf("Could not get validator proposer duties: %w", err)
 	}
-	if status != http.StatusOK {
-		return nil, fmt.Errorf("Could not get validator proposer duties: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 
 	var response ProposerDutiesResponse
 	if err := json.Unmarshal(responseBody, &response); err != nil {
@@ -389,12 +419,57 @@ func (c *StandardHttpClient) GetValidatorProposerDuties(indices []uint64, epoch
 	return proposerMap, nil
 }
 
+// Get the validator assigned to propose each slot of an epoch, keyed by slot
+func (c *StandardHttpClient) GetEpochProposers(ctx context.Context, epoch uint64) (map[uint64]uint64, error) {
+	responseBody, err := c.getRequest(ctx, fmt.Sprintf(RequestValidatorProposerDuties, strconv.FormatUint(epoch, 10)))
+	if err != nil {
+		return nil, fmt.Errorf("Could not get validator proposer duties: %w", err)
+	}
+
+	var response ProposerDutiesResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("Could not decode validator proposer duties data: %w", err)
+	}
+
+	proposers := make(map[uint64]uint64, len(response.Data))
+	for _, duty := range response.Data {
+		proposers[uint64(duty.Slot)] = uint64(duty.ValidatorIndex)
+	}
+	return proposers, nil
+}
+
+// Get the positions of validators in the sync committee for an epoch; validators that aren't on it are left out
+func (c *StandardHttpClient) GetSyncCommitteePositions(ctx context.Context, indices []uint64, epoch uint64) (map[uint64][]uint64, error) {
+	indicesStrings := make([]string, len(indices))
+	for i, index := range indices {
+		indicesStrings[i] = strconv.FormatUint(index, 10)
+	}
+
+	responseBody, err := c.postRequest(ctx, fmt.Sprintf(RequestValidatorSyncDuties, strconv.FormatUint(epoch, 10)), indicesStrings)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get validator sync duties: %w", err)
+	}
+
+	var response SyncDutiesResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("Could not decode validator sync duties data: %w", err)
+	}
+
+	positions := make(map[uint64][]uint64, len(response.Data))
+	for _, duty := range response.Data {
+		for _, position := range duty.SyncCommitteeIndices {
+			positions[uint64(duty.ValidatorIndex)] = append(positions[uint64(duty.ValidatorIndex)], uint64(position))
+		}
+	}
+	return positions, nil
+}
+
 // Get a validator's index
-func (c *StandardHttpClient) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error) {
+func (c *StandardHttpClient) GetValidatorIndex(ctx context.Context, pubkey types.ValidatorPubkey) (uint64, error) {
 
 	// Get validator
 	pubkeyString := hexutil.AddPrefix(pubkey.Hex())
-	validators, err := c.getValidatorsByOpts([]string{pubkeyString}, nil)
+	validators, err := c.getValidatorsByOpts(ctx, []string{pubkeyString}, nil, false)
 	if err != nil {
 		return 0, err
 	}
@@ -409,7 +484,7 @@ func (c *StandardHttpClient) GetValidatorIndex(pubkey types.ValidatorPubkey) (ui
 }
 
 // Get domain data for a domain type at a given epoch
-func (c *StandardHttpClient) GetDomainData(domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error) {
+func (c *StandardHttpClient) GetDomainData(ctx context.Context, domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error) {
 
 	// Data
 	var wg errgroup.Group
@@ -419,14 +494,14 @@ func (c *StandardHttpClient) GetDomainData(domainType []byte, epoch uint64, useG
 	// Get genesis
 	wg.Go(func() error {
 		var err error
-		genesis, err = c.getGenesis()
+		genesis, err = c.getGenesis(ctx)
 		return err
 	})
 
 	// Get fork
 	wg.Go(func() error {
 		var err error
-		fork, err = c.getFork("head")
+		fork, err = c.getFork(ctx, "head")
 		return err
 	})
 
@@ -452,9 +527,46 @@ func (c *StandardHttpClient) GetDomainData(domainType []byte, epoch uint64, useG
 
 }
 
+// Get the current fork and the genesis validators root, as needed by remote signers
+func (c *StandardHttpClient) GetForkInfo(ctx context.Context) (beacon.ForkInfo, error) {
+
+	// Data
+	var wg errgroup.Group
+	var genesis GenesisResponse
+	var fork ForkResponse
+
+	// Get genesis
+	wg.Go(func() error {
+		var err error
+		genesis, err = c.getGenesis(ctx)
+		return err
+	})
+
+	// Get fork
+	wg.Go(func() error {
+		var err error
+		fork, err = c.getFork(ctx, "head")
+		return err
+	})
+
+	// Wait for data
+	if err := wg.Wait(); err != nil {
+		return beacon.ForkInfo{}, err
+	}
+
+	// Return response
+	return beacon.ForkInfo{
+		PreviousVersion:       fork.Data.PreviousVersion,
+		CurrentVersion:        fork.Data.CurrentVersion,
+		Epoch:                 uint64(fork.Data.Epoch),
+		GenesisValidatorsRoot: genesis.Data.GenesisValidatorsRoot,
+	}, nil
+
+}
+
 // Perform a voluntary exit on a validator
-func (c *StandardHttpClient) ExitValidator(validatorIndex, epoch uint64, signature types.ValidatorSignature) error {
-	return c.postVoluntaryExit(VoluntaryExitRequest{
+func (c *StandardHttpClient) ExitValidator(ctx context.Context, validatorIndex, epoch uint64, signature types.ValidatorSignature) error {
+	return c.postVoluntaryExit(ctx, VoluntaryExitRequest{
 		Message: VoluntaryExitMessage{
 			Epoch:          uinteger(epoch),
 			ValidatorIndex: uinteger(validatorIndex),
@@ -464,10 +576,10 @@ func (c *StandardHttpClient) ExitValidator(validatorIndex, epoch uint64, signatu
 }
 
 // Get the ETH1 data for the target beacon block
-func (c *StandardHttpClient) GetEth1DataForEth2Block(blockId string) (beacon.Eth1Data, bool, error) {
+func (c *StandardHttpClient) GetEth1DataForEth2Block(ctx context.Context, blockId string) (beacon.Eth1Data, bool, error) {
 
 	// Get the Beacon block
-	block, exists, err := c.getBeaconBlock(blockId)
+	block, exists, err := c.getBeaconBlock(ctx, blockId)
 	if err != nil {
 		return beacon.Eth1Data{}, false, err
 	}
@@ -484,8 +596,8 @@ func (c *StandardHttpClient) GetEth1DataForEth2Block(blockId string) (beacon.Eth
 
 }
 
-func (c *StandardHttpClient) GetAttestations(blockId string) ([]beacon.AttestationInfo, bool, error) {
-	attestations, exists, err := c.getAttestations(blockId)
+func (c *StandardHttpClient) GetAttestations(ctx context.Context, blockId string) ([]beacon.AttestationInfo, bool, error) {
+	attestations, exists, err := c.getAttestations(ctx, blockId)
 	if err != nil {
 		return nil, false, err
 	}
@@ -496,20 +608,40 @@ func (c *StandardHttpClient) GetAttestations(blockId string) ([]beacon.Attestati
 	// Add attestation info
 	attestationInfo := make([]beacon.AttestationInfo, len(attestations.Data))
 	for i, attestation := range attestations.Data {
-		bitString := hexutil.RemovePrefix(attestation.AggregationBits)
-		attestationInfo[i].SlotIndex = uint64(attestation.Data.Slot)
-		attestationInfo[i].CommitteeIndex = uint64(attestation.Data.Index)
-		attestationInfo[i].AggregationBits, err = hex.DecodeString(bitString)
+		attestationInfo[i], err = getAttestationInfo(attestation)
 		if err != nil {
-			return nil, false, fmt.Errorf("Error decoding aggregation bits for attestation %d of block %s: %w", i, blockId, err)
+			return nil, false, fmt.Errorf("Error decoding attestation %d of block %s: %w", i, blockId, err)
 		}
 	}
 
 	return attestationInfo, true, nil
 }
 
-func (c *StandardHttpClient) GetBeaconBlock(blockId string) (beacon.BeaconBlock, bool, error) {
-	block, exists, err := c.getBeaconBlock(blockId)
+// Convert an attestation from the API, pre-Electra or Electra, to its info
+func getAttestationInfo(attestation Attestation) (beacon.AttestationInfo, error) {
+	info := beacon.AttestationInfo{
+		SlotIndex:       uint64(attestation.Data.Slot),
+		CommitteeIndex:  uint64(attestation.Data.Index),
+		BeaconBlockRoot: common.BytesToHash(attestation.Data.BeaconBlockRoot),
+		TargetEpoch:     uint64(attestation.Data.Target.Epoch),
+		TargetRoot:      common.BytesToHash(attestation.Data.Target.Root),
+	}
+	var err error
+	info.AggregationBits, err = hex.DecodeString(hexutil.RemovePrefix(attestation.AggregationBits))
+	if err != nil {
+		return beacon.AttestationInfo{}, fmt.Errorf("Error decoding aggregation bits: %w", err)
+	}
+	if attestation.CommitteeBits != "" {
+		info.CommitteeBits, err = hex.DecodeString(hexutil.RemovePrefix(attestation.CommitteeBits))
+		if err != nil {
+			return beacon.AttestationInfo{}, fmt.Errorf("Error decoding committee bits: %w", err)
+		}
+	}
+	return info, nil
+}
+
+func (c *StandardHttpClient) GetBeaconBlock(ctx context.Context, blockId string) (beacon.BeaconBlock, bool, error) {
+	block, exists, err := c.getBeaconBlock(ctx, blockId)
 	if err != nil {
 		return beacon.BeaconBlock{}, false, err
 	}
@@ -520,6 +652,12 @@ func (c *StandardHttpClient) GetBeaconBlock(blockId string) (beacon.BeaconBlock,
 	beaconBlock := beacon.BeaconBlock{
 		Slot:          uint64(block.Data.Message.Slot),
 		ProposerIndex: uint64(block.Data.Message.ProposerIndex),
+		ParentRoot:    common.BytesToHash(block.Data.Message.ParentRoot),
+	}
+
+	// Sync committees only exist after Altair
+	if block.Data.Message.Body.SyncAggregate != nil {
+		beaconBlock.SyncCommitteeBits = bitfield.Bitvector512(block.Data.Message.Body.SyncAggregate.SyncCommitteeBits)
 	}
 
 	// Execution payload only exists after the merge, so check for its existence
@@ -533,14 +671,9 @@ func (c *StandardHttpClient) GetBeaconBlock(blockId string) (beacon.BeaconBlock,
 
 	// Add attestation info
 	for i, attestation := range block.Data.Message.Body.Attestations {
-		bitString := hexutil.RemovePrefix(attestation.AggregationBits)
-		info := beacon.AttestationInfo{
-			SlotIndex:      uint64(attestation.Data.Slot),
-			CommitteeIndex: uint64(attestation.Data.Index),
-		}
-		info.AggregationBits, err = hex.DecodeString(bitString)
+		info, err := getAttestationInfo(attestation)
 		if err != nil {
-			return beacon.BeaconBlock{}, false, fmt.Errorf("Error decoding aggregation bits for attestation %d of block %s: %w", i, blockId, err)
+			return beacon.BeaconBlock{}, false, fmt.Errorf("Error decoding attestation %d of block %s: %w", i, blockId, err)
 		}
 		beaconBlock.Attestations = append(beaconBlock.Attestations, info)
 	}
@@ -549,8 +682,19 @@ func (c *StandardHttpClient) GetBeaconBlock(blockId string) (beacon.BeaconBlock,
 }
 
 // Get the attestation committees for the given epoch, or the current epoch if nil
-func (c *StandardHttpClient) GetCommitteesForEpoch(epoch *uint64) ([]beacon.Committee, error) {
-	response, err := c.getCommittees("head", epoch)
+func (c *StandardHttpClient) GetCommitteesForEpoch(ctx context.Context, epoch *uint64) ([]beacon.Committee, error) {
+
+	// Nodes only work out committees for epochs close to the state, so use the epoch's own state
+	stateId := "head"
+	if epoch != nil {
+		slotsPerEpoch, err := c.getSlotsPerEpoch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		stateId = strconv.FormatUint(*epoch*slotsPerEpoch, 10)
+	}
+
+	response, err := c.getCommittees(ctx, stateId, epoch)
 	if err != nil {
 		return nil, err
 	}
@@ -571,15 +715,155 @@ func (c *StandardHttpClient) GetCommitteesForEpoch(epoch *uint64) ([]beacon.Comm
 	return committees, nil
 }
 
+// Stream events from the beacon node until the stream drops or the context is cancelled.
+// Events are sent to the channel as they arrive; the stream always ends with an error.
+func (c *StandardHttpClient) StreamEvents(ctx context.Context, topics []beacon.EventTopic, events chan<- beacon.Event) error {
+
+	topicNames := make([]string, len(topics))
+	for i, topic := range topics {
+		topicNames[i] = string(topic)
+	}
+
+	// The stream has no overall timeout, so drop it if the beacon node goes quiet
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	idle := make(chan struct{})
+	idleTimer := time.AfterFunc(c.eventStreamIdleTimeout, func() {
+		close(idle)
+		cancel()
+	})
+	defer idleTimer.Stop()
+
+	// Open the stream
+	requestPath := fmt.Sprintf(RequestEventsPath, url.QueryEscape(strings.Join(topicNames, ",")))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(RequestUrlFormat, c.providerAddress, requestPath), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "text/event-stream")
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(response.Body)
+		return beacon.NewApiError(response.StatusCode, body)
+	}
+
+	// Read server-sent events; each one is an "event" line and "data" lines ending with a blank line
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventStreamLineSize)
+	var eventName string
+	var data []string
+	for scanner.Scan() {
+		idleTimer.Reset(c.eventStreamIdleTimeout)
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if eventName != "" && len(data) > 0 {
+				event, err := parseEvent(beacon.EventTopic(eventName), []byte(strings.Join(data, "\n")))
+				if err != nil {
+					return fmt.Errorf("Could not decode %s event: %w", eventName, err)
+				}
+				if event != nil {
+					select {
+					case events <- *event:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+			eventName = ""
+			data = nil
+		case strings.HasPrefix(line, ":"):
+			// Comment, used by some clients as a keep-alive
+		case strings.HasPrefix(line, "event:"):
+			eventName = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	select {
+	case <-idle:
+		return fmt.Errorf("no events received for %s: %w", c.eventStreamIdleTimeout, context.DeadlineExceeded)
+	default:
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("event stream closed by the beacon node")
+
+}
+
+// Decode the data of an event; events on topics we don't know are skipped
+func parseEvent(topic beacon.EventTopic, data []byte) (*beacon.Event, error) {
+	event := beacon.Event{Topic: topic}
+	switch topic {
+	case beacon.EventTopic_Head:
+		var head HeadEventData
+		if err := json.Unmarshal(data, &head); err != nil {
+			return nil, err
+		}
+		event.Head = &beacon.HeadEvent{
+			Slot:                uint64(head.Slot),
+			Block:               common.BytesToHash(head.Block),
+			State:               common.BytesToHash(head.State),
+			EpochTransition:     head.EpochTransition,
+			ExecutionOptimistic: head.ExecutionOptimistic,
+		}
+	case beacon.EventTopic_FinalizedCheckpoint:
+		var checkpoint FinalizedCheckpointEventData
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return nil, err
+		}
+		event.FinalizedCheckpoint = &beacon.FinalizedCheckpointEvent{
+			Epoch:               uint64(checkpoint.Epoch),
+			Block:               common.BytesToHash(checkpoint.Block),
+			State:               common.BytesToHash(checkpoint.State),
+			ExecutionOptimistic: checkpoint.ExecutionOptimistic,
+		}
+	case beacon.EventTopic_ChainReorg:
+		var reorg ChainReorgEventData
+		if err := json.Unmarshal(data, &reorg); err != nil {
+			return nil, err
+		}
+		event.ChainReorg = &beacon.ChainReorgEvent{
+			Slot:                uint64(reorg.Slot),
+			Epoch:               uint64(reorg.Epoch),
+			Depth:               uint64(reorg.Depth),
+			OldHeadBlock:        common.BytesToHash(reorg.OldHeadBlock),
+			NewHeadBlock:        common.BytesToHash(reorg.NewHeadBlock),
+			OldHeadState:        common.BytesToHash(reorg.OldHeadState),
+			NewHeadState:        common.BytesToHash(reorg.NewHeadState),
+			ExecutionOptimistic: reorg.ExecutionOptimistic,
+		}
+	case beacon.EventTopic_VoluntaryExit:
+		var exit VoluntaryExitEventData
+		if err := json.Unmarshal(data, &exit); err != nil {
+			return nil, err
+		}
+		event.VoluntaryExit = &beacon.VoluntaryExitEvent{
+			ValidatorIndex: uint64(exit.Message.ValidatorIndex),
+			Epoch:          uint64(exit.Message.Epoch),
+			Signature:      types.BytesToValidatorSignature(exit.Signature),
+		}
+	default:
+		return nil, nil
+	}
+	return &event, nil
+}
+
 // Get sync status
-func (c *StandardHttpClient) getSyncStatus() (SyncStatusResponse, error) {
-	responseBody, status, err := c.getRequest(RequestSyncStatusPath)
+func (c *StandardHttpClient) getSyncStatus(ctx context.Context) (SyncStatusResponse, error) {
+	responseBody, err := c.getRequest(ctx, RequestSyncStatusPath)
 	if err != nil {
 		return SyncStatusResponse{}, fmt.Errorf("Could not get node sync status: %w", err)
 	}
-	if status != http.StatusOK {
-		return SyncStatusResponse{}, fmt.Errorf("Could not get node sync status: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 	var syncStatus SyncStatusResponse
 	if err := json.Unmarshal(responseBody, &syncStatus); err != nil {
 		return SyncStatusResponse{}, fmt.Errorf("Could not decode node sync status: %w", err)
@@ -588,14 +872,11 @@ func (c *StandardHttpClient) getSyncStatus() (SyncStatusResponse, error) {
 }
 
 // Get the eth2 config
-func (c *StandardHttpClient) getEth2Config() (Eth2ConfigResponse, error) {
-	responseBody, status, err := c.getRequest(RequestEth2ConfigPath)
+func (c *StandardHttpClient) getEth2Config(ctx context.Context) (Eth2ConfigResponse, error) {
+	responseBody, err := c.getRequest(ctx, RequestEth2ConfigPath)
 	if err != nil {
 		return Eth2ConfigResponse{}, fmt.Errorf("Could not get eth2 config: %w", err)
 	}
-	if status != http.StatusOK {
-		return Eth2ConfigResponse{}, fmt.Errorf("Could not get eth2 config: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 	var eth2Config Eth2ConfigResponse
 	if err := json.Unmarshal(responseBody, &eth2Config); err != nil {
 		return Eth2ConfigResponse{}, fmt.Errorf("Could not decode eth2 config: %w", err)
@@ -604,14 +885,11 @@ func (c *StandardHttpClient) getEth2Config() (Eth2ConfigResponse, error) {
 }
 
 // Get the eth2 deposit contract info
-func (c *StandardHttpClient) getEth2DepositContract() (Eth2DepositContractResponse, error) {
-	responseBody, status, err := c.getRequest(RequestEth2DepositContractMethod)
+func (c *StandardHttpClient) getEth2DepositContract(ctx context.Context) (Eth2DepositContractResponse, error) {
+	responseBody, err := c.getRequest(ctx, RequestEth2DepositContractMethod)
 	if err != nil {
 		return Eth2DepositContractResponse{}, fmt.Errorf("Could not get eth2 deposit contract: %w", err)
 	}
-	if status != http.StatusOK {
-		return Eth2DepositContractResponse{}, fmt.Errorf("Could not get eth2 deposit contract: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 	var eth2DepositContract Eth2DepositContractResponse
 	if err := json.Unmarshal(responseBody, &eth2DepositContract); err != nil {
 		return Eth2DepositContractResponse{}, fmt.Errorf("Could not decode eth2 deposit contract: %w", err)
@@ -620,14 +898,11 @@ func (c *StandardHttpClient) getEth2DepositContract() (Eth2DepositContractRespon
 }
 
 // Get genesis information
-func (c *StandardHttpClient) getGenesis() (GenesisResponse, error) {
-	responseBody, status, err := c.getRequest(RequestGenesisPath)
+func (c *StandardHttpClient) getGenesis(ctx context.Context) (GenesisResponse, error) {
+	responseBody, err := c.getRequest(ctx, RequestGenesisPath)
 	if err != nil {
 		return GenesisResponse{}, fmt.Errorf("Could not get genesis data: %w", err)
 	}
-	if status != http.StatusOK {
-		return GenesisResponse{}, fmt.Errorf("Could not get genesis data: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 	var genesis GenesisResponse
 	if err := json.Unmarshal(responseBody, &genesis); err != nil {
 		return GenesisResponse{}, fmt.Errorf("Could not decode genesis: %w", err)
@@ -636,14 +911,11 @@ func (c *StandardHttpClient) getGenesis() (GenesisResponse, error) {
 }
 
 // Get finality checkpoints
-func (c *StandardHttpClient) getFinalityCheckpoints(stateId string) (FinalityCheckpointsResponse, error) {
-	responseBody, status, err := c.getRequest(fmt.Sprintf(RequestFinalityCheckpointsPath, stateId))
+func (c *StandardHttpClient) getFinalityCheckpoints(ctx context.Context, stateId string) (FinalityCheckpointsResponse, error) {
+	responseBody, err := c.getRequest(ctx, fmt.Sprintf(RequestFinalityCheckpointsPath, stateId))
 	if err != nil {
 		return FinalityCheckpointsResponse{}, fmt.Errorf("Could not get finality checkpoints: %w", err)
 	}
-	if status != http.StatusOK {
-		return FinalityCheckpointsResponse{}, fmt.Errorf("Could not get finality checkpoints: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 	var finalityCheckpoints FinalityCheckpointsResponse
 	if err := json.Unmarshal(responseBody, &finalityCheckpoints); err != nil {
 		return FinalityCheckpointsResponse{}, fmt.Errorf("Could not decode finality checkpoints: %w", err)
@@ -652,14 +924,11 @@ func (c *StandardHttpClient) getFinalityCheckpoints(stateId string) (FinalityChe
 }
 
 // Get fork
-func (c *StandardHttpClient) getFork(stateId string) (ForkResponse, error) {
-	responseBody, status, err := c.getRequest(fmt.Sprintf(RequestForkPath, stateId))
+func (c *StandardHttpClient) getFork(ctx context.Context, stateId string) (ForkResponse, error) {
+	responseBody, err := c.getRequest(ctx, fmt.Sprintf(RequestForkPath, stateId))
 	if err != nil {
 		return ForkResponse{}, fmt.Errorf("Could not get fork data: %w", err)
 	}
-	if status != http.StatusOK {
-		return ForkResponse{}, fmt.Errorf("Could not get fork data: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 	var fork ForkResponse
 	if err := json.Unmarshal(responseBody, &fork); err != nil {
 		return ForkResponse{}, fmt.Errorf("Could not decode fork data: %w", err)
@@ -667,19 +936,42 @@ func (c *StandardHttpClient) getFork(stateId string) (ForkResponse, error) {
 	return fork, nil
 }
 
-// Get validators
-func (c *StandardHttpClient) getValidators(stateId string, pubkeys []string) (ValidatorsResponse, error) {
+// Get validators; if sszEpoch is set, SSZ is requested and the epoch of the state is used to work out their statuses
+func (c *StandardHttpClient) getValidators(ctx context.Context, stateId string, pubkeys []string, sszEpoch *uint64) (ValidatorsResponse, error) {
 	var query string
 	if len(pubkeys) > 0 {
 		query = fmt.Sprintf("?id=%s", strings.Join(pubkeys, ","))
 	}
-	responseBody, status, err := c.getRequest(fmt.Sprintf(RequestValidatorsPath, stateId) + query)
+	requestPath := fmt.Sprintf(RequestValidatorsPath, stateId) + query
+
+	var responseBody []byte
+	var err error
+	if sszEpoch != nil && !c.isSszUnsupported() {
+		var contentType string
+		responseBody, contentType, err = c.sendRequestWithRetries(ctx, http.MethodGet, requestPath, nil, RequestAcceptSsz)
+		if err == nil && strings.HasPrefix(contentType, RequestSszType) {
+			validators, err := decodeSszValidators(responseBody, *sszEpoch)
+			if err != nil {
+				return ValidatorsResponse{}, fmt.Errorf("Could not decode validators: %w", err)
+			}
+			return validators, nil
+		}
+
+		// The node answered with JSON or refused the media type, so stop asking for SSZ
+		var apiErr *beacon.ApiError
+		if err == nil || (errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotAcceptable || apiErr.StatusCode == http.StatusUnsupportedMediaType)) {
+			atomic.StoreInt32(&c.sszUnsupported, 1)
+		}
+		if err != nil && atomic.LoadInt32(&c.sszUnsupported) == 1 {
+			responseBody, err = c.getRequest(ctx, requestPath)
+		}
+	} else {
+		responseBody, err = c.getRequest(ctx, requestPath)
+	}
 	if err != nil {
 		return ValidatorsResponse{}, fmt.Errorf("Could not get validators: %w", err)
 	}
-	if status != http.StatusOK {
-		return ValidatorsResponse{}, fmt.Errorf("Could not get validators: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
+
 	var validators ValidatorsResponse
 	if err := json.Unmarshal(responseBody, &validators); err != nil {
 		return ValidatorsResponse{}, fmt.Errorf("Could not decode validators: %w", err)
@@ -687,30 +979,53 @@ func (c *StandardHttpClient) getValidators(stateId string, pubkeys []string) (Va
 	return validators, nil
 }
 
-// Get validators by pubkeys and status options
-func (c *StandardHttpClient) getValidatorsByOpts(pubkeysOrIndices []string, opts *beacon.ValidatorStatusOptions) (ValidatorsResponse, error) {
+// Get validators by pubkeys and status options; bulk queries can ask for SSZ to cut down on decoding time and memory
+func (c *StandardHttpClient) getValidatorsByOpts(ctx context.Context, pubkeysOrIndices []string, opts *beacon.ValidatorStatusOptions, preferSsz bool) (ValidatorsResponse, error) {
 
 	// Get state ID
 	var stateId string
+	var slot *uint64
 	if opts == nil {
 		stateId = "head"
 	} else if opts.Slot != nil {
 		stateId = strconv.FormatInt(int64(*opts.Slot), 10)
+		slot = opts.Slot
 	} else if opts.Epoch != nil {
 
 		// Get eth2 config
-		eth2Config, err := c.getEth2Config()
+		slotsPerEpoch, err := c.getSlotsPerEpoch(ctx)
 		if err != nil {
 			return ValidatorsResponse{}, err
 		}
 
 		// Get slot nuimber
-		slot := *opts.Epoch * uint64(eth2Config.Data.SlotsPerEpoch)
-		stateId = strconv.FormatInt(int64(slot), 10)
+		epochSlot := *opts.Epoch * slotsPerEpoch
+		stateId = strconv.FormatInt(int64(epochSlot), 10)
+		slot = &epochSlot
 
 	} else {
 		return ValidatorsResponse{}, fmt.Errorf("must specify a slot or epoch when calling getValidatorsByOpts")
 	}
+
+	// SSZ responses don't include statuses, so pin the state to a slot and work them out from its epoch
+	var sszEpoch *uint64
+	if preferSsz && !c.isSszUnsupported() {
+		if slot == nil {
+			headSlot, err := c.getHeaderSlot(ctx, stateId)
+			if err != nil {
+				return ValidatorsResponse{}, err
+			}
+			stateId = strconv.FormatUint(headSlot, 10)
+			slot = &headSlot
+		}
+		slotsPerEpoch, err := c.getSlotsPerEpoch(ctx)
+		if err != nil {
+			return ValidatorsResponse{}, err
+		}
+		epoch := *slot / slotsPerEpoch
+		sszEpoch = &epoch
+	}
+
 	count := len(pubkeysOrIndices)
 	data := make([]Validator, count)
 	validFlags := make([]bool, count)
@@ -726,7 +1041,7 @@ func (c *StandardHttpClient) getValidatorsByOpts(pubkeysOrIndices []string, opts
 		wg.Go(func() error {
 			// Get & add validators
 			batch := pubkeysOrIndices[i:max]
-			validators, err := c.getValidators(stateId, batch)
+			validators, err := c.getValidators(ctx, stateId, batch, sszEpoch)
 			if err != nil {
 				return fmt.Errorf("error getting validator statuses: %w", err)
 			}
@@ -754,29 +1069,58 @@ func (c *StandardHttpClient) getValidatorsByOpts(pubkeysOrIndices []string, opts
 
 }
 
+// Get the slot of a block header, e.g. to pin "head" to a specific state
+func (c *StandardHttpClient) getHeaderSlot(ctx context.Context, blockId string) (uint64, error) {
+	responseBody, err := c.getRequest(ctx, fmt.Sprintf(RequestBeaconHeaderPath, blockId))
+	if err != nil {
+		return 0, fmt.Errorf("Could not get beacon block header: %w", err)
+	}
+	var header BeaconHeaderResponse
+	if err := json.Unmarshal(responseBody, &header); err != nil {
+		return 0, fmt.Errorf("Could not decode beacon block header: %w", err)
+	}
+	return uint64(header.Data.Header.Message.Slot), nil
+}
+
+// Get the number of slots per epoch, which is only requested from the node once
+func (c *StandardHttpClient) getSlotsPerEpoch(ctx context.Context) (uint64, error) {
+	if slotsPerEpoch := atomic.LoadUint64(&c.slotsPerEpoch); slotsPerEpoch != 0 {
+		return slotsPerEpoch, nil
+	}
+	eth2Config, err := c.getEth2Config(ctx)
+	if err != nil {
+		return 0, err
+	}
+	slotsPerEpoch := uint64(eth2Config.Data.SlotsPerEpoch)
+	if slotsPerEpoch == 0 {
+		return 0, fmt.Errorf("beacon node reported 0 slots per epoch")
+	}
+	atomic.StoreUint64(&c.slotsPerEpoch, slotsPerEpoch)
+	return slotsPerEpoch, nil
+}
+
+// Check if the beacon node has shown it doesn't serve SSZ
+func (c *StandardHttpClient) isSszUnsupported() bool {
+	return atomic.LoadInt32(&c.sszUnsupported) == 1
+}
+
 // Send voluntary exit request
-func (c *StandardHttpClient) postVoluntaryExit(request VoluntaryExitRequest) error {
-	responseBody, status, err := c.postRequest(RequestVoluntaryExitPath, request)
+func (c *StandardHttpClient) postVoluntaryExit(ctx context.Context, request VoluntaryExitRequest) error {
+	_, err := c.postRequest(ctx, RequestVoluntaryExitPath, request)
 	if err != nil {
 		return fmt.Errorf("Could not broadcast exit for validator at index %d: %w", request.Message.ValidatorIndex, err)
 	}
-	if status != http.StatusOK {
-		return fmt.Errorf("Could not broadcast exit for validator at index %d: HTTP status %d; response body: '%s'", request.Message.ValidatorIndex, status, string(responseBody))
-	}
 	return nil
 }
 
 // Get the target beacon block
-func (c *StandardHttpClient) getAttestations(blockId string) (AttestationsResponse, bool, error) {
-	responseBody, status, err := c.getRequest(fmt.Sprintf(RequestAttestationsPath, blockId))
-	if err != nil {
-		return AttestationsResponse{}, false, fmt.Errorf("Could not get attestations data for slot %s: %w", blockId, err)
-	}
-	if status == http.StatusNotFound {
+func (c *StandardHttpClient) getAttestations(ctx context.Context, blockId string) (AttestationsResponse, bool, error) {
+	responseBody, err := c.getRequest(ctx, fmt.Sprintf(RequestAttestationsPath, blockId))
+	if beacon.IsNotFound(err) {
 		return AttestationsResponse{}, false, nil
 	}
-	if status != http.StatusOK {
-		return AttestationsResponse{}, false, fmt.Errorf("Could not get attestations data for slot %s: HTTP status %d; response body: '%s'", blockId, status, string(responseBody))
+	if err != nil {
+		return AttestationsResponse{}, false, fmt.Errorf("Could not get attestations data for slot %s: %w", blockId, err)
 	}
 	var attestations AttestationsResponse
 	if err := json.Unmarshal(responseBody, &attestations); err != nil {
@@ -786,16 +1130,13 @@ func (c *StandardHttpClient) getAttestations(blockId string) (AttestationsRespon
 }
 
 // Get the target beacon block
-func (c *StandardHttpClient) getBeaconBlock(blockId string) (BeaconBlockResponse, bool, error) {
-	responseBody, status, err := c.getRequest(fmt.Sprintf(RequestBeaconBlockPath, blockId))
-	if err != nil {
-		return BeaconBlockResponse{}, false, fmt.Errorf("Could not get beacon block data: %w", err)
-	}
-	if status == http.StatusNotFound {
+func (c *StandardHttpClient) getBeaconBlock(ctx context.Context, blockId string) (BeaconBlockResponse, bool, error) {
+	responseBody, err := c.getRequest(ctx, fmt.Sprintf(RequestBeaconBlockPath, blockId))
+	if beacon.IsNotFound(err) {
 		return BeaconBlockResponse{}, false, nil
 	}
-	if status != http.StatusOK {
-		return BeaconBlockResponse{}, false, fmt.Errorf("Could not get beacon block data: HTTP status %d; response body: '%s'", status, string(responseBody))
+	if err != nil {
+		return BeaconBlockResponse{}, false, fmt.Errorf("Could not get beacon block data: %w", err)
 	}
 	var beaconBlock BeaconBlockResponse
 	if err := json.Unmarshal(responseBody, &beaconBlock); err != nil {
@@ -805,18 +1146,15 @@ func (c *StandardHttpClient) getBeaconBlock(blockId string) (BeaconBlockResponse
 }
 
 // Get the committees for the epoch
-func (c *StandardHttpClient) getCommittees(stateId string, epoch *uint64) (CommitteesResponse, error) {
+func (c *StandardHttpClient) getCommittees(ctx context.Context, stateId string, epoch *uint64) (CommitteesResponse, error) {
 	query := ""
 	if epoch != nil {
 		query = fmt.Sprintf("?epoch=%d", *epoch)
 	}
-	responseBody, status, err := c.getRequest(fmt.Sprintf(RequestCommitteePath, stateId) + query)
+	responseBody, err := c.getRequest(ctx, fmt.Sprintf(RequestCommitteePath, stateId)+query)
 	if err != nil {
 		return CommitteesResponse{}, fmt.Errorf("Could not get committees: %w", err)
 	}
-	if status != http.StatusOK {
-		return CommitteesResponse{}, fmt.Errorf("Could not get committees: HTTP status %d; response body: '%s'", status, string(responseBody))
-	}
 	var committees CommitteesResponse
 	if err := json.Unmarshal(responseBody, &committees); err != nil {
 		return CommitteesResponse{}, fmt.Errorf("Could not decode committees: %w", err)
@@ -825,42 +1163,71 @@ func (c *StandardHttpClient) getCommittees(stateId string, epoch *uint64) (Commi
 }
 
 // Make a GET request to the beacon node
-func (c *StandardHttpClient) getRequest(requestPath string) ([]byte, int, error) {
+func (c *StandardHttpClient) getRequest(ctx context.Context, requestPath string) ([]byte, error) {
+	body, _, err := c.sendRequestWithRetries(ctx, http.MethodGet, requestPath, nil, "")
+	return body, err
+}
 
-	// Send request
-	response, err := http.Get(fmt.Sprintf(RequestUrlFormat, c.providerAddress, requestPath))
-	if err != nil {
-		return []byte{}, 0, err
-	}
-	defer func() {
-		_ = response.Body.Close()
-	}()
+// Make a POST request to the beacon node
+func (c *StandardHttpClient) postRequest(ctx context.Context, requestPath string, requestBody interface{}) ([]byte, error) {
 
-	// Get response
-	body, err := ioutil.ReadAll(response.Body)
+	// Get request body
+	requestBodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
-		return []byte{}, 0, err
+		return []byte{}, err
 	}
 
-	// Return
-	return body, response.StatusCode, nil
+	body, _, err := c.sendRequestWithRetries(ctx, http.MethodPost, requestPath, requestBodyBytes, "")
+	return body, err
 
 }
 
-// Make a POST request to the beacon node
-func (c *StandardHttpClient) postRequest(requestPath string, requestBody interface{}) ([]byte, int, error) {
+// Send a request, retrying with backoff if the beacon node is unreachable or fails with a server error.
+// Returns the response body and its content type.
+func (c *StandardHttpClient) sendRequestWithRetries(ctx context.Context, method string, requestPath string, requestBody []byte, accept string) ([]byte, string, error) {
+	retryDelay := RequestRetryDelay
+	for attempt := 0; ; attempt++ {
+		body, contentType, err := c.sendRequest(ctx, method, requestPath, requestBody, accept)
+		if err == nil || attempt >= MaxRequestRetries || !beacon.IsClientFailure(err) {
+			return body, contentType, err
+		}
 
-	// Get request body
-	requestBodyBytes, err := json.Marshal(requestBody)
+		// Stop early if the caller gives up
+		select {
+		case <-ctx.Done():
+			return nil, "", err
+		case <-time.After(retryDelay):
+		}
+		retryDelay *= 2
+	}
+}
+
+// Send a single request; non-2xx responses are returned as a beacon.ApiError
+func (c *StandardHttpClient) sendRequest(ctx context.Context, method string, requestPath string, requestBody []byte, accept string) ([]byte, string, error) {
+
+	// Each attempt gets its own timeout so a stuck beacon node can't hang the caller
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var requestBodyReader io.Reader
+	if requestBody != nil {
+		requestBodyReader = bytes.NewReader(requestBody)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf(RequestUrlFormat, c.providerAddress, requestPath), requestBodyReader)
 	if err != nil {
-		return []byte{}, 0, err
+		return []byte{}, "", err
+	}
+	if requestBody != nil {
+		request.Header.Set("Content-Type", RequestContentType)
+	}
+	if accept != "" {
+		request.Header.Set("Accept", accept)
 	}
-	requestBodyReader := bytes.NewReader(requestBodyBytes)
 
 	// Send request
-	response, err := http.Post(fmt.Sprintf(RequestUrlFormat, c.providerAddress, requestPath), RequestContentType, requestBodyReader)
+	response, err := c.httpClient.Do(request)
 	if err != nil {
-		return []byte{}, 0, err
+		return []byte{}, "", err
 	}
 	defer func() {
 		_ = response.Body.Close()
@@ -869,10 +1236,70 @@ func (c *StandardHttpClient) postRequest(requestPath string, requestBody interfa
 	// Get response
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return []byte{}, 0, err
+		return []byte{}, "", err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return []byte{}, "", beacon.NewApiError(response.StatusCode, body)
 	}
 
 	// Return
-	return body, response.StatusCode, nil
+	return body, response.Header.Get("Content-Type"), nil
 
 }
+
+// Decode an SSZ list of validators, working out each one's status at the given epoch
+func decodeSszValidators(data []byte, epoch uint64) (ValidatorsResponse, error) {
+	containerSize := (&sszeth2.ValidatorContainer{Validator: &sszeth2.Validator{}}).SizeSSZ()
+	if len(data)%containerSize != 0 {
+		return ValidatorsResponse{}, fmt.Errorf("SSZ validator list has length %d, which is not a multiple of %d", len(data), containerSize)
+	}
+
+	validators := make([]Validator, len(data)/containerSize)
+	for i := range validators {
+		var container sszeth2.ValidatorContainer
+		if err := container.UnmarshalSSZ(data[i*containerSize : (i+1)*containerSize]); err != nil {
+			return ValidatorsResponse{}, err
+		}
+
+		validator := &validators[i]
+		validator.Index = uinteger(container.Index)
+		validator.Balance = uinteger(container.Balance)
+		validator.Status = string(getValidatorState(container.Validator, container.Balance, epoch))
+		validator.Validator.Pubkey = container.Validator.Pubkey
+		validator.Validator.WithdrawalCredentials = container.Validator.WithdrawalCredentials
+		validator.Validator.EffectiveBalance = uinteger(container.Validator.EffectiveBalance)
+		validator.Validator.Slashed = container.Validator.Slashed
+		validator.Validator.ActivationEligibilityEpoch = uinteger(container.Validator.ActivationEligibilityEpoch)
+		validator.Validator.ActivationEpoch = uinteger(container.Validator.ActivationEpoch)
+		validator.Validator.ExitEpoch = uinteger(container.Validator.ExitEpoch)
+		validator.Validator.WithdrawableEpoch = uinteger(container.Validator.WithdrawableEpoch)
+	}
+	return ValidatorsResponse{Data: validators}, nil
+}
+
+// Get a validator's status at an epoch, following the rules of the Beacon API's validator status spec
+func getValidatorState(validator *sszeth2.Validator, balance uint64, epoch uint64) beacon.ValidatorState {
+	switch {
+	case validator.ActivationEligibilityEpoch == farFutureEpoch:
+		return beacon.ValidatorState_PendingInitialized
+	case validator.ActivationEpoch > epoch:
+		return beacon.ValidatorState_PendingQueued
+	case validator.ExitEpoch > epoch:
+		if validator.ExitEpoch == farFutureEpoch {
+			return beacon.ValidatorState_ActiveOngoing
+		}
+		if validator.Slashed {
+			return beacon.ValidatorState_ActiveSlashed
+		}
+		return beacon.ValidatorState_ActiveExiting
+	case validator.WithdrawableEpoch > epoch:
+		if validator.Slashed {
+			return beacon.ValidatorState_ExitedSlashed
+		}
+		return beacon.ValidatorState_ExitedUnslashed
+	case balance > 0:
+		return beacon.ValidatorState_WithdrawalPossible
+	default:
+		return beacon.ValidatorState_WithdrawalDone
+	}
+}
@@ -0,0 +1,383 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	sszeth2 "github.com/stader-labs/stader-node/shared/types/eth2"
+)
+
+const testBlockRoot = "0x9a2fefd2fdb57f74993c7780ea5b9030d2897b615b89f808011ca5aebed54eaf"
+
+// An SSE head event for a slot, with the data padded to at least padding bytes
+func headEvent(slot int, padding int) string {
+	return fmt.Sprintf("event: head\ndata: {\"slot\":\"%d\",\"block\":\"%s\",\"state\":\"%s\",%s\"epoch_transition\":false}\n\n",
+		slot, testBlockRoot, testBlockRoot, strings.Repeat(" ", padding))
+}
+
+// A local stand-in for a beacon node's event stream; each connection is handled by the next handler in turn
+type sseServer struct {
+	server      *httptest.Server
+	handlers    []func(w http.ResponseWriter, r *http.Request, flush func())
+	connections int
+	lock        sync.Mutex
+}
+
+func newSseServer(t *testing.T, handlers ...func(w http.ResponseWriter, r *http.Request, flush func())) *sseServer {
+	s := &sseServer{handlers: handlers}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/events" {
+			http.NotFound(w, r)
+			return
+		}
+		s.lock.Lock()
+		connection := s.connections
+		s.connections++
+		s.lock.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+		flusher.Flush()
+		if connection < len(s.handlers) {
+			s.handlers[connection](w, r, flusher.Flush)
+			return
+		}
+		// Hold any further connections open without sending anything
+		<-r.Context().Done()
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *sseServer) getConnections() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.connections
+}
+
+// Stream events from the server until it ends, returning the events and the error the stream ended with
+func streamEvents(t *testing.T, client *StandardHttpClient) ([]beacon.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events := make(chan beacon.Event, 16)
+	err := client.StreamEvents(ctx, []beacon.EventTopic{beacon.EventTopic_Head}, events)
+	close(events)
+	received := []beacon.Event{}
+	for event := range events {
+		received = append(received, event)
+	}
+	return received, err
+}
+
+func TestStreamEventsReconnect(t *testing.T) {
+	server := newSseServer(t,
+		// The first stream drops after one event
+		func(w http.ResponseWriter, r *http.Request, flush func()) {
+			_, _ = w.Write([]byte(headEvent(1, 0)))
+			flush()
+		},
+		func(w http.ResponseWriter, r *http.Request, flush func()) {
+			_, _ = w.Write([]byte(headEvent(2, 0)))
+			flush()
+			<-r.Context().Done()
+		},
+	)
+	client := NewStandardHttpClient(server.server.URL, time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	streamErrors := make(chan error, 4)
+	events := beacon.SubscribeEvents(ctx, client, []beacon.EventTopic{beacon.EventTopic_Head}, func(err error) {
+		streamErrors <- err
+	})
+
+	for _, slot := range []uint64{1, 2} {
+		select {
+		case event := <-events:
+			if event.Head == nil || event.Head.Slot != slot {
+				t.Fatalf("got %+v, want the head event for slot %d", event, slot)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for the head event for slot %d", slot)
+		}
+	}
+	select {
+	case err := <-streamErrors:
+		if !strings.Contains(err.Error(), "closed by the beacon node") {
+			t.Errorf("unexpected error for the dropped stream: %s", err)
+		}
+	default:
+		t.Error("the dropped stream wasn't reported")
+	}
+	if connections := server.getConnections(); connections != 2 {
+		t.Errorf("connected %d times, want 2", connections)
+	}
+
+	// The channel is closed once the subscription is cancelled
+	cancel()
+	for range events {
+	}
+}
+
+func TestStreamEventsIdleTimeout(t *testing.T) {
+	server := newSseServer(t,
+		// Keep-alive comments hold the stream open past the idle timeout, then it goes quiet
+		func(w http.ResponseWriter, r *http.Request, flush func()) {
+			for i := 0; i < 5; i++ {
+				_, _ = w.Write([]byte(":\n"))
+				flush()
+				time.Sleep(50 * time.Millisecond)
+			}
+			_, _ = w.Write([]byte(headEvent(1, 0)))
+			flush()
+			<-r.Context().Done()
+		},
+	)
+	client := NewStandardHttpClient(server.server.URL, time.Second)
+	client.eventStreamIdleTimeout = 150 * time.Millisecond
+
+	started := time.Now()
+	events, err := streamEvents(t, client)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("stream ended with %v, want an idle timeout", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("the idle stream took %s to be dropped", elapsed)
+	}
+	if len(events) != 1 {
+		t.Errorf("got %d events before the stream went quiet, want 1", len(events))
+	}
+}
+
+func TestStreamEventsLongLines(t *testing.T) {
+	server := newSseServer(t,
+		// A line longer than the default scanner buffer, but within the limit
+		func(w http.ResponseWriter, r *http.Request, flush func()) {
+			_, _ = w.Write([]byte(headEvent(1, 200*1024)))
+			flush()
+		},
+		// A line over the limit ends the stream
+		func(w http.ResponseWriter, r *http.Request, flush func()) {
+			_, _ = w.Write([]byte(headEvent(2, maxEventStreamLineSize)))
+			flush()
+		},
+	)
+	client := NewStandardHttpClient(server.server.URL, time.Second)
+
+	events, err := streamEvents(t, client)
+	if len(events) != 1 || events[0].Head == nil || events[0].Head.Slot != 1 {
+		t.Fatalf("got %+v for a long line, want the head event for slot 1", events)
+	}
+	if err == nil || !strings.Contains(err.Error(), "closed by the beacon node") {
+		t.Errorf("stream ended with %v, want it to be closed by the server", err)
+	}
+
+	events, err = streamEvents(t, client)
+	if len(events) != 0 {
+		t.Errorf("got %d events from a line over the limit", len(events))
+	}
+	if err == nil || !strings.Contains(err.Error(), "token too long") {
+		t.Errorf("stream ended with %v, want the line to be too long", err)
+	}
+}
+
+// Validators in each state at epoch 200000, as a beacon node would report them
+const testStateEpoch = 200000
+
+var knownValidatorStates = []struct {
+	name      string
+	validator sszeth2.Validator
+	balance   uint64
+	state     beacon.ValidatorState
+}{
+	{"deposited", sszeth2.Validator{ActivationEligibilityEpoch: farFutureEpoch, ActivationEpoch: farFutureEpoch, ExitEpoch: farFutureEpoch, WithdrawableEpoch: farFutureEpoch}, 32e9, beacon.ValidatorState_PendingInitialized},
+	{"eligible", sszeth2.Validator{ActivationEligibilityEpoch: 199990, ActivationEpoch: farFutureEpoch, ExitEpoch: farFutureEpoch, WithdrawableEpoch: farFutureEpoch}, 32e9, beacon.ValidatorState_PendingQueued},
+	{"activation scheduled", sszeth2.Validator{ActivationEligibilityEpoch: 199990, ActivationEpoch: 200004, ExitEpoch: farFutureEpoch, WithdrawableEpoch: farFutureEpoch}, 32e9, beacon.ValidatorState_PendingQueued},
+	{"activated this epoch", sszeth2.Validator{ActivationEligibilityEpoch: 199990, ActivationEpoch: 200000, ExitEpoch: farFutureEpoch, WithdrawableEpoch: farFutureEpoch}, 32e9, beacon.ValidatorState_ActiveOngoing},
+	{"active", sszeth2.Validator{ActivationEligibilityEpoch: 0, ActivationEpoch: 0, ExitEpoch: farFutureEpoch, WithdrawableEpoch: farFutureEpoch}, 32e9, beacon.ValidatorState_ActiveOngoing},
+	{"exiting", sszeth2.Validator{ActivationEpoch: 100, ExitEpoch: 200100, WithdrawableEpoch: 200356}, 32e9, beacon.ValidatorState_ActiveExiting},
+	{"slashed while active", sszeth2.Validator{Slashed: true, ActivationEpoch: 100, ExitEpoch: 200100, WithdrawableEpoch: 208292}, 31e9, beacon.ValidatorState_ActiveSlashed},
+	{"exited this epoch", sszeth2.Validator{ActivationEpoch: 100, ExitEpoch: 200000, WithdrawableEpoch: 200256}, 32e9, beacon.ValidatorState_ExitedUnslashed},
+	{"exited", sszeth2.Validator{ActivationEpoch: 100, ExitEpoch: 199000, WithdrawableEpoch: 200256}, 32e9, beacon.ValidatorState_ExitedUnslashed},
+	{"exited slashed", sszeth2.Validator{Slashed: true, ActivationEpoch: 100, ExitEpoch: 199000, WithdrawableEpoch: 207192}, 30e9, beacon.ValidatorState_ExitedSlashed},
+	{"withdrawable", sszeth2.Validator{ActivationEpoch: 100, ExitEpoch: 190000, WithdrawableEpoch: 190256}, 32e9, beacon.ValidatorState_WithdrawalPossible},
+	{"withdrawable this epoch", sszeth2.Validator{ActivationEpoch: 100, ExitEpoch: 199744, WithdrawableEpoch: 200000}, 32e9, beacon.ValidatorState_WithdrawalPossible},
+	{"withdrawn", sszeth2.Validator{ActivationEpoch: 100, ExitEpoch: 190000, WithdrawableEpoch: 190256}, 0, beacon.ValidatorState_WithdrawalDone},
+	{"slashed and withdrawn", sszeth2.Validator{Slashed: true, ActivationEpoch: 100, ExitEpoch: 190000, WithdrawableEpoch: 198192}, 0, beacon.ValidatorState_WithdrawalDone},
+}
+
+func TestGetValidatorState(t *testing.T) {
+	for _, test := range knownValidatorStates {
+		validator := test.validator
+		if state := getValidatorState(&validator, test.balance, testStateEpoch); state != test.state {
+			t.Errorf("%s: got %s, want %s", test.name, state, test.state)
+		}
+	}
+}
+
+func TestGetAttestationInfo(t *testing.T) {
+	attestationJson := `{"aggregation_bits":"0x0b","data":{"slot":"8","index":"%s","beacon_block_root":"` + testBlockRoot + `","source":{"epoch":"1","root":"` + testBlockRoot + `"},"target":{"epoch":"2","root":"` + testBlockRoot + `"}}%s}`
+	tests := []struct {
+		name              string
+		json              string
+		wantCommittees    []int
+		wantCommitteeBits bool
+	}{
+		{name: "pre-Electra", json: fmt.Sprintf(attestationJson, "3", "")},
+		{name: "Electra", json: fmt.Sprintf(attestationJson, "0", `,"committee_bits":"0x0500000000000000"`), wantCommittees: []int{0, 2}, wantCommitteeBits: true},
+	}
+
+	for _, test := range tests {
+		var attestation Attestation
+		if err := json.Unmarshal([]byte(test.json), &attestation); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		info, err := getAttestationInfo(attestation)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if info.SlotIndex != 8 || info.TargetEpoch != 2 || info.AggregationBits.Len() != 3 || !info.AggregationBits.BitAt(1) {
+			t.Errorf("%s: got slot %d, target epoch %d, %d aggregation bits; want 8, 2, 3 with bit 1 set", test.name, info.SlotIndex, info.TargetEpoch, info.AggregationBits.Len())
+		}
+		if (info.CommitteeBits != nil) != test.wantCommitteeBits {
+			t.Errorf("%s: got committee bits %x", test.name, []byte(info.CommitteeBits))
+		}
+		if test.wantCommitteeBits && fmt.Sprint(info.CommitteeBits.BitIndices()) != fmt.Sprint(test.wantCommittees) {
+			t.Errorf("%s: got committees %v, want %v", test.name, info.CommitteeBits.BitIndices(), test.wantCommittees)
+		}
+	}
+}
+
+// Encode validators as the SSZ list the validators endpoint returns
+func encodeSszValidators(t testing.TB, containers []sszeth2.ValidatorContainer) []byte {
+	data := []byte{}
+	for i := range containers {
+		var err error
+		data, err = containers[i].MarshalSSZTo(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return data
+}
+
+func newTestValidatorContainer(index int, validator sszeth2.Validator, balance uint64) sszeth2.ValidatorContainer {
+	validator.Pubkey = bytes.Repeat([]byte{byte(index)}, 48)
+	validator.WithdrawalCredentials = bytes.Repeat([]byte{0x01}, 32)
+	validator.EffectiveBalance = 32e9
+	return sszeth2.ValidatorContainer{
+		Index:     uint64(index),
+		Balance:   balance,
+		Validator: &validator,
+	}
+}
+
+// The SSZ validators decode to the same response the beacon node gives as JSON
+func TestDecodeSszValidators(t *testing.T) {
+	containers := make([]sszeth2.ValidatorContainer, len(knownValidatorStates))
+	jsonValidators := make([]string, len(knownValidatorStates))
+	for i, test := range knownValidatorStates {
+		containers[i] = newTestValidatorContainer(i, test.validator, test.balance)
+		v := containers[i].Validator
+		jsonValidators[i] = fmt.Sprintf(`{"index":"%d","balance":"%d","status":"%s","validator":{"pubkey":"0x%x","withdrawal_credentials":"0x%x","effective_balance":"%d","slashed":%t,"activation_eligibility_epoch":"%d","activation_epoch":"%d","exit_epoch":"%d","withdrawable_epoch":"%d"}}`,
+			i, test.balance, test.state, v.Pubkey, v.WithdrawalCredentials, v.EffectiveBalance, v.Slashed, v.ActivationEligibilityEpoch, v.ActivationEpoch, v.ExitEpoch, v.WithdrawableEpoch)
+	}
+
+	var fromJson ValidatorsResponse
+	if err := json.Unmarshal([]byte(`{"data":[`+strings.Join(jsonValidators, ",")+`]}`), &fromJson); err != nil {
+		t.Fatal(err)
+	}
+	fromSsz, err := decodeSszValidators(encodeSszValidators(t, containers), testStateEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fromSsz.Data) != len(fromJson.Data) {
+		t.Fatalf("decoded %d validators from SSZ, want %d", len(fromSsz.Data), len(fromJson.Data))
+	}
+	for i := range fromJson.Data {
+		sszJson, _ := json.Marshal(fromSsz.Data[i])
+		jsonJson, _ := json.Marshal(fromJson.Data[i])
+		if !bytes.Equal(sszJson, jsonJson) {
+			t.Errorf("%s: decoded\n%s\nfrom SSZ, want\n%s", knownValidatorStates[i].name, sszJson, jsonJson)
+		}
+	}
+
+	if _, err := decodeSszValidators(make([]byte, containers[0].SizeSSZ()+1), testStateEpoch); err == nil {
+		t.Error("decoded a truncated SSZ validator list")
+	}
+}
+
+// A validator set the size of a large operator's bulk status query
+func newBenchmarkValidators(b *testing.B) ([]byte, []byte) {
+	containers := make([]sszeth2.ValidatorContainer, 10000)
+	for i := range containers {
+		test := knownValidatorStates[i%len(knownValidatorStates)]
+		containers[i] = newTestValidatorContainer(i, test.validator, test.balance)
+	}
+	sszData := encodeSszValidators(b, containers)
+	validators, err := decodeSszValidators(sszData, testStateEpoch)
+	if err != nil {
+		b.Fatal(err)
+	}
+	jsonData, err := json.Marshal(validators)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return sszData, jsonData
+}
+
+func BenchmarkDecodeValidatorsSsz(b *testing.B) {
+	sszData, _ := newBenchmarkValidators(b)
+	b.SetBytes(int64(len(sszData)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeSszValidators(sszData, testStateEpoch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeValidatorsJson(b *testing.B) {
+	_, jsonData := newBenchmarkValidators(b)
+	b.SetBytes(int64(len(jsonData)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var validators ValidatorsResponse
+		if err := json.Unmarshal(jsonData, &validators); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -89,18 +89,31 @@ type ForkResponse struct {
 type AttestationsResponse struct {
 	Data []Attestation `json:"data"`
 }
+type BeaconHeaderResponse struct {
+	Data struct {
+		Header struct {
+			Message struct {
+				Slot uinteger `json:"slot"`
+			} `json:"message"`
+		} `json:"header"`
+	} `json:"data"`
+}
 type BeaconBlockResponse struct {
 	Data struct {
 		Message struct {
-			Slot          uinteger `json:"slot"`
-			ProposerIndex uinteger `json:"proposer_index"`
+			Slot          uinteger  `json:"slot"`
+			ProposerIndex uinteger  `json:"proposer_index"`
+			ParentRoot    byteArray `json:"parent_root"`
 			Body          struct {
 				Eth1Data struct {
 					DepositRoot  byteArray `json:"deposit_root"`
 					DepositCount uinteger  `json:"deposit_count"`
 					BlockHash    byteArray `json:"block_hash"`
 				} `json:"eth1_data"`
-				Attestations     []Attestation `json:"attestations"`
+				Attestations  []Attestation `json:"attestations"`
+				SyncAggregate *struct {
+					SyncCommitteeBits byteArray `json:"sync_committee_bits"`
+				} `json:"sync_aggregate"`
 				ExecutionPayload *struct {
 					FeeRecipient byteArray `json:"fee_recipient"`
 					BlockNumber  uinteger  `json:"block_number"`
@@ -140,6 +153,7 @@ type ProposerDutiesResponse struct {
 }
 type ProposerDuty struct {
 	ValidatorIndex uinteger `json:"validator_index"`
+	Slot           uinteger `json:"slot"`
 }
 
 type CommitteesResponse struct {
@@ -154,17 +168,58 @@ type Committee struct {
 
 type Attestation struct {
 	AggregationBits string `json:"aggregation_bits"`
-	Data            struct {
-		Slot  uinteger `json:"slot"`
-		Index uinteger `json:"index"`
+	// Electra (EIP-7549) attestations cover several committees; data.index is always 0 for them
+	CommitteeBits string `json:"committee_bits,omitempty"`
+	Data          struct {
+		Slot            uinteger  `json:"slot"`
+		Index           uinteger  `json:"index"`
+		BeaconBlockRoot byteArray `json:"beacon_block_root"`
+		Target          struct {
+			Epoch uinteger  `json:"epoch"`
+			Root  byteArray `json:"root"`
+		} `json:"target"`
 	} `json:"data"`
 }
 
+type HeadEventData struct {
+	Slot                uinteger  `json:"slot"`
+	Block               byteArray `json:"block"`
+	State               byteArray `json:"state"`
+	EpochTransition     bool      `json:"epoch_transition"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+type FinalizedCheckpointEventData struct {
+	Block               byteArray `json:"block"`
+	State               byteArray `json:"state"`
+	Epoch               uinteger  `json:"epoch"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+type ChainReorgEventData struct {
+	Slot                uinteger  `json:"slot"`
+	Depth               uinteger  `json:"depth"`
+	OldHeadBlock        byteArray `json:"old_head_block"`
+	NewHeadBlock        byteArray `json:"new_head_block"`
+	OldHeadState        byteArray `json:"old_head_state"`
+	NewHeadState        byteArray `json:"new_head_state"`
+	Epoch               uinteger  `json:"epoch"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+type VoluntaryExitEventData struct {
+	Message struct {
+		Epoch          uinteger `json:"epoch"`
+		ValidatorIndex uinteger `json:"validator_index"`
+	} `json:"message"`
+	Signature byteArray `json:"signature"`
+}
+
 // Unsigned integer type
 type uinteger uint64
 
 func (i uinteger) MarshalJSON() ([]byte, error) {
-	return json.Marshal(strconv.Itoa(int(i)))
+	return json.Marshal(strconv.FormatUint(uint64(i), 10))
 }
 func (i *uinteger) UnmarshalJSON(data []byte) error {
 
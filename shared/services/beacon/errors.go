@@ -0,0 +1,90 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const maxApiErrorBodyLength = 512
+
+// An error response from the Beacon API (https://ethereum.github.io/beacon-APIs/)
+type ApiError struct {
+	StatusCode int          `json:"code"`
+	Message    string       `json:"message"`
+	Failures   []ApiFailure `json:"failures,omitempty"`
+}
+
+// A per-item failure of a batch request, such as one rejected message in a pool submission
+type ApiFailure struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// Decode a Beacon API error body; bodies that aren't in the standard format are kept as the message
+func NewApiError(statusCode int, body []byte) *ApiError {
+	apiErr := &ApiError{}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Message == "" {
+		message := strings.TrimSpace(string(body))
+		if len(message) > maxApiErrorBodyLength {
+			message = message[:maxApiErrorBodyLength]
+		}
+		apiErr = &ApiError{Message: message}
+	}
+	apiErr.StatusCode = statusCode
+	return apiErr
+}
+
+func (e *ApiError) Error() string {
+	message := fmt.Sprintf("HTTP status %d: %s", e.StatusCode, e.Message)
+	for _, failure := range e.Failures {
+		message += fmt.Sprintf("; item %d: %s", failure.Index, failure.Message)
+	}
+	return message
+}
+
+// Check if an error is the beacon node reporting that the requested resource doesn't exist
+func IsNotFound(err error) bool {
+	var apiErr *ApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Check if an error means the beacon node is unreachable or broken, as opposed to it rejecting a request.
+// Validation errors (4xx) and cancellations by the caller aren't client failures.
+func IsClientFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	// Connection refused, resets, DNS failures and timeouts
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,125 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package beacon
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Config
+const (
+	eventBufferSize          = 64
+	eventStreamRetryDelay    = time.Second
+	maxEventStreamRetryDelay = time.Minute
+)
+
+// Topics of the beacon node event stream (/eth/v1/events)
+type EventTopic string
+
+const (
+	EventTopic_Head                EventTopic = "head"
+	EventTopic_FinalizedCheckpoint EventTopic = "finalized_checkpoint"
+	EventTopic_ChainReorg          EventTopic = "chain_reorg"
+	EventTopic_VoluntaryExit       EventTopic = "voluntary_exit"
+)
+
+// A new head block
+type HeadEvent struct {
+	Slot                uint64
+	Block               common.Hash
+	State               common.Hash
+	EpochTransition     bool
+	ExecutionOptimistic bool
+}
+
+// A newly finalized checkpoint
+type FinalizedCheckpointEvent struct {
+	Epoch               uint64
+	Block               common.Hash
+	State               common.Hash
+	ExecutionOptimistic bool
+}
+
+// A head change that dropped blocks from the canonical chain
+type ChainReorgEvent struct {
+	Slot                uint64
+	Epoch               uint64
+	Depth               uint64
+	OldHeadBlock        common.Hash
+	NewHeadBlock        common.Hash
+	OldHeadState        common.Hash
+	NewHeadState        common.Hash
+	ExecutionOptimistic bool
+}
+
+// A voluntary exit that was added to the beacon node's operation pool
+type VoluntaryExitEvent struct {
+	ValidatorIndex uint64
+	Epoch          uint64
+	Signature      types.ValidatorSignature
+}
+
+// An event from the beacon node; only the field matching the topic is set
+type Event struct {
+	Topic               EventTopic
+	Head                *HeadEvent
+	FinalizedCheckpoint *FinalizedCheckpointEvent
+	ChainReorg          *ChainReorgEvent
+	VoluntaryExit       *VoluntaryExitEvent
+}
+
+// Subscribe to the beacon node's event stream, reconnecting with backoff whenever it drops.
+// Stream errors are passed to onError (if set); the channel is closed once the context is done.
+func SubscribeEvents(ctx context.Context, client Client, topics []EventTopic, onError func(error)) <-chan Event {
+	events := make(chan Event, eventBufferSize)
+	go func() {
+		defer close(events)
+		retryDelay := eventStreamRetryDelay
+		for {
+			started := time.Now()
+			err := client.StreamEvents(ctx, topics, events)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil && onError != nil {
+				onError(err)
+			}
+
+			// A stream that stayed up for a while was healthy, so start backing off from scratch
+			if time.Since(started) > maxEventStreamRetryDelay {
+				retryDelay = eventStreamRetryDelay
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+			retryDelay *= 2
+			if retryDelay > maxEventStreamRetryDelay {
+				retryDelay = maxEventStreamRetryDelay
+			}
+		}
+	}()
+	return events
+}
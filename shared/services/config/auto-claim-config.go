@@ -0,0 +1,218 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"github.com/stader-labs/stader-node/shared/types/config"
+)
+
+// Defaults
+const (
+	defaultAutoClaimSpRewardsThreshold       float64 = 0.1
+	defaultAutoClaimSpRewardsMaxCycles       uint64  = 10
+	defaultAutoClaimOperatorRewardsThreshold float64 = 0.1
+	defaultAutoClaimClRewardsThreshold       float64 = 0.05
+	defaultAutoClaimMaxBaseFee               float64 = 20
+)
+
+// Configuration for the node daemon's automatic reward claims
+type AutoClaimConfig struct {
+	Title string `yaml:"-"`
+
+	// Log what would be claimed without sending any transactions
+	DryRun config.Parameter `yaml:"dryRun,omitempty"`
+
+	// Socializing pool rewards
+	EnableSpRewards     config.Parameter `yaml:"enableSpRewards,omitempty"`
+	SpRewardsThreshold  config.Parameter `yaml:"spRewardsThreshold,omitempty"`
+	SpRewardsMaxBaseFee config.Parameter `yaml:"spRewardsMaxBaseFee,omitempty"`
+	SpRewardsMaxCycles  config.Parameter `yaml:"spRewardsMaxCycles,omitempty"`
+
+	// Operator rewards held by the operator rewards collector
+	EnableOperatorRewards     config.Parameter `yaml:"enableOperatorRewards,omitempty"`
+	OperatorRewardsThreshold  config.Parameter `yaml:"operatorRewardsThreshold,omitempty"`
+	OperatorRewardsMaxBaseFee config.Parameter `yaml:"operatorRewardsMaxBaseFee,omitempty"`
+
+	// CL rewards held by the validators' withdraw vaults
+	EnableClRewards     config.Parameter `yaml:"enableClRewards,omitempty"`
+	ClRewardsThreshold  config.Parameter `yaml:"clRewardsThreshold,omitempty"`
+	ClRewardsMaxBaseFee config.Parameter `yaml:"clRewardsMaxBaseFee,omitempty"`
+}
+
+// Generates a new auto-claim config
+func NewAutoClaimConfig(cfg *StaderConfig) *AutoClaimConfig {
+	return &AutoClaimConfig{
+		Title: "Auto-Claim Settings",
+
+		DryRun: config.Parameter{
+			ID:                   "autoClaimDryRun",
+			Name:                 "Dry Run",
+			Description:          "Enable this to have the node log and record the claims it would make under the policies below without sending any transactions. Use it to check your thresholds before letting the node claim on its own.",
+			Type:                 config.ParameterType_Bool,
+			Default:              map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		EnableSpRewards: config.Parameter{
+			ID:                   "autoClaimEnableSpRewards",
+			Name:                 "Auto-Claim Socializing Pool Rewards",
+			Description:          "Enable this to have the node claim its socializing pool rewards on its own once the unclaimed cycles it has downloaded merkle proofs for add up to the threshold below.",
+			Type:                 config.ParameterType_Bool,
+			Default:              map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		SpRewardsThreshold: config.Parameter{
+			ID:                   "autoClaimSpRewardsThreshold",
+			Name:                 "Socializing Pool Threshold",
+			Description:          "The unclaimed socializing pool rewards (in ETH) the node waits for before claiming them.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultAutoClaimSpRewardsThreshold},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		SpRewardsMaxBaseFee: config.Parameter{
+			ID:                   "autoClaimSpRewardsMaxBaseFee",
+			Name:                 "Socializing Pool Max Base Fee",
+			Description:          "Socializing pool rewards are only claimed while the network's base fee (in gwei) is below this.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultAutoClaimMaxBaseFee},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		SpRewardsMaxCycles: config.Parameter{
+			ID:                   "autoClaimSpRewardsMaxCycles",
+			Name:                 "Socializing Pool Cycles Per Claim",
+			Description:          "The most reward cycles the node claims in a single transaction. More cycles are claimed in further transactions.",
+			Type:                 config.ParameterType_Uint,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultAutoClaimSpRewardsMaxCycles},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		EnableOperatorRewards: config.Parameter{
+			ID:                   "autoClaimEnableOperatorRewards",
+			Name:                 "Auto-Claim Operator Rewards",
+			Description:          "Enable this to have the node claim the rewards held for it by the operator rewards collector on its own once they reach the threshold below. They are sent to your operator reward address.",
+			Type:                 config.ParameterType_Bool,
+			Default:              map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		OperatorRewardsThreshold: config.Parameter{
+			ID:                   "autoClaimOperatorRewardsThreshold",
+			Name:                 "Operator Rewards Threshold",
+			Description:          "The operator rewards (in ETH) the node waits for before claiming them.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultAutoClaimOperatorRewardsThreshold},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		OperatorRewardsMaxBaseFee: config.Parameter{
+			ID:                   "autoClaimOperatorRewardsMaxBaseFee",
+			Name:                 "Operator Rewards Max Base Fee",
+			Description:          "Operator rewards are only claimed while the network's base fee (in gwei) is below this.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultAutoClaimMaxBaseFee},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		EnableClRewards: config.Parameter{
+			ID:                   "autoClaimEnableClRewards",
+			Name:                 "Auto-Send CL Rewards",
+			Description:          "Enable this to have the node send the CL rewards that build up in your validators' withdraw vaults on its own, once a vault's operator share reaches the threshold below. Vaults of exited validators are left for settlement.",
+			Type:                 config.ParameterType_Bool,
+			Default:              map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		ClRewardsThreshold: config.Parameter{
+			ID:                   "autoClaimClRewardsThreshold",
+			Name:                 "CL Rewards Threshold",
+			Description:          "The operator share (in ETH) a withdraw vault has to hold before the node sends its rewards. Each vault is sent in its own transaction.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultAutoClaimClRewardsThreshold},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		ClRewardsMaxBaseFee: config.Parameter{
+			ID:                   "autoClaimClRewardsMaxBaseFee",
+			Name:                 "CL Rewards Max Base Fee",
+			Description:          "CL rewards are only sent while the network's base fee (in gwei) is below this.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultAutoClaimMaxBaseFee},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+	}
+}
+
+// Get the parameters for this config
+func (cfg *AutoClaimConfig) GetParameters() []*config.Parameter {
+	return []*config.Parameter{
+		&cfg.DryRun,
+		&cfg.EnableSpRewards,
+		&cfg.SpRewardsThreshold,
+		&cfg.SpRewardsMaxBaseFee,
+		&cfg.SpRewardsMaxCycles,
+		&cfg.EnableOperatorRewards,
+		&cfg.OperatorRewardsThreshold,
+		&cfg.OperatorRewardsMaxBaseFee,
+		&cfg.EnableClRewards,
+		&cfg.ClRewardsThreshold,
+		&cfg.ClRewardsMaxBaseFee,
+	}
+}
+
+// The the title for the config
+func (cfg *AutoClaimConfig) GetConfigTitle() string {
+	return cfg.Title
+}
@@ -107,6 +107,9 @@ type ExternalTekuConfig struct {
 	// Custom proposal graffiti
 	Graffiti config.Parameter `yaml:"graffiti,omitempty"`
 
+	// Toggle for enabling doppelganger detection
+	DoppelgangerDetection config.Parameter `yaml:"doppelgangerDetection,omitempty"`
+
 	// The Docker Hub tag for Teku
 	ContainerTag config.Parameter `yaml:"containerTag,omitempty"`
 
@@ -426,6 +429,18 @@ func NewExternalTekuConfig(cfg *StaderConfig) *ExternalTekuConfig {
 			OverwriteOnUpgrade:   false,
 		},
 
+		DoppelgangerDetection: config.Parameter{
+			ID:                   DoppelgangerDetectionID,
+			Name:                 "Enable Doppelgänger Detection",
+			Description:          "If enabled, your client will *intentionally* miss 1 or 2 attestations on startup to check if validator keys are already running elsewhere. If they are, it will disable validation duties for them to prevent you from being slashed.",
+			Type:                 config.ParameterType_Bool,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultDoppelgangerDetection},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Validator},
+			EnvironmentVariables: []string{"DOPPELGANGER_DETECTION"},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
 		ContainerTag: config.Parameter{
 			ID:          "containerTag",
 			Name:        "Container Tag",
@@ -576,6 +591,7 @@ func (cfg *ExternalTekuConfig) GetParameters() []*config.Parameter {
 	return []*config.Parameter{
 		&cfg.HttpUrl,
 		&cfg.Graffiti,
+		&cfg.DoppelgangerDetection,
 		&cfg.ContainerTag,
 		&cfg.AdditionalVcFlags,
 	}
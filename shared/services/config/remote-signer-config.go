@@ -0,0 +1,68 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"github.com/stader-labs/stader-node/shared/types/config"
+)
+
+// Defaults
+const (
+	defaultRemoteSignerUrl string = ""
+)
+
+// Configuration for a remote signer (such as Web3Signer) holding the validator keys
+type RemoteSignerConfig struct {
+	Title string `yaml:"-"`
+
+	// The URL of the signer's EIP-3030 API
+	Url config.Parameter `yaml:"url,omitempty"`
+}
+
+// Generates a new remote signer config
+func NewRemoteSignerConfig(cfg *StaderConfig) *RemoteSignerConfig {
+	return &RemoteSignerConfig{
+		Title: "Remote Signer Settings",
+
+		Url: config.Parameter{
+			ID:                   "remoteSignerUrl",
+			Name:                 "Remote Signer URL",
+			Description:          "The URL of your remote signer's API, for example http://web3signer:9000.\n\nThe signer must hold the keys for all of your validators. Your validator client and the Stader node will use it for every signature instead of local keystores.",
+			Type:                 config.ParameterType_String,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultRemoteSignerUrl},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node, config.ContainerID_Validator},
+			EnvironmentVariables: []string{"REMOTE_SIGNER_URL"},
+			CanBeBlank:           true,
+			OverwriteOnUpgrade:   false,
+		},
+	}
+}
+
+// Get the parameters for this config
+func (cfg *RemoteSignerConfig) GetParameters() []*config.Parameter {
+	return []*config.Parameter{
+		&cfg.Url,
+	}
+}
+
+// The the title for the config
+func (cfg *RemoteSignerConfig) GetConfigTitle() string {
+	return cfg.Title
+}
@@ -0,0 +1,135 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"github.com/stader-labs/stader-node/shared/types/config"
+)
+
+// Defaults
+const (
+	defaultSdCollateralWarningRatio float64 = 1.2
+	defaultSdCollateralTargetRatio  float64 = 1.5
+	defaultSdCollateralDailyBudget  float64 = 0
+	defaultSdCollateralMaxFee       float64 = 30
+)
+
+// Configuration for the node daemon's SD collateral health guard
+type SdCollateralConfig struct {
+	Title string `yaml:"-"`
+
+	// Warn when the collateral drops below this multiple of the pool's minimum threshold
+	WarningRatio config.Parameter `yaml:"warningRatio,omitempty"`
+
+	// Top up the collateral automatically from the node wallet
+	EnableAutoTopUp config.Parameter `yaml:"enableAutoTopUp,omitempty"`
+
+	// The multiple of the minimum threshold a top-up brings the collateral back to
+	TargetRatio config.Parameter `yaml:"targetRatio,omitempty"`
+
+	// The most SD that can be deposited automatically in any 24 hours
+	DailyBudget config.Parameter `yaml:"dailyBudget,omitempty"`
+
+	// The highest max fee (in gwei) an automatic top-up transaction may pay
+	MaxFee config.Parameter `yaml:"maxFee,omitempty"`
+}
+
+// Generates a new SD collateral config
+func NewSdCollateralConfig(cfg *StaderConfig) *SdCollateralConfig {
+	return &SdCollateralConfig{
+		Title: "SD Collateral Settings",
+
+		WarningRatio: config.Parameter{
+			ID:                   "sdCollateralWarningRatio",
+			Name:                 "Warning Ratio",
+			Description:          "The node will warn you when your SD collateral is worth less than this multiple of the permissionless pool's minimum collateral per validator.\n\nFor example, 1.2 warns once your collateral is within 20% of the minimum. Below the minimum you can't add new validators.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultSdCollateralWarningRatio},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		EnableAutoTopUp: config.Parameter{
+			ID:                   "sdCollateralEnableAutoTopUp",
+			Name:                 "Enable Automatic Top-Up",
+			Description:          "Enable this to let the node deposit SD from your node wallet as collateral whenever it falls below the warning ratio.\n\nThe node will approve the SD collateral contract and send the deposit transactions itself, staying within the daily budget and max fee below. The approval and the deposit together also stay within the transaction fee cap.",
+			Type:                 config.ParameterType_Bool,
+			Default:              map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		TargetRatio: config.Parameter{
+			ID:                   "sdCollateralTargetRatio",
+			Name:                 "Top-Up Target Ratio",
+			Description:          "An automatic top-up deposits enough SD to bring your collateral back to this multiple of the minimum collateral per validator. It is never topped up past the pool's maximum threshold.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultSdCollateralTargetRatio},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		DailyBudget: config.Parameter{
+			ID:                   "sdCollateralDailyBudget",
+			Name:                 "Daily Top-Up Budget",
+			Description:          "The most SD the node may deposit automatically in any 24 hour window. Top-ups that would go over it are reduced to what's left of the budget.\n\nA value of 0 only warns and never deposits.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultSdCollateralDailyBudget},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
+		MaxFee: config.Parameter{
+			ID:                   "sdCollateralMaxFee",
+			Name:                 "Top-Up Max Fee",
+			Description:          "The highest max fee (in gwei, including the priority fee) an automatic top-up transaction may pay. While the network's fees are above it, the top-up is postponed.",
+			Type:                 config.ParameterType_Float,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultSdCollateralMaxFee},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+	}
+}
+
+// Get the parameters for this config
+func (cfg *SdCollateralConfig) GetParameters() []*config.Parameter {
+	return []*config.Parameter{
+		&cfg.WarningRatio,
+		&cfg.EnableAutoTopUp,
+		&cfg.TargetRatio,
+		&cfg.DailyBudget,
+		&cfg.MaxFee,
+	}
+}
+
+// The the title for the config
+func (cfg *SdCollateralConfig) GetConfigTitle() string {
+	return cfg.Title
+}
@@ -136,6 +136,16 @@ type StaderConfig struct {
 	// MEV-Boost
 	EnableMevBoost config.Parameter `yaml:"enableMevBoost,omitempty"`
 	MevBoost       *MevBoostConfig  `yaml:"mevBoost,omitempty"`
+
+	// Remote signer
+	EnableRemoteSigner config.Parameter    `yaml:"enableRemoteSigner,omitempty"`
+	RemoteSigner       *RemoteSignerConfig `yaml:"remoteSigner,omitempty"`
+
+	// SD collateral health guard
+	SdCollateral *SdCollateralConfig `yaml:"sdCollateral,omitempty"`
+
+	// Automatic reward claims
+	AutoClaim *AutoClaimConfig `yaml:"autoClaim,omitempty"`
 }
 
 // Load configuration settings from a file
@@ -436,6 +446,18 @@ func NewStaderConfig(staderDir string, isNativeMode bool) *StaderConfig {
 			CanBeBlank:           false,
 			OverwriteOnUpgrade:   true,
 		},
+
+		EnableRemoteSigner: config.Parameter{
+			ID:                   "enableRemoteSigner",
+			Name:                 "Enable Remote Signer",
+			Description:          "Enable this if your validator keys are held by a remote signer such as Web3Signer instead of being derived from your node wallet.\n\nDeposits, pre-signed exits and validator exits will be signed by the remote signer, and your validator client will be configured to use it.",
+			Type:                 config.ParameterType_Bool,
+			Default:              map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node, config.ContainerID_Validator},
+			EnvironmentVariables: []string{"ENABLE_REMOTE_SIGNER"},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
 	}
 
 	// Set the defaults for choices
@@ -467,6 +489,9 @@ func NewStaderConfig(staderDir string, isNativeMode bool) *StaderConfig {
 	cfg.BitflyNodeMetrics = NewBitflyNodeMetricsConfig(cfg)
 	cfg.Native = NewNativeConfig(cfg)
 	cfg.MevBoost = NewMevBoostConfig(cfg)
+	cfg.RemoteSigner = NewRemoteSignerConfig(cfg)
+	cfg.SdCollateral = NewSdCollateralConfig(cfg)
+	cfg.AutoClaim = NewAutoClaimConfig(cfg)
 
 	// Apply the default values for mainnet
 	cfg.StaderNode.Network.Value = cfg.StaderNode.Network.Options[0].Value
@@ -535,6 +560,7 @@ func (cfg *StaderConfig) GetParameters() []*config.Parameter {
 		&cfg.NodeMetricsPort,
 		&cfg.ExporterMetricsPort,
 		&cfg.EnableMevBoost,
+		&cfg.EnableRemoteSigner,
 	}
 }
 
@@ -566,6 +592,9 @@ func (cfg *StaderConfig) GetSubconfigs() map[string]config.Config {
 		"bitflyNodeMetrics":  cfg.BitflyNodeMetrics,
 		"native":             cfg.Native,
 		"mevBoost":           cfg.MevBoost,
+		"remoteSigner":       cfg.RemoteSigner,
+		"sdCollateral":       cfg.SdCollateral,
+		"autoClaim":          cfg.AutoClaim,
 	}
 }
 
@@ -697,10 +726,8 @@ func (cfg *StaderConfig) IsDoppelgangerEnabled() (bool, error) {
 	case config.Mode_Local:
 		client := cfg.ConsensusClient.Value.(config.ConsensusClient)
 		switch client {
-		case config.ConsensusClient_Lighthouse, config.ConsensusClient_Lodestar, config.ConsensusClient_Nimbus, config.ConsensusClient_Prysm:
+		case config.ConsensusClient_Lighthouse, config.ConsensusClient_Lodestar, config.ConsensusClient_Nimbus, config.ConsensusClient_Prysm, config.ConsensusClient_Teku:
 			return cfg.ConsensusCommon.DoppelgangerDetection.Value.(bool), nil
-		case config.ConsensusClient_Teku:
-			return false, nil
 		default:
 			return false, fmt.Errorf("unknown consensus client [%v] selected", client)
 		}
@@ -715,7 +742,7 @@ func (cfg *StaderConfig) IsDoppelgangerEnabled() (bool, error) {
 		case config.ConsensusClient_Prysm:
 			return cfg.ExternalPrysm.DoppelgangerDetection.Value.(bool), nil
 		case config.ConsensusClient_Teku:
-			return false, nil
+			return cfg.ExternalTeku.DoppelgangerDetection.Value.(bool), nil
 		case config.ConsensusClient_Lodestar:
 			return cfg.ExternalLodestar.DoppelgangerDetection.Value.(bool), nil
 		default:
@@ -1000,6 +1027,11 @@ func (cfg *StaderConfig) GenerateEnvironmentVariables() map[string]string {
 		}
 	}
 
+	// Remote signer
+	if cfg.EnableRemoteSigner.Value == true {
+		config.AddParametersToEnvVars(cfg.RemoteSigner.GetParameters(), envVars)
+	}
+
 	return envVars
 
 }
@@ -1110,6 +1142,26 @@ func (cfg *StaderConfig) Validate() []string {
 		}
 	}
 
+	// Ensure there's a remote signer URL
+	if cfg.EnableRemoteSigner.Value == true && cfg.RemoteSigner.Url.Value.(string) == "" {
+		errors = append(errors, "You have the remote signer enabled but don't have a URL set. Please enter the URL of your remote signer to use it.")
+	}
+
+	// Ensure the SD collateral top-up can actually restore the collateral
+	if cfg.SdCollateral.EnableAutoTopUp.Value == true {
+		if cfg.SdCollateral.TargetRatio.Value.(float64) < cfg.SdCollateral.WarningRatio.Value.(float64) {
+			errors = append(errors, "The SD collateral top-up target ratio must be at least the warning ratio, otherwise a top-up would leave the collateral below the warning level.")
+		}
+		if cfg.SdCollateral.MaxFee.Value.(float64) <= 0 {
+			errors = append(errors, "You have automatic SD collateral top-ups enabled but the top-up max fee is 0. Please set the highest fee the node may pay for them.")
+		}
+	}
+
+	// Ensure the socializing pool auto-claim can claim at least one cycle at a time
+	if cfg.AutoClaim.EnableSpRewards.Value == true && cfg.AutoClaim.SpRewardsMaxCycles.Value.(uint64) == 0 {
+		errors = append(errors, "You have socializing pool auto-claims enabled but the cycles per claim is 0. Please set it to at least 1.")
+	}
+
 	return errors
 }
 
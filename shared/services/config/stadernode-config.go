@@ -21,12 +21,14 @@ package config
 
 import (
 	_ "embed"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mitchellh/go-homedir"
@@ -45,6 +47,7 @@ const (
 	DaemonDataPath              string = "/.stader/data"
 	GuardianFolder              string = "guardian"
 	SpRewardsMerkleProofsFolder string = "sp-rewards-merkle-proofs"
+	SpRewardsQuarantineFolder   string = "quarantine"
 	MerkleProofsFormat          string = "cycle-%s-%d.json"
 	FeeRecipientFilename        string = "stader-fee-recipient.txt"
 	NativeFeeRecipientFilename  string = "stader-fee-recipient-env.txt"
@@ -61,7 +64,10 @@ var devEncryptionKey string
 
 // --ignore-sync-check
 // Defaults
-const defaultProjectName string = "stader"
+const (
+	defaultProjectName             string = "stader"
+	defaultDoppelgangerWatchEpochs uint64 = 3
+)
 
 // Configuration for the Stader node
 type StaderNodeConfig struct {
@@ -95,9 +101,15 @@ type StaderNodeConfig struct {
 	// Max tx fee for a single tx override
 	TxFeeCap config.Parameter `yaml:"txFeeCap,omitempty"`
 
+	// Where max fee suggestions come from
+	FeeSource config.Parameter `yaml:"feeSource,omitempty"`
+
 	// URL for an EC with archive mode, for manual rewards tree generation
 	ArchiveECUrl config.Parameter `yaml:"archiveEcUrl,omitempty"`
 
+	// How many epochs newly loaded validator keys are watched for activity before the validator client can use them
+	DoppelgangerWatchEpochs config.Parameter `yaml:"doppelgangerWatchEpochs,omitempty"`
+
 	///////////////////////////
 	// Non-editable settings //
 	///////////////////////////
@@ -125,6 +137,9 @@ type StaderNodeConfig struct {
 
 	// the encryption keys to use for pre-sign
 	preSignEncryptionKey map[config.Network]string `yaml:"-"`
+
+	// A block before the Stader contracts were deployed, where the operator ledger starts scanning
+	ledgerStartBlock map[config.Network]uint64 `yaml:"-"`
 }
 
 // Generates a new Stadernode configuration
@@ -219,6 +234,31 @@ func NewStadernodeConfig(cfg *StaderConfig) *StaderNodeConfig {
 			OverwriteOnUpgrade:   false,
 		},
 
+		FeeSource: config.Parameter{
+			ID:                   "feeSource",
+			Name:                 "Fee Suggestion Source",
+			Description:          "Where the suggested max fees come from when you haven't set a manual max fee.\n\nThe Execution client source works on every network and doesn't contact any third party. If a third-party source is selected but can't be reached, your Execution client is used instead.",
+			Type:                 config.ParameterType_Choice,
+			Default:              map[config.Network]interface{}{config.Network_All: config.FeeSource_ExecutionClient},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+			Options: []config.ParameterOption{{
+				Name:        "Execution Client",
+				Description: "Estimate the fees from the recent blocks' base fees and priority fees, using your own Execution client.",
+				Value:       config.FeeSource_ExecutionClient,
+			}, {
+				Name:        "Etherchain",
+				Description: "Use the gas price oracle from beaconcha.in (formerly Etherchain). Only available on Mainnet.",
+				Value:       config.FeeSource_Etherchain,
+			}, {
+				Name:        "Etherscan",
+				Description: "Use the gas tracker from Etherscan. Only available on Mainnet.",
+				Value:       config.FeeSource_Etherscan,
+			}},
+		},
+
 		ArchiveECUrl: config.Parameter{
 			ID:                   "archiveECUrl",
 			Name:                 "Archive-Mode EC URL",
@@ -231,6 +271,18 @@ func NewStadernodeConfig(cfg *StaderConfig) *StaderNodeConfig {
 			OverwriteOnUpgrade:   false,
 		},
 
+		DoppelgangerWatchEpochs: config.Parameter{
+			ID:                   "doppelgangerWatchEpochs",
+			Name:                 "Doppelganger Watch Epochs",
+			Description:          "When validator keys that are already active on the Beacon Chain are added to this node (for example by recovering your wallet), the Stadernode keeps those keys out of your Validator Client and watches the chain for this many epochs first. If any of the keys attests, proposes or signs a sync committee message in that time, it is still running somewhere else and is kept out of the Validator Client so you don't get slashed. Your other validators keep running in the meantime.\n\nSet this to 0 to load the keys right away.",
+			Type:                 config.ParameterType_Uint,
+			Default:              map[config.Network]interface{}{config.Network_All: defaultDoppelgangerWatchEpochs},
+			AffectsContainers:    []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node},
+			EnvironmentVariables: []string{},
+			CanBeBlank:           false,
+			OverwriteOnUpgrade:   false,
+		},
+
 		beaconChainUrl: map[config.Network]string{
 			config.Network_Mainnet: "https://beaconcha.in",
 			config.Network_Prater:  "https://prater.beaconcha.in",
@@ -277,6 +329,13 @@ func NewStadernodeConfig(cfg *StaderConfig) *StaderNodeConfig {
 			config.Network_Mainnet:  prodEncryptionKey,
 			config.Network_Zhejiang: stageEncryptionKey,
 		},
+
+		ledgerStartBlock: map[config.Network]uint64{
+			config.Network_Prater:   8000000,
+			config.Network_Devnet:   0,
+			config.Network_Mainnet:  17000000,
+			config.Network_Zhejiang: 0,
+		},
 	}
 }
 
@@ -289,7 +348,9 @@ func (cfg *StaderNodeConfig) GetParameters() []*config.Parameter {
 		&cfg.ManualMaxFee,
 		&cfg.PriorityFee,
 		&cfg.TxFeeCap,
+		&cfg.FeeSource,
 		&cfg.ArchiveECUrl,
+		&cfg.DoppelgangerWatchEpochs,
 	}
 }
 
@@ -300,24 +361,8 @@ func (cfg *StaderNodeConfig) GetBeaconChainUrl() string {
 }
 
 // Getters for the non-editable parameters
-func (cfg *StaderNodeConfig) GetPresignSendApi() string {
-	return cfg.baseStaderBackendUrl[cfg.Network.Value.(config.Network)] + "/presign"
-}
-
-func (cfg *StaderNodeConfig) GetBulkPresignSendApi() string {
-	return cfg.baseStaderBackendUrl[cfg.Network.Value.(config.Network)] + "/presigns"
-}
-
-func (cfg *StaderNodeConfig) GetPresignCheckApi() string {
-	return cfg.baseStaderBackendUrl[cfg.Network.Value.(config.Network)] + "/msgSubmitted"
-}
-
-func (cfg *StaderNodeConfig) GetBulkPresignCheckApi() string {
-	return cfg.baseStaderBackendUrl[cfg.Network.Value.(config.Network)] + "/presignsSubmitted"
-}
-
-func (cfg *StaderNodeConfig) GetPresignPublicKeyApi() string {
-	return cfg.baseStaderBackendUrl[cfg.Network.Value.(config.Network)] + "/publicKey"
+func (cfg *StaderNodeConfig) GetStaderBackendUrl() string {
+	return cfg.baseStaderBackendUrl[cfg.Network.Value.(config.Network)]
 }
 
 func (cfg *StaderNodeConfig) GetMerkleProofApi() string {
@@ -340,6 +385,10 @@ func (cfg *StaderNodeConfig) GetPresignEncryptionKey() string {
 	return cfg.preSignEncryptionKey[cfg.Network.Value.(config.Network)]
 }
 
+func (cfg *StaderNodeConfig) GetLedgerStartBlock() uint64 {
+	return cfg.ledgerStartBlock[cfg.Network.Value.(config.Network)]
+}
+
 func (cfg *StaderNodeConfig) GetWalletPath() string {
 	if cfg.parent.IsNativeMode {
 		return filepath.Join(cfg.DataPath.Value.(string), "wallet")
@@ -364,6 +413,85 @@ func (cfg *StaderNodeConfig) GetValidatorKeychainPath() string {
 	return filepath.Join(DaemonDataPath, "validators")
 }
 
+func (cfg *StaderNodeConfig) GetPresignDatabasePath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), "presign.db")
+	}
+
+	return filepath.Join(DaemonDataPath, "presign.db")
+}
+
+// The record of the SD the node daemon has deposited as collateral on its own, used to enforce the daily budget
+func (cfg *StaderNodeConfig) GetSdTopUpHistoryPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), "sd-topups.json")
+	}
+
+	return filepath.Join(DaemonDataPath, "sd-topups.json")
+}
+
+// The record of the rewards the node daemon has claimed on its own
+func (cfg *StaderNodeConfig) GetAutoClaimLogPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), "auto-claims.jsonl")
+	}
+
+	return filepath.Join(DaemonDataPath, "auto-claims.jsonl")
+}
+
+// The journal of every transaction the node has submitted
+func (cfg *StaderNodeConfig) GetTxJournalPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), "transactions.json")
+	}
+
+	return filepath.Join(DaemonDataPath, "transactions.json")
+}
+
+// The validator keys being held back from the validator client until they've shown no activity
+func (cfg *StaderNodeConfig) GetDoppelgangerWatchPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), "doppelganger-watch.json")
+	}
+
+	return filepath.Join(DaemonDataPath, "doppelganger-watch.json")
+}
+
+// The keys held back by the doppelganger watch are stored outside of the folder the validator clients load keys from
+func (cfg *StaderNodeConfig) GetDoppelgangerHeldKeysPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), "doppelganger-held")
+	}
+
+	return filepath.Join(DaemonDataPath, "doppelganger-held")
+}
+
+// The operator's accounting ledger, built from the Stader contracts' event logs
+func (cfg *StaderNodeConfig) GetLedgerPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), "ledger.json")
+	}
+
+	return filepath.Join(DaemonDataPath, "ledger.json")
+}
+
+// The index of the operator's contract events the ledger and penalty history are read from
+func (cfg *StaderNodeConfig) GetOperatorIndexPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), "operator-index.db")
+	}
+
+	return filepath.Join(DaemonDataPath, "operator-index.db")
+}
+
+func (cfg *StaderNodeConfig) GetPerformanceDatabasePath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), GuardianFolder, "performance.db")
+	}
+
+	return filepath.Join(DaemonDataPath, GuardianFolder, "performance.db")
+}
+
 func (cfg *StaderNodeConfig) GetWalletPathInCLI() string {
 	return filepath.Join(cfg.DataPath.Value.(string), "wallet")
 }
@@ -453,6 +581,51 @@ func (cfg *StaderNodeConfig) GetSpRewardCyclePath(cycle int64, daemon bool) stri
 	return filepath.Join(cfg.DataPath.Value.(string), SpRewardsMerkleProofsFolder, fmt.Sprintf(MerkleProofsFormat, string(cfg.Network.Value.(config.Network)), cycle))
 }
 
+// Proofs that don't match the on-chain merkle root are kept here instead, so they are never claimed with
+func (cfg *StaderNodeConfig) GetSpRewardCycleQuarantinePath(cycle int64, daemon bool) string {
+	return filepath.Join(cfg.getSpRewardsQuarantineFolder(daemon), fmt.Sprintf(MerkleProofsFormat, string(cfg.Network.Value.(config.Network)), cycle))
+}
+
+// Get the cycles whose downloaded proofs are in quarantine
+func (cfg *StaderNodeConfig) GetQuarantinedCycles(daemon bool) ([]int64, error) {
+	quarantineFolder, err := homedir.Expand(cfg.getSpRewardsQuarantineFolder(daemon))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(quarantineFolder)
+	if os.IsNotExist(err) {
+		return []int64{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read merkle proof quarantine folder: %w", err)
+	}
+
+	// Names follow MerkleProofsFormat, e.g. cycle-mainnet-12.json
+	prefix := fmt.Sprintf("cycle-%s-", string(cfg.Network.Value.(config.Network)))
+	cycles := []int64{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		cycle, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".json"), 10, 64)
+		if err != nil {
+			continue
+		}
+		cycles = append(cycles, cycle)
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i] < cycles[j] })
+	return cycles, nil
+}
+
+func (cfg *StaderNodeConfig) getSpRewardsQuarantineFolder(daemon bool) string {
+	if daemon && !cfg.parent.IsNativeMode {
+		return filepath.Join(DaemonDataPath, SpRewardsMerkleProofsFolder, SpRewardsQuarantineFolder)
+	}
+
+	return filepath.Join(cfg.DataPath.Value.(string), SpRewardsMerkleProofsFolder, SpRewardsQuarantineFolder)
+}
+
 func (cfg *StaderNodeConfig) GetFeeRecipientFilePath() string {
 	if !cfg.parent.IsNativeMode {
 		return filepath.Join(DaemonDataPath, "validators", FeeRecipientFilename)
@@ -488,34 +661,13 @@ func (cfg *StaderNodeConfig) GetClaimData(cycles []*big.Int) ([]*big.Int, []*big
 			return nil, nil, nil, err
 		}
 
-		amountSdBigInt := big.NewInt(0)
-		amountSdBigInt, ok := amountSdBigInt.SetString(merkleData.Sd, 10)
-		if !ok {
-			return nil, nil, nil, fmt.Errorf("could not parse sd amount %s", merkleData.Sd)
-		}
-
-		// same thing above for eth
-		amountEthBigInt := big.NewInt(0)
-		amountEthBigInt, ok = amountEthBigInt.SetString(merkleData.Eth, 10)
-		if !ok {
-			return nil, nil, nil, fmt.Errorf("could not parse eth amount %s", merkleData.Eth)
+		amountSdBigInt, amountEthBigInt, cycleMerkleProofs, err := merkleData.Decode()
+		if err != nil {
+			return nil, nil, nil, err
 		}
 
 		amountSd = append(amountSd, amountSdBigInt)
 		amountEth = append(amountEth, amountEthBigInt)
-
-		// convert merkle proofs to [32]byte
-		cycleMerkleProofs := [][32]byte{}
-		for _, proof := range merkleData.Proof {
-			merkleProofBytes, err := hex.DecodeString(proof[2:])
-			if err != nil {
-				return nil, nil, nil, err
-			}
-			var proofBytes [32]byte
-			copy(proofBytes[:], merkleProofBytes[:32])
-			cycleMerkleProofs = append(cycleMerkleProofs, proofBytes)
-		}
-
 		merkleProofs = append(merkleProofs, cycleMerkleProofs)
 	}
 
@@ -0,0 +1,60 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package doppelganger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stader-labs/stader-node/shared/services/performance"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Check the given validators for anything they signed that made it on chain in a complete epoch.
+// Returns a description of the activity of each validator that was seen doing something.
+func CheckEpoch(ctx context.Context, tracker *performance.Tracker, epoch uint64, validators map[uint64]types.ValidatorPubkey) (map[uint64]string, error) {
+	record, err := tracker.ProcessEpoch(ctx, epoch, validators)
+	if err != nil {
+		return nil, fmt.Errorf("error checking epoch %d for validator activity: %w", epoch, err)
+	}
+	if record.UnplacedAttestations > 0 {
+		// One of them could be a held key's, so the epoch can't be cleared
+		return nil, fmt.Errorf("error checking epoch %d for validator activity: %d attestation(s) covered committees the beacon node didn't report", epoch, record.UnplacedAttestations)
+	}
+
+	activity := map[uint64]string{}
+	for index, duties := range record.Validators {
+		seen := []string{}
+		if duties.AttestationIncluded {
+			seen = append(seen, "attestation included")
+		}
+		if proposed := duties.ProposalsAssigned - duties.ProposalsMissed; proposed > 0 {
+			seen = append(seen, fmt.Sprintf("%d block(s) proposed", proposed))
+		}
+		if signed := duties.SyncAssigned - duties.SyncMissed; signed > 0 {
+			seen = append(seen, fmt.Sprintf("%d sync committee signature(s)", signed))
+		}
+		if len(seen) > 0 {
+			activity[index] = strings.Join(seen, ", ")
+		}
+	}
+	return activity, nil
+}
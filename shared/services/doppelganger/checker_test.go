@@ -0,0 +1,99 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package doppelganger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/go-bitfield"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/beacon/beacontest"
+	"github.com/stader-labs/stader-node/shared/services/performance"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+const (
+	testSlotsPerEpoch = 4
+	testEpoch         = 2
+	testSlot          = testEpoch * testSlotsPerEpoch
+	heldIndex         = 100
+	idleIndex         = 200
+)
+
+// A chain where the held key sits at position 1 of committee 2 and the idle key at position 0 of committee 0,
+// and the next block includes an Electra attestation for committees 0 to 2 with only the held key's bit set
+func newCheckerTest() *beacontest.MockClient {
+	bc := beacontest.NewMockClient()
+	bc.Committees[testEpoch] = []beacon.Committee{
+		{Slot: testSlot, Index: 0, Validators: []uint64{idleIndex, 1, 2}},
+		{Slot: testSlot, Index: 1, Validators: []uint64{3, 4}},
+		{Slot: testSlot, Index: 2, Validators: []uint64{5, heldIndex, 6}},
+	}
+
+	committeeBits := bitfield.NewBitvector64()
+	for _, committee := range []uint64{0, 1, 2} {
+		committeeBits.SetBitAt(committee, true)
+	}
+	aggregationBits := bitfield.NewBitlist(8)
+	aggregationBits.SetBitAt(3+2+1, true)
+
+	bc.Blocks[testSlot] = beacon.BeaconBlock{Slot: testSlot}
+	bc.Blocks[testSlot+1] = beacon.BeaconBlock{
+		Slot: testSlot + 1,
+		Attestations: []beacon.AttestationInfo{{
+			SlotIndex:       testSlot,
+			TargetEpoch:     testEpoch,
+			CommitteeBits:   committeeBits,
+			AggregationBits: aggregationBits,
+		}},
+	}
+	return bc
+}
+
+func TestCheckEpochAttestationOutsideCommitteeZero(t *testing.T) {
+	tracker := performance.NewTracker(newCheckerTest(), testSlotsPerEpoch)
+	activity, err := CheckEpoch(context.Background(), tracker, testEpoch, map[uint64]types.ValidatorPubkey{
+		heldIndex: {0x01},
+		idleIndex: {0x02},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seen, exists := activity[heldIndex]; !exists || seen != "attestation included" {
+		t.Errorf("held key activity is %q, want the included attestation", seen)
+	}
+	if seen, exists := activity[idleIndex]; exists {
+		t.Errorf("idle key was seen doing %q, want no activity", seen)
+	}
+}
+
+func TestCheckEpochUnknownCommittee(t *testing.T) {
+	bc := newCheckerTest()
+	bc.Committees[testEpoch] = bc.Committees[testEpoch][:2]
+	tracker := performance.NewTracker(bc, testSlotsPerEpoch)
+
+	activity, err := CheckEpoch(context.Background(), tracker, testEpoch, map[uint64]types.ValidatorPubkey{heldIndex: {0x01}})
+	if err == nil {
+		t.Errorf("got activity %v for an attestation covering an unknown committee, want an error so the key stays held", activity)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package doppelganger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/stader-labs/stader-node/shared/utils/sys"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+type KeyStatus string
+
+const (
+	KeyStatus_Watching KeyStatus = "watching"
+	// The key signed something on chain while the validator client was held back, so it is running somewhere else
+	KeyStatus_Detected KeyStatus = "detected"
+)
+
+// A validator key that is kept out of the validator client until it has shown no activity for long enough
+type WatchedKey struct {
+	Pubkey   types.ValidatorPubkey `json:"pubkey"`
+	Index    uint64                `json:"index"`
+	Reason   string                `json:"reason"`
+	QueuedAt time.Time             `json:"queuedAt"`
+	// The epochs that have to show no activity, inclusive
+	StartEpoch uint64 `json:"startEpoch"`
+	EndEpoch   uint64 `json:"endEpoch"`
+	// The next epoch that hasn't been checked yet
+	NextEpoch uint64    `json:"nextEpoch"`
+	Status    KeyStatus `json:"status"`
+	// What was seen on chain, for detected keys
+	DetectedEpoch uint64 `json:"detectedEpoch,omitempty"`
+	Activity      string `json:"activity,omitempty"`
+}
+
+// Watch the given key over the next epochs, starting from the one after the head so nothing the
+// validator client did before it was stopped is counted
+func NewWatchedKey(pubkey types.ValidatorPubkey, index uint64, reason string, headEpoch uint64, epochs uint64) WatchedKey {
+	return WatchedKey{
+		Pubkey:     pubkey,
+		Index:      index,
+		Reason:     reason,
+		QueuedAt:   time.Now(),
+		StartEpoch: headEpoch + 1,
+		EndEpoch:   headEpoch + epochs,
+		NextEpoch:  headEpoch + 1,
+		Status:     KeyStatus_Watching,
+	}
+}
+
+// The keys the node is holding back from the validator client, stored as a JSON file.
+// The API adds keys to it and the node daemon releases them; they run in different containers,
+// so every access takes a lock on a file next to it.
+type Watch struct {
+	path string
+}
+
+func NewWatch(path string) *Watch {
+	return &Watch{
+		path: path,
+	}
+}
+
+// Start watching the given keys, replacing any earlier watch of the same keys
+func (w *Watch) Add(keys []WatchedKey) error {
+	return w.Update(func(watched map[types.ValidatorPubkey]WatchedKey) error {
+		for _, key := range keys {
+			watched[key.Pubkey] = key
+		}
+		return nil
+	})
+}
+
+// Get the watched keys, oldest first
+func (w *Watch) GetKeys() ([]WatchedKey, error) {
+	unlock, err := sys.LockFile(w.path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	watched, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	return sortKeys(watched), nil
+}
+
+// Load, modify and save the watched keys while holding the lock; keys removed from the map stop being watched
+func (w *Watch) Update(modify func(map[types.ValidatorPubkey]WatchedKey) error) error {
+	unlock, err := sys.LockFile(w.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	watched, err := w.load()
+	if err != nil {
+		return err
+	}
+	if err := modify(watched); err != nil {
+		return err
+	}
+	return w.save(watched)
+}
+
+func (w *Watch) load() (map[types.ValidatorPubkey]WatchedKey, error) {
+	watched := map[types.ValidatorPubkey]WatchedKey{}
+	bytes, err := ioutil.ReadFile(w.path)
+	if os.IsNotExist(err) {
+		return watched, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the doppelganger watch at %s: %w", w.path, err)
+	}
+	keys := []WatchedKey{}
+	if err := json.Unmarshal(bytes, &keys); err != nil {
+		return nil, fmt.Errorf("could not parse the doppelganger watch at %s: %w", w.path, err)
+	}
+	for _, key := range keys {
+		watched[key.Pubkey] = key
+	}
+	return watched, nil
+}
+
+// The file is replaced in one step so readers never see half of it
+func (w *Watch) save(watched map[types.ValidatorPubkey]WatchedKey) error {
+	bytes, err := json.MarshalIndent(sortKeys(watched), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return fmt.Errorf("could not create the doppelganger watch folder: %w", err)
+	}
+	tempPath := w.path + ".tmp"
+	if err := ioutil.WriteFile(tempPath, bytes, 0644); err != nil {
+		return fmt.Errorf("could not write the doppelganger watch: %w", err)
+	}
+	return os.Rename(tempPath, w.path)
+}
+
+func sortKeys(watched map[types.ValidatorPubkey]WatchedKey) []WatchedKey {
+	keys := make([]WatchedKey, 0, len(watched))
+	for _, key := range watched {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].QueuedAt.Equal(keys[j].QueuedAt) {
+			return keys[i].Index < keys[j].Index
+		}
+		return keys[i].QueuedAt.Before(keys[j].QueuedAt)
+	})
+	return keys
+}
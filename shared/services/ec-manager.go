@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/fatih/color"
 	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/txmanager"
 	"github.com/stader-labs/stader-node/shared/types/api"
 	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
 	"github.com/stader-labs/stader-node/shared/utils/log"
@@ -48,6 +49,7 @@ type ExecutionClientManager struct {
 	primaryReady    bool
 	fallbackReady   bool
 	ignoreSyncCheck bool
+	txJournal       *txmanager.Journal
 }
 
 // This is a signature for a wrapped ethclient.Client function
@@ -104,6 +106,7 @@ func NewExecutionClientManager(cfg *config.StaderConfig) (*ExecutionClientManage
 		logger:        log.NewColorLogger(color.FgYellow),
 		primaryReady:  true,
 		fallbackReady: fallbackEc != nil,
+		txJournal:     txmanager.NewJournal(cfg.StaderNode.GetTxJournalPath()),
 	}, nil
 
 }
@@ -209,6 +212,17 @@ func (p *ExecutionClientManager) SuggestGasTipCap(ctx context.Context) (*big.Int
 	return result.(*big.Int), err
 }
 
+// FeeHistory retrieves the fee market history.
+func (p *ExecutionClientManager) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	result, err := p.runFunction(func(client *ethclient.Client) (interface{}, error) {
+		return client.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*ethereum.FeeHistory), err
+}
+
 // EstimateGas tries to estimate the gas needed to execute a specific
 // transaction based on the current pending state of the backend blockchain.
 // There is no guarantee that this is the true gas limit requirement as other
@@ -229,7 +243,20 @@ func (p *ExecutionClientManager) SendTransaction(ctx context.Context, tx *types.
 	_, err := p.runFunction(func(client *ethclient.Client) (interface{}, error) {
 		return nil, client.SendTransaction(ctx, tx)
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Record it so it can be sped up or cancelled if it gets stuck; the transaction is already out, so don't fail here
+	if err := p.txJournal.Record(tx); err != nil {
+		p.logger.Printlnf("WARNING: could not record transaction %s in the journal: %s", tx.Hash().Hex(), err.Error())
+	}
+	return nil
+}
+
+// Get the journal of the transactions sent through this manager
+func (p *ExecutionClientManager) GetTxJournal() *txmanager.Journal {
+	return p.txJournal
 }
 
 /// ==========================
@@ -0,0 +1,188 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package feehistory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+const (
+	// How many recent blocks the estimate is based on
+	historyBlocks uint64 = 20
+
+	// How many blocks ahead the base fee is predicted
+	predictedBlocks int = 5
+
+	// The base fee can move by at most 1/8 from one block to the next
+	baseFeeChangeDenominator int64 = 8
+)
+
+// The priority fee percentiles of each speed, and how many blocks of maximal base fee increases its max fee covers
+var speeds = []struct {
+	percentile float64
+	blocks     int
+}{
+	{10, 3}, // Slow
+	{50, 6}, // Standard
+	{90, 9}, // Fast
+}
+
+// The subset of the Execution client the estimate needs
+type Client interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// Suggested fees for one speed
+type FeeSuggestion struct {
+	// The highest base fee the transaction should tolerate, excluding the priority fee
+	MaxBaseFeeWei *big.Int `json:"maxBaseFeeWei"`
+	// The priority fee recent transactions at this speed paid
+	PriorityFeeWei *big.Int `json:"priorityFeeWei"`
+}
+
+type FeeEstimate struct {
+	// The base fee of the latest block
+	BaseFeeWei *big.Int `json:"baseFeeWei"`
+	// The predicted base fees of the next blocks, starting with the pending one which is known exactly
+	PredictedBaseFeesWei []*big.Int `json:"predictedBaseFeesWei"`
+	// The average fraction of the gas limit the recent blocks used
+	GasUsedRatio float64 `json:"gasUsedRatio"`
+
+	Slow     FeeSuggestion `json:"slow"`
+	Standard FeeSuggestion `json:"standard"`
+	Fast     FeeSuggestion `json:"fast"`
+}
+
+// Estimate the fees from the recent blocks' fee history
+func GetFeeEstimate(client Client) (FeeEstimate, error) {
+
+	percentiles := make([]float64, len(speeds))
+	for i, speed := range speeds {
+		percentiles[i] = speed.percentile
+	}
+	history, err := client.FeeHistory(context.Background(), historyBlocks, nil, percentiles)
+	if err != nil {
+		return FeeEstimate{}, fmt.Errorf("Could not get the fee history: %w", err)
+	}
+	if len(history.BaseFee) < 2 || len(history.GasUsedRatio) == 0 {
+		return FeeEstimate{}, fmt.Errorf("the Execution client returned an empty fee history, it may not support EIP-1559")
+	}
+
+	// BaseFee holds one more entry than there are blocks: the base fee of the pending block
+	latestBaseFee := history.BaseFee[len(history.BaseFee)-2]
+	nextBaseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	gasUsedRatio := 0.0
+	for _, ratio := range history.GasUsedRatio {
+		gasUsedRatio += ratio
+	}
+	gasUsedRatio /= float64(len(history.GasUsedRatio))
+
+	estimate := FeeEstimate{
+		BaseFeeWei:           latestBaseFee,
+		PredictedBaseFeesWei: predictBaseFees(nextBaseFee, gasUsedRatio, predictedBlocks),
+		GasUsedRatio:         gasUsedRatio,
+	}
+
+	// Take the median of each percentile across the blocks, leaving out empty blocks since they have no priority fees
+	tips := make([]*big.Int, len(speeds))
+	for i := range speeds {
+		rewards := []*big.Int{}
+		for block, blockRewards := range history.Reward {
+			if history.GasUsedRatio[block] == 0 || i >= len(blockRewards) {
+				continue
+			}
+			rewards = append(rewards, blockRewards[i])
+		}
+		tips[i] = median(rewards)
+	}
+
+	// Fall back on the client's own suggestion when there's nothing to go on, and never suggest a fast tip below it
+	suggestedTip, err := client.SuggestGasTipCap(context.Background())
+	if err != nil {
+		return FeeEstimate{}, fmt.Errorf("Could not get the suggested priority fee: %w", err)
+	}
+	for i := range tips {
+		if tips[i] == nil {
+			tips[i] = new(big.Int).Set(suggestedTip)
+		}
+	}
+	if tips[len(tips)-1].Cmp(suggestedTip) < 0 {
+		tips[len(tips)-1] = new(big.Int).Set(suggestedTip)
+	}
+
+	suggestions := make([]FeeSuggestion, len(speeds))
+	for i, speed := range speeds {
+		suggestions[i] = FeeSuggestion{
+			MaxBaseFeeWei:  maxBaseFeeAfter(nextBaseFee, speed.blocks),
+			PriorityFeeWei: tips[i],
+		}
+	}
+	estimate.Slow = suggestions[0]
+	estimate.Standard = suggestions[1]
+	estimate.Fast = suggestions[2]
+
+	return estimate, nil
+
+}
+
+// Predict the next base fees assuming blocks keep using the same fraction of the gas limit (EIP-1559: the base fee
+// moves by up to 1/8 depending on how far the gas used is from half the limit)
+func predictBaseFees(nextBaseFee *big.Int, gasUsedRatio float64, blocks int) []*big.Int {
+	baseFees := make([]*big.Int, 0, blocks)
+	baseFee := new(big.Float).SetInt(nextBaseFee)
+	change := big.NewFloat(1 + (gasUsedRatio-0.5)/0.5/float64(baseFeeChangeDenominator))
+	for i := 0; i < blocks; i++ {
+		if i > 0 {
+			baseFee.Mul(baseFee, change)
+		}
+		baseFeeWei, _ := baseFee.Int(nil)
+		baseFees = append(baseFees, baseFeeWei)
+	}
+	return baseFees
+}
+
+// The base fee after the given number of full blocks, the most it could rise to in that time
+func maxBaseFeeAfter(nextBaseFee *big.Int, blocks int) *big.Int {
+	baseFee := new(big.Int).Set(nextBaseFee)
+	for i := 0; i < blocks; i++ {
+		increase := new(big.Int).Div(baseFee, big.NewInt(baseFeeChangeDenominator))
+		baseFee.Add(baseFee, increase)
+	}
+	return baseFee
+}
+
+func median(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+	return new(big.Int).Set(sorted[len(sorted)/2])
+}
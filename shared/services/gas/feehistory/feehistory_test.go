@@ -0,0 +1,165 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package feehistory
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+const gwei int64 = 1e9
+
+// Returns a fixed fee history and tip suggestion
+type fakeClient struct {
+	history      ethereum.FeeHistory
+	suggestedTip *big.Int
+}
+
+func (c *fakeClient) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return &c.history, nil
+}
+
+func (c *fakeClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return c.suggestedTip, nil
+}
+
+func gweis(values ...int64) []*big.Int {
+	wei := make([]*big.Int, len(values))
+	for i, value := range values {
+		wei[i] = big.NewInt(value * gwei)
+	}
+	return wei
+}
+
+func checkWei(t *testing.T, name string, got *big.Int, want *big.Int) {
+	t.Helper()
+	if got == nil || got.Cmp(want) != 0 {
+		t.Errorf("%s is %v, want %s", name, got, want)
+	}
+}
+
+func TestPredictBaseFees(t *testing.T) {
+	for _, test := range []struct {
+		gasUsedRatio float64
+		want         []*big.Int
+	}{
+		// Full blocks raise the base fee by 1/8 each
+		{1, []*big.Int{big.NewInt(64e9), big.NewInt(72e9), big.NewInt(81e9)}},
+		// Half full blocks keep it where it is
+		{0.5, []*big.Int{big.NewInt(64e9), big.NewInt(64e9), big.NewInt(64e9)}},
+		// Empty blocks lower it by 1/8 each
+		{0, []*big.Int{big.NewInt(64e9), big.NewInt(56e9), big.NewInt(49e9)}},
+		// Three quarters full blocks raise it by 1/16 each
+		{0.75, []*big.Int{big.NewInt(64e9), big.NewInt(68e9), big.NewInt(72.25e9)}},
+	} {
+		got := predictBaseFees(big.NewInt(64e9), test.gasUsedRatio, len(test.want))
+		if len(got) != len(test.want) {
+			t.Fatalf("predicted %d base fees, want %d", len(got), len(test.want))
+		}
+		for i := range got {
+			checkWei(t, "predicted base fee", got[i], test.want[i])
+		}
+	}
+}
+
+func TestMaxBaseFeeAfter(t *testing.T) {
+	checkWei(t, "max base fee after 0 blocks", maxBaseFeeAfter(big.NewInt(64e9), 0), big.NewInt(64e9))
+	checkWei(t, "max base fee after 2 blocks", maxBaseFeeAfter(big.NewInt(64e9), 2), big.NewInt(81e9))
+	// Each block's increase is rounded down, as the protocol does
+	checkWei(t, "max base fee after 2 blocks", maxBaseFeeAfter(big.NewInt(100), 2), big.NewInt(126))
+}
+
+func TestMedian(t *testing.T) {
+	if median(nil) != nil {
+		t.Error("the median of no values isn't nil")
+	}
+	values := gweis(5, 1, 3)
+	checkWei(t, "median of 3 values", median(values), big.NewInt(3*gwei))
+	checkWei(t, "median of 4 values", median(gweis(4, 1, 3, 2)), big.NewInt(3*gwei))
+	checkWei(t, "first value after taking the median", values[0], big.NewInt(5*gwei))
+}
+
+func TestGetFeeEstimate(t *testing.T) {
+	client := &fakeClient{
+		history: ethereum.FeeHistory{
+			// Four blocks plus the pending one
+			BaseFee:      gweis(10, 12, 14, 16, 18),
+			GasUsedRatio: []float64{1, 0, 1, 1},
+			Reward: [][]*big.Int{
+				gweis(1, 2, 5),
+				gweis(100, 100, 100), // Empty, so left out
+				gweis(2, 3, 7),
+				gweis(3, 4, 6),
+			},
+		},
+		suggestedTip: big.NewInt(1 * gwei),
+	}
+
+	estimate, err := GetFeeEstimate(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWei(t, "base fee", estimate.BaseFeeWei, big.NewInt(16*gwei))
+	checkWei(t, "next base fee", estimate.PredictedBaseFeesWei[0], big.NewInt(18*gwei))
+	if estimate.GasUsedRatio != 0.75 {
+		t.Errorf("gas used ratio is %f, want 0.75", estimate.GasUsedRatio)
+	}
+
+	checkWei(t, "slow priority fee", estimate.Slow.PriorityFeeWei, big.NewInt(2*gwei))
+	checkWei(t, "standard priority fee", estimate.Standard.PriorityFeeWei, big.NewInt(3*gwei))
+	checkWei(t, "fast priority fee", estimate.Fast.PriorityFeeWei, big.NewInt(6*gwei))
+	checkWei(t, "slow max base fee", estimate.Slow.MaxBaseFeeWei, maxBaseFeeAfter(big.NewInt(18*gwei), 3))
+	checkWei(t, "fast max base fee", estimate.Fast.MaxBaseFeeWei, maxBaseFeeAfter(big.NewInt(18*gwei), 9))
+
+	// The fast priority fee is never below the client's suggestion
+	client.suggestedTip = big.NewInt(10 * gwei)
+	estimate, err = GetFeeEstimate(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWei(t, "fast priority fee", estimate.Fast.PriorityFeeWei, big.NewInt(10*gwei))
+	checkWei(t, "standard priority fee", estimate.Standard.PriorityFeeWei, big.NewInt(3*gwei))
+}
+
+func TestGetFeeEstimateWithoutRewards(t *testing.T) {
+	client := &fakeClient{
+		history: ethereum.FeeHistory{
+			BaseFee:      gweis(10, 10, 10),
+			GasUsedRatio: []float64{0, 0},
+			Reward:       [][]*big.Int{gweis(1, 2, 3), gweis(1, 2, 3)},
+		},
+		suggestedTip: big.NewInt(2 * gwei),
+	}
+	estimate, err := GetFeeEstimate(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, suggestion := range []FeeSuggestion{estimate.Slow, estimate.Standard, estimate.Fast} {
+		checkWei(t, "priority fee of empty blocks", suggestion.PriorityFeeWei, client.suggestedTip)
+	}
+
+	client.history = ethereum.FeeHistory{BaseFee: gweis(10), GasUsedRatio: []float64{}}
+	if _, err := GetFeeEstimate(client); err == nil {
+		t.Error("an empty fee history didn't return an error")
+	}
+}
@@ -26,9 +26,9 @@ import (
 
 	"github.com/stader-labs/stader-node/shared/utils/log"
 
-	"github.com/stader-labs/stader-node/shared/services/gas/etherchain"
-	"github.com/stader-labs/stader-node/shared/services/gas/etherscan"
+	"github.com/stader-labs/stader-node/shared/services/gas/feehistory"
 	"github.com/stader-labs/stader-node/shared/services/stader"
+	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
 	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
 	"github.com/stader-labs/stader-node/shared/utils/math"
 	staderCore "github.com/stader-labs/stader-node/stader-lib/stader"
@@ -59,16 +59,16 @@ func AssignMaxFeeAndLimit(gasInfo staderCore.GasInfo, staderClient *stader.Clien
 	// Get the priority fee - prioritize the CLI arguments, default to the config file setting
 	if maxPriorityFeeGwei == 0 {
 		maxPriorityFee := eth.GweiToWei(cfg.StaderNode.PriorityFee.Value.(float64))
-		if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-			fmt.Printf("%sNOTE: max priority fee not set or set to 0, defaulting to 2 gwei%s\n", log.ColorYellow, log.ColorReset)
-			maxPriorityFeeGwei = 2
-		} else {
+		if maxPriorityFee != nil && maxPriorityFee.Uint64() != 0 {
 			maxPriorityFeeGwei = eth.WeiToGwei(maxPriorityFee)
 		}
 	}
 
 	// Use the requested max fee and priority fee if provided
 	if maxFeeGwei != 0 {
+		if maxPriorityFeeGwei == 0 {
+			maxPriorityFeeGwei = getDefaultPriorityFeeGwei(nil)
+		}
 		fmt.Printf("%sUsing the requested max fee of %.2f gwei (including a max priority fee of %.2f gwei).\n", log.ColorYellow, maxFeeGwei, maxPriorityFeeGwei)
 
 		var lowLimit float64
@@ -83,30 +83,20 @@ func AssignMaxFeeAndLimit(gasInfo staderCore.GasInfo, staderClient *stader.Clien
 		fmt.Printf("Total cost: %.4f to %.4f ETH%s\n", lowLimit, highLimit, log.ColorReset)
 
 	} else {
+		// Get the latest suggestion from the selected source
+		feeSource := cfg.StaderNode.FeeSource.Value.(cfgtypes.FeeSource)
+		suggestion, err := GetFeeSuggestion(GetFeeOracles(feeSource, staderClient))
+		if err != nil {
+			return err
+		}
+		if maxPriorityFeeGwei == 0 {
+			maxPriorityFeeGwei = getDefaultPriorityFeeGwei(suggestion.PriorityFeeWei)
+		}
 		if headless {
-			maxFeeWei, err := GetHeadlessMaxFeeWei()
-			if err != nil {
-				return err
-			}
-			maxFeeGwei = eth.WeiToGwei(maxFeeWei)
+			maxFeeGwei = eth.WeiToGwei(getHeadlessMaxFeeWei(suggestion)) + maxPriorityFeeGwei
 		} else {
-			// Try to get the latest gas prices from Etherchain
-			etherchainData, err := etherchain.GetGasPrices()
-			if err == nil {
-				// Print the Etherchain data and ask for an amount
-				maxFeeGwei = handleEtherchainGasPrices(etherchainData, maxPriorityFeeGwei)
-
-			} else {
-				// Fallback to Etherscan
-				fmt.Printf("%sWarning: couldn't get gas estimates from Etherchain - %s\nFalling back to Etherscan%s\n", log.ColorYellow, err.Error(), log.ColorReset)
-				etherscanData, err := etherscan.GetGasPrices()
-				if err == nil {
-					// Print the Etherscan data and ask for an amount
-					maxFeeGwei = handleEtherscanGasPrices(etherscanData, maxPriorityFeeGwei)
-				} else {
-					return fmt.Errorf("Error getting gas price suggestions: %w", err)
-				}
-			}
+			// Print the suggestion and ask for an amount
+			maxFeeGwei = handleGasPrices(suggestion, maxPriorityFeeGwei)
 		}
 		fmt.Printf("%sUsing a max fee of %.2f gwei and a priority fee of %.2f gwei.\n%s", log.ColorBlue, maxFeeGwei, maxPriorityFeeGwei, log.ColorReset)
 	}
@@ -124,23 +114,40 @@ func AssignMaxFeeAndLimit(gasInfo staderCore.GasInfo, staderClient *stader.Clien
 
 }
 
-// Get the suggested max fee for service operations
-func GetHeadlessMaxFeeWei() (*big.Int, error) {
-	etherchainData, err := etherchain.GetGasPrices()
-	if err == nil {
-		return etherchainData.RapidWei, nil
+// Get the suggested max fee for service operations, not including the priority fee
+func GetHeadlessMaxFeeWei(staderClient *stader.Client, source cfgtypes.FeeSource) (*big.Int, error) {
+	suggestion, err := GetFeeSuggestion(GetFeeOracles(source, staderClient))
+	if err != nil {
+		return nil, err
 	}
+	return getHeadlessMaxFeeWei(suggestion), nil
+}
 
-	fmt.Printf("%sWarning: couldn't get gas estimates from Etherchain - %s\nFalling back to Etherscan%s\n", log.ColorYellow, err.Error(), log.ColorReset)
-	etherscanData, err := etherscan.GetGasPrices()
-	if err == nil {
-		return eth.GweiToWei(etherscanData.FastGwei), nil
+// Service operations pay Etherchain's rapid price as they always have, or the fast price of the other sources
+func getHeadlessMaxFeeWei(suggestion FeeSuggestion) *big.Int {
+	if suggestion.RapidWei != nil {
+		return suggestion.RapidWei
 	}
+	return suggestion.FastWei
+}
 
-	return nil, fmt.Errorf("Error getting gas price suggestions: %w", err)
+// Get the priority fee to use when none is set: the one fast transactions paid recently if the Execution client
+// suggested it, or 2 gwei otherwise
+func getDefaultPriorityFeeGwei(suggestedWei *big.Int) float64 {
+	if suggestedWei != nil && suggestedWei.Sign() > 0 {
+		priorityFeeGwei := eth.WeiToGwei(suggestedWei)
+		fmt.Printf("%sNOTE: max priority fee not set or set to 0, using the suggested %.2f gwei%s\n", log.ColorYellow, priorityFeeGwei, log.ColorReset)
+		return priorityFeeGwei
+	}
+	fmt.Printf("%sNOTE: max priority fee not set or set to 0, defaulting to 2 gwei%s\n", log.ColorYellow, log.ColorReset)
+	return 2
 }
 
-func handleEtherchainGasPrices(gasSuggestion etherchain.GasFeeSuggestion, priorityFee float64) float64 {
+func handleGasPrices(gasSuggestion FeeSuggestion, priorityFee float64) float64 {
+	if gasSuggestion.Estimate != nil {
+		printFeeEstimate(*gasSuggestion.Estimate, priorityFee)
+	}
+
 	fastGwei := math.RoundUp(eth.WeiToGwei(gasSuggestion.FastWei)+priorityFee, 0)
 
 	for {
@@ -155,7 +162,7 @@ func handleEtherchainGasPrices(gasSuggestion etherchain.GasFeeSuggestion, priori
 
 		desiredPriceFloat, err := strconv.ParseFloat(desiredPrice, 64)
 		if err != nil {
-			fmt.Printf("Not a valid gas price (%s), try again.", err.Error())
+			fmt.Printf("Not a valid gas price (%s), try again.\n", err.Error())
 			continue
 		}
 		if desiredPriceFloat <= 0 {
@@ -168,31 +175,25 @@ func handleEtherchainGasPrices(gasSuggestion etherchain.GasFeeSuggestion, priori
 
 }
 
-func handleEtherscanGasPrices(gasSuggestion etherscan.GasFeeSuggestion, priorityFee float64) float64 {
-
-	fastGwei := math.RoundUp(gasSuggestion.FastGwei+priorityFee, 0)
-
-	for {
-		desiredPrice := cliutils.Prompt(
-			fmt.Sprintf("Please enter your max fee (including the priority fee) or leave blank for the default of %d gwei:", int(fastGwei)),
-			"^(?:[1-9]\\d*|0)?(?:\\.\\d+)?$",
-			"Not a valid gas price, try again:")
-
-		if desiredPrice == "" {
-			return fastGwei
-		}
-
-		desiredPriceFloat, err := strconv.ParseFloat(desiredPrice, 64)
-		if err != nil {
-			fmt.Println("Not a valid gas price (%s), try again.", err.Error())
-			continue
-		}
-		if desiredPriceFloat <= 0 {
-			fmt.Println("Max fee must be greater than zero.")
-			continue
-		}
-
-		return desiredPriceFloat
+func printFeeEstimate(estimate feehistory.FeeEstimate, priorityFee float64) {
+	fmt.Printf("%s+============ Suggested Fees ============+\n", log.ColorBlue)
+	fmt.Printf("| Current base fee: %.2f gwei\n", eth.WeiToGwei(estimate.BaseFeeWei))
+	predicted := estimate.PredictedBaseFeesWei
+	if len(predicted) > 0 {
+		fmt.Printf("| Next block: %.2f gwei, in %d blocks: %.2f gwei\n", eth.WeiToGwei(predicted[0]), len(predicted), eth.WeiToGwei(predicted[len(predicted)-1]))
+	}
+	for _, speed := range []struct {
+		name       string
+		suggestion feehistory.FeeSuggestion
+	}{
+		{"Slow", estimate.Slow},
+		{"Standard", estimate.Standard},
+		{"Fast", estimate.Fast},
+	} {
+		fmt.Printf("| %-8s priority fee %.2f gwei, max fee %.2f gwei\n", speed.name, eth.WeiToGwei(speed.suggestion.PriorityFeeWei), eth.WeiToGwei(speed.suggestion.MaxBaseFeeWei)+eth.WeiToGwei(speed.suggestion.PriorityFeeWei))
+	}
+	fmt.Printf("+========================================+%s\n", log.ColorReset)
+	if eth.GweiToWei(priorityFee).Cmp(estimate.Slow.PriorityFeeWei) < 0 {
+		fmt.Printf("%sNOTE: your priority fee of %.2f gwei is lower than what slow transactions are paying right now, so your transaction may take a long time to be included.%s\n", log.ColorYellow, priorityFee, log.ColorReset)
 	}
-
 }
@@ -0,0 +1,143 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package gas
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/stader-labs/stader-node/shared/services/gas/etherchain"
+	"github.com/stader-labs/stader-node/shared/services/gas/etherscan"
+	"github.com/stader-labs/stader-node/shared/services/gas/feehistory"
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+// Suggested max fees, not including the priority fee
+type FeeSuggestion struct {
+	SlowWei     *big.Int
+	StandardWei *big.Int
+	FastWei     *big.Int
+
+	// Etherchain's rapid price, which service operations pay when it's available
+	RapidWei *big.Int
+
+	// The priority fee fast transactions paid recently and the full estimate, only available from the Execution client
+	PriorityFeeWei *big.Int
+	Estimate       *feehistory.FeeEstimate
+}
+
+// A source of max fee suggestions
+type FeeOracle interface {
+	Name() string
+	GetFeeSuggestion() (FeeSuggestion, error)
+}
+
+// Get the fee oracles to try, in order: the selected source first, then the Execution client if that was a third party
+func GetFeeOracles(source cfgtypes.FeeSource, staderClient *stader.Client) []FeeOracle {
+	ecOracle := &executionClientOracle{staderClient: staderClient}
+	switch source {
+	case cfgtypes.FeeSource_Etherchain:
+		return []FeeOracle{&etherchainOracle{}, ecOracle}
+	case cfgtypes.FeeSource_Etherscan:
+		return []FeeOracle{&etherscanOracle{}, ecOracle}
+	default:
+		return []FeeOracle{ecOracle}
+	}
+}
+
+// Get a fee suggestion from the first of the oracles that works
+func GetFeeSuggestion(oracles []FeeOracle) (FeeSuggestion, error) {
+	var err error
+	for i, oracle := range oracles {
+		var suggestion FeeSuggestion
+		suggestion, err = oracle.GetFeeSuggestion()
+		if err == nil {
+			return suggestion, nil
+		}
+		if i < len(oracles)-1 {
+			fmt.Printf("%sWarning: couldn't get gas estimates from %s - %s\nFalling back to %s%s\n", log.ColorYellow, oracle.Name(), err.Error(), oracles[i+1].Name(), log.ColorReset)
+		}
+	}
+	return FeeSuggestion{}, fmt.Errorf("Error getting gas price suggestions: %w", err)
+}
+
+// Estimates the fees from the fee history of the node's own Execution client
+type executionClientOracle struct {
+	staderClient *stader.Client
+}
+
+func (o *executionClientOracle) Name() string {
+	return "your Execution client"
+}
+
+func (o *executionClientOracle) GetFeeSuggestion() (FeeSuggestion, error) {
+	response, err := o.staderClient.GetFeeEstimate()
+	if err != nil {
+		return FeeSuggestion{}, err
+	}
+	estimate := response.Estimate
+	return FeeSuggestion{
+		SlowWei:        estimate.Slow.MaxBaseFeeWei,
+		StandardWei:    estimate.Standard.MaxBaseFeeWei,
+		FastWei:        estimate.Fast.MaxBaseFeeWei,
+		PriorityFeeWei: estimate.Fast.PriorityFeeWei,
+		Estimate:       &estimate,
+	}, nil
+}
+
+type etherchainOracle struct{}
+
+func (o *etherchainOracle) Name() string {
+	return "Etherchain"
+}
+
+func (o *etherchainOracle) GetFeeSuggestion() (FeeSuggestion, error) {
+	data, err := etherchain.GetGasPrices()
+	if err != nil {
+		return FeeSuggestion{}, err
+	}
+	return FeeSuggestion{
+		SlowWei:     data.SlowWei,
+		StandardWei: data.StandardWei,
+		FastWei:     data.FastWei,
+		RapidWei:    data.RapidWei,
+	}, nil
+}
+
+type etherscanOracle struct{}
+
+func (o *etherscanOracle) Name() string {
+	return "Etherscan"
+}
+
+func (o *etherscanOracle) GetFeeSuggestion() (FeeSuggestion, error) {
+	data, err := etherscan.GetGasPrices()
+	if err != nil {
+		return FeeSuggestion{}, err
+	}
+	return FeeSuggestion{
+		SlowWei:     eth.GweiToWei(data.SlowGwei),
+		StandardWei: eth.GweiToWei(data.StandardGwei),
+		FastWei:     eth.GweiToWei(data.FastGwei),
+	}, nil
+}
@@ -0,0 +1,96 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package ledger
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"date", "block", "type", "kind", "asset", "amount", "currency", "price", "value", "tx_hash", "log_index", "contract"}
+
+// Write the records as CSV with one row per record
+func WriteCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, record := range records {
+		price := ""
+		value := ""
+		if record.Currency != "" {
+			price = strconv.FormatFloat(record.Price, 'f', -1, 64)
+			value = strconv.FormatFloat(record.Value, 'f', 2, 64)
+		}
+		row := []string{
+			record.Time.UTC().Format(time.RFC3339),
+			strconv.FormatUint(record.BlockNumber, 10),
+			string(record.Type),
+			string(record.Kind),
+			string(record.Asset),
+			FormatAmount(record.Amount),
+			record.Currency,
+			price,
+			value,
+			record.TxHash.Hex(),
+			strconv.FormatUint(uint64(record.LogIndex), 10),
+			record.Contract.Hex(),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Write the records as an indented JSON array
+func WriteJSON(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// Format a wei amount in whole units without losing precision
+func FormatAmount(wei *big.Int) string {
+	if wei == nil {
+		return "0"
+	}
+	negative := wei.Sign() < 0
+	digits := new(big.Int).Abs(wei).String()
+	if len(digits) <= 18 {
+		digits = strings.Repeat("0", 19-len(digits)) + digits
+	}
+	whole := digits[:len(digits)-18]
+	fraction := strings.TrimRight(digits[len(digits)-18:], "0")
+	amount := whole
+	if fraction != "" {
+		amount += "." + fraction
+	}
+	if negative {
+		amount = "-" + amount
+	}
+	return amount
+}
@@ -0,0 +1,200 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type RecordType string
+
+const (
+	// The operator's share of a NodeElRewardVault withdrawal
+	RecordType_ElRewards RecordType = "el_rewards"
+	// The operator's share of a validator withdraw vault's DistributedRewards
+	RecordType_ClRewards RecordType = "cl_rewards"
+	// The operator's share of a validator withdraw vault's SettledFunds after an exit
+	RecordType_ExitSettlement RecordType = "exit_settlement"
+	// Rewards claimed from the socializing pool
+	RecordType_SocializingPoolClaim RecordType = "socializing_pool_claim"
+	// Rewards paid out by the operator rewards collector
+	RecordType_OperatorRewardsClaim RecordType = "operator_rewards_claim"
+	RecordType_SdDeposit            RecordType = "sd_deposit"
+	RecordType_SdWithdrawal         RecordType = "sd_withdrawal"
+	RecordType_SdSlash              RecordType = "sd_slash"
+)
+
+// How a record counts for accounting
+type RecordKind string
+
+const (
+	// Rewards earned by the operator
+	RecordKind_Income RecordKind = "income"
+	// The bond returned after an exit, net of penalties and including any rewards left in the vault
+	RecordKind_Principal RecordKind = "principal"
+	// Rewards that were already counted as income being paid out to the reward address
+	RecordKind_Payout RecordKind = "payout"
+	// SD moved into or out of the collateral contract
+	RecordKind_Collateral RecordKind = "collateral"
+	RecordKind_Loss       RecordKind = "loss"
+)
+
+type Asset string
+
+const (
+	Asset_Eth Asset = "ETH"
+	Asset_Sd  Asset = "SD"
+)
+
+// A single entry of the operator's ledger
+type Record struct {
+	Type        RecordType     `json:"type"`
+	Kind        RecordKind     `json:"kind"`
+	Asset       Asset          `json:"asset"`
+	Amount      *big.Int       `json:"amount"`
+	BlockNumber uint64         `json:"blockNumber"`
+	Time        time.Time      `json:"time"`
+	TxHash      common.Hash    `json:"txHash"`
+	LogIndex    uint           `json:"logIndex"`
+	Contract    common.Address `json:"contract"`
+
+	// Filled in from a price source when the ledger is exported
+	Currency string  `json:"currency,omitempty"`
+	Price    float64 `json:"price,omitempty"`
+	Value    float64 `json:"value,omitempty"`
+}
+
+// A socializing pool claim makes an ETH and an SD record out of the same log
+func (r Record) id() string {
+	return fmt.Sprintf("%s-%d-%s-%s", r.TxHash.Hex(), r.LogIndex, r.Type, r.Asset)
+}
+
+type ledgerFile struct {
+	// The first block whose events haven't been added yet
+	NextBlock uint64   `json:"nextBlock"`
+	Records   []Record `json:"records"`
+}
+
+// The operator's ledger, stored as a JSON file that is extended from the operator's event index every time it's updated
+type Store struct {
+	path string
+	lock sync.Mutex
+}
+
+func NewStore(path string) *Store {
+	return &Store{
+		path: path,
+	}
+}
+
+// Get the records in [from, to], in chain order
+func (s *Store) GetRecords(from time.Time, to time.Time) ([]Record, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{}
+	for _, record := range file.Records {
+		if record.Time.Before(from) || record.Time.After(to) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Get the block the ledger has been updated up to, exclusive
+func (s *Store) GetScannedBlock() (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	return file.NextBlock, nil
+}
+
+// Load, modify and save the ledger while holding the lock
+func (s *Store) update(modify func(*ledgerFile) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := modify(file); err != nil {
+		return err
+	}
+	return s.save(file)
+}
+
+func (s *Store) load() (*ledgerFile, error) {
+	file := &ledgerFile{
+		Records: []Record{},
+	}
+	bytes, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the ledger at %s: %w", s.path, err)
+	}
+	if err := json.Unmarshal(bytes, file); err != nil {
+		return nil, fmt.Errorf("could not parse the ledger at %s: %w", s.path, err)
+	}
+	return file, nil
+}
+
+// The file is replaced in one step so a failed write never loses the ledger
+func (s *Store) save(file *ledgerFile) error {
+	sort.SliceStable(file.Records, func(i, j int) bool {
+		if file.Records[i].BlockNumber != file.Records[j].BlockNumber {
+			return file.Records[i].BlockNumber < file.Records[j].BlockNumber
+		}
+		return file.Records[i].LogIndex < file.Records[j].LogIndex
+	})
+	bytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("could not create the ledger folder: %w", err)
+	}
+	tempPath := s.path + ".tmp"
+	if err := ioutil.WriteFile(tempPath, bytes, 0644); err != nil {
+		return fmt.Errorf("could not write the ledger: %w", err)
+	}
+	return os.Rename(tempPath, s.path)
+}
@@ -0,0 +1,210 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package ledger
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	coinGeckoHistoryUrl string = "https://api.coingecko.com/api/v3/coins/%s/history?date=%s&localization=false"
+	coinGeckoMaxRetries int    = 3
+	coinGeckoRetryDelay        = 30 * time.Second
+	priceFileDateLayout string = "2006-01-02"
+)
+
+// Provides the fiat price of an asset on a given day
+type PriceSource interface {
+	GetPrice(asset Asset, currency string, t time.Time) (float64, error)
+}
+
+// A price source that leaves the records unpriced
+type NoPriceSource struct{}
+
+func (s NoPriceSource) GetPrice(asset Asset, currency string, t time.Time) (float64, error) {
+	return 0, nil
+}
+
+// Daily prices from the CoinGecko public API
+type CoinGeckoPriceSource struct {
+	client *http.Client
+	cache  map[string]float64
+}
+
+func NewCoinGeckoPriceSource() *CoinGeckoPriceSource {
+	return &CoinGeckoPriceSource{
+		client: &http.Client{Timeout: 30 * time.Second},
+		cache:  map[string]float64{},
+	}
+}
+
+type coinGeckoHistoryResponse struct {
+	MarketData struct {
+		CurrentPrice map[string]float64 `json:"current_price"`
+	} `json:"market_data"`
+}
+
+func (s *CoinGeckoPriceSource) GetPrice(asset Asset, currency string, t time.Time) (float64, error) {
+	var coinId string
+	switch asset {
+	case Asset_Eth:
+		coinId = "ethereum"
+	case Asset_Sd:
+		coinId = "stader"
+	default:
+		return 0, fmt.Errorf("no CoinGecko price for asset '%s'", asset)
+	}
+	currency = strings.ToLower(currency)
+	date := t.UTC().Format("02-01-2006")
+	cacheKey := fmt.Sprintf("%s-%s-%s", coinId, currency, date)
+	if price, exists := s.cache[cacheKey]; exists {
+		return price, nil
+	}
+
+	// The public API is rate limited, so back off and retry when it pushes back
+	var body []byte
+	for attempt := 0; ; attempt++ {
+		response, err := s.client.Get(fmt.Sprintf(coinGeckoHistoryUrl, coinId, date))
+		if err != nil {
+			return 0, fmt.Errorf("error getting the %s price for %s: %w", asset, date, err)
+		}
+		body, err = ioutil.ReadAll(response.Body)
+		_ = response.Body.Close()
+		if err != nil {
+			return 0, err
+		}
+		if response.StatusCode == http.StatusTooManyRequests && attempt < coinGeckoMaxRetries {
+			time.Sleep(coinGeckoRetryDelay)
+			continue
+		}
+		if response.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("error getting the %s price for %s: request failed with code %d", asset, date, response.StatusCode)
+		}
+		break
+	}
+
+	var history coinGeckoHistoryResponse
+	if err := json.Unmarshal(body, &history); err != nil {
+		return 0, fmt.Errorf("could not decode the CoinGecko price response: %w", err)
+	}
+	price, exists := history.MarketData.CurrentPrice[currency]
+	if !exists {
+		return 0, fmt.Errorf("CoinGecko has no %s price in %s for %s", asset, strings.ToUpper(currency), date)
+	}
+	s.cache[cacheKey] = price
+	return price, nil
+}
+
+// Daily prices read from a CSV file with date (YYYY-MM-DD), asset and price columns, all in one currency.
+// Days without a price use the latest earlier one in the file.
+type FilePriceSource struct {
+	prices map[Asset][]datedPrice
+}
+
+type datedPrice struct {
+	date  time.Time
+	price float64
+}
+
+func NewFilePriceSource(path string) (*FilePriceSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the price file: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the price file: %w", err)
+	}
+
+	source := &FilePriceSource{prices: map[Asset][]datedPrice{}}
+	for i, row := range rows {
+		if len(row) != 3 {
+			return nil, fmt.Errorf("line %d of the price file should have 3 columns but has %d", i+1, len(row))
+		}
+		date, err := time.Parse(priceFileDateLayout, strings.TrimSpace(row[0]))
+		if err != nil {
+			if i == 0 {
+				// Header
+				continue
+			}
+			return nil, fmt.Errorf("invalid date on line %d of the price file: %w", i+1, err)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price on line %d of the price file: %w", i+1, err)
+		}
+		asset := Asset(strings.ToUpper(strings.TrimSpace(row[1])))
+		source.prices[asset] = append(source.prices[asset], datedPrice{date: date, price: price})
+	}
+	for _, prices := range source.prices {
+		sort.Slice(prices, func(i, j int) bool {
+			return prices[i].date.Before(prices[j].date)
+		})
+	}
+	return source, nil
+}
+
+func (s *FilePriceSource) GetPrice(asset Asset, currency string, t time.Time) (float64, error) {
+	prices := s.prices[asset]
+	price := 0.0
+	found := false
+	for _, dated := range prices {
+		if dated.date.After(t) {
+			break
+		}
+		price = dated.price
+		found = true
+	}
+	if !found {
+		return 0, fmt.Errorf("the price file has no %s price on or before %s", asset, t.UTC().Format(priceFileDateLayout))
+	}
+	return price, nil
+}
+
+// Fill in the price and fiat value of each record
+func ApplyPrices(records []Record, source PriceSource, currency string) error {
+	currency = strings.ToUpper(currency)
+	for i := range records {
+		price, err := source.GetPrice(records[i].Asset, currency, records[i].Time)
+		if err != nil {
+			return err
+		}
+		if price == 0 {
+			continue
+		}
+		amount, _ := new(big.Float).Quo(new(big.Float).SetInt(records[i].Amount), big.NewFloat(1e18)).Float64()
+		records[i].Currency = currency
+		records[i].Price = price
+		records[i].Value = amount * price
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/indexer"
+)
+
+// Blocks newer than this are left for a later update. The index drops the events of blocks that get reorged out,
+// but records that were already added to the ledger would stay.
+const Confirmations uint64 = 64
+
+// The index sources the records are read from
+var ledgerSources = []string{
+	indexer.SdCollateralSource,
+	indexer.SocializingPoolSource,
+	indexer.OperatorRewardsCollectorSource,
+	indexer.NodeElRewardVaultSource,
+	indexer.ValidatorWithdrawVaultSource,
+}
+
+// The subset of the Execution client the ledger needs to timestamp its records
+type HeaderClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Add the records of the operator's indexed events from where the ledger was left up to toBlock.
+// The index must have been synced past toBlock.
+func (s *Store) Update(ix *indexer.Indexer, client HeaderClient, toBlock uint64) error {
+	s.lock.Lock()
+	file, err := s.load()
+	s.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	if file.NextBlock > toBlock {
+		return nil
+	}
+
+	events, err := ix.Query(indexer.Query{
+		Sources:   ledgerSources,
+		FromBlock: file.NextBlock,
+		ToBlock:   toBlock,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading the operator's events: %w", err)
+	}
+
+	records := []Record{}
+	blockTimes := map[uint64]time.Time{}
+	for _, event := range events {
+		eventRecords, err := getRecords(ix, event)
+		if err != nil {
+			return err
+		}
+		for i := range eventRecords {
+			eventRecords[i].Time, err = getBlockTime(client, blockTimes, eventRecords[i].BlockNumber)
+			if err != nil {
+				return fmt.Errorf("error getting block times: %w", err)
+			}
+		}
+		records = append(records, eventRecords...)
+	}
+
+	return s.addRecords(toBlock+1, records)
+}
+
+// Add records that haven't been seen yet and move the ledger forward
+func (s *Store) addRecords(next uint64, records []Record) error {
+	return s.update(func(file *ledgerFile) error {
+		seen := make(map[string]bool, len(file.Records))
+		for _, record := range file.Records {
+			seen[record.id()] = true
+		}
+		for _, record := range records {
+			if seen[record.id()] {
+				continue
+			}
+			seen[record.id()] = true
+			file.Records = append(file.Records, record)
+		}
+		if next > file.NextBlock {
+			file.NextBlock = next
+		}
+		return nil
+	})
+}
+
+// Get the records of an indexed event; records without an amount are left out
+func getRecords(ix *indexer.Indexer, event indexer.Event) ([]Record, error) {
+	records := []Record{}
+	add := func(recordType RecordType, recordKind RecordKind, asset Asset, amount *big.Int) {
+		if amount == nil || amount.Sign() == 0 {
+			return
+		}
+		records = append(records, Record{
+			Type:        recordType,
+			Kind:        recordKind,
+			Asset:       asset,
+			Amount:      amount,
+			BlockNumber: event.BlockNumber,
+			TxHash:      event.TxHash,
+			LogIndex:    event.LogIndex,
+			Contract:    event.Address,
+		})
+	}
+
+	var err error
+	switch event.Source + "." + event.Name {
+	case indexer.SdCollateralSource + ".SDDeposited":
+		var deposit contracts.SdCollateralSDDeposited
+		if err = ix.Unpack(event, &deposit); err == nil {
+			add(RecordType_SdDeposit, RecordKind_Collateral, Asset_Sd, deposit.SdAmount)
+		}
+	case indexer.SdCollateralSource + ".SDWithdrawn":
+		var withdrawal contracts.SdCollateralSDWithdrawn
+		if err = ix.Unpack(event, &withdrawal); err == nil {
+			add(RecordType_SdWithdrawal, RecordKind_Collateral, Asset_Sd, withdrawal.SdAmount)
+		}
+	case indexer.SdCollateralSource + ".SDSlashed":
+		var slash contracts.SdCollateralSDSlashed
+		if err = ix.Unpack(event, &slash); err == nil {
+			add(RecordType_SdSlash, RecordKind_Loss, Asset_Sd, slash.SdSlashed)
+		}
+	case indexer.SocializingPoolSource + ".OperatorRewardsClaimed":
+		var claim contracts.SocializingPoolOperatorRewardsClaimed
+		if err = ix.Unpack(event, &claim); err == nil {
+			add(RecordType_SocializingPoolClaim, RecordKind_Income, Asset_Eth, claim.EthRewards)
+			add(RecordType_SocializingPoolClaim, RecordKind_Income, Asset_Sd, claim.SdRewards)
+		}
+	case indexer.OperatorRewardsCollectorSource + ".Claimed":
+		var claim contracts.OperatorRewardsCollectorClaimed
+		if err = ix.Unpack(event, &claim); err == nil {
+			add(RecordType_OperatorRewardsClaim, RecordKind_Payout, Asset_Eth, claim.Amount)
+		}
+	case indexer.NodeElRewardVaultSource + ".Withdrawal":
+		var withdrawal contracts.NodeElRewardVaultWithdrawal
+		if err = ix.Unpack(event, &withdrawal); err == nil {
+			add(RecordType_ElRewards, RecordKind_Income, Asset_Eth, withdrawal.OperatorAmount)
+		}
+	case indexer.ValidatorWithdrawVaultSource + ".DistributedRewards":
+		var distribution contracts.ValidatorWithdrawVaultDistributedRewards
+		if err = ix.Unpack(event, &distribution); err == nil {
+			add(RecordType_ClRewards, RecordKind_Income, Asset_Eth, distribution.OperatorShare)
+		}
+	case indexer.ValidatorWithdrawVaultSource + ".SettledFunds":
+		var settlement contracts.ValidatorWithdrawVaultSettledFunds
+		if err = ix.Unpack(event, &settlement); err == nil {
+			add(RecordType_ExitSettlement, RecordKind_Principal, Asset_Eth, settlement.OperatorShare)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func getBlockTime(client HeaderClient, cache map[uint64]time.Time, blockNumber uint64) (time.Time, error) {
+	if blockTime, exists := cache[blockNumber]; exists {
+		return blockTime, nil
+	}
+	header, err := client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return time.Time{}, err
+	}
+	blockTime := time.Unix(int64(header.Time), 0).UTC()
+	cache[blockNumber] = blockTime
+	return blockTime, nil
+}
@@ -0,0 +1,106 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/indexer"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Open the index of the operator's contract events and bring it up to date, returning the block it's synced to
+func SyncOperatorIndex(c *cli.Context, nodeAddress common.Address, operatorId *big.Int, operatorInfo types.OperatorInfo, validators map[types.ValidatorPubkey]contracts.Validator) (*indexer.Indexer, uint64, error) {
+	cfg, err := GetConfig(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	ec, err := GetEthClient(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	pnr, err := GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	addresses := indexer.OperatorContracts{
+		ValidatorWithdrawVaults: map[common.Address]uint64{},
+		StartBlock:              cfg.StaderNode.GetLedgerStartBlock(),
+	}
+	addresses.PermissionlessNodeRegistry, err = GetPermissionlessNodeRegistryAddress(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	addresses.SocializingPool, err = GetSocializingPoolAddress(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	addresses.SdCollateral, err = GetSdCollateralAddress(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	addresses.Penalty, err = GetPenaltyTrackerAddress(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	addresses.OperatorRewardsCollector, err = GetOperatorRewardsCollectorAddress(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Operators in the socializing pool don't get a vault of their own
+	if !operatorInfo.OptedForSocializingPool {
+		addresses.NodeElRewardVault, err = node.GetNodeElRewardAddress(pnr, 1, operatorId, nil)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	pubkeys := make([]types.ValidatorPubkey, 0, len(validators))
+	for pubkey, validator := range validators {
+		pubkeys = append(pubkeys, pubkey)
+		// A vault can't have paid anything out before its validator was deposited
+		if validator.DepositBlock == nil || validator.DepositBlock.Sign() == 0 {
+			continue
+		}
+		addresses.ValidatorWithdrawVaults[validator.WithdrawVaultAddress] = validator.DepositBlock.Uint64()
+	}
+
+	eventLogInterval, err := cfg.GetEventLogInterval()
+	if err != nil {
+		return nil, 0, err
+	}
+	filter := indexer.NewOperatorFilter(nodeAddress, operatorInfo.OperatorRewardAddress, operatorId, pubkeys)
+	ix, err := indexer.NewOperatorIndexer(ec, cfg.StaderNode.GetOperatorIndexPath(), uint64(eventLogInterval), addresses, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error opening the operator's event index: %w", err)
+	}
+	synced, err := ix.Sync(context.Background())
+	if err != nil {
+		return nil, 0, fmt.Errorf("error indexing the operator's events: %w", err)
+	}
+	return ix, synced, nil
+}
@@ -0,0 +1,308 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package performance
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Config
+const (
+	FileMode = 0600
+	DirMode  = 0700
+
+	// Keep about a week of per-epoch records
+	MaxEpochHistory = 225 * 7
+
+	// The guardian writes the database and the metrics exporter reads it, so it is only held open for one transaction at a time
+	openTimeout = 10 * time.Second
+)
+
+var (
+	epochsBucket = []byte("epochs")
+	totalsBucket = []byte("totals")
+	metaBucket   = []byte("meta")
+
+	lastEpochKey = []byte("lastEpoch")
+)
+
+// How a validator performed its duties in one epoch
+type ValidatorDuties struct {
+	Index  uint64                `json:"index"`
+	Pubkey types.ValidatorPubkey `json:"pubkey"`
+
+	// Attestation; the delay is the number of slots between the attestation's slot and its first inclusion
+	AttestationAssigned bool   `json:"attestationAssigned"`
+	AttestationIncluded bool   `json:"attestationIncluded"`
+	InclusionDelay      uint64 `json:"inclusionDelay"`
+	HeadCorrect         bool   `json:"headCorrect"`
+	TargetCorrect       bool   `json:"targetCorrect"`
+
+	// Block proposals
+	ProposalsAssigned uint64 `json:"proposalsAssigned"`
+	ProposalsMissed   uint64 `json:"proposalsMissed"`
+
+	// Sync committee signatures, counted over the epoch's blocks
+	SyncAssigned uint64 `json:"syncAssigned"`
+	SyncMissed   uint64 `json:"syncMissed"`
+}
+
+// The duties of every tracked validator in one epoch
+type EpochRecord struct {
+	Epoch       uint64                     `json:"epoch"`
+	ProcessedAt time.Time                  `json:"processedAt"`
+	Validators  map[uint64]ValidatorDuties `json:"validators"`
+	// Electra attestations for the epoch that covered a committee the beacon node didn't report, so any tracked seats after it couldn't be checked
+	UnplacedAttestations uint64 `json:"unplacedAttestations,omitempty"`
+}
+
+// A validator's duties summed over every epoch it was tracked in
+type ValidatorTotals struct {
+	Index     uint64                `json:"index"`
+	Pubkey    types.ValidatorPubkey `json:"pubkey"`
+	LastEpoch uint64                `json:"lastEpoch"`
+
+	AttestationsAssigned uint64 `json:"attestationsAssigned"`
+	AttestationsIncluded uint64 `json:"attestationsIncluded"`
+	AttestationsMissed   uint64 `json:"attestationsMissed"`
+	HeadCorrect          uint64 `json:"headCorrect"`
+	TargetCorrect        uint64 `json:"targetCorrect"`
+	InclusionDelaySum    uint64 `json:"inclusionDelaySum"`
+
+	ProposalsAssigned uint64 `json:"proposalsAssigned"`
+	ProposalsMissed   uint64 `json:"proposalsMissed"`
+
+	SyncAssigned uint64 `json:"syncAssigned"`
+	SyncMissed   uint64 `json:"syncMissed"`
+
+	// The duties of the last epoch the validator was tracked in
+	Latest ValidatorDuties `json:"latest"`
+}
+
+// Add an epoch's duties to the totals
+func (t *ValidatorTotals) add(epoch uint64, duties ValidatorDuties) {
+	t.Index = duties.Index
+	t.Pubkey = duties.Pubkey
+	t.LastEpoch = epoch
+	t.Latest = duties
+
+	if duties.AttestationAssigned {
+		t.AttestationsAssigned++
+		if duties.AttestationIncluded {
+			t.AttestationsIncluded++
+			t.InclusionDelaySum += duties.InclusionDelay
+		} else {
+			t.AttestationsMissed++
+		}
+		if duties.HeadCorrect {
+			t.HeadCorrect++
+		}
+		if duties.TargetCorrect {
+			t.TargetCorrect++
+		}
+	}
+	t.ProposalsAssigned += duties.ProposalsAssigned
+	t.ProposalsMissed += duties.ProposalsMissed
+	t.SyncAssigned += duties.SyncAssigned
+	t.SyncMissed += duties.SyncMissed
+}
+
+// Local store of validator duty performance, kept under the data path
+type Database struct {
+	path string
+}
+
+// Create a new performance database at the path; the file is created on the first write
+func NewDatabase(path string) *Database {
+	return &Database{
+		path: path,
+	}
+}
+
+// Get the last epoch that was recorded; false if none has been
+func (db *Database) GetLastEpoch() (uint64, bool, error) {
+	var lastEpoch uint64
+	found := false
+	err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(metaBucket)
+		if bucket == nil {
+			return nil
+		}
+		if data := bucket.Get(lastEpochKey); data != nil {
+			lastEpoch = binary.BigEndian.Uint64(data)
+			found = true
+		}
+		return nil
+	})
+	return lastEpoch, found, err
+}
+
+// Get the totals of every validator that has been tracked, keyed by validator index
+func (db *Database) GetTotals() (map[uint64]ValidatorTotals, error) {
+	totals := map[uint64]ValidatorTotals{}
+	err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(totalsBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var validatorTotals ValidatorTotals
+			if err := json.Unmarshal(v, &validatorTotals); err != nil {
+				return fmt.Errorf("Could not decode performance totals for validator %d: %w", binary.BigEndian.Uint64(k), err)
+			}
+			totals[validatorTotals.Index] = validatorTotals
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// Get the record of an epoch; false if it isn't in the database
+func (db *Database) GetEpoch(epoch uint64) (EpochRecord, bool, error) {
+	var record EpochRecord
+	found := false
+	err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(epochsBucket)
+		if bucket == nil {
+			return nil
+		}
+		data := bucket.Get(uint64Key(epoch))
+		if data == nil {
+			return nil
+		}
+		found = true
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("Could not decode performance record for epoch %d: %w", epoch, err)
+		}
+		return nil
+	})
+	return record, found, err
+}
+
+// Store an epoch's record, add it to the validator totals and drop records past the history limit.
+// Epochs at or before the last recorded one are ignored so totals aren't counted twice.
+func (db *Database) RecordEpoch(record EpochRecord) error {
+	if err := os.MkdirAll(filepath.Dir(db.path), DirMode); err != nil {
+		return fmt.Errorf("Could not create performance database directory: %w", err)
+	}
+
+	bdb, err := bolt.Open(db.path, FileMode, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return fmt.Errorf("Could not open performance database %s: %w", db.path, err)
+	}
+	defer bdb.Close()
+
+	return bdb.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		epochs, err := tx.CreateBucketIfNotExists(epochsBucket)
+		if err != nil {
+			return err
+		}
+		totals, err := tx.CreateBucketIfNotExists(totalsBucket)
+		if err != nil {
+			return err
+		}
+
+		if data := meta.Get(lastEpochKey); data != nil && binary.BigEndian.Uint64(data) >= record.Epoch {
+			return nil
+		}
+
+		// Store the epoch
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("Could not encode performance record for epoch %d: %w", record.Epoch, err)
+		}
+		if err := epochs.Put(uint64Key(record.Epoch), data); err != nil {
+			return err
+		}
+
+		// Update the totals
+		for index, duties := range record.Validators {
+			var validatorTotals ValidatorTotals
+			if data := totals.Get(uint64Key(index)); data != nil {
+				if err := json.Unmarshal(data, &validatorTotals); err != nil {
+					return fmt.Errorf("Could not decode performance totals for validator %d: %w", index, err)
+				}
+			}
+			validatorTotals.add(record.Epoch, duties)
+			data, err := json.Marshal(validatorTotals)
+			if err != nil {
+				return fmt.Errorf("Could not encode performance totals for validator %d: %w", index, err)
+			}
+			if err := totals.Put(uint64Key(index), data); err != nil {
+				return err
+			}
+		}
+
+		// Drop old epochs; keys are big-endian so the cursor walks them in order
+		if record.Epoch >= MaxEpochHistory {
+			cutoff := record.Epoch - MaxEpochHistory
+			oldKeys := [][]byte{}
+			cursor := epochs.Cursor()
+			for k, _ := cursor.First(); k != nil && binary.BigEndian.Uint64(k) <= cutoff; k, _ = cursor.Next() {
+				oldKeys = append(oldKeys, append([]byte{}, k...))
+			}
+			for _, k := range oldKeys {
+				if err := epochs.Delete(k); err != nil {
+					return err
+				}
+			}
+		}
+
+		return meta.Put(lastEpochKey, uint64Key(record.Epoch))
+	})
+}
+
+// Run a read transaction, treating a missing database as empty
+func (db *Database) view(fn func(tx *bolt.Tx) error) error {
+	if _, err := os.Stat(db.path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	bdb, err := bolt.Open(db.path, FileMode, &bolt.Options{Timeout: openTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("Could not open performance database %s: %w", db.path, err)
+	}
+	defer bdb.Close()
+
+	return bdb.View(fn)
+}
+
+// Encode an epoch or validator index as a database key
+func uint64Key(value uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, value)
+	return key
+}
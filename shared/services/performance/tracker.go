@@ -0,0 +1,278 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package performance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Config
+const (
+	blockThreadLimit = 8
+)
+
+// A tracked validator's seat in an attestation committee
+type committeeSeat struct {
+	index    uint64
+	position uint64
+}
+
+// Works out how the node's validators performed their duties in each epoch
+type Tracker struct {
+	bc            beacon.Client
+	slotsPerEpoch uint64
+}
+
+// Create a new tracker
+func NewTracker(bc beacon.Client, slotsPerEpoch uint64) *Tracker {
+	return &Tracker{
+		bc:            bc,
+		slotsPerEpoch: slotsPerEpoch,
+	}
+}
+
+// Get the most recent epoch whose attestations can no longer be included, given the current head epoch
+func GetLastCompleteEpoch(headEpoch uint64) (uint64, bool) {
+	// Attestations can be included until the end of the epoch after theirs
+	if headEpoch < 2 {
+		return 0, false
+	}
+	return headEpoch - 2, true
+}
+
+// Work out the duties of the given validators in an epoch.
+// The epoch should be complete (see GetLastCompleteEpoch) so late inclusions aren't counted as misses.
+func (t *Tracker) ProcessEpoch(ctx context.Context, epoch uint64, validators map[uint64]types.ValidatorPubkey) (EpochRecord, error) {
+	record := EpochRecord{
+		Epoch:       epoch,
+		ProcessedAt: time.Now(),
+		Validators:  map[uint64]ValidatorDuties{},
+	}
+	if len(validators) == 0 {
+		return record, nil
+	}
+
+	indices := make([]uint64, 0, len(validators))
+	for index, pubkey := range validators {
+		indices = append(indices, index)
+		record.Validators[index] = ValidatorDuties{
+			Index:  index,
+			Pubkey: pubkey,
+		}
+	}
+
+	// Get the duties
+	committees, err := t.bc.GetCommitteesForEpoch(ctx, &epoch)
+	if err != nil {
+		return EpochRecord{}, fmt.Errorf("Could not get committees for epoch %d: %w", epoch, err)
+	}
+	proposers, err := t.bc.GetEpochProposers(ctx, epoch)
+	if err != nil {
+		return EpochRecord{}, fmt.Errorf("Could not get proposers for epoch %d: %w", epoch, err)
+	}
+	syncPositions, err := t.bc.GetSyncCommitteePositions(ctx, indices, epoch)
+	if err != nil {
+		return EpochRecord{}, fmt.Errorf("Could not get sync committee positions for epoch %d: %w", epoch, err)
+	}
+
+	// Get the blocks of this epoch and the next, where its attestations can be included
+	firstSlot := epoch * t.slotsPerEpoch
+	blocks, err := t.getBlocks(ctx, firstSlot, firstSlot+2*t.slotsPerEpoch)
+	if err != nil {
+		return EpochRecord{}, err
+	}
+	blocksBySlot := make(map[uint64]beacon.BeaconBlock, len(blocks))
+	for _, block := range blocks {
+		blocksBySlot[block.Slot] = block
+	}
+
+	record.UnplacedAttestations = t.processAttestations(record.Validators, epoch, committees, blocks)
+	t.processProposals(record.Validators, proposers, blocksBySlot)
+	t.processSyncCommittee(record.Validators, firstSlot, syncPositions, blocksBySlot)
+
+	return record, nil
+}
+
+// Check the inclusion and votes of the validators' attestations.
+// Returns the number of attestations whose seats couldn't all be placed.
+func (t *Tracker) processAttestations(duties map[uint64]ValidatorDuties, epoch uint64, committees []beacon.Committee, blocks []beacon.BeaconBlock) uint64 {
+	firstSlot := epoch * t.slotsPerEpoch
+	lastSlot := firstSlot + t.slotsPerEpoch - 1
+
+	// Find the seats of tracked validators and the size of every committee, keyed by slot and committee index
+	seats := map[[2]uint64][]committeeSeat{}
+	committeeSizes := map[[2]uint64]uint64{}
+	for _, committee := range committees {
+		if committee.Slot < firstSlot || committee.Slot > lastSlot {
+			continue
+		}
+		key := [2]uint64{committee.Slot, committee.Index}
+		committeeSizes[key] = uint64(len(committee.Validators))
+		for position, index := range committee.Validators {
+			validatorDuties, tracked := duties[index]
+			if !tracked {
+				continue
+			}
+			validatorDuties.AttestationAssigned = true
+			duties[index] = validatorDuties
+			seats[key] = append(seats[key], committeeSeat{index: index, position: uint64(position)})
+		}
+	}
+
+	// Check the attestations in each block; blocks are in slot order so the first inclusion is the earliest
+	unplaced := uint64(0)
+	for _, block := range blocks {
+		for _, attestation := range block.Attestations {
+			if attestation.SlotIndex < firstSlot || attestation.SlotIndex > lastSlot {
+				continue
+			}
+
+			// Pre-Electra, the aggregation bits only cover the committee in the attestation data
+			if attestation.CommitteeBits == nil {
+				for _, seat := range seats[[2]uint64{attestation.SlotIndex, attestation.CommitteeIndex}] {
+					t.checkSeat(duties, seat, seat.position, epoch, attestation, block, blocks)
+				}
+				continue
+			}
+
+			// Since Electra, they cover each committee in the committee bits back to back, in index order
+			offset := uint64(0)
+			for _, committeeIndex := range attestation.CommitteeBits.BitIndices() {
+				key := [2]uint64{attestation.SlotIndex, uint64(committeeIndex)}
+				size, known := committeeSizes[key]
+				if !known {
+					// The offsets of any later committees can't be worked out
+					unplaced++
+					break
+				}
+				for _, seat := range seats[key] {
+					t.checkSeat(duties, seat, offset+seat.position, epoch, attestation, block, blocks)
+				}
+				offset += size
+			}
+		}
+	}
+	return unplaced
+}
+
+// Record a validator's attestation as included if it's the first inclusion of its aggregation bit
+func (t *Tracker) checkSeat(duties map[uint64]ValidatorDuties, seat committeeSeat, bit uint64, epoch uint64, attestation beacon.AttestationInfo, block beacon.BeaconBlock, blocks []beacon.BeaconBlock) {
+	validatorDuties := duties[seat.index]
+	if validatorDuties.AttestationIncluded || bit >= attestation.AggregationBits.Len() || !attestation.AggregationBits.BitAt(bit) {
+		return
+	}
+
+	firstSlot := epoch * t.slotsPerEpoch
+	validatorDuties.AttestationIncluded = true
+	validatorDuties.InclusionDelay = block.Slot - attestation.SlotIndex
+	headRoot, headKnown := getCanonicalRoot(blocks, attestation.SlotIndex)
+	validatorDuties.HeadCorrect = headKnown && attestation.BeaconBlockRoot == headRoot
+	targetRoot, targetKnown := getCanonicalRoot(blocks, firstSlot)
+	validatorDuties.TargetCorrect = targetKnown && attestation.TargetEpoch == epoch && attestation.TargetRoot == targetRoot
+	duties[seat.index] = validatorDuties
+}
+
+// Check which of the validators' proposal slots have a block from them
+func (t *Tracker) processProposals(duties map[uint64]ValidatorDuties, proposers map[uint64]uint64, blocksBySlot map[uint64]beacon.BeaconBlock) {
+	for slot, index := range proposers {
+		validatorDuties, tracked := duties[index]
+		if !tracked {
+			continue
+		}
+		validatorDuties.ProposalsAssigned++
+		if block, exists := blocksBySlot[slot]; !exists || block.ProposerIndex != index {
+			validatorDuties.ProposalsMissed++
+		}
+		duties[index] = validatorDuties
+	}
+}
+
+// Check the validators' sync committee signatures in each of the epoch's blocks; empty slots don't count against them
+func (t *Tracker) processSyncCommittee(duties map[uint64]ValidatorDuties, firstSlot uint64, syncPositions map[uint64][]uint64, blocksBySlot map[uint64]beacon.BeaconBlock) {
+	for slot := firstSlot; slot < firstSlot+t.slotsPerEpoch; slot++ {
+		block, exists := blocksBySlot[slot]
+		if !exists || block.SyncCommitteeBits == nil {
+			continue
+		}
+		for index, positions := range syncPositions {
+			validatorDuties, tracked := duties[index]
+			if !tracked {
+				continue
+			}
+			for _, position := range positions {
+				validatorDuties.SyncAssigned++
+				if position >= block.SyncCommitteeBits.Len() || !block.SyncCommitteeBits.BitAt(position) {
+					validatorDuties.SyncMissed++
+				}
+			}
+			duties[index] = validatorDuties
+		}
+	}
+}
+
+// Get the blocks in a range of slots, in slot order; empty slots are left out
+func (t *Tracker) getBlocks(ctx context.Context, startSlot uint64, endSlot uint64) ([]beacon.BeaconBlock, error) {
+	blocks := make([]beacon.BeaconBlock, endSlot-startSlot)
+	exists := make([]bool, endSlot-startSlot)
+
+	var wg errgroup.Group
+	wg.SetLimit(blockThreadLimit)
+	for slot := startSlot; slot < endSlot; slot++ {
+		slot := slot
+		wg.Go(func() error {
+			block, found, err := t.bc.GetBeaconBlock(ctx, fmt.Sprint(slot))
+			if err != nil {
+				return fmt.Errorf("Could not get beacon block for slot %d: %w", slot, err)
+			}
+			blocks[slot-startSlot] = block
+			exists[slot-startSlot] = found
+			return nil
+		})
+	}
+	if err := wg.Wait(); err != nil {
+		return nil, err
+	}
+
+	existingBlocks := make([]beacon.BeaconBlock, 0, len(blocks))
+	for i, block := range blocks {
+		if exists[i] {
+			existingBlocks = append(existingBlocks, block)
+		}
+	}
+	return existingBlocks, nil
+}
+
+// Get the root of the canonical block at a slot (the latest block at or before it), which is the parent of the first block after it
+func getCanonicalRoot(blocks []beacon.BeaconBlock, slot uint64) (common.Hash, bool) {
+	for _, block := range blocks {
+		if block.Slot > slot {
+			return block.ParentRoot, true
+		}
+	}
+	return common.Hash{}, false
+}
@@ -0,0 +1,139 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package performance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/go-bitfield"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/beacon/beacontest"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+const (
+	testSlotsPerEpoch = 4
+	testEpoch         = 2
+	testSlot          = testEpoch * testSlotsPerEpoch
+	trackedIndex      = 100
+)
+
+var testHeadRoot = common.HexToHash("0x01")
+
+// A chain where the tracked validator sits at position 1 of committee 1 in the epoch's first slot,
+// and the next block includes the given attestation for that slot
+func newAttestationTest(attestation beacon.AttestationInfo) *beacontest.MockClient {
+	bc := beacontest.NewMockClient()
+	bc.Committees[testEpoch] = []beacon.Committee{
+		{Slot: testSlot, Index: 0, Validators: []uint64{1, 2, 3}},
+		{Slot: testSlot, Index: 1, Validators: []uint64{4, trackedIndex, 5}},
+		{Slot: testSlot, Index: 2, Validators: []uint64{6, 7}},
+	}
+
+	attestation.SlotIndex = testSlot
+	attestation.BeaconBlockRoot = testHeadRoot
+	attestation.TargetEpoch = testEpoch
+	attestation.TargetRoot = testHeadRoot
+	bc.Blocks[testSlot] = beacon.BeaconBlock{Slot: testSlot}
+	bc.Blocks[testSlot+1] = beacon.BeaconBlock{
+		Slot:         testSlot + 1,
+		ParentRoot:   testHeadRoot,
+		Attestations: []beacon.AttestationInfo{attestation},
+	}
+	return bc
+}
+
+// Make aggregation bits of the given length with the given bits set
+func newAggregationBits(length uint64, bits ...uint64) bitfield.Bitlist {
+	aggregationBits := bitfield.NewBitlist(length)
+	for _, bit := range bits {
+		aggregationBits.SetBitAt(bit, true)
+	}
+	return aggregationBits
+}
+
+// Make committee bits with the given committees set
+func newCommitteeBits(committees ...uint64) bitfield.Bitvector64 {
+	committeeBits := bitfield.NewBitvector64()
+	for _, committee := range committees {
+		committeeBits.SetBitAt(committee, true)
+	}
+	return committeeBits
+}
+
+func TestProcessEpochAttestations(t *testing.T) {
+	tests := []struct {
+		name         string
+		attestation  beacon.AttestationInfo
+		wantIncluded bool
+	}{
+		{
+			name:         "pre-Electra in the validator's committee",
+			attestation:  beacon.AttestationInfo{CommitteeIndex: 1, AggregationBits: newAggregationBits(3, 1)},
+			wantIncluded: true,
+		},
+		{
+			name:        "pre-Electra in another committee",
+			attestation: beacon.AttestationInfo{CommitteeIndex: 0, AggregationBits: newAggregationBits(3, 1)},
+		},
+		{
+			name:         "Electra with only the validator's committee",
+			attestation:  beacon.AttestationInfo{CommitteeBits: newCommitteeBits(1), AggregationBits: newAggregationBits(3, 1)},
+			wantIncluded: true,
+		},
+		{
+			name:         "Electra after committee 0",
+			attestation:  beacon.AttestationInfo{CommitteeBits: newCommitteeBits(0, 1, 2), AggregationBits: newAggregationBits(8, 3+1)},
+			wantIncluded: true,
+		},
+		{
+			name:        "Electra with the same position in committee 0",
+			attestation: beacon.AttestationInfo{CommitteeBits: newCommitteeBits(0, 1), AggregationBits: newAggregationBits(6, 1)},
+		},
+		{
+			name:        "Electra without the validator's committee",
+			attestation: beacon.AttestationInfo{CommitteeBits: newCommitteeBits(0, 2), AggregationBits: newAggregationBits(5, 0, 1, 2, 3, 4)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tracker := NewTracker(newAttestationTest(test.attestation), testSlotsPerEpoch)
+			record, err := tracker.ProcessEpoch(context.Background(), testEpoch, map[uint64]types.ValidatorPubkey{trackedIndex: {}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			duties := record.Validators[trackedIndex]
+			if !duties.AttestationAssigned {
+				t.Error("attestation duty not assigned")
+			}
+			if duties.AttestationIncluded != test.wantIncluded {
+				t.Fatalf("attestation included is %t, want %t", duties.AttestationIncluded, test.wantIncluded)
+			}
+			if test.wantIncluded && (duties.InclusionDelay != 1 || !duties.HeadCorrect || !duties.TargetCorrect) {
+				t.Errorf("got inclusion delay %d, head correct %t, target correct %t; want 1, true, true", duties.InclusionDelay, duties.HeadCorrect, duties.TargetCorrect)
+			}
+		})
+	}
+}
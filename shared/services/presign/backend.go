@@ -0,0 +1,199 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package presign
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	stader_backend "github.com/stader-labs/stader-node/shared/types/stader-backend"
+	"github.com/stader-labs/stader-node/shared/utils/crypto"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Config
+const (
+	RequestContentType = "application/json"
+
+	RequestPresignPath          = "/presign"
+	RequestBulkPresignPath      = "/presigns"
+	RequestPresignCheckPath     = "/msgSubmitted"
+	RequestBulkPresignCheckPath = "/presignsSubmitted"
+	RequestEncryptionKeyPath    = "/publicKey"
+	DefaultRequestTimeout       = 30 * time.Second
+	maxErrorResponseBodyLength  = 512
+	maxResponseBodyLength       = 10 * 1024 * 1024
+)
+
+// A service that stores presigned exit messages, so validators can be exited on the operator's behalf
+type PresignBackend interface {
+	// Get the key exit signatures are encrypted with before they are sent
+	GetPublicKey() (*rsa.PublicKey, error)
+	IsPresignedKeyRegistered(validatorPubKey types.ValidatorPubkey) (bool, error)
+	BulkIsPresignedKeyRegistered(validatorPubKeys []types.ValidatorPubkey) (map[string]bool, error)
+	SendPresignedMessage(preSignedMessage stader_backend.PreSignSendApiRequestType) (*stader_backend.PreSignSendApiResponseType, error)
+	SendBulkPresignedMessages(preSignedMessages []stader_backend.PreSignSendApiRequestType) (map[string]stader_backend.PreSignSendApiResponseType, error)
+}
+
+// Presign backend reached over HTTP, such as the Stader backend
+type HttpBackend struct {
+	baseUrl         string
+	pinnedPublicKey *rsa.PublicKey
+	httpClient      *http.Client
+}
+
+// Create a new HTTP backend; exit signatures are only ever encrypted with the pinned public key
+func NewHttpBackend(baseUrl string, pinnedPublicKey *rsa.PublicKey, timeout time.Duration) *HttpBackend {
+	return &HttpBackend{
+		baseUrl:         strings.TrimSuffix(baseUrl, "/"),
+		pinnedPublicKey: pinnedPublicKey,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Decode an encryption public key in the form it is pinned in the config: a base64 encoded PEM block
+func DecodePublicKey(encodedPublicKey string) (*rsa.PublicKey, error) {
+	decodedPublicKey, err := crypto.DecodeBase64(encodedPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Could not decode presign encryption key: %w", err)
+	}
+	publicKey, err := crypto.BytesToPublicKey(decodedPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse presign encryption key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// Encode an encryption public key as a base64 encoded PEM block
+func EncodePublicKey(publicKey *rsa.PublicKey) (string, error) {
+	publicKeyBytes, err := crypto.PublicKeyToBytes(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("Could not encode presign encryption key: %w", err)
+	}
+	return crypto.EncodeBase64(publicKeyBytes), nil
+}
+
+// Get the pinned encryption key, after checking the backend is still using it
+func (b *HttpBackend) GetPublicKey() (*rsa.PublicKey, error) {
+	var response stader_backend.PublicKeyApiResponse
+	if err := b.getRequest(RequestEncryptionKeyPath, &response); err != nil {
+		return nil, fmt.Errorf("Could not get the presign encryption key: %w", err)
+	}
+	serverPublicKey, err := DecodePublicKey(response.Value)
+	if err != nil {
+		return nil, err
+	}
+	if !serverPublicKey.Equal(b.pinnedPublicKey) {
+		return nil, fmt.Errorf("The presign backend at %s is using a different encryption key than the one in the Stader config; refusing to send exit messages to it", b.baseUrl)
+	}
+	return b.pinnedPublicKey, nil
+}
+
+// Check if the backend already has a presigned message for a validator
+func (b *HttpBackend) IsPresignedKeyRegistered(validatorPubKey types.ValidatorPubkey) (bool, error) {
+	var response stader_backend.PreSignCheckApiResponseType
+	err := b.postRequest(RequestPresignCheckPath, stader_backend.PreSignCheckApiRequestType{
+		ValidatorPublicKey: validatorPubKey.String(),
+	}, &response)
+	if err != nil {
+		return false, fmt.Errorf("Could not check presigned message for validator %s: %w", validatorPubKey, err)
+	}
+	return response.Value, nil
+}
+
+// Check which validators the backend already has a presigned message for
+func (b *HttpBackend) BulkIsPresignedKeyRegistered(validatorPubKeys []types.ValidatorPubkey) (map[string]bool, error) {
+	var response stader_backend.BulkPreSignCheckApiResponseType
+	err := b.postRequest(RequestBulkPresignCheckPath, stader_backend.BulkPreSignCheckApiRequestType{
+		ValidatorPubKeys: validatorPubKeys,
+	}, &response)
+	if err != nil {
+		return nil, fmt.Errorf("Could not check presigned messages: %w", err)
+	}
+	return response, nil
+}
+
+// Send a presigned message to the backend
+func (b *HttpBackend) SendPresignedMessage(preSignedMessage stader_backend.PreSignSendApiRequestType) (*stader_backend.PreSignSendApiResponseType, error) {
+	var response stader_backend.PreSignSendApiResponseType
+	if err := b.postRequest(RequestPresignPath, preSignedMessage, &response); err != nil {
+		return nil, fmt.Errorf("Could not send presigned message for validator %s: %w", preSignedMessage.ValidatorPublicKey, err)
+	}
+	return &response, nil
+}
+
+// Send a batch of presigned messages to the backend, getting the result for each validator
+func (b *HttpBackend) SendBulkPresignedMessages(preSignedMessages []stader_backend.PreSignSendApiRequestType) (map[string]stader_backend.PreSignSendApiResponseType, error) {
+	var response stader_backend.BulkPreSignSendApiResponseType
+	if err := b.postRequest(RequestBulkPresignPath, preSignedMessages, &response); err != nil {
+		return nil, fmt.Errorf("Could not send presigned messages: %w", err)
+	}
+	return response, nil
+}
+
+// Make a GET request to the backend and decode the response
+func (b *HttpBackend) getRequest(requestPath string, response interface{}) error {
+	httpResponse, err := b.httpClient.Get(b.baseUrl + requestPath)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(httpResponse, response)
+}
+
+// Make a POST request to the backend and decode the response
+func (b *HttpBackend) postRequest(requestPath string, requestBody interface{}, response interface{}) error {
+	requestBodyBytes, err := json.Marshal(requestBody)
+	if err != nil {
+		return fmt.Errorf("Could not encode request body: %w", err)
+	}
+	httpResponse, err := b.httpClient.Post(b.baseUrl+requestPath, RequestContentType, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return err
+	}
+	return decodeResponse(httpResponse, response)
+}
+
+// Check the status of a backend response and decode its body
+func decodeResponse(httpResponse *http.Response, response interface{}) error {
+	defer httpResponse.Body.Close()
+	responseBody, err := ioutil.ReadAll(io.LimitReader(httpResponse.Body, maxResponseBodyLength))
+	if err != nil {
+		return fmt.Errorf("Could not read response body: %w", err)
+	}
+	if httpResponse.StatusCode < 200 || httpResponse.StatusCode > 299 {
+		if len(responseBody) > maxErrorResponseBodyLength {
+			responseBody = responseBody[:maxErrorResponseBodyLength]
+		}
+		return fmt.Errorf("HTTP status %d; response body: '%s'", httpResponse.StatusCode, string(responseBody))
+	}
+	if err := json.Unmarshal(responseBody, response); err != nil {
+		return fmt.Errorf("Could not decode response body: %w", err)
+	}
+	return nil
+}
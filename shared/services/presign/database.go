@@ -0,0 +1,226 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package presign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Config
+const (
+	FileMode        = 0600
+	DirMode         = 0700
+	MaxHistory      = 10
+	RetryBackoff    = time.Minute
+	MaxRetryBackoff = 24 * time.Hour
+
+	// The node daemon, guardian and API all open the database, so it is only held open for one transaction at a time
+	openTimeout = 10 * time.Second
+)
+
+var validatorsBucket = []byte("validators")
+
+// A single presigned exit message sent to the backend
+type Submission struct {
+	ExitEpoch      uint64    `json:"exitEpoch"`
+	ValidatorIndex uint64    `json:"validatorIndex"`
+	SubmittedAt    time.Time `json:"submittedAt"`
+	Accepted       bool      `json:"accepted"`
+	Response       string    `json:"response"`
+}
+
+// The presign history and retry state of a validator
+type ValidatorRecord struct {
+	Pubkey      types.ValidatorPubkey `json:"pubkey"`
+	Accepted    bool                  `json:"accepted"`
+	Retries     uint64                `json:"retries"`
+	NextAttempt time.Time             `json:"nextAttempt"`
+	Submissions []Submission          `json:"submissions"`
+}
+
+// Check if a new presigned message should be sent for the validator yet
+func (r ValidatorRecord) CanRetry(now time.Time) bool {
+	return !now.Before(r.NextAttempt)
+}
+
+// Local store of presign submissions, kept under the data path
+type Database struct {
+	path string
+}
+
+// Create a new presign database at the path; the file is created on the first write
+func NewDatabase(path string) *Database {
+	return &Database{
+		path: path,
+	}
+}
+
+// Get the delay before the next attempt after the given number of failed attempts
+func Backoff(retries uint64) time.Duration {
+	backoff := RetryBackoff
+	for i := uint64(1); i < retries; i++ {
+		backoff *= 2
+		if backoff >= MaxRetryBackoff {
+			return MaxRetryBackoff
+		}
+	}
+	return backoff
+}
+
+// Get the records of every validator in the database
+func (db *Database) GetRecords() (map[types.ValidatorPubkey]ValidatorRecord, error) {
+	records := map[types.ValidatorPubkey]ValidatorRecord{}
+
+	// Nothing has been submitted yet
+	if _, err := os.Stat(db.path); errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+
+	bdb, err := bolt.Open(db.path, FileMode, &bolt.Options{Timeout: openTimeout, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("Could not open presign database %s: %w", db.path, err)
+	}
+	defer bdb.Close()
+
+	err = bdb.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(validatorsBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var record ValidatorRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("Could not decode presign record for %s: %w", types.BytesToValidatorPubkey(k).Hex(), err)
+			}
+			records[record.Pubkey] = record
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Record a batch of submissions and update the retry state of each validator
+func (db *Database) RecordSubmissions(submissions map[types.ValidatorPubkey]Submission) error {
+	return db.update(func(bucket *bolt.Bucket) error {
+		for pubkey, submission := range submissions {
+			record, err := getRecord(bucket, pubkey)
+			if err != nil {
+				return err
+			}
+
+			record.Submissions = append(record.Submissions, submission)
+			if len(record.Submissions) > MaxHistory {
+				record.Submissions = record.Submissions[len(record.Submissions)-MaxHistory:]
+			}
+			if submission.Accepted {
+				record.Accepted = true
+				record.Retries = 0
+				record.NextAttempt = time.Time{}
+			} else {
+				record.Accepted = false
+				record.Retries++
+				record.NextAttempt = submission.SubmittedAt.Add(Backoff(record.Retries))
+			}
+
+			if err := putRecord(bucket, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Mark validators the backend reports as already registered, such as keys presigned before the database existed
+func (db *Database) MarkAccepted(pubkeys []types.ValidatorPubkey) error {
+	return db.update(func(bucket *bolt.Bucket) error {
+		for _, pubkey := range pubkeys {
+			record, err := getRecord(bucket, pubkey)
+			if err != nil {
+				return err
+			}
+			if record.Accepted {
+				continue
+			}
+
+			record.Accepted = true
+			record.Retries = 0
+			record.NextAttempt = time.Time{}
+			if err := putRecord(bucket, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Run a write transaction against the validators bucket
+func (db *Database) update(fn func(bucket *bolt.Bucket) error) error {
+	if err := os.MkdirAll(filepath.Dir(db.path), DirMode); err != nil {
+		return fmt.Errorf("Could not create presign database directory: %w", err)
+	}
+
+	bdb, err := bolt.Open(db.path, FileMode, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return fmt.Errorf("Could not open presign database %s: %w", db.path, err)
+	}
+	defer bdb.Close()
+
+	return bdb.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(validatorsBucket)
+		if err != nil {
+			return err
+		}
+		return fn(bucket)
+	})
+}
+
+// Get the record of a validator, or a new record if it has none
+func getRecord(bucket *bolt.Bucket, pubkey types.ValidatorPubkey) (ValidatorRecord, error) {
+	data := bucket.Get(pubkey.Bytes())
+	if data == nil {
+		return ValidatorRecord{Pubkey: pubkey}, nil
+	}
+
+	var record ValidatorRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return ValidatorRecord{}, fmt.Errorf("Could not decode presign record for %s: %w", pubkey.Hex(), err)
+	}
+	return record, nil
+}
+
+// Store the record of a validator
+func putRecord(bucket *bolt.Bucket, record ValidatorRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("Could not encode presign record for %s: %w", record.Pubkey.Hex(), err)
+	}
+	return bucket.Put(record.Pubkey.Bytes(), data)
+}
@@ -0,0 +1,230 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package presigntest
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/presign"
+	stader_backend "github.com/stader-labs/stader-node/shared/types/stader-backend"
+	"github.com/stader-labs/stader-node/shared/utils/crypto"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// The beacon queries the mock backend needs to check an exit message
+type ExitVerificationClient interface {
+	GetValidatorStatusByIndex(ctx context.Context, index string, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error)
+	GetDomainData(ctx context.Context, domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error)
+}
+
+// In-process stand-in for the Stader presign backend.
+// It decrypts each exit message and only accepts it if the signature is valid for the validator on the beacon chain.
+type MockServer struct {
+	privateKey *rsa.PrivateKey
+	bc         ExitVerificationClient
+	server     *httptest.Server
+
+	registered map[string]types.ValidatorSignature
+	lock       sync.Mutex
+}
+
+// Create a new mock backend that decrypts exit messages with the private key
+func NewMockServer(privateKey *rsa.PrivateKey, bc ExitVerificationClient) *MockServer {
+	return &MockServer{
+		privateKey: privateKey,
+		bc:         bc,
+		registered: map[string]types.ValidatorSignature{},
+	}
+}
+
+// Start serving the backend API on a local port, returning its URL
+func (m *MockServer) Start() string {
+	m.server = httptest.NewServer(m)
+	return m.server.URL
+}
+
+// Stop serving the backend API
+func (m *MockServer) Close() {
+	if m.server != nil {
+		m.server.Close()
+	}
+}
+
+// Get the exit signature the backend accepted for a validator
+func (m *MockServer) GetExitSignature(validatorPubKey types.ValidatorPubkey) (types.ValidatorSignature, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	signature, ok := m.registered[validatorPubKey.String()]
+	return signature, ok
+}
+
+// Handle a backend API request
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case presign.RequestEncryptionKeyPath:
+		encodedPublicKey, err := presign.EncodePublicKey(&m.privateKey.PublicKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJson(w, stader_backend.PublicKeyApiResponse{Value: encodedPublicKey})
+
+	case presign.RequestPresignCheckPath:
+		var request stader_backend.PreSignCheckApiRequestType
+		if !readJson(w, r, &request) {
+			return
+		}
+		writeJson(w, stader_backend.PreSignCheckApiResponseType{Value: m.isRegistered(request.ValidatorPublicKey)})
+
+	case presign.RequestBulkPresignCheckPath:
+		var request stader_backend.BulkPreSignCheckApiRequestType
+		if !readJson(w, r, &request) {
+			return
+		}
+		response := stader_backend.BulkPreSignCheckApiResponseType{}
+		for _, validatorPubKey := range request.ValidatorPubKeys {
+			response[validatorPubKey.String()] = m.isRegistered(validatorPubKey.String())
+		}
+		writeJson(w, response)
+
+	case presign.RequestPresignPath:
+		var request stader_backend.PreSignSendApiRequestType
+		if !readJson(w, r, &request) {
+			return
+		}
+		writeJson(w, m.register(r.Context(), request))
+
+	case presign.RequestBulkPresignPath:
+		var request stader_backend.BulkPreSignSendApiRequestType
+		if !readJson(w, r, &request) {
+			return
+		}
+		response := stader_backend.BulkPreSignSendApiResponseType{}
+		for _, message := range request {
+			response[message.ValidatorPublicKey] = m.register(r.Context(), message)
+		}
+		writeJson(w, response)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Check if the backend has accepted an exit message for a validator
+func (m *MockServer) isRegistered(validatorPubKey string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, ok := m.registered[validatorPubKey]
+	return ok
+}
+
+// Check an exit message and store it if it's valid
+func (m *MockServer) register(ctx context.Context, message stader_backend.PreSignSendApiRequestType) stader_backend.PreSignSendApiResponseType {
+	signature, err := m.verify(ctx, message)
+	if err != nil {
+		return stader_backend.PreSignSendApiResponseType{Success: false, Error: err.Error()}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.registered[message.ValidatorPublicKey] = signature
+	return stader_backend.PreSignSendApiResponseType{Success: true}
+}
+
+// Decrypt an exit message and check its signature against the beacon chain
+func (m *MockServer) verify(ctx context.Context, message stader_backend.PreSignSendApiRequestType) (types.ValidatorSignature, error) {
+	validatorPubKey, err := types.HexToValidatorPubkey(message.ValidatorPublicKey)
+	if err != nil {
+		return types.ValidatorSignature{}, err
+	}
+	epoch, err := strconv.ParseUint(message.Message.Epoch, 10, 64)
+	if err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("invalid epoch %s", message.Message.Epoch)
+	}
+	validatorIndex, err := strconv.ParseUint(message.Message.ValidatorIndex, 10, 64)
+	if err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("invalid validator index %s", message.Message.ValidatorIndex)
+	}
+
+	// Decrypt the signature
+	encryptedSignature, err := crypto.DecodeBase64(message.Signature)
+	if err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("signature is not base64 encoded: %w", err)
+	}
+	decryptedSignature, err := crypto.DecryptUsingPrivateKey(encryptedSignature, m.privateKey)
+	if err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("could not decrypt signature: %w", err)
+	}
+	signature, err := types.HexToValidatorSignature(string(decryptedSignature))
+	if err != nil {
+		return types.ValidatorSignature{}, err
+	}
+
+	// Check the message is for the validator at that index
+	status, err := m.bc.GetValidatorStatusByIndex(ctx, message.Message.ValidatorIndex, nil)
+	if err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("could not get validator %d: %w", validatorIndex, err)
+	}
+	if !status.Exists || status.Pubkey != validatorPubKey {
+		return types.ValidatorSignature{}, fmt.Errorf("validator %d does not have pubkey %s", validatorIndex, validatorPubKey)
+	}
+
+	// Check the signature against the voluntary exit domain
+	signatureDomain, err := m.bc.GetDomainData(ctx, eth2types.DomainVoluntaryExit[:], epoch, false)
+	if err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("could not get the voluntary exit domain: %w", err)
+	}
+	if err := validator.VerifySignedExitMessage(validatorPubKey, validatorIndex, epoch, signatureDomain, signature); err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("invalid exit signature: %w", err)
+	}
+
+	return signature, nil
+}
+
+// Decode a JSON request body, replying with an error if it's invalid
+func readJson(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// Write a JSON response
+func writeJson(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", presign.RequestContentType)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -63,6 +63,9 @@ func RequireNodePassword(c *cli.Context) error {
 }
 
 func RequireNodeWallet(c *cli.Context) error {
+	if isWatchOnlyWallet(c) {
+		return nil
+	}
 	if err := RequireNodePassword(c); err != nil {
 		return err
 	}
@@ -220,6 +223,15 @@ func getNodeWalletInitialized(c *cli.Context) (bool, error) {
 	return w.GetInitialized()
 }
 
+// Check if the node wallet only knows the node address, with the keys held on an offline machine
+func isWatchOnlyWallet(c *cli.Context) bool {
+	w, err := GetWallet(c)
+	if err != nil {
+		return false
+	}
+	return w.IsWatchOnly()
+}
+
 // Check if the node is registered
 func isNodeRegistered(c *cli.Context) (bool, error) {
 	w, err := GetWallet(c)
@@ -491,7 +503,7 @@ func waitBeaconClientSynced(c *cli.Context, verbose bool, timeout int64) (bool,
 		}
 
 		// Get sync status
-		syncStatus, err := bcMgr.GetSyncStatus()
+		syncStatus, err := bcMgr.GetSyncStatus(context.Background())
 		if err != nil {
 			return false, err
 		}
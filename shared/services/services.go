@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package services
 
 import (
+	"crypto/rsa"
 	"fmt"
 	"math/big"
 	"os"
@@ -34,9 +35,15 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/doppelganger"
+	"github.com/stader-labs/stader-node/shared/services/ledger"
 	"github.com/stader-labs/stader-node/shared/services/passwords"
+	"github.com/stader-labs/stader-node/shared/services/performance"
+	"github.com/stader-labs/stader-node/shared/services/presign"
+	"github.com/stader-labs/stader-node/shared/services/txmanager"
 	"github.com/stader-labs/stader-node/shared/services/wallet"
 	lhkeystore "github.com/stader-labs/stader-node/shared/services/wallet/keystore/lighthouse"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
 
 	lokeystore "github.com/stader-labs/stader-node/shared/services/wallet/keystore/lodestar"
 	nmkeystore "github.com/stader-labs/stader-node/shared/services/wallet/keystore/nimbus"
@@ -59,6 +66,16 @@ var (
 	ecManager       *ExecutionClientManager
 	bcManager       *BeaconClientManager
 	docker          *client.Client
+	remoteSigner    *web3signer.Client
+	presignDb       *presign.Database
+	performanceDb   *performance.Database
+	presignBackend  presign.PresignBackend
+	doppelWatch     *doppelganger.Watch
+	ledgerStore     *ledger.Store
+
+	// Initializers that can fail keep their error, since they only run once
+	presignBackendErr error
+	dockerErr         error
 
 	initCfg             sync.Once
 	initPasswordManager sync.Once
@@ -66,6 +83,12 @@ var (
 	initECManager       sync.Once
 	initBCManager       sync.Once
 	initDocker          sync.Once
+	initRemoteSigner    sync.Once
+	initPresignDb       sync.Once
+	initPerformanceDb   sync.Once
+	initPresignBackend  sync.Once
+	initDoppelWatch     sync.Once
+	initLedgerStore     sync.Once
 )
 
 //
@@ -105,6 +128,14 @@ func GetEthClient(c *cli.Context) (*ExecutionClientManager, error) {
 	return ec, nil
 }
 
+func GetTxJournal(c *cli.Context) (*txmanager.Journal, error) {
+	ec, err := GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+	return ec.GetTxJournal(), nil
+}
+
 func GetStaderConfigContract(c *cli.Context) (*stader.StaderConfigContractManager, error) {
 	cfg, err := getConfig(c)
 	if err != nil {
@@ -434,6 +465,54 @@ func GetDocker(c *cli.Context) (*client.Client, error) {
 	return getDocker()
 }
 
+func GetRemoteSigner(c *cli.Context) (*web3signer.Client, error) {
+	cfg, err := getConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return getRemoteSigner(cfg)
+}
+
+func GetPresignDatabase(c *cli.Context) (*presign.Database, error) {
+	cfg, err := getConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return getPresignDatabase(cfg), nil
+}
+
+func GetPerformanceDatabase(c *cli.Context) (*performance.Database, error) {
+	cfg, err := getConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return getPerformanceDatabase(cfg), nil
+}
+
+func GetDoppelgangerWatch(c *cli.Context) (*doppelganger.Watch, error) {
+	cfg, err := getConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return getDoppelgangerWatch(cfg), nil
+}
+
+func GetLedgerStore(c *cli.Context) (*ledger.Store, error) {
+	cfg, err := getConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return getLedgerStore(cfg), nil
+}
+
+func GetPresignBackend(c *cli.Context) (presign.PresignBackend, error) {
+	cfg, err := getConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return getPresignBackend(cfg)
+}
+
 //
 // Service instance getters
 //
@@ -496,6 +575,19 @@ func getWallet(c *cli.Context, cfg *config.StaderConfig, pm *passwords.PasswordM
 		nodeWallet.AddKeystore("prysm", prysmKeystore)
 		nodeWallet.AddKeystore("teku", tekuKeystore)
 		nodeWallet.AddKeystore("lodestar", lodestarKeystore)
+		nodeWallet.SetHeldKeystore(lhkeystore.NewKeystore(os.ExpandEnv(cfg.StaderNode.GetDoppelgangerHeldKeysPath()), pm))
+
+		// Offline signing support
+		if nodeAddress := c.GlobalString("node-address"); nodeAddress != "" {
+			if !common.IsHexAddress(nodeAddress) {
+				err = fmt.Errorf("Invalid node address: %s", nodeAddress)
+				return
+			}
+			nodeWallet.SetWatchOnlyAddress(common.HexToAddress(nodeAddress))
+		}
+		if c.GlobalBool("export-unsigned-tx") {
+			nodeWallet.EnableUnsignedTransactionExport(c.GlobalUint64("gasLimit"))
+		}
 	})
 	return nodeWallet, err
 }
@@ -536,10 +628,59 @@ func getBeaconClient(c *cli.Context, cfg *config.StaderConfig) (*BeaconClientMan
 	return bcManager, err
 }
 
+func getRemoteSigner(cfg *config.StaderConfig) (*web3signer.Client, error) {
+	if cfg.EnableRemoteSigner.Value != true {
+		return nil, fmt.Errorf("The remote signer is not enabled.")
+	}
+	initRemoteSigner.Do(func() {
+		remoteSigner = web3signer.NewClient(cfg.RemoteSigner.Url.Value.(string), web3signer.DefaultRequestTimeout)
+	})
+	return remoteSigner, nil
+}
+
+func getPresignDatabase(cfg *config.StaderConfig) *presign.Database {
+	initPresignDb.Do(func() {
+		presignDb = presign.NewDatabase(cfg.StaderNode.GetPresignDatabasePath())
+	})
+	return presignDb
+}
+
+func getPerformanceDatabase(cfg *config.StaderConfig) *performance.Database {
+	initPerformanceDb.Do(func() {
+		performanceDb = performance.NewDatabase(cfg.StaderNode.GetPerformanceDatabasePath())
+	})
+	return performanceDb
+}
+
+func getDoppelgangerWatch(cfg *config.StaderConfig) *doppelganger.Watch {
+	initDoppelWatch.Do(func() {
+		doppelWatch = doppelganger.NewWatch(cfg.StaderNode.GetDoppelgangerWatchPath())
+	})
+	return doppelWatch
+}
+
+func getLedgerStore(cfg *config.StaderConfig) *ledger.Store {
+	initLedgerStore.Do(func() {
+		ledgerStore = ledger.NewStore(cfg.StaderNode.GetLedgerPath())
+	})
+	return ledgerStore
+}
+
+func getPresignBackend(cfg *config.StaderConfig) (presign.PresignBackend, error) {
+	initPresignBackend.Do(func() {
+		var pinnedPublicKey *rsa.PublicKey
+		pinnedPublicKey, presignBackendErr = presign.DecodePublicKey(cfg.StaderNode.GetPresignEncryptionKey())
+		if presignBackendErr != nil {
+			return
+		}
+		presignBackend = presign.NewHttpBackend(cfg.StaderNode.GetStaderBackendUrl(), pinnedPublicKey, presign.DefaultRequestTimeout)
+	})
+	return presignBackend, presignBackendErr
+}
+
 func getDocker() (*client.Client, error) {
-	var err error
 	initDocker.Do(func() {
-		docker, err = client.NewClientWithOpts(client.WithVersion(DockerAPIVersion))
+		docker, dockerErr = client.NewClientWithOpts(client.WithVersion(DockerAPIVersion))
 	})
-	return docker, err
+	return docker, dockerErr
 }
@@ -21,6 +21,7 @@ package stader
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -28,8 +29,14 @@ import (
 	"github.com/stader-labs/stader-node/shared/types/api"
 )
 
+// Returned instead of waiting when the transaction was exported for offline signing, since it hasn't been sent
+var ErrTransactionsExported = errors.New("The transactions were exported instead of being sent. Sign them on the machine that holds your wallet with `stader-cli node sign-transactions`, then send them from this node with `stader-cli node broadcast`.")
+
 // Wait for a transaction
 func (c *Client) WaitForTransaction(txHash common.Hash) (api.APIResponse, error) {
+	if c.IsExportingUnsignedTransactions() {
+		return api.APIResponse{}, ErrTransactionsExported
+	}
 	responseBytes, err := c.callAPI(fmt.Sprintf("wait %s", txHash.String()))
 	if err != nil {
 		return api.APIResponse{}, fmt.Errorf("Error waiting for tx: %w", err)
@@ -21,6 +21,7 @@ package stader
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -50,6 +51,7 @@ import (
 
 	"github.com/stader-labs/stader-node/shared/services/config"
 	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
+	"github.com/stader-labs/stader-node/shared/utils/offline"
 	staderUtils "github.com/stader-labs/stader-node/shared/utils/stdr"
 )
 
@@ -113,17 +115,28 @@ type Client struct {
 	debugPrint         bool
 	ignoreSyncCheck    bool
 	forceFallbacks     bool
+
+	// Offline signing support
+	nodeAddress          string
+	exportUnsignedTxPath string
+	exportedTransactions []offline.UnsignedTransaction
 }
 
 // Create new Stader client from CLI context
 func NewClientFromCtx(c *cli.Context) (*Client, error) {
-	return NewClient(c.GlobalString("config-path"),
+	client, err := NewClient(c.GlobalString("config-path"),
 		c.GlobalString("daemon-path"),
 		c.GlobalFloat64("maxFee"),
 		c.GlobalFloat64("maxPrioFee"),
 		c.GlobalUint64("gasLimit"),
 		c.GlobalString("nonce"),
 		c.GlobalBool("debug"))
+	if err != nil {
+		return nil, err
+	}
+	client.nodeAddress = c.GlobalString("node-address")
+	client.exportUnsignedTxPath = c.GlobalString("export-unsigned-tx")
+	return client, nil
 }
 
 // Create new Stader client
@@ -295,7 +308,7 @@ func (c *Client) UpdatePrometheusConfiguration(settings map[string]string) error
 	}
 	err = os.Chmod(prometheusConfigPath, 0664)
 	if err != nil {
-		return fmt.Errorf("Could not set Prometheus config file permissions: %w", shellescape.Quote(prometheusConfigPath), err)
+		return fmt.Errorf("Could not set Prometheus config file permissions on %s: %w", shellescape.Quote(prometheusConfigPath), err)
 	}
 
 	return nil
@@ -413,11 +426,13 @@ func (c *Client) MigrateLegacyConfig(legacyConfigFilePath string, legacySettings
 				cfg.ExternalLighthouse.DoppelgangerDetection.Value = true
 				cfg.ExternalLodestar.DoppelgangerDetection.Value = true
 				cfg.ExternalPrysm.DoppelgangerDetection.Value = true
+				cfg.ExternalTeku.DoppelgangerDetection.Value = true
 			} else {
 				cfg.ConsensusCommon.DoppelgangerDetection.Value = false
 				cfg.ExternalLighthouse.DoppelgangerDetection.Value = false
 				cfg.ExternalPrysm.DoppelgangerDetection.Value = false
 				cfg.ExternalLodestar.DoppelgangerDetection.Value = false
+				cfg.ExternalTeku.DoppelgangerDetection.Value = false
 			}
 		case "ETH2_RPC_PORT":
 			convertUintParam(param, &cfg.Prysm.RpcPort, network, 16)
@@ -1556,15 +1571,16 @@ func (c *Client) callAPI(args string, otherArgs ...string) ([]byte, error) {
 		if err != nil {
 			return []byte{}, err
 		}
-		cmd = fmt.Sprintf("docker exec %s %s %s %s %s %s api %s", shellescape.Quote(containerName), shellescape.Quote(APIBinPath), ignoreSyncCheckFlag, forceFallbackECFlag, c.getGasOpts(), c.getCustomNonce(), args)
+		cmd = fmt.Sprintf("docker exec %s %s %s %s %s %s %s api %s", shellescape.Quote(containerName), shellescape.Quote(APIBinPath), ignoreSyncCheckFlag, forceFallbackECFlag, c.getGasOpts(), c.getCustomNonce(), c.getOfflineOpts(), args)
 	} else {
-		cmd = fmt.Sprintf("%s --settings %s %s %s %s %s api %s",
+		cmd = fmt.Sprintf("%s --settings %s %s %s %s %s %s api %s",
 			c.daemonPath,
 			shellescape.Quote(fmt.Sprintf("%s/%s", c.configPath, SettingsFile)),
 			ignoreSyncCheckFlag,
 			forceFallbackECFlag,
 			c.getGasOpts(),
 			c.getCustomNonce(),
+			c.getOfflineOpts(),
 			args)
 	}
 
@@ -1589,13 +1605,13 @@ func (c *Client) callAPIWithEnvVars(envVars map[string]string, args string, othe
 		if err != nil {
 			return []byte{}, err
 		}
-		cmd = fmt.Sprintf("docker exec %s %s %s %s %s %s %s api %s", envArgs, shellescape.Quote(containerName), shellescape.Quote(APIBinPath), ignoreSyncCheckFlag, forceFallbackECFlag, c.getGasOpts(), c.getCustomNonce(), args)
+		cmd = fmt.Sprintf("docker exec %s %s %s %s %s %s %s %s api %s", envArgs, shellescape.Quote(containerName), shellescape.Quote(APIBinPath), ignoreSyncCheckFlag, forceFallbackECFlag, c.getGasOpts(), c.getCustomNonce(), c.getOfflineOpts(), args)
 	} else {
 		envArgs := ""
 		for key, value := range envVars {
 			envArgs += fmt.Sprintf("%s=%s ", key, shellescape.Quote(value))
 		}
-		cmd = fmt.Sprintf("%s %s --settings %s %s %s %s %s api %s",
+		cmd = fmt.Sprintf("%s %s --settings %s %s %s %s %s %s api %s",
 			envArgs,
 			c.daemonPath,
 			shellescape.Quote(fmt.Sprintf("%s/%s", c.configPath, SettingsFile)),
@@ -1603,6 +1619,7 @@ func (c *Client) callAPIWithEnvVars(envVars map[string]string, args string, othe
 			forceFallbackECFlag,
 			c.getGasOpts(),
 			c.getCustomNonce(),
+			c.getOfflineOpts(),
 			args)
 	}
 
@@ -1661,9 +1678,56 @@ func (c *Client) runApiCall(cmd string) ([]byte, error) {
 	c.maxPrioFee = c.originalMaxPrioFee
 	c.gasLimit = c.originalGasLimit
 
+	// Write out any transactions that were built for offline signing instead of being sent
+	if err == nil && c.exportUnsignedTxPath != "" {
+		err = c.exportUnsignedTransactions(output)
+	}
+
 	return output, err
 }
 
+// Check if transactions are exported for offline signing instead of being sent
+func (c *Client) IsExportingUnsignedTransactions() bool {
+	return c.exportUnsignedTxPath != ""
+}
+
+// Add the unsigned transactions in an API response to the export file.
+// Every transaction exported by the command so far is kept in the file, and each new one is given the nonce after
+// the previous one, since the API can only see the nonces of transactions that have already been sent.
+func (c *Client) exportUnsignedTransactions(output []byte) error {
+	var response struct {
+		UnsignedTransactions []offline.UnsignedTransaction `json:"unsignedTransactions"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil || len(response.UnsignedTransactions) == 0 {
+		return nil
+	}
+
+	for _, tx := range response.UnsignedTransactions {
+		if count := len(c.exportedTransactions); count > 0 {
+			nextNonce := c.exportedTransactions[count-1].Nonce + 1
+			if tx.Nonce < nextNonce {
+				tx.Nonce = nextNonce
+			}
+		}
+		c.exportedTransactions = append(c.exportedTransactions, tx)
+	}
+
+	exportBytes, err := json.MarshalIndent(offline.UnsignedTransactions{Transactions: c.exportedTransactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Could not encode the unsigned transactions: %w", err)
+	}
+	exportPath, err := homedir.Expand(c.exportUnsignedTxPath)
+	if err != nil {
+		return fmt.Errorf("Could not expand the unsigned transaction file path: %w", err)
+	}
+	if err := ioutil.WriteFile(exportPath, exportBytes, 0600); err != nil {
+		return fmt.Errorf("Could not write the unsigned transactions to %s: %w", exportPath, err)
+	}
+
+	fmt.Printf("%d unsigned transaction(s) were written to %s instead of being sent.\n", len(c.exportedTransactions), exportPath)
+	return nil
+}
+
 // Get the API container name
 func (c *Client) getAPIContainerName() (string, error) {
 	cfg, _, err := c.LoadConfig()
@@ -1681,7 +1745,18 @@ func (c *Client) getGasOpts() string {
 	var opts string
 	opts += fmt.Sprintf("--maxFee %f ", c.maxFee)
 	opts += fmt.Sprintf("--maxPrioFee %f ", c.maxPrioFee)
-	opts += fmt.Sprintf("--gasLimit %d ", 100000)
+	opts += fmt.Sprintf("--gasLimit %d ", c.gasLimit)
+	return opts
+}
+
+func (c *Client) getOfflineOpts() string {
+	var opts string
+	if c.nodeAddress != "" {
+		opts += fmt.Sprintf("--node-address %s ", shellescape.Quote(c.nodeAddress))
+	}
+	if c.exportUnsignedTxPath != "" {
+		opts += "--export-unsigned-tx "
+	}
 	return opts
 }
 
@@ -0,0 +1,75 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package stader
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/stader-labs/stader-node/shared/utils/offline"
+)
+
+func TestExportUnsignedTransactionsSequencesNonces(t *testing.T) {
+	exportPath := filepath.Join(t.TempDir(), "unsigned.json")
+	c := &Client{exportUnsignedTxPath: exportPath}
+
+	// Both API calls see the same pending nonce, since neither transaction has been sent
+	for _, name := range []string{"approve", "deposit"} {
+		output, err := json.Marshal(map[string]interface{}{
+			"status":               "success",
+			"unsignedTransactions": []offline.UnsignedTransaction{{Nonce: 7, Data: hexutil.Bytes(name)}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.exportUnsignedTransactions(output); err != nil {
+			t.Fatalf("exporting the %s transaction: %s", name, err)
+		}
+	}
+
+	exportBytes, err := ioutil.ReadFile(exportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported offline.UnsignedTransactions
+	if err := json.Unmarshal(exportBytes, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Transactions) != 2 {
+		t.Fatalf("exported %d transactions, want 2", len(exported.Transactions))
+	}
+	for i, tx := range exported.Transactions {
+		if uint64(tx.Nonce) != uint64(7+i) {
+			t.Errorf("transaction %d has nonce %d, want %d", i, tx.Nonce, 7+i)
+		}
+	}
+}
+
+func TestExportUnsignedTransactionsWriteError(t *testing.T) {
+	c := &Client{exportUnsignedTxPath: filepath.Join(t.TempDir(), "missing", "unsigned.json")}
+	output := []byte(`{"status":"success","unsignedTransactions":[{"nonce":"0x1"}]}`)
+	if err := c.exportUnsignedTransactions(output); err == nil {
+		t.Error("writing to a missing directory didn't return an error")
+	}
+}
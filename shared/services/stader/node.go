@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	string_utils "github.com/stader-labs/stader-node/shared/utils/string-utils"
@@ -252,6 +253,38 @@ func (c *Client) ExitValidator(validatorPubKey types.ValidatorPubkey) (api.ExitV
 	return response, nil
 }
 
+func (c *Client) CanExitValidators(validatorPubKeys []types.ValidatorPubkey) (api.CanExitValidatorsResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("validator can-exit-validators %s", joinPubkeys(validatorPubKeys)))
+	if err != nil {
+		return api.CanExitValidatorsResponse{}, fmt.Errorf("could not get validator can-exit-validators response: %w", err)
+	}
+	var response api.CanExitValidatorsResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.CanExitValidatorsResponse{}, fmt.Errorf("could not decode validator can-exit-validators response: %w", err)
+	}
+	if response.Error != "" {
+		return api.CanExitValidatorsResponse{}, fmt.Errorf("could not get validator can-exit-validators response: %s", response.Error)
+	}
+
+	return response, nil
+}
+
+func (c *Client) ExitValidators(validatorPubKeys []types.ValidatorPubkey) (api.ExitValidatorsResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("validator exit-validators %s", joinPubkeys(validatorPubKeys)))
+	if err != nil {
+		return api.ExitValidatorsResponse{}, fmt.Errorf("could not get validator exit-validators response: %w", err)
+	}
+	var response api.ExitValidatorsResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.ExitValidatorsResponse{}, fmt.Errorf("could not decode validator exit-validators response: %w", err)
+	}
+	if response.Error != "" {
+		return api.ExitValidatorsResponse{}, fmt.Errorf("could not get validator exit-validators response: %s", response.Error)
+	}
+
+	return response, nil
+}
+
 func (c *Client) GetContractsInfo() (api.ContractsInfoResponse, error) {
 	responseBytes, err := c.callAPI("node get-contracts-info")
 	if err != nil {
@@ -267,6 +300,95 @@ func (c *Client) GetContractsInfo() (api.ContractsInfoResponse, error) {
 	return response, nil
 }
 
+// Get the penalties of the node's validators and their force exit history
+func (c *Client) NodePenalties() (api.NodePenaltiesResponse, error) {
+	responseBytes, err := c.callAPI("node penalties")
+	if err != nil {
+		return api.NodePenaltiesResponse{}, fmt.Errorf("could not get node penalties: %w", err)
+	}
+	var response api.NodePenaltiesResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.NodePenaltiesResponse{}, fmt.Errorf("could not decode node penalties response: %w", err)
+	}
+	if response.Error != "" {
+		return api.NodePenaltiesResponse{}, fmt.Errorf("could not get node penalties: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Update the node's income ledger and get its records between the given unix timestamps
+func (c *Client) NodeLedger(from uint64, to uint64) (api.NodeLedgerResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("node ledger %d %d", from, to))
+	if err != nil {
+		return api.NodeLedgerResponse{}, fmt.Errorf("could not get node ledger: %w", err)
+	}
+	var response api.NodeLedgerResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.NodeLedgerResponse{}, fmt.Errorf("could not decode node ledger response: %w", err)
+	}
+	if response.Error != "" {
+		return api.NodeLedgerResponse{}, fmt.Errorf("could not get node ledger: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Get the transactions the node has sent
+func (c *Client) NodeTxList() (api.NodeTransactionsResponse, error) {
+	responseBytes, err := c.callAPI("node tx-list")
+	if err != nil {
+		return api.NodeTransactionsResponse{}, fmt.Errorf("could not get node transactions: %w", err)
+	}
+	var response api.NodeTransactionsResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.NodeTransactionsResponse{}, fmt.Errorf("could not decode node transactions response: %w", err)
+	}
+	if response.Error != "" {
+		return api.NodeTransactionsResponse{}, fmt.Errorf("could not get node transactions: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Check whether a pending transaction can be replaced
+func (c *Client) CanNodeTxReplace(hash common.Hash) (api.CanReplaceTransactionResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("node can-tx-replace %s", hash.Hex()))
+	if err != nil {
+		return api.CanReplaceTransactionResponse{}, fmt.Errorf("could not get can replace transaction status: %w", err)
+	}
+	var response api.CanReplaceTransactionResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.CanReplaceTransactionResponse{}, fmt.Errorf("could not decode can replace transaction response: %w", err)
+	}
+	if response.Error != "" {
+		return api.CanReplaceTransactionResponse{}, fmt.Errorf("could not get can replace transaction status: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Resend a pending transaction with higher fees
+func (c *Client) NodeTxSpeedup(hash common.Hash) (api.ReplaceTransactionResponse, error) {
+	return c.replaceTransaction("tx-speedup", "speed up", hash)
+}
+
+// Cancel a pending transaction
+func (c *Client) NodeTxCancel(hash common.Hash) (api.ReplaceTransactionResponse, error) {
+	return c.replaceTransaction("tx-cancel", "cancel", hash)
+}
+
+func (c *Client) replaceTransaction(command string, action string, hash common.Hash) (api.ReplaceTransactionResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("node %s %s", command, hash.Hex()))
+	if err != nil {
+		return api.ReplaceTransactionResponse{}, fmt.Errorf("could not %s transaction: %w", action, err)
+	}
+	var response api.ReplaceTransactionResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.ReplaceTransactionResponse{}, fmt.Errorf("could not decode %s transaction response: %w", action, err)
+	}
+	if response.Error != "" {
+		return api.ReplaceTransactionResponse{}, fmt.Errorf("could not %s transaction: %s", action, response.Error)
+	}
+	return response, nil
+}
+
 // Make a node deposit
 func (c *Client) NodeDeposit(amountWei *big.Int, numValidators *big.Int, reloadKeys bool) (api.NodeDepositResponse, error) {
 	responseBytes, err := c.callAPI(fmt.Sprintf("validator deposit %s %s %t", amountWei.String(), numValidators, reloadKeys))
@@ -283,6 +405,104 @@ func (c *Client) NodeDeposit(amountWei *big.Int, numValidators *big.Int, reloadK
 	return response, nil
 }
 
+// Check whether the node can deposit a set of externally generated validator keystores
+func (c *Client) CanImportValidatorKeys(amountWei *big.Int, password string, keystores []string) (api.CanImportValidatorKeysResponse, error) {
+	args := append([]string{password}, keystores...)
+	responseBytes, err := c.callAPI(fmt.Sprintf("validator can-import-keys %s", amountWei.String()), args...)
+	if err != nil {
+		return api.CanImportValidatorKeysResponse{}, fmt.Errorf("could not get can import validator keys status: %w", err)
+	}
+	var response api.CanImportValidatorKeysResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.CanImportValidatorKeysResponse{}, fmt.Errorf("could not decode can import validator keys response: %w", err)
+	}
+	if response.Error != "" {
+		return api.CanImportValidatorKeysResponse{}, fmt.Errorf("could not get can import validator keys status: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Import a set of externally generated validator keystores and deposit them
+func (c *Client) ImportValidatorKeys(amountWei *big.Int, reloadKeys bool, password string, keystores []string) (api.ImportValidatorKeysResponse, error) {
+	args := append([]string{password}, keystores...)
+	responseBytes, err := c.callAPI(fmt.Sprintf("validator import-keys %s %t", amountWei.String(), reloadKeys), args...)
+	if err != nil {
+		return api.ImportValidatorKeysResponse{}, fmt.Errorf("could not import validator keys: %w", err)
+	}
+	var response api.ImportValidatorKeysResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.ImportValidatorKeysResponse{}, fmt.Errorf("could not decode import validator keys response: %w", err)
+	}
+	if response.Error != "" {
+		return api.ImportValidatorKeysResponse{}, fmt.Errorf("could not import validator keys: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Do the chain reads for a deposit whose validator keys will be created and signed on an offline machine
+func (c *Client) ExportDepositRequest(amountWei *big.Int, numValidators *big.Int) (api.ExportDepositRequestResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("validator export-deposit-request %s %s", amountWei.String(), numValidators))
+	if err != nil {
+		return api.ExportDepositRequestResponse{}, fmt.Errorf("could not export deposit request: %w", err)
+	}
+	var response api.ExportDepositRequestResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.ExportDepositRequestResponse{}, fmt.Errorf("could not decode export deposit request response: %w", err)
+	}
+	if response.Error != "" {
+		return api.ExportDepositRequestResponse{}, fmt.Errorf("could not export deposit request: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Create the validator keys for an exported deposit request and sign the deposit transaction
+func (c *Client) SignDepositRequest(depositRequest string) (api.SignTransactionsResponse, error) {
+	responseBytes, err := c.callAPI("validator sign-deposit-request", depositRequest)
+	if err != nil {
+		return api.SignTransactionsResponse{}, fmt.Errorf("could not sign deposit request: %w", err)
+	}
+	var response api.SignTransactionsResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.SignTransactionsResponse{}, fmt.Errorf("could not decode sign deposit request response: %w", err)
+	}
+	if response.Error != "" {
+		return api.SignTransactionsResponse{}, fmt.Errorf("could not sign deposit request: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Sign transactions exported with --export-unsigned-tx
+func (c *Client) SignTransactions(unsignedTransactions string) (api.SignTransactionsResponse, error) {
+	responseBytes, err := c.callAPI("node sign-transactions", unsignedTransactions)
+	if err != nil {
+		return api.SignTransactionsResponse{}, fmt.Errorf("could not sign transactions: %w", err)
+	}
+	var response api.SignTransactionsResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.SignTransactionsResponse{}, fmt.Errorf("could not decode sign transactions response: %w", err)
+	}
+	if response.Error != "" {
+		return api.SignTransactionsResponse{}, fmt.Errorf("could not sign transactions: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Broadcast transactions that were signed on an offline machine
+func (c *Client) BroadcastTransactions(signedTransactions string) (api.BroadcastTransactionsResponse, error) {
+	responseBytes, err := c.callAPI("node broadcast-transactions", signedTransactions)
+	if err != nil {
+		return api.BroadcastTransactionsResponse{}, fmt.Errorf("could not broadcast transactions: %w", err)
+	}
+	var response api.BroadcastTransactionsResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.BroadcastTransactionsResponse{}, fmt.Errorf("could not decode broadcast transactions response: %w", err)
+	}
+	if response.Error != "" {
+		return api.BroadcastTransactionsResponse{}, fmt.Errorf("could not broadcast transactions: %s", response.Error)
+	}
+	return response, nil
+}
+
 // Check whether the node can send tokens
 func (c *Client) CanNodeSend(amountWei *big.Int, token string) (api.CanNodeSendResponse, error) {
 	responseBytes, err := c.callAPI(fmt.Sprintf("node can-send %s %s", amountWei.String(), token))
@@ -444,6 +664,102 @@ func (c *Client) SendClRewards(validatorPubKey types.ValidatorPubkey) (api.SendC
 	return response, nil
 }
 
+func (c *Client) CanSendClRewardsBatch(validatorPubKeys []types.ValidatorPubkey) (api.CanSendClRewardsBatchResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("validator can-send-cl-rewards-batch %s", joinPubkeys(validatorPubKeys)))
+	if err != nil {
+		return api.CanSendClRewardsBatchResponse{}, fmt.Errorf("could not get validator can-send-cl-rewards-batch response: %w", err)
+	}
+	var response api.CanSendClRewardsBatchResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.CanSendClRewardsBatchResponse{}, fmt.Errorf("could not decode validator can-send-cl-rewards-batch response: %w", err)
+	}
+	if response.Error != "" {
+		return api.CanSendClRewardsBatchResponse{}, fmt.Errorf("could not get validator can-send-cl-rewards-batch response: %s", response.Error)
+	}
+
+	return response, nil
+}
+
+func (c *Client) SendClRewardsBatch(validatorPubKeys []types.ValidatorPubkey) (api.SendClRewardsBatchResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("validator send-cl-rewards-batch %s", joinPubkeys(validatorPubKeys)))
+	if err != nil {
+		return api.SendClRewardsBatchResponse{}, fmt.Errorf("could not get validator send-cl-rewards-batch response: %w", err)
+	}
+	var response api.SendClRewardsBatchResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.SendClRewardsBatchResponse{}, fmt.Errorf("could not decode validator send-cl-rewards-batch response: %w", err)
+	}
+	if response.Error != "" {
+		return api.SendClRewardsBatchResponse{}, fmt.Errorf("could not get validator send-cl-rewards-batch response: %s", response.Error)
+	}
+
+	return response, nil
+}
+
+func (c *Client) CanSettleExitFunds(validatorPubKey types.ValidatorPubkey) (api.CanSettleExitFunds, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("validator can-settle-exit-funds %s", validatorPubKey))
+	if err != nil {
+		return api.CanSettleExitFunds{}, fmt.Errorf("could not get validator can-settle-exit-funds response: %w", err)
+	}
+	var response api.CanSettleExitFunds
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.CanSettleExitFunds{}, fmt.Errorf("could not decode validator can-settle-exit-funds response: %w", err)
+	}
+	if response.Error != "" {
+		return api.CanSettleExitFunds{}, fmt.Errorf("could not get validator can-settle-exit-funds response: %s", response.Error)
+	}
+
+	return response, nil
+}
+
+func (c *Client) GetPresignStatus() (api.PresignStatusResponse, error) {
+	responseBytes, err := c.callAPI("validator presign-status")
+	if err != nil {
+		return api.PresignStatusResponse{}, fmt.Errorf("could not get validator presign-status response: %w", err)
+	}
+	var response api.PresignStatusResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.PresignStatusResponse{}, fmt.Errorf("could not decode validator presign-status response: %w", err)
+	}
+	if response.Error != "" {
+		return api.PresignStatusResponse{}, fmt.Errorf("could not get validator presign-status response: %s", response.Error)
+	}
+
+	return response, nil
+}
+
+func (c *Client) GetDoppelgangerStatus() (api.DoppelgangerStatusResponse, error) {
+	responseBytes, err := c.callAPI("validator doppelganger-status")
+	if err != nil {
+		return api.DoppelgangerStatusResponse{}, fmt.Errorf("could not get validator doppelganger-status response: %w", err)
+	}
+	var response api.DoppelgangerStatusResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.DoppelgangerStatusResponse{}, fmt.Errorf("could not decode validator doppelganger-status response: %w", err)
+	}
+	if response.Error != "" {
+		return api.DoppelgangerStatusResponse{}, fmt.Errorf("could not get validator doppelganger-status response: %s", response.Error)
+	}
+
+	return response, nil
+}
+
+func (c *Client) RetryDoppelgangerWatch() (api.RetryDoppelgangerWatchResponse, error) {
+	responseBytes, err := c.callAPI("validator doppelganger-retry")
+	if err != nil {
+		return api.RetryDoppelgangerWatchResponse{}, fmt.Errorf("could not get validator doppelganger-retry response: %w", err)
+	}
+	var response api.RetryDoppelgangerWatchResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.RetryDoppelgangerWatchResponse{}, fmt.Errorf("could not decode validator doppelganger-retry response: %w", err)
+	}
+	if response.Error != "" {
+		return api.RetryDoppelgangerWatchResponse{}, fmt.Errorf("could not get validator doppelganger-retry response: %s", response.Error)
+	}
+
+	return response, nil
+}
+
 func (c *Client) CanWithdrawSd(amount *big.Int) (api.CanWithdrawSdResponse, error) {
 	responseBytes, err := c.callAPI(fmt.Sprintf("node can-withdraw-sd %s", amount.String()))
 	if err != nil {
@@ -634,3 +950,12 @@ func (c *Client) UpdateOperatorRewardAddress(operatorRewardAddress common.Addres
 	}
 	return response, nil
 }
+
+// Join validator pubkeys into the comma-separated form the batch API commands take
+func joinPubkeys(validatorPubKeys []types.ValidatorPubkey) string {
+	pubkeyStrings := make([]string, len(validatorPubKeys))
+	for i, validatorPubKey := range validatorPubKeys {
+		pubkeyStrings[i] = validatorPubKey.String()
+	}
+	return strings.Join(pubkeyStrings, ",")
+}
@@ -59,3 +59,19 @@ func (c *Client) GetClientStatus() (api.ClientStatusResponse, error) {
 	}
 	return response, nil
 }
+
+// Get fee suggestions from the Execution client's fee history
+func (c *Client) GetFeeEstimate() (api.FeeEstimateResponse, error) {
+	responseBytes, err := c.callAPI("service get-fee-estimate")
+	if err != nil {
+		return api.FeeEstimateResponse{}, fmt.Errorf("Could not get fee estimate: %w", err)
+	}
+	var response api.FeeEstimateResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.FeeEstimateResponse{}, fmt.Errorf("Could not decode fee estimate response: %w", err)
+	}
+	if response.Error != "" {
+		return api.FeeEstimateResponse{}, fmt.Errorf("Could not get fee estimate: %s", response.Error)
+	}
+	return response, nil
+}
@@ -0,0 +1,167 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package stader
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+)
+
+// Where the interchange file is mounted inside the slashing protection container
+const slashingProtectionDir string = "/slashing-protection"
+
+// The one-off container a validator client's own tooling runs in to export or import its slashing protection history
+type SlashingProtectionContainer struct {
+	Name   string
+	Client cfgtypes.ConsensusClient
+	Image  string
+
+	// The host directory the validator container mounts as /validators, which holds every client's data
+	ValidatorsDir string
+
+	// Lodestar reads the genesis validators root from a beacon node, so it needs to reach one
+	DockerNetwork string
+	BeaconNodeUrl string
+
+	Network cfgtypes.Network
+}
+
+// Exports the client's slashing protection history to the interchange file in the target directory
+func (c *Client) ExportSlashingProtection(container SlashingProtectionContainer, targetDir string) error {
+	file := filepath.Join(slashingProtectionDir, validator.SlashingProtectionFileName)
+	args, err := getSlashingProtectionArgs(container, "export", file)
+	if err != nil {
+		return err
+	}
+
+	runCmd, err := c.getSlashingProtectionRunCommand(container)
+	if err != nil {
+		return err
+	}
+
+	cmd := fmt.Sprintf("%s -v %s:%s %s", runCmd, targetDir, slashingProtectionDir, args)
+	return c.printOutput(cmd)
+}
+
+// Imports the given interchange file into the client's slashing protection database
+func (c *Client) ImportSlashingProtection(container SlashingProtectionContainer, sourceFile string) error {
+	file := filepath.Join(slashingProtectionDir, validator.SlashingProtectionFileName)
+	args, err := getSlashingProtectionArgs(container, "import", file)
+	if err != nil {
+		return err
+	}
+
+	runCmd, err := c.getSlashingProtectionRunCommand(container)
+	if err != nil {
+		return err
+	}
+
+	cmd := fmt.Sprintf("%s -v %s:%s:ro %s", runCmd, sourceFile, file, args)
+	return c.printOutput(cmd)
+}
+
+// The validator container doesn't have to exist yet, so its volumes are mounted directly
+func (c *Client) getSlashingProtectionRunCommand(container SlashingProtectionContainer) (string, error) {
+	cmd := fmt.Sprintf("docker run --rm --name %s --user root -v %s:/validators", container.Name, container.ValidatorsDir)
+	if container.Network == cfgtypes.Network_Zhejiang {
+		configPath, err := homedir.Expand(c.configPath)
+		if err != nil {
+			return "", fmt.Errorf("error expanding config path: %w", err)
+		}
+		cmd = fmt.Sprintf("%s -v %s:/zhejiang:ro", cmd, filepath.Join(configPath, "zhejiang"))
+	}
+	if container.DockerNetwork != "" {
+		cmd = fmt.Sprintf("%s --network %s", cmd, container.DockerNetwork)
+	}
+	return cmd, nil
+}
+
+// Get the entrypoint, image and arguments that run the client's slashing protection tooling against the same data directories start-vc.sh uses
+func getSlashingProtectionArgs(container SlashingProtectionContainer, operation string, file string) (string, error) {
+	network := container.Network
+	if network == cfgtypes.Network_Devnet {
+		network = cfgtypes.Network_Prater
+	}
+
+	var entrypoint string
+	var args []string
+	switch container.Client {
+	case cfgtypes.ConsensusClient_Lighthouse:
+		entrypoint = "/usr/local/bin/lighthouse"
+		args = []string{"account", "validator", "slashing-protection", operation, file, "--datadir", "/validators/lighthouse"}
+		if network == cfgtypes.Network_Zhejiang {
+			args = append(args, "--testnet-dir=/zhejiang")
+		} else {
+			args = append(args, "--network", string(network))
+		}
+
+	case cfgtypes.ConsensusClient_Nimbus:
+		entrypoint = "/home/user/nimbus-eth2/build/nimbus_beacon_node"
+		args = []string{"slashingdb", operation, file, "--data-dir=/validators/nimbus", "--validators-dir=/validators/nimbus/validators"}
+
+	case cfgtypes.ConsensusClient_Prysm:
+		entrypoint = "/app/cmd/validator/validator"
+		args = []string{"slashing-protection-history", operation, "--accept-terms-of-use", "--datadir=/validators/prysm-non-hd"}
+		if network == cfgtypes.Network_Zhejiang {
+			args = append(args, "--chain-config-file=/zhejiang/config.yaml")
+		} else {
+			args = append(args, "--"+string(network))
+		}
+		// Prysm always exports to slashing_protection.json in the given directory
+		if operation == "export" {
+			args = append(args, "--slashing-protection-export-dir="+filepath.Dir(file))
+		} else {
+			args = append(args, "--slashing-protection-json-file="+file)
+		}
+
+	case cfgtypes.ConsensusClient_Teku:
+		entrypoint = "/opt/teku/bin/teku"
+		args = []string{"slashing-protection", operation, "--data-path=/validators/teku"}
+		if operation == "export" {
+			args = append(args, "--to="+file)
+		} else {
+			args = append(args, "--from="+file)
+		}
+
+	case cfgtypes.ConsensusClient_Lodestar:
+		if container.BeaconNodeUrl == "" {
+			return "", fmt.Errorf("Lodestar requires a beacon node to %s its slashing protection history", operation)
+		}
+		entrypoint = "/usr/app/node_modules/.bin/lodestar"
+		args = []string{"validator", "slashing-protection", operation, "--dataDir", "/validators/lodestar", "--file", file, "--beaconNodes", container.BeaconNodeUrl}
+		if network == cfgtypes.Network_Zhejiang {
+			args = append(args, "--paramsFile=/zhejiang/config.yaml")
+		} else if network == cfgtypes.Network_Prater {
+			args = append(args, "--network", "goerli")
+		} else {
+			args = append(args, "--network", string(network))
+		}
+
+	default:
+		return "", fmt.Errorf("unsupported validator client [%s]", container.Client)
+	}
+
+	return fmt.Sprintf("--entrypoint %s %s %s", entrypoint, container.Image, strings.Join(args, " ")), nil
+}
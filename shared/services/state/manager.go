@@ -25,11 +25,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/stader-labs/stader-node/shared/services"
 	"github.com/stader-labs/stader-node/shared/services/beacon"
 	"github.com/stader-labs/stader-node/shared/services/config"
 	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
 	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/node"
 	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/types"
 	"github.com/urfave/cli"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -64,7 +69,7 @@ func NewMetricsCache(c *cli.Context, cfg *config.StaderConfig, ec stader.Executi
 
 	// Get the Beacon config info
 	var err error
-	m.BeaconConfig, err = m.bc.GetEth2Config()
+	m.BeaconConfig, err = m.bc.GetEth2Config(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -109,12 +114,45 @@ func (m *MetricsCacheManager) getNodeMetrics(nodeAddress common.Address, slotNum
 	if err != nil {
 		return nil, err
 	}
+
+	forceExits, err := m.getForceExits(nodeAddress, state.StaderNetworkDetails.ValidatorInfoMap)
+	if err != nil {
+		// Report the rest of the metrics, the index is synced again on the next update
+		m.logLine("error getting force exits: %s", err.Error())
+	}
+	state.StaderNetworkDetails.ForceExits = forceExits
+
 	return state, nil
 }
 
+// Get the force exits of the node's validators from the operator's event index, which is shared with the API
+func (m *MetricsCacheManager) getForceExits(nodeAddress common.Address, validators map[types.ValidatorPubkey]contracts.Validator) (map[types.ValidatorPubkey]stdr.ForceExitEvent, error) {
+	pnr, err := services.GetPermissionlessNodeRegistry(m.c)
+	if err != nil {
+		return nil, err
+	}
+	operatorId, err := node.GetOperatorId(pnr, nodeAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	if operatorId.Int64() == 0 {
+		return map[types.ValidatorPubkey]stdr.ForceExitEvent{}, nil
+	}
+	operatorInfo, err := node.GetOperatorInfo(pnr, operatorId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ix, _, err := services.SyncOperatorIndex(m.c, nodeAddress, operatorId, operatorInfo, validators)
+	if err != nil {
+		return nil, err
+	}
+	return stdr.GetIndexedForceExits(ix)
+}
+
 // Logs a line if the logger is specified
 func (m *MetricsCacheManager) logLine(format string, v ...interface{}) {
 	if m.log != nil {
-		m.log.Printlnf(format, v)
+		m.log.Printlnf(format, v...)
 	}
 }
@@ -1,19 +1,22 @@
 package state
 
 import (
+	"context"
 	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stader-labs/stader-node/shared/services"
 	"github.com/stader-labs/stader-node/shared/utils/stdr"
 	"github.com/stader-labs/stader-node/stader-lib/contracts"
 	"github.com/urfave/cli"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/stader-labs/stader-node/shared/utils/math"
 	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
 
 	"github.com/stader-labs/stader-node/shared/utils/eth2"
-	penalty_tracker "github.com/stader-labs/stader-node/stader-lib/penalty-tracker"
 	pool_utils "github.com/stader-labs/stader-node/stader-lib/pool-utils"
 	socializing_pool "github.com/stader-labs/stader-node/stader-lib/socializing-pool"
 	stader_config "github.com/stader-labs/stader-node/stader-lib/stader-config"
@@ -81,12 +84,19 @@ type MetricDetails struct {
 	FrontRunValidators *big.Int
 	// done
 	FundsSettledValidators *big.Int
+	// Validators on the beacon chain whose presigned exit hasn't been accepted by the backend
+	PresignPendingValidators *big.Int
+	// Downloaded socializing pool merkle proofs that didn't match the on-chain root
+	QuarantinedMerkleProofs *big.Int
 	// done
 	ValidatorStatusMap map[types.ValidatorPubkey]beacon.ValidatorStatus
 	ValidatorInfoMap   map[types.ValidatorPubkey]contracts.Validator
 
 	// done
 	CumulativePenalty float64
+	// Per-validator penalty breakdown and the force exits of the node's validators from the operator's event index
+	ValidatorPenalties map[types.ValidatorPubkey]stdr.ValidatorPenalty
+	ForceExits         map[types.ValidatorPubkey]stdr.ForceExitEvent
 	// done
 	UnclaimedClRewards float64
 	// done
@@ -114,8 +124,11 @@ type MetricDetails struct {
 }
 
 type MetricsCache struct {
-	// Block / slot for this state
+	// Block / slot for this state. Every contract figure in StaderNetworkDetails was read at
+	// ElBlockNumber, and every beacon figure at BeaconSlotNumber.
 	ElBlockNumber    uint64
+	ElBlockHash      common.Hash
+	ElBlockTime      time.Time
 	BeaconSlotNumber uint64
 	BeaconConfig     beacon.Eth2Config
 
@@ -128,6 +141,30 @@ type MetricsCache struct {
 	log *log.ColorLogger
 }
 
+// Error messages returned by the supported ECs when the state for a block has been pruned
+var stateUnavailablePatterns = []string{
+	"missing trie node",
+	"header not found",
+	"state not available",
+	"historical state",
+	"state is not available",
+	"pruned",
+}
+
+// Returned when the EC no longer has the state for the block a metrics cache is pinned to
+type StateUnavailableError struct {
+	BlockNumber uint64
+	Err         error
+}
+
+func (e *StateUnavailableError) Error() string {
+	return fmt.Sprintf("the execution client no longer has the state for block %d (it has likely been pruned): %s", e.BlockNumber, e.Err.Error())
+}
+
+func (e *StateUnavailableError) Unwrap() error {
+	return e.Err
+}
+
 func CreateMetricsCache(
 	c *cli.Context,
 	cfg *config.StaderNodeConfig,
@@ -210,7 +247,7 @@ func CreateMetricsCache(
 	}
 
 	// Get the execution block for the given slot
-	beaconBlock, exists, err := bc.GetBeaconBlock(fmt.Sprintf("%d", slotNumber))
+	beaconBlock, exists, err := bc.GetBeaconBlock(context.Background(), fmt.Sprintf("%d", slotNumber))
 	if err != nil {
 		return nil, fmt.Errorf("error getting Beacon block for slot %d: %w", slotNumber, err)
 	}
@@ -221,10 +258,23 @@ func CreateMetricsCache(
 	// Get the corresponding block on the EL
 	elBlockNumber := beaconBlock.ExecutionBlockNumber
 
+	// Make sure the EC can still serve state for that block before reading anything from it
+	elBlockHeader, err := getStateHeader(ec, elBlockNumber, prnAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every contract read below is pinned to this block so all figures come from the same state
+	opts := &bind.CallOpts{
+		BlockNumber: big.NewInt(0).SetUint64(elBlockNumber),
+	}
+
 	// Create the state wrapper
 	state := &MetricsCache{
 		BeaconSlotNumber: slotNumber,
 		ElBlockNumber:    elBlockNumber,
+		ElBlockHash:      elBlockHeader.Hash(),
+		ElBlockTime:      time.Unix(int64(elBlockHeader.Time), 0),
 		BeaconConfig:     beaconConfig,
 		log:              log,
 	}
@@ -234,51 +284,51 @@ func CreateMetricsCache(
 	start := time.Now()
 
 	// fetch all validator pub keys
-	operatorId, err := node.GetOperatorId(prn, nodeAddress, nil)
+	operatorId, err := node.GetOperatorId(prn, nodeAddress, opts)
 	if err != nil {
 		return nil, err
 	}
-	operatorElRewardAddress, err := node.GetNodeElRewardAddress(prn, 1, operatorId, nil)
+	operatorElRewardAddress, err := node.GetNodeElRewardAddress(prn, 1, operatorId, opts)
 	if err != nil {
 		return nil, err
 	}
-	elRewardAddressBalance, err := tokens.GetEthBalance(prn.Client, operatorElRewardAddress, nil)
+	elRewardAddressBalance, err := tokens.GetEthBalance(prn.Client, operatorElRewardAddress, opts)
 	if err != nil {
 		return nil, err
 	}
-	operatorElRewards, err := pool_utils.CalculateRewardShare(putils, 1, elRewardAddressBalance, nil)
+	operatorElRewards, err := pool_utils.CalculateRewardShare(putils, 1, elRewardAddressBalance, opts)
 	if err != nil {
 		return nil, err
 	}
-	operatorSdColletaral, err := sd_collateral.GetOperatorSdBalance(sdc, nodeAddress, nil)
+	operatorSdColletaral, err := sd_collateral.GetOperatorSdBalance(sdc, nodeAddress, opts)
 	if err != nil {
 		return nil, err
 	}
-	totalValidatorKeys, err := node.GetTotalValidatorKeys(prn, operatorId, nil)
+	totalValidatorKeys, err := node.GetTotalValidatorKeys(prn, operatorId, opts)
 	if err != nil {
 		return nil, err
 	}
-	poolThreshold, err := sd_collateral.GetPoolThreshold(sdc, 1, nil)
+	poolThreshold, err := sd_collateral.GetPoolThreshold(sdc, 1, opts)
 	if err != nil {
 		return nil, err
 	}
-	operatorSdCollateralInEth, err := sd_collateral.ConvertSdToEth(sdc, operatorSdColletaral, nil)
+	operatorSdCollateralInEth, err := sd_collateral.ConvertSdToEth(sdc, operatorSdColletaral, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	operatorNonTerminalKeys, err := node.GetTotalNonTerminalValidatorKeys(prn, nodeAddress, totalValidatorKeys, nil)
+	operatorNonTerminalKeys, err := node.GetTotalNonTerminalValidatorKeys(prn, nodeAddress, totalValidatorKeys, opts)
 	if err != nil {
 		return nil, err
 	}
 	operatorEthCollateral := float64(4 * operatorNonTerminalKeys)
 
-	nextRewardCycleDetails, err := socializing_pool.GetRewardDetails(sp, nil)
+	nextRewardCycleDetails, err := socializing_pool.GetRewardDetails(sp, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	validatorInfoMap, pubkeys, err := stdr.GetAllValidatorsRegisteredWithOperator(prn, operatorId, nodeAddress, nil)
+	validatorInfoMap, pubkeys, err := stdr.GetAllValidatorsRegisteredWithOperator(prn, operatorId, nodeAddress, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -293,22 +343,51 @@ func CreateMetricsCache(
 	fundsSettledValidators := big.NewInt(0)
 	invalidSignatureValidators := big.NewInt(0)
 	frontRunValidators := big.NewInt(0)
+	presignPendingValidators := big.NewInt(0)
 	totalClRewards := big.NewInt(0)
 	cumulativePenalty := big.NewInt(0)
 
 	// Get the validator stats from Beacon
-	statusMap, err := bc.GetValidatorStatuses(pubkeys, &beacon.ValidatorStatusOptions{
+	statusMap, err := bc.GetValidatorStatuses(context.Background(), pubkeys, &beacon.ValidatorStatusOptions{
 		Slot: &slotNumber,
 	})
 	if err != nil {
 		return nil, err
 	}
+
+	presignDb, err := services.GetPresignDatabase(c)
+	if err != nil {
+		return nil, err
+	}
+	presignRecords, err := presignDb.GetRecords()
+	if err != nil {
+		return nil, fmt.Errorf("error getting presign records: %w", err)
+	}
+
+	quarantinedCycles, err := cfg.GetQuarantinedCycles(true)
+	if err != nil {
+		return nil, fmt.Errorf("error getting quarantined merkle proofs: %w", err)
+	}
+
+	// The rewards threshold is the same for every validator, so only read it once
+	rewardsThreshold, err := stader_config.GetRewardsThreshold(sdcfg, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mc, err := stader.NewMultiCaller(ec, stader.Multicall3Address)
+	if err != nil {
+		return nil, err
+	}
+
+	validatorPenalties, err := stdr.GetValidatorPenalties(pt, pubkeys, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawVaults := []common.Address{}
 	for _, pubKey := range pubkeys {
-		totalValidatorPenalty, err := penalty_tracker.GetCumulativeValidatorPenalty(pt, pubKey, nil)
-		if err != nil {
-			return nil, err
-		}
-		cumulativePenalty.Add(cumulativePenalty, totalValidatorPenalty)
+		cumulativePenalty.Add(cumulativePenalty, validatorPenalties[pubKey].TotalPenalty)
 
 		validatorContractInfo, ok := validatorInfoMap[pubKey]
 		if !ok {
@@ -352,6 +431,9 @@ func CreateMetricsCache(
 			withdrawnValidators.Add(withdrawnValidators, big.NewInt(1))
 			continue
 		}
+		if inBeaconChain && !presignRecords[pubKey].Accepted {
+			presignPendingValidators.Add(presignPendingValidators, big.NewInt(1))
+		}
 		if inBeaconChain && eth2.IsValidatorQueued(status) {
 			beaconChainQueuedValidators.Add(beaconChainQueuedValidators, big.NewInt(1))
 		}
@@ -362,24 +444,35 @@ func CreateMetricsCache(
 			activeValidators.Add(activeValidators, big.NewInt(1))
 		}
 
-		validatorWithdrawVault := validatorInfoMap[pubKey].WithdrawVaultAddress
-		withdrawVaultBalance, err := tokens.GetEthBalance(prn.Client, validatorWithdrawVault, nil)
-		if err != nil {
-			return nil, err
-		}
-		withdrawVaultRewardShares, err := pool_utils.CalculateRewardShare(putils, 1, withdrawVaultBalance, nil)
-		if err != nil {
+		withdrawVaults = append(withdrawVaults, validatorContractInfo.WithdrawVaultAddress)
+	}
+
+	// Batch the withdraw vault balance reads, then the reward share calculations that depend on them
+	withdrawVaultBalances := make([]*big.Int, len(withdrawVaults))
+	for i, withdrawVault := range withdrawVaults {
+		if err := mc.AddEthBalance(withdrawVault, &withdrawVaultBalances[i]); err != nil {
 			return nil, err
 		}
-		rewardsThreshold, err := stader_config.GetRewardsThreshold(sdcfg, nil)
-		if err != nil {
+	}
+	if err := mc.Execute(opts); err != nil {
+		return nil, fmt.Errorf("error getting withdraw vault balances: %w", err)
+	}
+
+	withdrawVaultRewardShares := make([]types.RewardShare, len(withdrawVaults))
+	for i := range withdrawVaults {
+		if err := mc.AddCall(putils.PoolUtilsContract, &withdrawVaultRewardShares[i], "calculateRewardShare", uint8(1), withdrawVaultBalances[i]); err != nil {
 			return nil, err
 		}
-		if withdrawVaultRewardShares.OperatorShare.Cmp(rewardsThreshold) > 0 {
+	}
+	if err := mc.Execute(opts); err != nil {
+		return nil, fmt.Errorf("error getting withdraw vault reward shares: %w", err)
+	}
+
+	for _, rewardShare := range withdrawVaultRewardShares {
+		if rewardShare.OperatorShare.Cmp(rewardsThreshold) > 0 {
 			continue
-		} else {
-			totalClRewards.Add(totalClRewards, withdrawVaultRewardShares.OperatorShare)
 		}
+		totalClRewards.Add(totalClRewards, rewardShare.OperatorShare)
 	}
 
 	state.ValidatorDetails = statusMap
@@ -390,7 +483,7 @@ func CreateMetricsCache(
 
 	start = time.Now()
 
-	rewardClaimData, err := getClaimedAndUnclaimedSocializingSdAndEth(cfg, sp, nodeAddress)
+	rewardClaimData, err := getClaimedAndUnclaimedSocializingSdAndEth(cfg, sp, nodeAddress, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -403,45 +496,45 @@ func CreateMetricsCache(
 
 	metricsDetails := MetricDetails{}
 
-	sdPrice, err := sd_collateral.ConvertEthToSd(sdc, big.NewInt(1000000000000000000), nil)
+	sdPrice, err := sd_collateral.ConvertEthToSd(sdc, big.NewInt(1000000000000000000), opts)
 	if err != nil {
 		return nil, err
 	}
-	ethPrice, err := sd_collateral.ConvertSdToEth(sdc, big.NewInt(1000000000000000000), nil)
+	ethPrice, err := sd_collateral.ConvertSdToEth(sdc, big.NewInt(1000000000000000000), opts)
 	if err != nil {
 		return nil, err
 	}
-	totalOperators, err := node.GetNextOperatorId(prn, nil)
+	totalOperators, err := node.GetNextOperatorId(prn, opts)
 	if err != nil {
 		return nil, err
 	}
-	totalValidators, err := node.GetNextValidatorId(prn, nil)
+	totalValidators, err := node.GetNextValidatorId(prn, opts)
 	if err != nil {
 		return nil, err
 	}
-	totalActiveValidators, err := node.GetTotalActiveValidators(prn, nil)
+	totalActiveValidators, err := node.GetTotalActiveValidators(prn, opts)
 	if err != nil {
 		return nil, err
 	}
-	prnEthBalanceInWei, err := tokens.GetEthBalance(prn.Client, prnAddress, nil)
+	prnEthBalanceInWei, err := tokens.GetEthBalance(prn.Client, prnAddress, opts)
 	if err != nil {
 		return nil, err
 	}
 	prnEthBalance := eth.WeiToEth(prnEthBalanceInWei)
 	totalQueuedValidators := prnEthBalance / 3
-	totalSdCollateral, err := tokens.BalanceOf(sdt, sdcAddress, nil)
+	totalSdCollateral, err := tokens.BalanceOf(sdt, sdcAddress, opts)
 	if err != nil {
 		return nil, err
 	}
-	permissionlessPoolThreshold, err := sd_collateral.GetPoolThreshold(sdc, 1, nil)
+	permissionlessPoolThreshold, err := sd_collateral.GetPoolThreshold(sdc, 1, opts)
 	if err != nil {
 		return nil, err
 	}
-	ethxSupply, err := tokens.TotalSupply(ethx, nil)
+	ethxSupply, err := tokens.TotalSupply(ethx, opts)
 	if err != nil {
 		return nil, err
 	}
-	totalStakedAssets, err := stake_pool_manager.GetTotalAssets(spm, nil)
+	totalStakedAssets, err := stake_pool_manager.GetTotalAssets(spm, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -480,7 +573,10 @@ func CreateMetricsCache(
 	metricsDetails.FrontRunValidators = frontRunValidators
 	metricsDetails.InvalidSignatureValidators = invalidSignatureValidators
 	metricsDetails.FundsSettledValidators = fundsSettledValidators
+	metricsDetails.PresignPendingValidators = presignPendingValidators
+	metricsDetails.QuarantinedMerkleProofs = big.NewInt(int64(len(quarantinedCycles)))
 	metricsDetails.CumulativePenalty = math.RoundDown(eth.WeiToEth(cumulativePenalty), 2)
+	metricsDetails.ValidatorPenalties = validatorPenalties
 	metricsDetails.UnclaimedClRewards = math.RoundDown(eth.WeiToEth(totalClRewards), 18)
 	metricsDetails.NextSocializingPoolRewardCycle = nextRewardCycleDetails
 	metricsDetails.UnclaimedNonSocializingPoolElRewards = math.RoundDown(eth.WeiToEth(operatorElRewards.OperatorShare), 2)
@@ -496,6 +592,41 @@ func CreateMetricsCache(
 	return state, nil
 }
 
+// Gets the header of the given EL block and checks that the EC still has the state for it
+func getStateHeader(ec stader.ExecutionClient, elBlockNumber uint64, probeAddress common.Address) (*ethtypes.Header, error) {
+	blockNumber := big.NewInt(0).SetUint64(elBlockNumber)
+
+	header, err := ec.HeaderByNumber(context.Background(), blockNumber)
+	if err != nil {
+		if isStateUnavailableError(err) {
+			return nil, &StateUnavailableError{BlockNumber: elBlockNumber, Err: err}
+		}
+		return nil, fmt.Errorf("error getting EL header for block %d: %w", elBlockNumber, err)
+	}
+
+	// A header alone doesn't mean the state is there, so probe it with a cheap balance lookup
+	_, err = ec.BalanceAt(context.Background(), probeAddress, blockNumber)
+	if err != nil {
+		if isStateUnavailableError(err) {
+			return nil, &StateUnavailableError{BlockNumber: elBlockNumber, Err: err}
+		}
+		return nil, fmt.Errorf("error reading EL state at block %d: %w", elBlockNumber, err)
+	}
+
+	return header, nil
+}
+
+// Checks if an EC error means the state for the requested block is no longer available
+func isStateUnavailableError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, pattern := range stateUnavailablePatterns {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // Logs a line if the logger is specified
 func (s *MetricsCache) logLine(format string, v ...interface{}) {
 	if s.log != nil {
@@ -507,6 +638,7 @@ func getClaimedAndUnclaimedSocializingSdAndEth(
 	cfg *config.StaderNodeConfig,
 	sp *stader.SocializingPoolContractManager,
 	nodeAccount common.Address,
+	opts *bind.CallOpts,
 ) (struct {
 	unclaimedEth *big.Int
 	unclaimedSd  *big.Int
@@ -530,7 +662,7 @@ func getClaimedAndUnclaimedSocializingSdAndEth(
 	outstruct.claimedEth = big.NewInt(0)
 	outstruct.claimedSd = big.NewInt(0)
 
-	rewardDetails, err := socializing_pool.GetRewardDetails(sp, nil)
+	rewardDetails, err := socializing_pool.GetRewardDetails(sp, opts)
 	if err != nil {
 		return outstruct, err
 	}
@@ -547,7 +679,7 @@ func getClaimedAndUnclaimedSocializingSdAndEth(
 		if !exists {
 			continue
 		}
-		claimed, err := socializing_pool.HasClaimedRewards(sp, nodeAccount, big.NewInt(i), nil)
+		claimed, err := socializing_pool.HasClaimedRewards(sp, nodeAccount, big.NewInt(i), opts)
 		if err != nil {
 			return outstruct, err
 		}
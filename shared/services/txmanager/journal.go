@@ -0,0 +1,231 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package txmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/stader-labs/stader-node/shared/utils/sys"
+)
+
+// Finished transactions are kept in the journal for this long
+const journalRetention = 30 * 24 * time.Hour
+
+type TxStatus string
+
+const (
+	TxStatus_Pending TxStatus = "pending"
+	TxStatus_Mined   TxStatus = "mined"
+	TxStatus_Failed  TxStatus = "failed"
+	// Another transaction with the same nonce was mined instead
+	TxStatus_Replaced TxStatus = "replaced"
+)
+
+type TxKind string
+
+const (
+	TxKind_Original TxKind = "original"
+	TxKind_Speedup  TxKind = "speedup"
+	TxKind_Cancel   TxKind = "cancel"
+)
+
+// A transaction the node submitted
+type TxRecord struct {
+	Hash        common.Hash     `json:"hash"`
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Nonce       uint64          `json:"nonce"`
+	Value       *big.Int        `json:"value"`
+	Data        hexutil.Bytes   `json:"data"`
+	GasLimit    uint64          `json:"gasLimit"`
+	MaxFee      *big.Int        `json:"maxFee"`
+	PriorityFee *big.Int        `json:"priorityFee"`
+	ChainID     *big.Int        `json:"chainId"`
+	Kind        TxKind          `json:"kind"`
+	// The transaction this one replaces, for speedups and cancellations
+	Replaces    common.Hash `json:"replaces,omitempty"`
+	SubmittedAt time.Time   `json:"submittedAt"`
+	Status      TxStatus    `json:"status"`
+	BlockNumber uint64      `json:"blockNumber,omitempty"`
+}
+
+// A local record of the transactions the node has submitted, stored as a JSON file.
+// The daemon and the CLI both write to it, so it's guarded by a lock file rather than a mutex.
+type Journal struct {
+	path string
+}
+
+func NewJournal(path string) *Journal {
+	return &Journal{
+		path: path,
+	}
+}
+
+// Add a submitted transaction to the journal
+func (j *Journal) Record(tx *types.Transaction) error {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return fmt.Errorf("could not get the sender of transaction %s: %w", tx.Hash().Hex(), err)
+	}
+
+	record := TxRecord{
+		Hash:        tx.Hash(),
+		From:        from,
+		To:          tx.To(),
+		Nonce:       tx.Nonce(),
+		Value:       tx.Value(),
+		Data:        tx.Data(),
+		GasLimit:    tx.Gas(),
+		MaxFee:      tx.GasFeeCap(),
+		PriorityFee: tx.GasTipCap(),
+		ChainID:     tx.ChainId(),
+		Kind:        TxKind_Original,
+		SubmittedAt: time.Now(),
+		Status:      TxStatus_Pending,
+	}
+
+	return j.update(func(records []TxRecord) ([]TxRecord, error) {
+		for _, existing := range records {
+			if existing.Hash == record.Hash {
+				return records, nil
+			}
+		}
+		return append(records, record), nil
+	})
+}
+
+// Mark a recorded transaction as a replacement of another one
+func (j *Journal) SetReplacement(hash common.Hash, kind TxKind, replaces common.Hash) error {
+	return j.update(func(records []TxRecord) ([]TxRecord, error) {
+		for i := range records {
+			if records[i].Hash == hash {
+				records[i].Kind = kind
+				records[i].Replaces = replaces
+				return records, nil
+			}
+		}
+		return nil, fmt.Errorf("transaction %s is not in the journal", hash.Hex())
+	})
+}
+
+// Get the recorded transactions, oldest first
+func (j *Journal) GetRecords() ([]TxRecord, error) {
+	unlock, err := j.acquireLock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return j.load()
+}
+
+// Get a single recorded transaction
+func (j *Journal) GetRecord(hash common.Hash) (TxRecord, bool, error) {
+	records, err := j.GetRecords()
+	if err != nil {
+		return TxRecord{}, false, err
+	}
+	for _, record := range records {
+		if record.Hash == hash {
+			return record, true, nil
+		}
+	}
+	return TxRecord{}, false, nil
+}
+
+// Load, modify and save the journal while holding the lock
+func (j *Journal) update(modify func([]TxRecord) ([]TxRecord, error)) error {
+	unlock, err := j.acquireLock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	records, err := j.load()
+	if err != nil {
+		return err
+	}
+	records, err = modify(records)
+	if err != nil {
+		return err
+	}
+	return j.save(records)
+}
+
+// Lock the journal against other processes
+func (j *Journal) acquireLock() (func() error, error) {
+	unlock, err := sys.LockFile(j.path + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("could not lock the transaction journal: %w", err)
+	}
+	return unlock, nil
+}
+
+func (j *Journal) load() ([]TxRecord, error) {
+	bytes, err := ioutil.ReadFile(j.path)
+	if os.IsNotExist(err) {
+		return []TxRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the transaction journal at %s: %w", j.path, err)
+	}
+	records := []TxRecord{}
+	if err := json.Unmarshal(bytes, &records); err != nil {
+		return nil, fmt.Errorf("could not parse the transaction journal at %s: %w", j.path, err)
+	}
+	return records, nil
+}
+
+// Save the journal, dropping old finished transactions; the file is replaced in one step so readers never see half of it
+func (j *Journal) save(records []TxRecord) error {
+	kept := make([]TxRecord, 0, len(records))
+	for _, record := range records {
+		if record.Status != TxStatus_Pending && time.Since(record.SubmittedAt) > journalRetention {
+			continue
+		}
+		kept = append(kept, record)
+	}
+	sort.SliceStable(kept, func(i, k int) bool {
+		return kept[i].SubmittedAt.Before(kept[k].SubmittedAt)
+	})
+
+	bytes, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("could not create the transaction journal folder: %w", err)
+	}
+	tempPath := j.path + ".tmp"
+	if err := ioutil.WriteFile(tempPath, bytes, 0644); err != nil {
+		return fmt.Errorf("could not write the transaction journal: %w", err)
+	}
+	return os.Rename(tempPath, j.path)
+}
@@ -0,0 +1,105 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package txmanager
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Execution client whose transactions get mined right after their first receipt lookup
+type racingClient struct {
+	lookups map[common.Hash]int
+	mined   map[common.Hash]bool
+	nonce   uint64
+}
+
+func (c *racingClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.lookups[txHash]++
+	if c.mined[txHash] && c.lookups[txHash] > 1 {
+		return &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100)}, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *racingClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.nonce, nil
+}
+
+func TestRefreshMinedBetweenChecks(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainId := big.NewInt(1)
+	signer := types.LatestSignerForChainID(chainId)
+	journal := NewJournal(filepath.Join(t.TempDir(), "transactions.json"))
+
+	// Two transactions with the same nonce, where only the first one gets mined
+	var hashes []common.Hash
+	for _, tip := range []int64{1, 2} {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   chainId,
+			Nonce:     5,
+			GasTipCap: big.NewInt(tip),
+			GasFeeCap: big.NewInt(100),
+			Gas:       transferGasLimit,
+			To:        &common.Address{},
+			Value:     big.NewInt(0),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := journal.Record(tx); err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, tx.Hash())
+	}
+
+	client := &racingClient{
+		lookups: map[common.Hash]int{},
+		mined:   map[common.Hash]bool{hashes[0]: true},
+		nonce:   6,
+	}
+	if _, err := journal.Refresh(client); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[common.Hash]TxStatus{hashes[0]: TxStatus_Mined, hashes[1]: TxStatus_Replaced}
+	for hash, status := range expected {
+		record, found, err := journal.GetRecord(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Fatalf("transaction %s is not in the journal", hash.Hex())
+		}
+		if record.Status != status {
+			t.Errorf("transaction %s: expected status %s, got %s", hash.Hex(), status, record.Status)
+		}
+	}
+}
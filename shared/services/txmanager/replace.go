@@ -0,0 +1,176 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/stader-labs/stader-node/shared/services/gas/feehistory"
+)
+
+const (
+	// A pending transaction is considered stuck once it has waited this long
+	StuckAfter = 3 * time.Minute
+
+	// The gas limit of a plain ETH transfer, used by cancellations
+	transferGasLimit uint64 = 21000
+)
+
+// The subset of the Execution client the journal needs to follow its transactions
+type Client interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// Update the status of the pending transactions from the chain and return all of the records
+func (j *Journal) Refresh(client Client) ([]TxRecord, error) {
+	var refreshed []TxRecord
+	err := j.update(func(records []TxRecord) ([]TxRecord, error) {
+		nonces := map[common.Address]uint64{}
+		for i := range records {
+			record := &records[i]
+			if record.Status != TxStatus_Pending {
+				continue
+			}
+
+			mined, err := updateFromReceipt(client, record)
+			if err != nil {
+				return nil, err
+			}
+			if mined {
+				continue
+			}
+
+			// Once the account's nonce has moved past it, another transaction with the same nonce got mined instead
+			nonce, exists := nonces[record.From]
+			if !exists {
+				nonce, err = client.NonceAt(context.Background(), record.From, nil)
+				if err != nil {
+					return nil, err
+				}
+				nonces[record.From] = nonce
+			}
+			if record.Nonce >= nonce {
+				continue
+			}
+
+			// The transaction itself may have been mined after its receipt was checked, so look again before
+			// marking it as replaced
+			mined, err = updateFromReceipt(client, record)
+			if err != nil {
+				return nil, err
+			}
+			if !mined {
+				record.Status = TxStatus_Replaced
+			}
+		}
+		refreshed = records
+		return records, nil
+	})
+	return refreshed, err
+}
+
+// Set the status of a record from its receipt, returning false if it hasn't been mined
+func updateFromReceipt(client Client, record *TxRecord) (bool, error) {
+	receipt, err := client.TransactionReceipt(context.Background(), record.Hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if receipt == nil {
+		return false, nil
+	}
+	record.Status = TxStatus_Mined
+	if receipt.Status == types.ReceiptStatusFailed {
+		record.Status = TxStatus_Failed
+	}
+	record.BlockNumber = receipt.BlockNumber.Uint64()
+	return true, nil
+}
+
+// Check if a transaction has been pending for too long or can't be included at the next block's base fee
+func IsStuck(record TxRecord, nextBaseFee *big.Int) bool {
+	if record.Status != TxStatus_Pending {
+		return false
+	}
+	if time.Since(record.SubmittedAt) > StuckAfter {
+		return true
+	}
+	return nextBaseFee != nil && record.MaxFee != nil && record.MaxFee.Cmp(nextBaseFee) < 0
+}
+
+// Get the fees for a replacement of the transaction: the current fast fees, but at least 1/8 above the original's
+// since clients reject replacements that don't raise both fees by at least 10%
+func GetReplacementFees(record TxRecord, estimate feehistory.FeeEstimate, priorityFee *big.Int) (*big.Int, *big.Int) {
+	tip := maxOf(bump(record.PriorityFee), priorityFee, estimate.Fast.PriorityFeeWei)
+	maxFee := maxOf(bump(record.MaxFee), new(big.Int).Add(estimate.Fast.MaxBaseFeeWei, tip))
+	return maxFee, tip
+}
+
+// Build an unsigned transaction with the same nonce as the record. A speedup resends the same call; a cancellation
+// sends nothing to the node account itself, so the nonce is used up without doing anything.
+func BuildReplacement(record TxRecord, maxFee *big.Int, priorityFee *big.Int, cancel bool) *types.Transaction {
+	txData := &types.DynamicFeeTx{
+		ChainID:   record.ChainID,
+		Nonce:     record.Nonce,
+		GasTipCap: priorityFee,
+		GasFeeCap: maxFee,
+		Gas:       record.GasLimit,
+		To:        record.To,
+		Value:     record.Value,
+		Data:      record.Data,
+	}
+	if cancel {
+		from := record.From
+		txData.Gas = transferGasLimit
+		txData.To = &from
+		txData.Value = big.NewInt(0)
+		txData.Data = nil
+	}
+	return types.NewTx(txData)
+}
+
+func bump(fee *big.Int) *big.Int {
+	if fee == nil {
+		return big.NewInt(0)
+	}
+	bumped := new(big.Int).Div(fee, big.NewInt(8))
+	bumped.Add(bumped, fee)
+	return bumped.Add(bumped, big.NewInt(1))
+}
+
+func maxOf(values ...*big.Int) *big.Int {
+	max := big.NewInt(0)
+	for _, value := range values {
+		if value != nil && value.Cmp(max) > 0 {
+			max = value
+		}
+	}
+	return new(big.Int).Set(max)
+}
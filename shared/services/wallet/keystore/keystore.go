@@ -21,6 +21,7 @@ package keystore
 
 import (
 	"github.com/sethvargo/go-password/password"
+	"github.com/stader-labs/stader-node/stader-lib/types"
 	eth2types "github.com/wealdtech/go-eth2-types/v2"
 )
 
@@ -39,5 +40,11 @@ func GenerateRandomPassword() (string, error) {
 // Validator keystore interface
 type Keystore interface {
 	StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPath string) error
+	DeleteValidatorKey(pubkey types.ValidatorPubkey) error
 	GetKeystoreDir() string
 }
+
+// A validator keystore that can read back the keys stored in it
+type KeyLoader interface {
+	LoadValidatorKey(pubkey types.ValidatorPubkey) (*eth2types.BLSPrivateKey, error)
+}
@@ -139,3 +139,61 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
 	return nil
 
 }
+
+// Load a validator key stored in the keystore
+func (ks *Keystore) LoadValidatorKey(pubkey stadertypes.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
+
+	// Read secret from disk
+	secretFilePath := filepath.Join(ks.keystorePath, KeystoreDir, SecretsDir, hexutil.AddPrefix(pubkey.Hex()))
+	password, err := ioutil.ReadFile(secretFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read validator secret from disk: %w", err)
+	}
+
+	// Read key store from disk
+	keyFilePath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex()), KeyFileName)
+	keyStoreBytes, err := ioutil.ReadFile(keyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read validator key from disk: %w", err)
+	}
+
+	// Decode key store
+	var keyStore validatorKey
+	if err := json.Unmarshal(keyStoreBytes, &keyStore); err != nil {
+		return nil, fmt.Errorf("Could not decode validator key: %w", err)
+	}
+
+	// Decrypt key
+	keyBytes, err := ks.encryptor.Decrypt(keyStore.Crypto, string(password))
+	if err != nil {
+		return nil, fmt.Errorf("Could not decrypt validator key: %w", err)
+	}
+	key, err := eth2types.BLSPrivateKeyFromBytes(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("Could not decode validator key: %w", err)
+	}
+
+	// Return
+	return key, nil
+
+}
+
+// Remove a validator key from the keystore, so the validator client no longer loads it
+func (ks *Keystore) DeleteValidatorKey(pubkey stadertypes.ValidatorPubkey) error {
+
+	// Delete key
+	keyPath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex()))
+	if err := os.RemoveAll(keyPath); err != nil {
+		return fmt.Errorf("Could not delete validator key from disk: %w", err)
+	}
+
+	// Delete secret
+	secretFilePath := filepath.Join(ks.keystorePath, KeystoreDir, SecretsDir, hexutil.AddPrefix(pubkey.Hex()))
+	if err := os.Remove(secretFilePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Could not delete validator secret from disk: %w", err)
+	}
+
+	// Return
+	return nil
+
+}
@@ -0,0 +1,103 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package lighthouse
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	stadertypes "github.com/stader-labs/stader-node/stader-lib/types"
+	"gopkg.in/yaml.v2"
+
+	hexutil "github.com/stader-labs/stader-node/shared/utils/hex"
+)
+
+// Config
+const (
+	DefinitionsFileName = "validator_definitions.yml"
+	Web3SignerType      = "web3signer"
+)
+
+// Write the validator definitions for keys held by a remote signer, replacing any earlier remote signer definitions.
+// Lighthouse can't list the signer's keys on its own, so every key it should validate with has to be defined here.
+// Definitions of other types, such as local keystores, are kept.
+func (ks *Keystore) StoreRemoteValidatorKeys(pubkeys []stadertypes.ValidatorPubkey, signerUrl string) error {
+
+	// Get definitions file path
+	definitionsFilePath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, DefinitionsFileName)
+
+	// Keep the existing definitions that aren't for the remote signer
+	kept := []yaml.MapSlice{}
+	existing, err := ioutil.ReadFile(definitionsFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Could not read validator definitions: %w", err)
+	}
+	if err == nil {
+		var definitions []yaml.MapSlice
+		if err := yaml.Unmarshal(existing, &definitions); err != nil {
+			return fmt.Errorf("Could not parse validator definitions: %w", err)
+		}
+		for _, definition := range definitions {
+			isRemote := false
+			for _, item := range definition {
+				if item.Key == "type" && item.Value == Web3SignerType {
+					isRemote = true
+				}
+			}
+			if !isRemote {
+				kept = append(kept, definition)
+			}
+		}
+	}
+
+	// Build definitions; the values are quoted so the pubkeys aren't read as hex numbers
+	var definitions strings.Builder
+	definitions.WriteString("---\n")
+	if len(kept) > 0 {
+		keptBytes, err := yaml.Marshal(kept)
+		if err != nil {
+			return fmt.Errorf("Could not encode validator definitions: %w", err)
+		}
+		definitions.Write(keptBytes)
+	}
+	for _, pubkey := range pubkeys {
+		definitions.WriteString("- enabled: true\n")
+		definitions.WriteString(fmt.Sprintf("  voting_public_key: %q\n", hexutil.AddPrefix(pubkey.Hex())))
+		definitions.WriteString(fmt.Sprintf("  type: %s\n", Web3SignerType))
+		definitions.WriteString(fmt.Sprintf("  url: %q\n", signerUrl))
+	}
+
+	// Create validators dir
+	if err := os.MkdirAll(filepath.Dir(definitionsFilePath), DirMode); err != nil {
+		return fmt.Errorf("Could not create validator key folder: %w", err)
+	}
+
+	// Write definitions to disk
+	if err := ioutil.WriteFile(definitionsFilePath, []byte(definitions.String()), FileMode); err != nil {
+		return fmt.Errorf("Could not write validator definitions to disk: %w", err)
+	}
+
+	// Return
+	return nil
+
+}
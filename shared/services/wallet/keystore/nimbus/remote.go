@@ -0,0 +1,101 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package nimbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	stadertypes "github.com/stader-labs/stader-node/stader-lib/types"
+
+	hexutil "github.com/stader-labs/stader-node/shared/utils/hex"
+)
+
+// Config
+const (
+	RemoteKeyFileName = "remote_keystore.json"
+	Web3SignerType    = "web3signer"
+)
+
+// A key Nimbus signs with through a remote signer
+type remoteKey struct {
+	Version uint   `json:"version"`
+	Pubkey  string `json:"pubkey"`
+	Remote  string `json:"remote"`
+	Type    string `json:"type"`
+}
+
+// Write a remote keystore for each key held by a remote signer, and remove the remote keystores of any other keys.
+// Local keystores are left alone.
+func (ks *Keystore) StoreRemoteValidatorKeys(pubkeys []stadertypes.ValidatorPubkey, signerUrl string) error {
+
+	validatorsPath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir)
+	if err := os.MkdirAll(validatorsPath, DirMode); err != nil {
+		return fmt.Errorf("Could not create validator key folder: %w", err)
+	}
+
+	keep := map[string]bool{}
+	for _, pubkey := range pubkeys {
+		name := hexutil.AddPrefix(pubkey.Hex())
+		keep[name] = true
+
+		keyBytes, err := json.Marshal(remoteKey{
+			Version: 1,
+			Pubkey:  name,
+			Remote:  signerUrl,
+			Type:    Web3SignerType,
+		})
+		if err != nil {
+			return fmt.Errorf("Could not encode remote keystore for %s: %w", name, err)
+		}
+		keyPath := filepath.Join(validatorsPath, name)
+		if err := os.MkdirAll(keyPath, DirMode); err != nil {
+			return fmt.Errorf("Could not create validator key folder: %w", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(keyPath, RemoteKeyFileName), keyBytes, FileMode); err != nil {
+			return fmt.Errorf("Could not write remote keystore for %s to disk: %w", name, err)
+		}
+	}
+
+	// Drop the remote keystores of keys the validator client shouldn't use anymore
+	entries, err := ioutil.ReadDir(validatorsPath)
+	if err != nil {
+		return fmt.Errorf("Could not read validator key folder: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || keep[entry.Name()] {
+			continue
+		}
+		remoteKeyPath := filepath.Join(validatorsPath, entry.Name(), RemoteKeyFileName)
+		if _, err := os.Stat(remoteKeyPath); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(validatorsPath, entry.Name())); err != nil {
+			return fmt.Errorf("Could not remove remote keystore %s: %w", entry.Name(), err)
+		}
+	}
+
+	// Return
+	return nil
+
+}
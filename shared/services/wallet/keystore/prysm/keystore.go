@@ -27,9 +27,11 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
 	staderkeystore "github.com/stader-labs/stader-node/shared/services/wallet/keystore"
+	"github.com/stader-labs/stader-node/stader-lib/types"
 	eth2types "github.com/wealdtech/go-eth2-types/v2"
 	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
 
@@ -56,6 +58,9 @@ type Keystore struct {
 	pm           *passwords.PasswordManager
 	as           *accountStore
 	encryptor    *eth2ks.Encryptor
+
+	// When the account store file was last written, to notice another process changing it
+	asModTime time.Time
 }
 
 // Encrypted validator keystore
@@ -109,6 +114,43 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
 	ks.as.PrivateKeys = append(ks.as.PrivateKeys, key.Marshal())
 	ks.as.PublicKeys = append(ks.as.PublicKeys, key.PublicKey().Marshal())
 
+	// Save account store
+	return ks.save()
+
+}
+
+// Remove a validator key from the account store, so the validator client no longer loads it
+func (ks *Keystore) DeleteValidatorKey(pubkey types.ValidatorPubkey) error {
+
+	// Initialize the account store
+	if err := ks.initialize(); err != nil {
+		return err
+	}
+
+	// Remove validator key from account store
+	privateKeys := [][]byte{}
+	publicKeys := [][]byte{}
+	for ki := 0; ki < len(ks.as.PublicKeys); ki++ {
+		if bytes.Equal(pubkey.Bytes(), ks.as.PublicKeys[ki]) {
+			continue
+		}
+		privateKeys = append(privateKeys, ks.as.PrivateKeys[ki])
+		publicKeys = append(publicKeys, ks.as.PublicKeys[ki])
+	}
+	if len(publicKeys) == len(ks.as.PublicKeys) {
+		return nil
+	}
+	ks.as.PrivateKeys = privateKeys
+	ks.as.PublicKeys = publicKeys
+
+	// Save account store
+	return ks.save()
+
+}
+
+// Encrypt the account store and write it to disk
+func (ks *Keystore) save() error {
+
 	// Encode account store
 	asBytes, err := json.Marshal(ks.as)
 	if err != nil {
@@ -156,6 +198,9 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
 	if err := ioutil.WriteFile(keystoreFilePath, ksBytes, FileMode); err != nil {
 		return fmt.Errorf("Could not write keystore to disk: %w", err)
 	}
+	if info, err := os.Stat(keystoreFilePath); err == nil {
+		ks.asModTime = info.ModTime()
+	}
 
 	// Return if wallet config file exists
 	if _, err := os.Stat(configFilePath); !os.IsNotExist(err) {
@@ -183,9 +228,12 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
 // Initialize the account store
 func (ks *Keystore) initialize() error {
 
-	// Cancel if already initialized
+	// Cancel if already initialized, unless the node daemon or the API has written the account store since
 	if ks.as != nil {
-		return nil
+		info, err := os.Stat(filepath.Join(ks.keystorePath, KeystoreDir, WalletDir, AccountsDir, KeystoreFileName))
+		if err != nil || info.ModTime().Equal(ks.asModTime) {
+			return nil
+		}
 	}
 
 	// Create the random keystore password if it doesn't exist
@@ -221,11 +269,15 @@ func (ks *Keystore) initialize() error {
 	password = string(passwordBytes)
 
 	// Read keystore file; initialize empty account store if it doesn't exist
-	ksBytes, err := ioutil.ReadFile(filepath.Join(ks.keystorePath, KeystoreDir, WalletDir, AccountsDir, KeystoreFileName))
+	keystoreFilePath := filepath.Join(ks.keystorePath, KeystoreDir, WalletDir, AccountsDir, KeystoreFileName)
+	ksBytes, err := ioutil.ReadFile(keystoreFilePath)
 	if err != nil {
 		ks.as = &accountStore{}
 		return nil
 	}
+	if info, err := os.Stat(keystoreFilePath); err == nil {
+		ks.asModTime = info.ModTime()
+	}
 
 	// Decode keystore
 	keystore := &validatorKeystore{}
@@ -138,3 +138,23 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
 	return nil
 
 }
+
+// Remove a validator key from the keystore, so the validator client no longer loads it
+func (ks *Keystore) DeleteValidatorKey(pubkey stadertypes.ValidatorPubkey) error {
+
+	// Delete key
+	keyPath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex())+".json")
+	if err := os.RemoveAll(keyPath); err != nil {
+		return fmt.Errorf("Could not delete validator key from disk: %w", err)
+	}
+
+	// Delete secret
+	secretFilePath := filepath.Join(ks.keystorePath, KeystoreDir, SecretsDir, hexutil.AddPrefix(pubkey.Hex())+".txt")
+	if err := os.Remove(secretFilePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Could not delete validator secret from disk: %w", err)
+	}
+
+	// Return
+	return nil
+
+}
@@ -29,12 +29,38 @@ import (
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/stader-labs/stader-node/shared/utils/offline"
 )
 
+// Use a node address without its key, for a node whose wallet is kept on an offline machine
+func (w *Wallet) SetWatchOnlyAddress(address common.Address) {
+	w.watchAddress = &address
+}
+
+// Check if the wallet only knows the node address
+func (w *Wallet) IsWatchOnly() bool {
+	return w.watchAddress != nil
+}
+
+// Build transactions without signing or sending them, recording them so they can be signed offline.
+// A non-zero gas limit is used instead of estimating, for transactions that can only succeed once an earlier exported one is mined.
+func (w *Wallet) EnableUnsignedTransactionExport(gasLimit uint64) {
+	w.exportUnsignedTx = true
+	w.gasLimit = gasLimit
+}
+
 // Get the node account
 func (w *Wallet) GetNodeAccount() (accounts.Account, error) {
 
+	// Watch-only wallets only have the address
+	if w.watchAddress != nil {
+		return accounts.Account{Address: *w.watchAddress}, nil
+	}
+
 	// Check wallet is initialized
 	if !w.IsInitialized() {
 		return accounts.Account{}, errors.New("Wallet is not initialized")
@@ -67,7 +93,15 @@ func (w *Wallet) GetNodeAccount() (accounts.Account, error) {
 // Get a transactor for the node account
 func (w *Wallet) GetNodeAccountTransactor() (*bind.TransactOpts, error) {
 
+	// Export transactions for offline signing instead of sending them
+	if w.exportUnsignedTx {
+		return w.getExportTransactor()
+	}
+
 	// Check wallet is initialized
+	if w.watchAddress != nil {
+		return nil, errors.New("The node wallet is watch-only; use --export-unsigned-tx to export the transaction and sign it on the machine that holds the wallet")
+	}
 	if !w.IsInitialized() {
 		return nil, errors.New("Wallet is not initialized")
 	}
@@ -88,6 +122,52 @@ func (w *Wallet) GetNodeAccountTransactor() (*bind.TransactOpts, error) {
 
 }
 
+// Get a transactor that builds transactions without signing or sending them, for requests that are completed on an offline machine
+func (w *Wallet) GetNodeAccountTemplateTransactor() (*bind.TransactOpts, error) {
+
+	// Get node account
+	account, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create & return transactor
+	return &bind.TransactOpts{
+		From: account.Address,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+		NoSend:    true,
+		GasFeeCap: w.maxFee,
+		GasTipCap: w.maxPriorityFee,
+		GasLimit:  w.gasLimit,
+		Context:   context.Background(),
+	}, nil
+
+}
+
+// Get a transactor that builds transactions without signing or sending them, recording each one for export
+func (w *Wallet) getExportTransactor() (*bind.TransactOpts, error) {
+
+	// Get template transactor
+	transactor, err := w.GetNodeAccountTemplateTransactor()
+	if err != nil {
+		return nil, err
+	}
+
+	// Record transactions instead of signing them
+	transactor.Signer = func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		unsignedTx, err := offline.NewUnsignedTransaction(address, tx)
+		if err != nil {
+			return nil, err
+		}
+		offline.RecordUnsignedTransaction(unsignedTx)
+		return tx, nil
+	}
+	return transactor, nil
+
+}
+
 func (w *Wallet) GetNodePrivateKey() (*ecdsa.PrivateKey, error) {
 	// Check wallet is initialized
 	if !w.IsInitialized() {
@@ -24,12 +24,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 
 	"github.com/stader-labs/stader-node/stader-lib/types"
 	stadertypes "github.com/stader-labs/stader-node/stader-lib/types"
 	eth2types "github.com/wealdtech/go-eth2-types/v2"
 	eth2util "github.com/wealdtech/go-eth2-util"
+
+	"github.com/stader-labs/stader-node/shared/services/wallet/keystore"
 )
 
 // Config
@@ -93,6 +96,11 @@ func (w *Wallet) GetValidatorKeyByPubkey(pubkey stadertypes.ValidatorPubkey) (*e
 		}
 	}
 
+	// Check for cached imported validator key
+	if key, ok := w.importedValidatorKeys[pubkeyHex]; ok {
+		return key, nil
+	}
+
 	// Find matching validator key
 	var index uint
 	var validatorKey *eth2types.BLSPrivateKey
@@ -105,8 +113,12 @@ func (w *Wallet) GetValidatorKeyByPubkey(pubkey stadertypes.ValidatorPubkey) (*e
 		}
 	}
 
-	// Check validator key
+	// Fall back to keys that were imported rather than derived from the wallet seed
 	if validatorKey == nil {
+		if key := w.loadImportedValidatorKey(pubkey); key != nil {
+			w.importedValidatorKeys[pubkeyHex] = key
+			return key, nil
+		}
 		return nil, fmt.Errorf("Validator %s key not found", pubkeyHex)
 	}
 
@@ -177,6 +189,85 @@ func (w *Wallet) StoreValidatorKey(key *eth2types.BLSPrivateKey, path string) er
 
 }
 
+// Import a validator key that wasn't derived from the wallet seed, storing it in every keystore
+func (w *Wallet) ImportValidatorKey(key *eth2types.BLSPrivateKey, derivationPath string) error {
+
+	// Check wallet is initialized
+	if !w.IsInitialized() {
+		return errors.New("Wallet is not initialized")
+	}
+
+	// Update keystores
+	if err := w.StoreValidatorKey(key, derivationPath); err != nil {
+		return err
+	}
+
+	// Cache validator key
+	pubkey := types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+	w.importedValidatorKeys[pubkey.Hex()] = key
+
+	// Return
+	return nil
+
+}
+
+// Move a validator key out of every validator client keystore into the held keystore, so the validator client
+// doesn't load it until it's released
+func (w *Wallet) HoldValidatorKey(pubkey stadertypes.ValidatorPubkey) error {
+
+	// Check the held keystore is set
+	if w.heldKeystore == nil {
+		return errors.New("No keystore is set up for held validator keys")
+	}
+
+	// Get validator key
+	key, err := w.GetValidatorKeyByPubkey(pubkey)
+	if err != nil {
+		return err
+	}
+
+	// Store the key before removing it anywhere else, so it can't get lost
+	if err := w.heldKeystore.StoreValidatorKey(key, w.getValidatorKeyPath(pubkey)); err != nil {
+		return fmt.Errorf("Could not store held validator key: %w", err)
+	}
+	for name := range w.keystores {
+		if err := w.keystores[name].DeleteValidatorKey(pubkey); err != nil {
+			return fmt.Errorf("Could not remove validator key from %s keystore: %w", name, err)
+		}
+	}
+
+	// Return
+	return nil
+
+}
+
+// Move a held validator key back into every validator client keystore
+func (w *Wallet) ReleaseValidatorKey(pubkey stadertypes.ValidatorPubkey) error {
+
+	// Check the held keystore is set
+	if w.heldKeystore == nil {
+		return errors.New("No keystore is set up for held validator keys")
+	}
+
+	// Get validator key
+	key, err := w.GetValidatorKeyByPubkey(pubkey)
+	if err != nil {
+		return err
+	}
+
+	// Update keystores
+	if err := w.StoreValidatorKey(key, w.getValidatorKeyPath(pubkey)); err != nil {
+		return err
+	}
+	if err := w.heldKeystore.DeleteValidatorKey(pubkey); err != nil {
+		return fmt.Errorf("Could not remove held validator key: %w", err)
+	}
+
+	// Return
+	return nil
+
+}
+
 // Deletes all of the keystore directories and persistent VC storage
 func (w *Wallet) DeleteValidatorStores() error {
 
@@ -187,6 +278,11 @@ func (w *Wallet) DeleteValidatorStores() error {
 			return fmt.Errorf("error deleting validator directory for %s: %w", name, err)
 		}
 	}
+	if w.heldKeystore != nil {
+		if err := os.RemoveAll(w.heldKeystore.GetKeystoreDir()); err != nil {
+			return fmt.Errorf("error deleting held validator keys: %w", err)
+		}
+	}
 
 	return nil
 
@@ -388,6 +484,56 @@ func (w *Wallet) getValidatorPrivateKey(index uint) (*eth2types.BLSPrivateKey, s
 
 }
 
+// Load an imported validator key from the first keystore that has it, or nil if none do
+func (w *Wallet) loadImportedValidatorKey(pubkey stadertypes.ValidatorPubkey) *eth2types.BLSPrivateKey {
+
+	// Initialize BLS support
+	if err := initializeBLS(); err != nil {
+		return nil
+	}
+
+	// Iterate in a fixed order so the same keystore is preferred every time
+	names := make([]string, 0, len(w.keystores))
+	for name := range w.keystores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Keys held back by the doppelganger watch are only in the held keystore
+	keystores := make([]keystore.Keystore, 0, len(names)+1)
+	for _, name := range names {
+		keystores = append(keystores, w.keystores[name])
+	}
+	if w.heldKeystore != nil {
+		keystores = append(keystores, w.heldKeystore)
+	}
+
+	for _, ks := range keystores {
+		loader, ok := ks.(keystore.KeyLoader)
+		if !ok {
+			continue
+		}
+		key, err := loader.LoadValidatorKey(pubkey)
+		if err != nil || key == nil {
+			continue
+		}
+		if bytes.Equal(pubkey.Bytes(), key.PublicKey().Marshal()) {
+			return key
+		}
+	}
+
+	return nil
+
+}
+
+// Get the derivation path of a validator key, or an empty path if it was imported rather than derived from the wallet seed
+func (w *Wallet) getValidatorKeyPath(pubkey stadertypes.ValidatorPubkey) string {
+	if index, ok := w.validatorKeyIndices[pubkey.Hex()]; ok {
+		return fmt.Sprintf(ValidatorKeyPath, index)
+	}
+	return ""
+}
+
 // Initialize BLS support
 var initBLS sync.Once
 
@@ -31,6 +31,7 @@ import (
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/google/uuid"
@@ -72,12 +73,20 @@ type Wallet struct {
 	nodeKeyPath string
 
 	// Validator key caches
-	validatorKeys       map[uint]*eth2types.BLSPrivateKey
-	validatorKeyIndices map[string]uint
+	validatorKeys         map[uint]*eth2types.BLSPrivateKey
+	validatorKeyIndices   map[string]uint
+	importedValidatorKeys map[string]*eth2types.BLSPrivateKey
 
 	// Keystores
 	keystores map[string]keystore.Keystore
 
+	// Keys held back from the validator client by the doppelganger watch, kept outside of every validator client's folder
+	heldKeystore keystore.Keystore
+
+	// Offline signing support; a watch-only wallet only knows the node address
+	watchAddress     *common.Address
+	exportUnsignedTx bool
+
 	// Desired gas price & limit from config
 	maxFee         *big.Int
 	maxPriorityFee *big.Int
@@ -100,16 +109,17 @@ func NewWallet(walletPath string, chainId uint, maxFee *big.Int, maxPriorityFee
 
 	// Initialize wallet
 	w := &Wallet{
-		walletPath:          walletPath,
-		pm:                  passwordManager,
-		encryptor:           eth2ks.New(),
-		chainID:             big.NewInt(int64(chainId)),
-		validatorKeys:       map[uint]*eth2types.BLSPrivateKey{},
-		validatorKeyIndices: map[string]uint{},
-		keystores:           map[string]keystore.Keystore{},
-		maxFee:              maxFee,
-		maxPriorityFee:      maxPriorityFee,
-		gasLimit:            gasLimit,
+		walletPath:            walletPath,
+		pm:                    passwordManager,
+		encryptor:             eth2ks.New(),
+		chainID:               big.NewInt(int64(chainId)),
+		validatorKeys:         map[uint]*eth2types.BLSPrivateKey{},
+		validatorKeyIndices:   map[string]uint{},
+		importedValidatorKeys: map[string]*eth2types.BLSPrivateKey{},
+		keystores:             map[string]keystore.Keystore{},
+		maxFee:                maxFee,
+		maxPriorityFee:        maxPriorityFee,
+		gasLimit:              gasLimit,
 	}
 
 	// Load & decrypt wallet store
@@ -133,6 +143,11 @@ func (w *Wallet) AddKeystore(name string, ks keystore.Keystore) {
 	w.keystores[name] = ks
 }
 
+// Set the keystore validator keys are moved to while they're held back from the validator client
+func (w *Wallet) SetHeldKeystore(ks keystore.Keystore) {
+	w.heldKeystore = ks
+}
+
 // Check if the wallet has been initialized
 func (w *Wallet) IsInitialized() bool {
 	return (w.ws != nil && w.seed != nil && w.mk != nil)
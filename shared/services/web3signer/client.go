@@ -0,0 +1,228 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package web3signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	hexutil "github.com/stader-labs/stader-node/shared/utils/hex"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Config
+const (
+	RequestContentType = "application/json"
+
+	RequestUpcheckPath    = "/upcheck"
+	RequestPublicKeysPath = "/api/v1/eth2/publicKeys"
+	RequestSignPath       = "/api/v1/eth2/sign/%s"
+
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// Client for a remote signer implementing the EIP-3030 signing API, such as Web3Signer
+type Client struct {
+	providerAddress string
+	httpClient      *http.Client
+}
+
+// Create a new client instance
+func NewClient(providerAddress string, timeout time.Duration) *Client {
+	return &Client{
+		providerAddress: strings.TrimSuffix(providerAddress, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Get the signer URL
+func (c *Client) GetUrl() string {
+	return c.providerAddress
+}
+
+// Check that the signer is up
+func (c *Client) Upcheck() error {
+	responseBody, status, err := c.getRequest(RequestUpcheckPath)
+	if err != nil {
+		return fmt.Errorf("Could not reach remote signer at %s: %w", c.providerAddress, err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("Remote signer at %s is not ready: HTTP status %d; response body: '%s'", c.providerAddress, status, string(responseBody))
+	}
+	return nil
+}
+
+// Get the validator public keys the signer holds
+func (c *Client) GetPublicKeys() ([]types.ValidatorPubkey, error) {
+	responseBody, status, err := c.getRequest(RequestPublicKeysPath)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get remote signer public keys: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Could not get remote signer public keys: HTTP status %d; response body: '%s'", status, string(responseBody))
+	}
+
+	var pubkeyStrings []string
+	if err := json.Unmarshal(responseBody, &pubkeyStrings); err != nil {
+		return nil, fmt.Errorf("Could not decode remote signer public keys: %w", err)
+	}
+
+	pubkeys := make([]types.ValidatorPubkey, 0, len(pubkeyStrings))
+	for _, pubkeyString := range pubkeyStrings {
+		pubkey, err := types.HexToValidatorPubkey(hexutil.RemovePrefix(pubkeyString))
+		if err != nil {
+			return nil, fmt.Errorf("Remote signer returned an invalid public key: %w", err)
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys, nil
+}
+
+// Check if the signer holds the key for a validator
+func (c *Client) HasPublicKey(pubkey types.ValidatorPubkey) (bool, error) {
+	pubkeys, err := c.GetPublicKeys()
+	if err != nil {
+		return false, err
+	}
+	for _, signerPubkey := range pubkeys {
+		if signerPubkey == pubkey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Sign deposit data for a validator
+func (c *Client) SignDeposit(pubkey types.ValidatorPubkey, withdrawalCredentials common.Hash, amount uint64, genesisForkVersion []byte, signingRoot common.Hash) (types.ValidatorSignature, error) {
+	return c.Sign(pubkey, SignRequest{
+		Type:        SignRequestType_Deposit,
+		SigningRoot: signingRoot.Hex(),
+		Deposit: &DepositMessage{
+			Pubkey:                hexutil.AddPrefix(pubkey.Hex()),
+			WithdrawalCredentials: withdrawalCredentials.Hex(),
+			Amount:                strconv.FormatUint(amount, 10),
+			GenesisForkVersion:    encodeBytes(genesisForkVersion),
+		},
+	})
+}
+
+// Sign a voluntary exit for a validator
+func (c *Client) SignVoluntaryExit(pubkey types.ValidatorPubkey, validatorIndex uint64, epoch uint64, forkInfo beacon.ForkInfo, signingRoot common.Hash) (types.ValidatorSignature, error) {
+	return c.Sign(pubkey, SignRequest{
+		Type: SignRequestType_VoluntaryExit,
+		ForkInfo: &ForkInfo{
+			Fork: Fork{
+				PreviousVersion: encodeBytes(forkInfo.PreviousVersion),
+				CurrentVersion:  encodeBytes(forkInfo.CurrentVersion),
+				Epoch:           strconv.FormatUint(forkInfo.Epoch, 10),
+			},
+			GenesisValidatorsRoot: encodeBytes(forkInfo.GenesisValidatorsRoot),
+		},
+		SigningRoot: signingRoot.Hex(),
+		VoluntaryExit: &VoluntaryExit{
+			Epoch:          strconv.FormatUint(epoch, 10),
+			ValidatorIndex: strconv.FormatUint(validatorIndex, 10),
+		},
+	})
+}
+
+// Send a signing request for a validator key
+func (c *Client) Sign(pubkey types.ValidatorPubkey, request SignRequest) (types.ValidatorSignature, error) {
+	responseBody, status, err := c.postRequest(fmt.Sprintf(RequestSignPath, hexutil.AddPrefix(pubkey.Hex())), request)
+	if err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("Could not sign %s for validator %s: %w", request.Type, pubkey.Hex(), err)
+	}
+	if status == http.StatusNotFound {
+		return types.ValidatorSignature{}, fmt.Errorf("Remote signer does not hold the key for validator %s", pubkey.Hex())
+	}
+	if status != http.StatusOK {
+		return types.ValidatorSignature{}, fmt.Errorf("Could not sign %s for validator %s: HTTP status %d; response body: '%s'", request.Type, pubkey.Hex(), status, string(responseBody))
+	}
+
+	// The signer answers with JSON if asked to, but older versions only return the signature as plain text
+	signatureString := strings.TrimSpace(string(responseBody))
+	var signResponse SignResponse
+	if err := json.Unmarshal(responseBody, &signResponse); err == nil {
+		signatureString = signResponse.Signature
+	}
+
+	signature, err := types.HexToValidatorSignature(hexutil.RemovePrefix(signatureString))
+	if err != nil {
+		return types.ValidatorSignature{}, fmt.Errorf("Remote signer returned an invalid signature for validator %s: %w", pubkey.Hex(), err)
+	}
+	return signature, nil
+}
+
+// Make a GET request to the signer
+func (c *Client) getRequest(requestPath string) ([]byte, int, error) {
+	request, err := http.NewRequest(http.MethodGet, c.providerAddress+requestPath, nil)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	return c.doRequest(request)
+}
+
+// Make a POST request to the signer
+func (c *Client) postRequest(requestPath string, requestBody interface{}) ([]byte, int, error) {
+	requestBodyBytes, err := json.Marshal(requestBody)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	request, err := http.NewRequest(http.MethodPost, c.providerAddress+requestPath, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	request.Header.Set("Content-Type", RequestContentType)
+	return c.doRequest(request)
+}
+
+// Send a request and read the response
+func (c *Client) doRequest(request *http.Request) ([]byte, int, error) {
+	request.Header.Set("Accept", RequestContentType)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	return body, response.StatusCode, nil
+}
+
+// Hex encode bytes with a 0x prefix
+func encodeBytes(value []byte) string {
+	return hexutil.AddPrefix(fmt.Sprintf("%x", value))
+}
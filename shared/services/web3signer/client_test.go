@@ -0,0 +1,179 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package web3signer_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
+	"github.com/stader-labs/stader-node/shared/services/web3signer/web3signertest"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+func testPubkey(b byte) types.ValidatorPubkey {
+	var pubkey types.ValidatorPubkey
+	for i := range pubkey {
+		pubkey[i] = b
+	}
+	return pubkey
+}
+
+func testSignature(b byte) types.ValidatorSignature {
+	var signature types.ValidatorSignature
+	for i := range signature {
+		signature[i] = b
+	}
+	return signature
+}
+
+func startSigner(t *testing.T) (*web3signertest.MockServer, *web3signer.Client) {
+	signer := web3signertest.NewMockServer(map[types.ValidatorPubkey]types.ValidatorSignature{
+		testPubkey(0xa1): testSignature(0x11),
+		testPubkey(0xa2): testSignature(0x22),
+	})
+	url := signer.Start()
+	t.Cleanup(signer.Close)
+	// A trailing slash on the configured URL shouldn't break the request paths
+	return signer, web3signer.NewClient(url+"/", 5*time.Second)
+}
+
+func TestUpcheck(t *testing.T) {
+	signer, client := startSigner(t)
+	if err := client.Upcheck(); err != nil {
+		t.Fatalf("upcheck failed on a ready signer: %s", err)
+	}
+	signer.SetReady(false)
+	if err := client.Upcheck(); err == nil {
+		t.Fatal("upcheck passed on a signer that isn't ready")
+	}
+}
+
+func TestGetPublicKeys(t *testing.T) {
+	_, client := startSigner(t)
+	pubkeys, err := client.GetPublicKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pubkeys) != 2 {
+		t.Fatalf("got %d public keys, want 2", len(pubkeys))
+	}
+
+	for _, test := range []struct {
+		pubkey types.ValidatorPubkey
+		held   bool
+	}{
+		{testPubkey(0xa1), true},
+		{testPubkey(0xa2), true},
+		{testPubkey(0xa3), false},
+	} {
+		held, err := client.HasPublicKey(test.pubkey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held != test.held {
+			t.Errorf("HasPublicKey(%s) = %t, want %t", test.pubkey.Hex(), held, test.held)
+		}
+	}
+}
+
+func TestSignDeposit(t *testing.T) {
+	signer, client := startSigner(t)
+	withdrawalCredentials := common.HexToHash("0x0100000000000000000000001234567890123456789012345678901234567890")
+	signingRoot := common.HexToHash("0xabcdef")
+
+	signature, err := client.SignDeposit(testPubkey(0xa1), withdrawalCredentials, 1000000000, []byte{0x00, 0x00, 0x10, 0x20}, signingRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature != testSignature(0x11) {
+		t.Errorf("got signature %s, want the signer's signature for the key", signature.Hex())
+	}
+
+	requests := signer.GetRequests()
+	if len(requests) != 1 {
+		t.Fatalf("signer got %d requests, want 1", len(requests))
+	}
+	request := requests[0]
+	if request.Type != web3signer.SignRequestType_Deposit || request.Deposit == nil {
+		t.Fatalf("signer got a %s request without the deposit message", request.Type)
+	}
+	if request.SigningRoot != signingRoot.Hex() {
+		t.Errorf("signing root is %s, want %s", request.SigningRoot, signingRoot.Hex())
+	}
+	if request.Deposit.Amount != "1000000000" {
+		t.Errorf("deposit amount is %s, want 1000000000", request.Deposit.Amount)
+	}
+	if request.Deposit.WithdrawalCredentials != withdrawalCredentials.Hex() {
+		t.Errorf("withdrawal credentials are %s, want %s", request.Deposit.WithdrawalCredentials, withdrawalCredentials.Hex())
+	}
+	if request.Deposit.GenesisForkVersion != "0x00001020" {
+		t.Errorf("genesis fork version is %s, want 0x00001020", request.Deposit.GenesisForkVersion)
+	}
+	if request.Deposit.Pubkey != "0x"+testPubkey(0xa1).Hex() {
+		t.Errorf("deposit pubkey is %s, want the signing key", request.Deposit.Pubkey)
+	}
+}
+
+func TestSignVoluntaryExitPlainText(t *testing.T) {
+	signer, client := startSigner(t)
+	// Older signers answer with the bare signature
+	signer.SetPlainText(true)
+
+	forkInfo := beacon.ForkInfo{
+		PreviousVersion:       []byte{0x01, 0x00, 0x10, 0x20},
+		CurrentVersion:        []byte{0x02, 0x00, 0x10, 0x20},
+		Epoch:                 100,
+		GenesisValidatorsRoot: common.HexToHash("0x043db0d9a83813551ee2f33450d23797757d430911a9320530ad8a0eabc43efb").Bytes(),
+	}
+	signature, err := client.SignVoluntaryExit(testPubkey(0xa2), 42, 200, forkInfo, common.HexToHash("0x1234"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature != testSignature(0x22) {
+		t.Errorf("got signature %s, want the signer's signature for the key", signature.Hex())
+	}
+
+	request := signer.GetRequests()[0]
+	if request.Type != web3signer.SignRequestType_VoluntaryExit || request.VoluntaryExit == nil || request.ForkInfo == nil {
+		t.Fatalf("signer got a %s request without the exit message and fork info", request.Type)
+	}
+	if request.VoluntaryExit.Epoch != "200" || request.VoluntaryExit.ValidatorIndex != "42" {
+		t.Errorf("exit message is for validator %s at epoch %s, want 42 at 200", request.VoluntaryExit.ValidatorIndex, request.VoluntaryExit.Epoch)
+	}
+	if request.ForkInfo.Fork.CurrentVersion != "0x02001020" || request.ForkInfo.Fork.Epoch != "100" {
+		t.Errorf("fork is %s at epoch %s, want 0x02001020 at 100", request.ForkInfo.Fork.CurrentVersion, request.ForkInfo.Fork.Epoch)
+	}
+}
+
+func TestSignUnknownKey(t *testing.T) {
+	_, client := startSigner(t)
+	_, err := client.SignDeposit(testPubkey(0xa3), common.Hash{}, 1000000000, []byte{0, 0, 0, 0}, common.Hash{})
+	if err == nil {
+		t.Fatal("signing with a key the signer doesn't hold succeeded")
+	}
+	if !strings.Contains(err.Error(), "does not hold the key") {
+		t.Errorf("unexpected error for a missing key: %s", err)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package web3signer
+
+// Signing request types (https://consensys.github.io/web3signer/web3signer-eth2.html)
+type SignRequestType string
+
+const (
+	SignRequestType_Deposit       SignRequestType = "DEPOSIT"
+	SignRequestType_VoluntaryExit SignRequestType = "VOLUNTARY_EXIT"
+)
+
+// Request types
+type Fork struct {
+	PreviousVersion string `json:"previous_version"`
+	CurrentVersion  string `json:"current_version"`
+	Epoch           string `json:"epoch"`
+}
+type ForkInfo struct {
+	Fork                  Fork   `json:"fork"`
+	GenesisValidatorsRoot string `json:"genesis_validators_root"`
+}
+type DepositMessage struct {
+	Pubkey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                string `json:"amount"`
+	GenesisForkVersion    string `json:"genesis_fork_version"`
+}
+type VoluntaryExit struct {
+	Epoch          string `json:"epoch"`
+	ValidatorIndex string `json:"validator_index"`
+}
+type SignRequest struct {
+	Type          SignRequestType `json:"type"`
+	ForkInfo      *ForkInfo       `json:"fork_info,omitempty"`
+	SigningRoot   string          `json:"signingRoot,omitempty"`
+	Deposit       *DepositMessage `json:"deposit,omitempty"`
+	VoluntaryExit *VoluntaryExit  `json:"voluntary_exit,omitempty"`
+}
+
+// Response types
+type SignResponse struct {
+	Signature string `json:"signature"`
+}
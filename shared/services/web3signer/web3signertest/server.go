@@ -0,0 +1,142 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package web3signertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
+	hexutil "github.com/stader-labs/stader-node/shared/utils/hex"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// In-process stand-in for a Web3Signer instance.
+// It holds a fixed set of keys and answers every signing request for one of them with the key's fixed signature.
+type MockServer struct {
+	server *httptest.Server
+
+	pubkeys    []types.ValidatorPubkey
+	signatures map[types.ValidatorPubkey]types.ValidatorSignature
+	plainText  bool
+	ready      bool
+	requests   []web3signer.SignRequest
+	lock       sync.Mutex
+}
+
+// Create a new mock signer holding the given keys
+func NewMockServer(signatures map[types.ValidatorPubkey]types.ValidatorSignature) *MockServer {
+	m := &MockServer{
+		signatures: signatures,
+		ready:      true,
+	}
+	for pubkey := range signatures {
+		m.pubkeys = append(m.pubkeys, pubkey)
+	}
+	return m
+}
+
+// Start serving the signing API on a local port, returning its URL
+func (m *MockServer) Start() string {
+	m.server = httptest.NewServer(m)
+	return m.server.URL
+}
+
+// Stop serving the signing API
+func (m *MockServer) Close() {
+	if m.server != nil {
+		m.server.Close()
+	}
+}
+
+// Answer signing requests with the bare signature, as older signer versions do
+func (m *MockServer) SetPlainText(plainText bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.plainText = plainText
+}
+
+// Set whether the upcheck reports the signer as ready
+func (m *MockServer) SetReady(ready bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.ready = ready
+}
+
+// Get the signing requests the signer has answered
+func (m *MockServer) GetRequests() []web3signer.SignRequest {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]web3signer.SignRequest{}, m.requests...)
+}
+
+// Handle a signing API request
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	switch {
+	case r.URL.Path == web3signer.RequestUpcheckPath:
+		if !m.ready {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("OK"))
+
+	case r.URL.Path == web3signer.RequestPublicKeysPath:
+		pubkeys := make([]string, len(m.pubkeys))
+		for i, pubkey := range m.pubkeys {
+			pubkeys[i] = hexutil.AddPrefix(pubkey.Hex())
+		}
+		w.Header().Set("Content-Type", web3signer.RequestContentType)
+		_ = json.NewEncoder(w).Encode(pubkeys)
+
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, strings.TrimSuffix(web3signer.RequestSignPath, "%s")):
+		pubkey, err := types.HexToValidatorPubkey(hexutil.RemovePrefix(strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(web3signer.RequestSignPath, "%s"))))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		signature, ok := m.signatures[pubkey]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var request web3signer.SignRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.requests = append(m.requests, request)
+
+		if m.plainText {
+			_, _ = w.Write([]byte(hexutil.AddPrefix(signature.Hex())))
+			return
+		}
+		w.Header().Set("Content-Type", web3signer.RequestContentType)
+		_ = json.NewEncoder(w).Encode(web3signer.SignResponse{Signature: hexutil.AddPrefix(signature.Hex())})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
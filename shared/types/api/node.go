@@ -5,6 +5,10 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/stader-labs/stader-node/shared/services/doppelganger"
+	"github.com/stader-labs/stader-node/shared/services/ledger"
+	"github.com/stader-labs/stader-node/shared/services/presign"
+	"github.com/stader-labs/stader-node/shared/services/txmanager"
 	"github.com/stader-labs/stader-node/shared/utils/stdr"
 	"github.com/stader-labs/stader-node/stader-lib/stader"
 	"github.com/stader-labs/stader-node/stader-lib/types"
@@ -104,9 +108,34 @@ type CanNodeDepositResponse struct {
 }
 
 type NodeDepositResponse struct {
-	Status string      `json:"status"`
-	Error  string      `json:"error"`
-	TxHash common.Hash `json:"txHash"`
+	Status            string      `json:"status"`
+	Error             string      `json:"error"`
+	TxHash            common.Hash `json:"txHash"`
+	DoppelgangerWatch bool        `json:"doppelgangerWatch"`
+}
+
+type CanImportValidatorKeysResponse struct {
+	Status                   string                  `json:"status"`
+	Error                    string                  `json:"error"`
+	CanImport                bool                    `json:"canImport"`
+	Pubkeys                  []types.ValidatorPubkey `json:"pubkeys"`
+	ExistingOnBeaconChain    []types.ValidatorPubkey `json:"existingOnBeaconChain"`
+	RegisteredWithStader     []types.ValidatorPubkey `json:"registeredWithStader"`
+	InsufficientBalance      bool                    `json:"insufficientBalance"`
+	DepositPaused            bool                    `json:"depositPaused"`
+	NotEnoughSdCollateral    bool                    `json:"notEnoughSdCollateral"`
+	MaxValidatorLimitReached bool                    `json:"maxValidatorLimitReached"`
+	InputKeyLimitReached     bool                    `json:"inputKeyLimitReached"`
+	InputKeyLimit            uint16                  `json:"inputKeyLimit"`
+	GasInfo                  stader.GasInfo          `json:"gasInfo"`
+}
+
+type ImportValidatorKeysResponse struct {
+	Status            string                  `json:"status"`
+	Error             string                  `json:"error"`
+	Pubkeys           []types.ValidatorPubkey `json:"pubkeys"`
+	TxHash            common.Hash             `json:"txHash"`
+	DoppelgangerWatch bool                    `json:"doppelgangerWatch"`
 }
 
 type CanNodeSendResponse struct {
@@ -189,6 +218,34 @@ type CanExitValidatorResponse struct {
 	ValidatorNotActive     bool   `json:"validatorNotActive"`
 }
 
+type CanExitValidatorResult struct {
+	Pubkey  types.ValidatorPubkey    `json:"pubkey"`
+	Error   string                   `json:"error"`
+	CanExit CanExitValidatorResponse `json:"canExit"`
+}
+
+type CanExitValidatorsResponse struct {
+	Status     string                   `json:"status"`
+	Error      string                   `json:"error"`
+	Validators []CanExitValidatorResult `json:"validators"`
+}
+
+// Outcome of one validator's operation in a batch command
+type BatchValidatorResult struct {
+	Pubkey  types.ValidatorPubkey `json:"pubkey"`
+	Success bool                  `json:"success"`
+	Error   string                `json:"error"`
+	Amount  *big.Int              `json:"amount"`
+	TxHash  common.Hash           `json:"txHash"`
+}
+
+type ExitValidatorsResponse struct {
+	Status         string                 `json:"status"`
+	Error          string                 `json:"error"`
+	BeaconChainUrl string                 `json:"beaconChainUrl"`
+	Results        []BatchValidatorResult `json:"results"`
+}
+
 type ExitValidatorResponse struct {
 	BeaconChainUrl string `json:"beaconChainUrl"`
 	Status         string `json:"status"`
@@ -219,9 +276,30 @@ type CanSendClRewardsResponse struct {
 	NoClRewards         bool           `json:"noClRewards"`
 	TooManyClRewards    bool           `json:"tooManyClRewards"`
 	ValidatorNotFound   bool           `json:"validatorNotFound"`
+	ClRewardsAmount     *big.Int       `json:"clRewardsAmount"`
 	GasInfo             stader.GasInfo `json:"gasInfo"`
 }
 
+type CanSendClRewardsResult struct {
+	Pubkey  types.ValidatorPubkey    `json:"pubkey"`
+	Error   string                   `json:"error"`
+	CanSend CanSendClRewardsResponse `json:"canSend"`
+}
+
+type CanSendClRewardsBatchResponse struct {
+	Status       string                   `json:"status"`
+	Error        string                   `json:"error"`
+	Validators   []CanSendClRewardsResult `json:"validators"`
+	TotalGasInfo stader.GasInfo           `json:"totalGasInfo"`
+}
+
+type SendClRewardsBatchResponse struct {
+	Status                string                 `json:"status"`
+	Error                 string                 `json:"error"`
+	OperatorRewardAddress common.Address         `json:"operatorRewardAddress"`
+	Results               []BatchValidatorResult `json:"results"`
+}
+
 type SendClRewardsResponse struct {
 	Status                string         `json:"status"`
 	Error                 string         `json:"error"`
@@ -235,8 +313,12 @@ type CanSettleExitFunds struct {
 	Error                  string         `json:"error"`
 	ValidatorNotWithdrawn  bool           `json:"validatorNotWithdrawn"`
 	ValidatorNotRegistered bool           `json:"validatorNotRegistered"`
+	ValidatorNotOwned      bool           `json:"validatorNotOwned"`
 	NoEthToWithdraw        bool           `json:"notEthToWithdraw"`
 	VaultAlreadySettled    bool           `json:"vaultAlreadySettled"`
+	WithdrawVaultAddress   common.Address `json:"withdrawVaultAddress"`
+	WithdrawVaultBalance   *big.Int       `json:"withdrawVaultBalance"`
+	OperatorShare          *big.Int       `json:"operatorShare"`
 	GasInfo                stader.GasInfo `json:"gasInfo"`
 }
 
@@ -248,6 +330,26 @@ type SettleExitFunds struct {
 	TxHash                common.Hash    `json:"txHash"`
 }
 
+type PresignStatusResponse struct {
+	Status     string                    `json:"status"`
+	Error      string                    `json:"error"`
+	Validators []presign.ValidatorRecord `json:"validators"`
+}
+
+type DoppelgangerStatusResponse struct {
+	Status      string                    `json:"status"`
+	Error       string                    `json:"error"`
+	WatchEpochs uint64                    `json:"watchEpochs"`
+	Keys        []doppelganger.WatchedKey `json:"keys"`
+}
+
+type RetryDoppelgangerWatchResponse struct {
+	Status   string                  `json:"status"`
+	Error    string                  `json:"error"`
+	Pubkeys  []types.ValidatorPubkey `json:"pubkeys"`
+	EndEpoch uint64                  `json:"endEpoch"`
+}
+
 type CanSendElRewardsResponse struct {
 	Status      string         `json:"status"`
 	Error       string         `json:"error"`
@@ -300,9 +402,10 @@ type CanDownloadSpMerkleProofsResponse struct {
 }
 
 type DownloadSpMerkleProofsResponse struct {
-	Status           string  `json:"status"`
-	Error            string  `json:"error"`
-	DownloadedCycles []int64 `json:"downloadedCycles"`
+	Status            string  `json:"status"`
+	Error             string  `json:"error"`
+	DownloadedCycles  []int64 `json:"downloadedCycles"`
+	QuarantinedCycles []int64 `json:"quarantinedCycles"`
 }
 
 type DetailedMerkleProofInfo struct {
@@ -323,6 +426,7 @@ type CanClaimSpRewardsResponse struct {
 	ClaimedCycles                 []*big.Int `json:"claimedCycles"`
 	UnclaimedCycles               []*big.Int `json:"unclaimedCycles"`
 	CyclesToDownload              []*big.Int `json:"cyclesToDownload"`
+	QuarantinedCycles             []int64    `json:"quarantinedCycles"`
 }
 
 type EstimateClaimSpRewardsGasResponse struct {
@@ -404,3 +508,59 @@ type ClaimRewards struct {
 	OperatorRewardAddress  common.Address `json:"operatorRewardAddress"`
 	TxHash                 common.Hash    `json:"txHash"`
 }
+
+type NodePenaltiesResponse struct {
+	Status                            string                  `json:"status"`
+	Error                             string                  `json:"error"`
+	Registered                        bool                    `json:"registered"`
+	ExitPenaltyThreshold              *big.Int                `json:"exitPenaltyThreshold"`
+	MevTheftPenaltyPerStrike          *big.Int                `json:"mevTheftPenaltyPerStrike"`
+	MissedAttestationPenaltyPerStrike *big.Int                `json:"missedAttestationPenaltyPerStrike"`
+	ValidatorPenalties                []stdr.ValidatorPenalty `json:"validatorPenalties"`
+	ForceExits                        []ForceExitDetails      `json:"forceExits"`
+}
+
+type ForceExitDetails struct {
+	stdr.ForceExitEvent
+	Time        time.Time `json:"time"`
+	SettledTime time.Time `json:"settledTime"`
+}
+
+type NodeLedgerResponse struct {
+	Status     string `json:"status"`
+	Error      string `json:"error"`
+	Registered bool   `json:"registered"`
+	// Every source has been scanned for records up to this block, exclusive
+	ScannedToBlock uint64          `json:"scannedToBlock"`
+	Records        []ledger.Record `json:"records"`
+}
+
+type NodeTransaction struct {
+	txmanager.TxRecord
+	Stuck bool `json:"stuck"`
+}
+
+type NodeTransactionsResponse struct {
+	Status       string            `json:"status"`
+	Error        string            `json:"error"`
+	Transactions []NodeTransaction `json:"transactions"`
+}
+
+type CanReplaceTransactionResponse struct {
+	Status         string             `json:"status"`
+	Error          string             `json:"error"`
+	NotFound       bool               `json:"notFound"`
+	NotPending     bool               `json:"notPending"`
+	NotNodeAccount bool               `json:"notNodeAccount"`
+	ExceedsFeeCap  bool               `json:"exceedsFeeCap"`
+	Transaction    txmanager.TxRecord `json:"transaction"`
+	MaxFee         *big.Int           `json:"maxFee"`
+	PriorityFee    *big.Int           `json:"priorityFee"`
+	GasLimit       uint64             `json:"gasLimit"`
+}
+
+type ReplaceTransactionResponse struct {
+	Status string      `json:"status"`
+	Error  string      `json:"error"`
+	TxHash common.Hash `json:"txHash"`
+}
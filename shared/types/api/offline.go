@@ -0,0 +1,31 @@
+package api
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/stader-labs/stader-node/shared/utils/offline"
+)
+
+type ExportDepositRequestResponse struct {
+	Status                   string                  `json:"status"`
+	Error                    string                  `json:"error"`
+	InsufficientBalance      bool                    `json:"insufficientBalance"`
+	DepositPaused            bool                    `json:"depositPaused"`
+	NotEnoughSdCollateral    bool                    `json:"notEnoughSdCollateral"`
+	MaxValidatorLimitReached bool                    `json:"maxValidatorLimitReached"`
+	InputKeyLimitReached     bool                    `json:"inputKeyLimitReached"`
+	InputKeyLimit            uint16                  `json:"inputKeyLimit"`
+	Request                  *offline.DepositRequest `json:"request"`
+}
+
+type SignTransactionsResponse struct {
+	Status             string                     `json:"status"`
+	Error              string                     `json:"error"`
+	SignedTransactions offline.SignedTransactions `json:"signedTransactions"`
+}
+
+type BroadcastTransactionsResponse struct {
+	Status   string        `json:"status"`
+	Error    string        `json:"error"`
+	TxHashes []common.Hash `json:"txHashes"`
+}
@@ -19,7 +19,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 */
 package api
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/stader-labs/stader-node/shared/services/gas/feehistory"
+)
 
 type TerminateDataFolderResponse struct {
 	Status        string `json:"status"`
@@ -55,3 +59,9 @@ type ClientStatusResponse struct {
 	EcManagerStatus ClientManagerStatus `json:"ecManagerStatus"`
 	BcManagerStatus ClientManagerStatus `json:"bcManagerStatus"`
 }
+
+type FeeEstimateResponse struct {
+	Status   string                 `json:"status"`
+	Error    string                 `json:"error"`
+	Estimate feehistory.FeeEstimate `json:"estimate"`
+}
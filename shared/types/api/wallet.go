@@ -61,11 +61,12 @@ type InitWalletResponse struct {
 }
 
 type RecoverWalletResponse struct {
-	Status         string                  `json:"status"`
-	Error          string                  `json:"error"`
-	AccountAddress common.Address          `json:"accountAddress"`
-	ValidatorKeys  []types.ValidatorPubkey `json:"validatorKeys"`
-	OperatorExists bool                    `json:"operatorExists"`
+	Status            string                  `json:"status"`
+	Error             string                  `json:"error"`
+	AccountAddress    common.Address          `json:"accountAddress"`
+	ValidatorKeys     []types.ValidatorPubkey `json:"validatorKeys"`
+	OperatorExists    bool                    `json:"operatorExists"`
+	DoppelgangerWatch bool                    `json:"doppelgangerWatch"`
 }
 
 type RebuildValidatorKeysResponse struct {
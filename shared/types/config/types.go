@@ -29,6 +29,7 @@ type RewardsMode string
 type MevRelayID string
 type MevSelectionMode string
 type NimbusPruningMode string
+type FeeSource string
 
 // Enum to describe which container(s) a parameter impacts, so the Stadernode knows which
 // ones to restart upon a settings change
@@ -129,6 +130,13 @@ const (
 	NimbusPruningMode_Prune   NimbusPruningMode = "prune"
 )
 
+// Enum to describe where max fee suggestions come from
+const (
+	FeeSource_ExecutionClient FeeSource = "executionClient"
+	FeeSource_Etherchain      FeeSource = "etherchain"
+	FeeSource_Etherscan       FeeSource = "etherscan"
+)
+
 type Config interface {
 	GetConfigTitle() string
 	GetParameters() []*Parameter
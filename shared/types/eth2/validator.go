@@ -0,0 +1,40 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package eth2
+
+// A validator's record in the beacon state
+type Validator struct {
+	Pubkey                     []byte `json:"pubkey" ssz-size:"48"`
+	WithdrawalCredentials      []byte `json:"withdrawal_credentials" ssz-size:"32"`
+	EffectiveBalance           uint64 `json:"effective_balance"`
+	Slashed                    bool   `json:"slashed"`
+	ActivationEligibilityEpoch uint64 `json:"activation_eligibility_epoch"`
+	ActivationEpoch            uint64 `json:"activation_epoch"`
+	ExitEpoch                  uint64 `json:"exit_epoch"`
+	WithdrawableEpoch          uint64 `json:"withdrawable_epoch"`
+}
+
+// A validator with its index and balance, as listed by the beacon API's validators endpoint.
+// The SSZ encoding has no status field, so it has to be derived from the epochs.
+type ValidatorContainer struct {
+	Index     uint64     `json:"index"`
+	Balance   uint64     `json:"balance"`
+	Validator *Validator `json:"validator"`
+}
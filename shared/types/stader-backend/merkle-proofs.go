@@ -1,5 +1,12 @@
 package stader_backend
 
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
 type CycleMerkleProofs struct {
 	Root  string   `json:"root"`
 	Eth   string   `json:"eth"`
@@ -7,3 +14,48 @@ type CycleMerkleProofs struct {
 	Proof []string `json:"proof"`
 	Cycle int64    `json:"cycle"`
 }
+
+// Decode the amounts and proof into the form the socializing pool contract takes
+func (p CycleMerkleProofs) Decode() (*big.Int, *big.Int, [][32]byte, error) {
+	amountSd, ok := big.NewInt(0).SetString(p.Sd, 10)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("could not parse sd amount %s", p.Sd)
+	}
+	amountEth, ok := big.NewInt(0).SetString(p.Eth, 10)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("could not parse eth amount %s", p.Eth)
+	}
+
+	merkleProof := [][32]byte{}
+	for _, proof := range p.Proof {
+		proofBytes, err := decodeHash(proof)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid merkle proof element %s: %w", proof, err)
+		}
+		merkleProof = append(merkleProof, proofBytes)
+	}
+
+	return amountSd, amountEth, merkleProof, nil
+}
+
+// Decode the merkle root the backend built the proof against
+func (p CycleMerkleProofs) DecodeRoot() ([32]byte, error) {
+	root, err := decodeHash(p.Root)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("invalid merkle root %s: %w", p.Root, err)
+	}
+	return root, nil
+}
+
+func decodeHash(value string) ([32]byte, error) {
+	var hash [32]byte
+	decoded, err := hexutil.Decode(value)
+	if err != nil {
+		return hash, err
+	}
+	if len(decoded) != len(hash) {
+		return hash, fmt.Errorf("expected %d bytes, got %d", len(hash), len(decoded))
+	}
+	copy(hash[:], decoded)
+	return hash, nil
+}
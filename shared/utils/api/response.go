@@ -26,6 +26,7 @@ import (
 	"reflect"
 
 	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/offline"
 )
 
 // Print an API response
@@ -72,6 +73,15 @@ func PrintResponse(response interface{}, responseError error) {
 		return
 	}
 
+	// Report transactions that were built for offline signing instead of being sent
+	if ef.String() == "" {
+		responseBytes, err = offline.AddRecordedTransactions(responseBytes)
+		if err != nil {
+			PrintErrorResponse(fmt.Errorf("Could not encode unsigned transactions: %w", err))
+			return
+		}
+	}
+
 	// Print
 	fmt.Println(string(responseBytes))
 
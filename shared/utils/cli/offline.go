@@ -0,0 +1,43 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/stader-labs/stader-node/shared/utils/offline"
+)
+
+// Write signed transactions in the format read by the broadcast command
+func WriteSignedTransactions(path string, signedTransactions offline.SignedTransactions) error {
+	signedBytes, err := json.MarshalIndent(signedTransactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding signed transactions: %w", err)
+	}
+	if err := ioutil.WriteFile(path, signedBytes, 0600); err != nil {
+		return fmt.Errorf("error writing signed transactions to %s: %w", path, err)
+	}
+
+	fmt.Printf("%d signed transaction(s) were written to %s.\n", len(signedTransactions.Transactions), path)
+	fmt.Println("Copy this file to your online node and send the transactions with `stader-cli node broadcast`.")
+	return nil
+}
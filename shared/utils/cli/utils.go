@@ -64,6 +64,11 @@ func PrintMultiTransactionNonceWarning() {
 // Implementation of PrintTransactionHash and PrintTransactionHashNoCancel
 func printTransactionHashImpl(staderClient *stader.Client, hash common.Hash, finalMessage string) {
 
+	// Exported transactions haven't been sent yet
+	if staderClient.IsExportingUnsignedTransactions() {
+		return
+	}
+
 	cfg, isNew, err := staderClient.LoadConfig()
 	if err != nil {
 		fmt.Printf("Warning: couldn't read config file so the transaction URL will be unavailable (%s).\n", err)
@@ -48,6 +48,14 @@ func ValidateArgCount(c *cli.Context, count int) error {
 	return nil
 }
 
+// Validate command argument count for commands that take a variable number of trailing arguments
+func ValidateMinArgCount(c *cli.Context, count int) error {
+	if len(c.Args()) < count {
+		return fmt.Errorf("incorrect argument count; usage: %s", c.Command.UsageText)
+	}
+	return nil
+}
+
 // Validate a big int
 func ValidateBigInt(name, value string) (*big.Int, error) {
 	val, success := big.NewInt(0).SetString(value, 0)
@@ -263,3 +271,23 @@ func ValidatePubkey(name, value string) (types.ValidatorPubkey, error) {
 	}
 	return pubkey, nil
 }
+
+// Validate a comma-separated list of validator pubkeys
+func ValidatePubkeys(name, value string) ([]types.ValidatorPubkey, error) {
+	pubkeys := []types.ValidatorPubkey{}
+	for _, element := range strings.Split(value, ",") {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+		pubkey, err := ValidatePubkey(name, element)
+		if err != nil {
+			return nil, err
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	if len(pubkeys) == 0 {
+		return nil, fmt.Errorf("invalid %s '%s': no validator pubkeys provided", name, value)
+	}
+	return pubkeys, nil
+}
@@ -23,7 +23,24 @@ func BytesToPublicKey(pub []byte) (*rsa.PublicKey, error) {
 		return nil, err
 	}
 
-	return key.(*rsa.PublicKey), nil
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+func PublicKeyToBytes(publicKey *rsa.PublicKey) ([]byte, error) {
+	b, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: b,
+	}), nil
 }
 
 func EncryptUsingPublicKey(data []byte, publicKey *rsa.PublicKey) ([]byte, error) {
@@ -34,3 +51,7 @@ func EncryptUsingPublicKey(data []byte, publicKey *rsa.PublicKey) ([]byte, error
 
 	return exitMsgEncrypted, nil
 }
+
+func DecryptUsingPrivateKey(data []byte, privateKey *rsa.PrivateKey) ([]byte, error) {
+	return rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, data, nil)
+}
@@ -0,0 +1,59 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package offline
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The request written by the online node for a deposit that is signed on an offline machine.
+// The online node does all of the chain reads; the offline machine creates the validator keys, signs the deposit data
+// against the withdrawal credentials below, builds the addValidatorKeys call and signs the transaction:
+//
+//	{
+//	  "nodeAddress": "0x<node address>",
+//	  "operatorId": 12,
+//	  "genesisForkVersion": "0x00000000",
+//	  "preDepositAmountGwei": 1000000000,
+//	  "depositAmountGwei": 31000000000,
+//	  "validators": [
+//	    { "operatorKeyIndex": 3, "withdrawVault": "0x...", "withdrawalCredentials": "0x01..." }
+//	  ],
+//	  "transaction": <UnsignedTransaction, with empty data>
+//	}
+//
+// The transaction's gas limit is estimated online with placeholder keys, since the real ones don't exist yet.
+type DepositRequest struct {
+	NodeAddress          common.Address            `json:"nodeAddress"`
+	OperatorId           uint64                    `json:"operatorId"`
+	GenesisForkVersion   hexutil.Bytes             `json:"genesisForkVersion"`
+	PreDepositAmountGwei uint64                    `json:"preDepositAmountGwei"`
+	DepositAmountGwei    uint64                    `json:"depositAmountGwei"`
+	Validators           []DepositRequestValidator `json:"validators"`
+	Transaction          UnsignedTransaction       `json:"transaction"`
+}
+
+// A validator to create as part of a deposit request
+type DepositRequestValidator struct {
+	OperatorKeyIndex      uint64         `json:"operatorKeyIndex"`
+	WithdrawVault         common.Address `json:"withdrawVault"`
+	WithdrawalCredentials common.Hash    `json:"withdrawalCredentials"`
+}
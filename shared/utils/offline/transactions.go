@@ -0,0 +1,218 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package offline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// A transaction built by the online node but not signed or sent, so it can be signed on an offline machine.
+// Quantities are hex encoded the same way the Ethereum JSON-RPC API encodes them:
+//
+//	{
+//	  "chainId": "0x1",
+//	  "from": "0x<node address>",
+//	  "to": "0x<contract address>",
+//	  "nonce": "0x2a",
+//	  "value": "0x0",
+//	  "gas": "0x30d40",
+//	  "maxFeePerGas": "0x6fc23ac00",
+//	  "maxPriorityFeePerGas": "0x77359400",
+//	  "data": "0x<calldata>"
+//	}
+type UnsignedTransaction struct {
+	ChainID              *hexutil.Big   `json:"chainId"`
+	From                 common.Address `json:"from"`
+	To                   common.Address `json:"to"`
+	Nonce                hexutil.Uint64 `json:"nonce"`
+	Value                *hexutil.Big   `json:"value"`
+	Gas                  hexutil.Uint64 `json:"gas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	Data                 hexutil.Bytes  `json:"data"`
+}
+
+// The file written by the "export unsigned request" step and read by the offline signing step:
+//
+//	{ "transactions": [ <UnsignedTransaction>, ... ] }
+//
+// Commands that send several transactions in one go (such as the batched send-cl-rewards, or deposit-sd with its approval) export all of them, with sequential nonces.
+type UnsignedTransactions struct {
+	Transactions []UnsignedTransaction `json:"transactions"`
+}
+
+// A transaction signed on the offline machine, ready to be broadcast by the online node:
+//
+//	{
+//	  "hash": "0x<transaction hash>",
+//	  "from": "0x<node address>",
+//	  "nonce": "0x2a",
+//	  "rawTransaction": "0x02f8..."
+//	}
+//
+// rawTransaction is the EIP-2718 encoded signed transaction, as accepted by eth_sendRawTransaction.
+type SignedTransaction struct {
+	Hash           common.Hash    `json:"hash"`
+	From           common.Address `json:"from"`
+	Nonce          hexutil.Uint64 `json:"nonce"`
+	RawTransaction hexutil.Bytes  `json:"rawTransaction"`
+}
+
+// The file written by the offline signing step and read by the "broadcast signed transaction" step:
+//
+//	{ "transactions": [ <SignedTransaction>, ... ], "validatorPubkeys": [ "0x...", ... ] }
+//
+// validatorPubkeys is only set for deposits and lists the validator keys created on the offline machine.
+type SignedTransactions struct {
+	Transactions     []SignedTransaction `json:"transactions"`
+	ValidatorPubkeys []string            `json:"validatorPubkeys,omitempty"`
+}
+
+// Create an unsigned transaction record from a transaction built by the contract bindings
+func NewUnsignedTransaction(from common.Address, tx *types.Transaction) (UnsignedTransaction, error) {
+	if tx.To() == nil {
+		return UnsignedTransaction{}, fmt.Errorf("contract creation transactions can't be exported")
+	}
+	return UnsignedTransaction{
+		ChainID:              (*hexutil.Big)(tx.ChainId()),
+		From:                 from,
+		To:                   *tx.To(),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Data:                 tx.Data(),
+	}, nil
+}
+
+// Build the EIP-1559 transaction described by the record
+func (t UnsignedTransaction) ToTransaction() (*types.Transaction, error) {
+	if t.ChainID == nil || t.Value == nil || t.MaxFeePerGas == nil || t.MaxPriorityFeePerGas == nil {
+		return nil, fmt.Errorf("unsigned transaction with nonce %d is missing its chainId, value or fee fields", t.Nonce)
+	}
+	to := t.To
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   (*big.Int)(t.ChainID),
+		Nonce:     uint64(t.Nonce),
+		GasTipCap: (*big.Int)(t.MaxPriorityFeePerGas),
+		GasFeeCap: (*big.Int)(t.MaxFeePerGas),
+		Gas:       uint64(t.Gas),
+		To:        &to,
+		Value:     (*big.Int)(t.Value),
+		Data:      t.Data,
+	}), nil
+}
+
+// Create a signed transaction record from the encoded signed transaction
+func NewSignedTransaction(from common.Address, signedTx []byte) (SignedTransaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(signedTx); err != nil {
+		return SignedTransaction{}, fmt.Errorf("error decoding signed transaction: %w", err)
+	}
+	return SignedTransaction{
+		Hash:           tx.Hash(),
+		From:           from,
+		Nonce:          hexutil.Uint64(tx.Nonce()),
+		RawTransaction: signedTx,
+	}, nil
+}
+
+// Decode the signed transaction, making sure it matches the hash in the record
+func (t SignedTransaction) ToTransaction() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(t.RawTransaction); err != nil {
+		return nil, fmt.Errorf("error decoding signed transaction %s: %w", t.Hash.Hex(), err)
+	}
+	if tx.Hash() != t.Hash {
+		return nil, fmt.Errorf("signed transaction hash %s doesn't match the recorded hash %s", tx.Hash().Hex(), t.Hash.Hex())
+	}
+	return tx, nil
+}
+
+// Sign an exported transaction with sign (normally the node wallet's Sign), after checking it was built for this node and chain
+func SignTransaction(sign func([]byte) ([]byte, error), nodeAddress common.Address, chainID *big.Int, unsignedTx UnsignedTransaction) (SignedTransaction, error) {
+	if unsignedTx.From != nodeAddress {
+		return SignedTransaction{}, fmt.Errorf("transaction is from %s, not the node address %s", unsignedTx.From.Hex(), nodeAddress.Hex())
+	}
+	if unsignedTx.ChainID == nil || unsignedTx.ChainID.ToInt().Cmp(chainID) != 0 {
+		return SignedTransaction{}, fmt.Errorf("transaction is for chain %v, not the wallet's chain %s", unsignedTx.ChainID, chainID.String())
+	}
+
+	tx, err := unsignedTx.ToTransaction()
+	if err != nil {
+		return SignedTransaction{}, err
+	}
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		return SignedTransaction{}, fmt.Errorf("error encoding transaction: %w", err)
+	}
+	signedTx, err := sign(txBytes)
+	if err != nil {
+		return SignedTransaction{}, err
+	}
+	return NewSignedTransaction(nodeAddress, signedTx)
+}
+
+// Unsigned transactions built by the current API command
+var (
+	recordedTransactions []UnsignedTransaction
+	recordLock           sync.Mutex
+)
+
+// Record a transaction that was built instead of being signed and sent
+func RecordUnsignedTransaction(tx UnsignedTransaction) {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	recordedTransactions = append(recordedTransactions, tx)
+}
+
+// Get the transactions recorded so far
+func GetRecordedTransactions() []UnsignedTransaction {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	return append([]UnsignedTransaction{}, recordedTransactions...)
+}
+
+// Add the recorded transactions to an encoded API response under "unsignedTransactions"
+func AddRecordedTransactions(responseBytes []byte) ([]byte, error) {
+	transactions := GetRecordedTransactions()
+	if len(transactions) == 0 {
+		return responseBytes, nil
+	}
+
+	response := map[string]json.RawMessage{}
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return nil, err
+	}
+	transactionBytes, err := json.Marshal(transactions)
+	if err != nil {
+		return nil, err
+	}
+	response["unsignedTransactions"] = transactionBytes
+	return json.Marshal(response)
+}
@@ -2,15 +2,42 @@ package stader
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/mitchellh/go-homedir"
 	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/config"
 	stader_backend "github.com/stader-labs/stader-node/shared/types/stader-backend"
 	"github.com/stader-labs/stader-node/shared/utils/net"
+	socializing_pool "github.com/stader-labs/stader-node/stader-lib/socializing-pool"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
 	"github.com/urfave/cli"
-	"net/http"
 )
 
+// A downloaded merkle proof that doesn't prove the operator's rewards against the on-chain root
+type InvalidMerkleProofError struct {
+	Cycle  int64
+	Reason string
+}
+
+func (e *InvalidMerkleProofError) Error() string {
+	return fmt.Sprintf("merkle proof for cycle %d is invalid: %s", e.Cycle, e.Reason)
+}
+
+// A proof kept in quarantine, along with why it was rejected
+type QuarantinedCycleMerkleProofs struct {
+	stader_backend.CycleMerkleProofs
+	Reason        string    `json:"reason"`
+	QuarantinedAt time.Time `json:"quarantinedAt"`
+}
+
 func GetAllMerkleProofsForOperator(c *cli.Context, operator common.Address) ([]*stader_backend.CycleMerkleProofs, error) {
 	config, err := services.GetConfig(c)
 	if err != nil {
@@ -36,3 +63,117 @@ func GetAllMerkleProofsForOperator(c *cli.Context, operator common.Address) ([]*
 	}
 	return allMerkleProofs, nil
 }
+
+// Check a cycle's proof against the socializing pool contract, since that's what the claim will be checked against.
+// If the contract rejects it, the operator's leaf and root are recomputed locally to explain why.
+// Returns an InvalidMerkleProofError if the proof doesn't hold.
+func VerifyCycleMerkleProof(sp *stader.SocializingPoolContractManager, operator common.Address, cycleMerkleProof *stader_backend.CycleMerkleProofs) error {
+	cycle := big.NewInt(cycleMerkleProof.Cycle)
+
+	amountSd, amountEth, merkleProof, err := cycleMerkleProof.Decode()
+	if err != nil {
+		return &InvalidMerkleProofError{Cycle: cycleMerkleProof.Cycle, Reason: err.Error()}
+	}
+
+	valid, err := socializing_pool.VerifyProof(sp, operator, cycle, amountSd, amountEth, merkleProof, nil)
+	if err != nil {
+		return fmt.Errorf("could not verify the merkle proof of cycle %d: %w", cycleMerkleProof.Cycle, err)
+	}
+	if valid {
+		return nil
+	}
+
+	reason, err := getInvalidMerkleProofReason(sp, operator, cycleMerkleProof, amountSd, amountEth, merkleProof)
+	if err != nil {
+		return err
+	}
+	return &InvalidMerkleProofError{Cycle: cycleMerkleProof.Cycle, Reason: reason}
+}
+
+// Work out why the contract rejected a proof by comparing the backend's root and the one recomputed from the operator's
+// leaf with the on-chain root
+func getInvalidMerkleProofReason(sp *stader.SocializingPoolContractManager, operator common.Address, cycleMerkleProof *stader_backend.CycleMerkleProofs, amountSd *big.Int, amountEth *big.Int, merkleProof [][32]byte) (string, error) {
+	onChainRoot, err := socializing_pool.GetCycleMerkleRoot(sp, big.NewInt(cycleMerkleProof.Cycle), nil)
+	if err != nil {
+		return "", fmt.Errorf("could not get the merkle root of cycle %d: %w", cycleMerkleProof.Cycle, err)
+	}
+	if onChainRoot == [32]byte{} {
+		return "the cycle has no merkle root on chain", nil
+	}
+
+	// The backend's root should be the one that was submitted on chain
+	if cycleMerkleProof.Root != "" {
+		backendRoot, err := cycleMerkleProof.DecodeRoot()
+		if err != nil {
+			return err.Error(), nil
+		}
+		if backendRoot != onChainRoot {
+			return fmt.Sprintf("root %s does not match the on-chain root %s", common.Hash(backendRoot).Hex(), common.Hash(onChainRoot).Hex()), nil
+		}
+	}
+
+	leaf := socializing_pool.ComputeRewardsLeaf(operator, amountSd, amountEth)
+	computedRoot := socializing_pool.ComputeMerkleRoot(leaf, merkleProof)
+	if computedRoot != onChainRoot {
+		return fmt.Sprintf("proof of %s SD and %s ETH leads to root %s instead of the on-chain root %s", cycleMerkleProof.Sd, cycleMerkleProof.Eth, common.Hash(computedRoot).Hex(), common.Hash(onChainRoot).Hex()), nil
+	}
+
+	return "the socializing pool contract rejected the proof", nil
+}
+
+// Verify a downloaded cycle proof and save it for claiming, or move it to quarantine if it's invalid.
+// Returns an InvalidMerkleProofError if the proof was quarantined.
+func SaveCycleMerkleProof(cfg *config.StaderConfig, sp *stader.SocializingPoolContractManager, operator common.Address, cycleMerkleProof *stader_backend.CycleMerkleProofs) error {
+	quarantinePath, err := homedir.Expand(cfg.StaderNode.GetSpRewardCycleQuarantinePath(cycleMerkleProof.Cycle, true))
+	if err != nil {
+		return err
+	}
+
+	verifyErr := VerifyCycleMerkleProof(sp, operator, cycleMerkleProof)
+	var invalidProofErr *InvalidMerkleProofError
+	if errors.As(verifyErr, &invalidProofErr) {
+		quarantined := QuarantinedCycleMerkleProofs{
+			CycleMerkleProofs: *cycleMerkleProof,
+			Reason:            invalidProofErr.Reason,
+			QuarantinedAt:     time.Now(),
+		}
+		if err := writeJsonFile(quarantinePath, quarantined); err != nil {
+			return fmt.Errorf("could not quarantine merkle proof for cycle %d: %w", cycleMerkleProof.Cycle, err)
+		}
+		return verifyErr
+	}
+	if verifyErr != nil {
+		return verifyErr
+	}
+
+	proofPath, err := homedir.Expand(cfg.StaderNode.GetSpRewardCyclePath(cycleMerkleProof.Cycle, true))
+	if err != nil {
+		return err
+	}
+	if err := writeJsonFile(proofPath, cycleMerkleProof); err != nil {
+		return fmt.Errorf("could not save merkle proof for cycle %d: %w", cycleMerkleProof.Cycle, err)
+	}
+
+	// The backend has fixed a proof it served before
+	if err := os.Remove(quarantinePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not remove quarantined merkle proof for cycle %d: %w", cycleMerkleProof.Cycle, err)
+	}
+	return nil
+}
+
+func writeJsonFile(path string, value interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(value)
+	if err != nil {
+		return fmt.Errorf("error encoding JSON: %v", err)
+	}
+	return nil
+}
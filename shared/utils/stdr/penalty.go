@@ -0,0 +1,150 @@
+package stdr
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/indexer"
+	penalty_tracker "github.com/stader-labs/stader-node/stader-lib/penalty-tracker"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+// Validators whose penalty has reached this fraction of the exit threshold are flagged as at risk of a force exit
+const PenaltyWarningRatio = 0.75
+
+// The penalty breakdown of a single validator
+type ValidatorPenalty struct {
+	Pubkey types.ValidatorPubkey `json:"pubkey"`
+	// The total as last recorded on-chain by the penalty oracle; this is what the force exit is decided on
+	TotalPenalty             *big.Int `json:"totalPenalty"`
+	MevTheftPenalty          *big.Int `json:"mevTheftPenalty"`
+	MissedAttestationPenalty *big.Int `json:"missedAttestationPenalty"`
+	AdditionalPenalty        *big.Int `json:"additionalPenalty"`
+	ExitPenaltyThreshold     *big.Int `json:"exitPenaltyThreshold"`
+}
+
+// The fraction of the exit threshold the validator's penalty has reached
+func (p ValidatorPenalty) ThresholdRatio() float64 {
+	if p.ExitPenaltyThreshold == nil || p.ExitPenaltyThreshold.Sign() == 0 || p.TotalPenalty == nil {
+		return 0
+	}
+	return eth.WeiToEth(p.TotalPenalty) / eth.WeiToEth(p.ExitPenaltyThreshold)
+}
+
+func (p ValidatorPenalty) AtForceExitRisk() bool {
+	return p.ThresholdRatio() >= PenaltyWarningRatio
+}
+
+// The penalty components don't add up to the recorded total until the penalty oracle next updates it
+func (p ValidatorPenalty) PendingPenalty() *big.Int {
+	pending := big.NewInt(0)
+	for _, component := range []*big.Int{p.MevTheftPenalty, p.MissedAttestationPenalty, p.AdditionalPenalty} {
+		if component != nil {
+			pending.Add(pending, component)
+		}
+	}
+	if p.TotalPenalty != nil {
+		pending.Sub(pending, p.TotalPenalty)
+	}
+	if pending.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return pending
+}
+
+// A ForceExitValidator event for one of the node's validators
+type ForceExitEvent struct {
+	Pubkey      types.ValidatorPubkey `json:"pubkey"`
+	BlockNumber uint64                `json:"blockNumber"`
+	TxHash      common.Hash           `json:"txHash"`
+	// The block the validator was marked as settled in, 0 if it hasn't been yet
+	SettledBlockNumber uint64 `json:"settledBlockNumber"`
+}
+
+// Get the penalty breakdown of each of the given validators in one batch
+func GetValidatorPenalties(pt *stader.PenaltyTrackerContractManager, pubkeys []types.ValidatorPubkey, opts *bind.CallOpts) (map[types.ValidatorPubkey]ValidatorPenalty, error) {
+	exitPenaltyThreshold, err := penalty_tracker.GetValidatorExitPenaltyThreshold(pt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mc, err := stader.NewMultiCaller(pt.Client, stader.Multicall3Address)
+	if err != nil {
+		return nil, err
+	}
+
+	penalties := make([]ValidatorPenalty, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		pubkeyRoot := penalty_tracker.GetPubkeyRoot(pubkey)
+		penalties[i].Pubkey = pubkey
+		penalties[i].ExitPenaltyThreshold = exitPenaltyThreshold
+		if err := mc.AddCall(pt.PenaltyContract, &penalties[i].TotalPenalty, "totalPenaltyAmount", pubkey.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := mc.AddCall(pt.PenaltyContract, &penalties[i].MevTheftPenalty, "calculateMEVTheftPenalty", pubkeyRoot); err != nil {
+			return nil, err
+		}
+		if err := mc.AddCall(pt.PenaltyContract, &penalties[i].MissedAttestationPenalty, "calculateMissedAttestationPenalty", pubkeyRoot); err != nil {
+			return nil, err
+		}
+		if err := mc.AddCall(pt.PenaltyContract, &penalties[i].AdditionalPenalty, "getAdditionalPenaltyAmount", pubkey.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := mc.Execute(opts); err != nil {
+		return nil, fmt.Errorf("error getting validator penalties: %w", err)
+	}
+
+	penaltyMap := make(map[types.ValidatorPubkey]ValidatorPenalty, len(penalties))
+	for _, penalty := range penalties {
+		penaltyMap[penalty.Pubkey] = penalty
+	}
+	return penaltyMap, nil
+}
+
+// Get the force exits and settlements of the operator's validators from its event index.
+// The index only keeps the penalty events of the operator's own validators.
+func GetIndexedForceExits(ix *indexer.Indexer) (map[types.ValidatorPubkey]ForceExitEvent, error) {
+	events, err := ix.Query(indexer.Query{
+		Sources: []string{indexer.PenaltySource},
+		Events:  []string{"ForceExitValidator", "ValidatorMarkedAsSettled"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exits := map[types.ValidatorPubkey]ForceExitEvent{}
+	for _, event := range events {
+		switch event.Name {
+		case "ForceExitValidator":
+			var forceExit contracts.PenaltyTrackerForceExitValidator
+			if err := ix.Unpack(event, &forceExit); err != nil {
+				return nil, err
+			}
+			pubkey := types.BytesToValidatorPubkey(forceExit.Pubkey)
+			exits[pubkey] = ForceExitEvent{
+				Pubkey:      pubkey,
+				BlockNumber: event.BlockNumber,
+				TxHash:      event.TxHash,
+			}
+		case "ValidatorMarkedAsSettled":
+			var settlement contracts.PenaltyTrackerValidatorMarkedAsSettled
+			if err := ix.Unpack(event, &settlement); err != nil {
+				return nil, err
+			}
+			pubkey := types.BytesToValidatorPubkey(settlement.Pubkey)
+			exit, exists := exits[pubkey]
+			if !exists {
+				continue
+			}
+			exit.SettledBlockNumber = event.BlockNumber
+			exits[pubkey] = exit
+		}
+	}
+	return exits, nil
+}
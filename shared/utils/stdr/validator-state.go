@@ -24,6 +24,7 @@ var ValidatorState = map[uint8]string{
 type ValidatorInfo struct {
 	Status                           uint8
 	StatusToDisplay                  string
+	BeaconStatus                     beacon.ValidatorState
 	Pubkey                           []byte
 	PreDepositSignature              []byte
 	DepositSignature                 []byte
@@ -36,6 +37,7 @@ type ValidatorInfo struct {
 	DepositTime                      time.Time
 	WithdrawnBlock                   *big.Int
 	WithdrawnTime                    time.Time
+	Penalty                          ValidatorPenalty
 }
 
 func GetAllValidatorsRegisteredWithOperator(pnr *stader.PermissionlessNodeRegistryContractManager, operatorId *big.Int, operatorAddress common.Address, opts *bind.CallOpts) (map[types.ValidatorPubkey]contracts.Validator, []types.ValidatorPubkey, error) {
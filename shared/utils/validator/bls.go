@@ -19,8 +19,50 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 */
 package validator
 
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
 // BLS signing root with domain
 type signingRoot struct {
 	ObjectRoot []byte `ssz-size:"32"`
 	Domain     []byte `ssz-size:"32"`
 }
+
+// Initialize BLS support; the result of the first attempt is returned on every call
+var initBLS sync.Once
+var initBLSErr error
+
+func initializeBLS() error {
+	initBLS.Do(func() {
+		if err := eth2types.InitBLS(); err != nil {
+			initBLSErr = fmt.Errorf("Could not initialize BLS library: %w", err)
+		}
+	})
+	return initBLSErr
+}
+
+// Check a signature made by a key we don't hold locally
+func verifySignature(validatorPubkey types.ValidatorPubkey, signature types.ValidatorSignature, signingRoot [32]byte) error {
+	if err := initializeBLS(); err != nil {
+		return err
+	}
+
+	pubkey, err := eth2types.BLSPublicKeyFromBytes(validatorPubkey.Bytes())
+	if err != nil {
+		return fmt.Errorf("Could not decode validator pubkey %s: %w", validatorPubkey.Hex(), err)
+	}
+	sig, err := eth2types.BLSSignatureFromBytes(signature.Bytes())
+	if err != nil {
+		return fmt.Errorf("Could not decode signature: %w", err)
+	}
+	if !sig.Verify(signingRoot[:], pubkey) {
+		return errors.New("signature does not match the validator pubkey")
+	}
+	return nil
+}
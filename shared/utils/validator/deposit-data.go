@@ -20,11 +20,15 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package validator
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stader-labs/stader-node/shared/types/eth2"
+	"github.com/stader-labs/stader-node/stader-lib/types"
 	eth2types "github.com/wealdtech/go-eth2-types/v2"
 
 	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
 )
 
 // Get deposit data & root for a given validator key and withdrawal credentials
@@ -36,10 +40,64 @@ func GetDepositData(validatorKey *eth2types.BLSPrivateKey, withdrawalCredentials
 		Amount:                amount,
 	}
 
+	// Get signing root with domain
+	srHash, err := getDepositSigningRoot(dd, eth2Config)
+	if err != nil {
+		return eth2.DepositData{}, common.Hash{}, err
+	}
+
+	return buildDepositData(dd, validatorKey.Sign(srHash[:]).Marshal())
+}
+
+// Get deposit data & root for a validator whose key is held by a remote signer.
+// The returned signature is verified before use, since a bad deposit signature would lose the deposit.
+func GetDepositDataFromRemoteSigner(signer *web3signer.Client, validatorPubkey types.ValidatorPubkey, withdrawalCredentials common.Hash, eth2Config beacon.Eth2Config, amount uint64) (eth2.DepositData, common.Hash, error) {
+	// Build deposit data
+	dd := eth2.DepositDataNoSignature{
+		PublicKey:             validatorPubkey.Bytes(),
+		WithdrawalCredentials: withdrawalCredentials[:],
+		Amount:                amount,
+	}
+
+	// Get signing root with domain
+	srHash, err := getDepositSigningRoot(dd, eth2Config)
+	if err != nil {
+		return eth2.DepositData{}, common.Hash{}, err
+	}
+
+	// Get the signature from the remote signer
+	signature, err := signer.SignDeposit(validatorPubkey, withdrawalCredentials, amount, eth2Config.GenesisForkVersion, srHash)
+	if err != nil {
+		return eth2.DepositData{}, common.Hash{}, err
+	}
+	if err := verifySignature(validatorPubkey, signature, srHash); err != nil {
+		return eth2.DepositData{}, common.Hash{}, fmt.Errorf("Remote signer returned a bad deposit signature: %w", err)
+	}
+
+	return buildDepositData(dd, signature.Bytes())
+}
+
+// Check a deposit signature against the validator's pubkey without needing its private key.
+// A deposit with a bad signature is ignored by the Beacon chain, so Stader marks such keys as invalid.
+func VerifyDepositSignature(validatorPubkey types.ValidatorPubkey, withdrawalCredentials common.Hash, eth2Config beacon.Eth2Config, amount uint64, signature types.ValidatorSignature) error {
+	dd := eth2.DepositDataNoSignature{
+		PublicKey:             validatorPubkey.Bytes(),
+		WithdrawalCredentials: withdrawalCredentials[:],
+		Amount:                amount,
+	}
+	srHash, err := getDepositSigningRoot(dd, eth2Config)
+	if err != nil {
+		return err
+	}
+	return verifySignature(validatorPubkey, signature, srHash)
+}
+
+// Get the deposit signing root with the deposit domain
+func getDepositSigningRoot(dd eth2.DepositDataNoSignature, eth2Config beacon.Eth2Config) (common.Hash, error) {
 	// Get signing root
 	or, err := dd.HashTreeRoot()
 	if err != nil {
-		return eth2.DepositData{}, common.Hash{}, err
+		return common.Hash{}, err
 	}
 
 	sr := eth2.SigningRoot{
@@ -50,15 +108,19 @@ func GetDepositData(validatorKey *eth2types.BLSPrivateKey, withdrawalCredentials
 	// Get signing root with domain
 	srHash, err := sr.HashTreeRoot()
 	if err != nil {
-		return eth2.DepositData{}, common.Hash{}, err
+		return common.Hash{}, err
 	}
+	return srHash, nil
+}
 
+// Build the signed deposit data and its root
+func buildDepositData(dd eth2.DepositDataNoSignature, signature []byte) (eth2.DepositData, common.Hash, error) {
 	// Build deposit data struct (with signature)
 	var depositData = eth2.DepositData{
 		PublicKey:             dd.PublicKey,
 		WithdrawalCredentials: dd.WithdrawalCredentials,
 		Amount:                dd.Amount,
-		Signature:             validatorKey.Sign(srHash[:]).Marshal(),
+		Signature:             signature,
 	}
 
 	// Get deposit data root
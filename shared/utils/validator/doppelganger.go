@@ -0,0 +1,93 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/client"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/doppelganger"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	stadertypes "github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Hand newly stored validator keys to the validator client.
+// Keys that are already active on the Beacon chain may still be running on another machine, so they are moved out of
+// the validator client's keystores and watched for activity by the node daemon, which moves them back once they've
+// stayed quiet for long enough. The validator client keeps running every other key in the meantime.
+// Returns true if any of the keys are held back by the watch.
+func ActivateValidatorKeys(cfg *config.StaderConfig, bc beacon.Client, d *client.Client, w *wallet.Wallet, watch *doppelganger.Watch, pubkeys []stadertypes.ValidatorPubkey, reason string, log *log.ColorLogger) (bool, error) {
+	watchEpochs := cfg.StaderNode.DoppelgangerWatchEpochs.Value.(uint64)
+	activeKeys := map[stadertypes.ValidatorPubkey]beacon.ValidatorStatus{}
+	if watchEpochs > 0 && len(pubkeys) > 0 {
+		statuses, err := bc.GetValidatorStatuses(context.Background(), pubkeys, nil)
+		if err != nil {
+			return false, fmt.Errorf("error getting validator statuses: %w", err)
+		}
+		for pubkey, status := range statuses {
+			if status.Exists && CanBeSigning(status.Status) {
+				activeKeys[pubkey] = status
+			}
+		}
+	}
+
+	if len(activeKeys) > 0 {
+		head, err := bc.GetBeaconHead(context.Background())
+		if err != nil {
+			return false, fmt.Errorf("error getting beacon head: %w", err)
+		}
+		keys := make([]doppelganger.WatchedKey, 0, len(activeKeys))
+		for pubkey, status := range activeKeys {
+			if err := w.HoldValidatorKey(pubkey); err != nil {
+				return false, fmt.Errorf("error holding validator key %s back from the validator client: %w", pubkey.Hex(), err)
+			}
+			keys = append(keys, doppelganger.NewWatchedKey(pubkey, status.Index, reason, head.Epoch, watchEpochs))
+		}
+		if err := watch.Add(keys); err != nil {
+			return false, fmt.Errorf("error adding validator keys to the doppelganger watch: %w", err)
+		}
+		if log != nil {
+			log.Printlnf("%d of the keys are already active on the Beacon chain, holding them back from the validator client while they are watched for %d epochs...", len(keys), watchEpochs)
+		}
+	}
+
+	// Nothing the validator client loads has changed if every new key is held back
+	if len(activeKeys) < len(pubkeys) {
+		if err := RestartValidator(cfg, bc, log, d); err != nil {
+			return len(activeKeys) > 0, err
+		}
+	}
+	return len(activeKeys) > 0, nil
+}
+
+// Check if a validator in the given state is assigned duties, so another instance of it would leave traces on chain
+func CanBeSigning(state beacon.ValidatorState) bool {
+	switch state {
+	case beacon.ValidatorState_ActiveOngoing, beacon.ValidatorState_ActiveExiting, beacon.ValidatorState_ActiveSlashed:
+		return true
+	default:
+		return false
+	}
+}
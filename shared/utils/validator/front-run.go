@@ -0,0 +1,184 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package validator
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// How far back the deposit contract is searched for deposits of a key.
+// Older deposits have been processed by the Beacon chain, so they show up in the key's validator status instead.
+const DepositLookbackBlocks uint64 = 16384
+
+// Gwei amounts of the two deposits Stader makes for each permissionless validator
+const (
+	PreDepositAmountGwei uint64 = 1000000000
+	DepositAmountGwei    uint64 = 31000000000
+)
+
+// A DepositEvent of the Beacon deposit contract
+type BeaconDeposit struct {
+	Pubkey                types.ValidatorPubkey    `json:"pubkey"`
+	WithdrawalCredentials common.Hash              `json:"withdrawalCredentials"`
+	AmountGwei            uint64                   `json:"amountGwei"`
+	Signature             types.ValidatorSignature `json:"signature"`
+	BlockNumber           uint64                   `json:"blockNumber"`
+	TxHash                common.Hash              `json:"txHash"`
+}
+
+// Check if a deposit can take the validator away from the withdrawal credentials it was registered with.
+// The Beacon chain ignores a key's first deposit if its signature is bad, so only a validly signed one can front-run Stader's.
+func (d BeaconDeposit) IsFrontRun(withdrawalCredentials common.Hash, eth2Config beacon.Eth2Config) bool {
+	if d.WithdrawalCredentials == withdrawalCredentials {
+		return false
+	}
+	return VerifyDepositSignature(d.Pubkey, d.WithdrawalCredentials, eth2Config, d.AmountGwei, d.Signature) == nil
+}
+
+// Check the pre-deposit and deposit signatures of a key against the withdrawal credentials it will be registered with.
+// Stader marks keys with a bad signature as invalid and the operator loses their bond.
+func VerifyStaderDepositSignatures(pubkey types.ValidatorPubkey, withdrawalCredentials common.Hash, eth2Config beacon.Eth2Config, preDepositSignature types.ValidatorSignature, depositSignature types.ValidatorSignature) error {
+	if err := VerifyDepositSignature(pubkey, withdrawalCredentials, eth2Config, PreDepositAmountGwei, preDepositSignature); err != nil {
+		return fmt.Errorf("invalid pre-deposit signature for validator %s: %w", pubkey.Hex(), err)
+	}
+	if err := VerifyDepositSignature(pubkey, withdrawalCredentials, eth2Config, DepositAmountGwei, depositSignature); err != nil {
+		return fmt.Errorf("invalid deposit signature for validator %s: %w", pubkey.Hex(), err)
+	}
+	return nil
+}
+
+// Scan [fromBlock, toBlock] of the Beacon deposit contract in chunks of interval blocks for deposits of the given keys,
+// adding them to deposits. Pubkeys aren't indexed, so every deposit in the range is read and the others are dropped.
+// Returns the first block that hasn't been scanned, so a failed scan can be resumed.
+func ScanBeaconDeposits(depositContract *contracts.BeaconDeposit, pubkeys map[types.ValidatorPubkey]bool, fromBlock uint64, toBlock uint64, interval uint64, deposits map[types.ValidatorPubkey][]BeaconDeposit) (uint64, error) {
+	if interval == 0 {
+		return fromBlock, fmt.Errorf("the event log interval must be greater than 0")
+	}
+
+	for start := fromBlock; start <= toBlock; start += interval {
+		end := start + interval - 1
+		if end > toBlock {
+			end = toBlock
+		}
+
+		iterator, err := depositContract.FilterDepositEvent(&bind.FilterOpts{Start: start, End: &end})
+		if err != nil {
+			return start, fmt.Errorf("error scanning deposits in blocks %d to %d: %w", start, end, err)
+		}
+		for iterator.Next() {
+			event := iterator.Event
+			if event.Raw.Removed || len(event.Pubkey) != types.ValidatorPubkeyLength {
+				continue
+			}
+			pubkey := types.BytesToValidatorPubkey(event.Pubkey)
+			if !pubkeys[pubkey] {
+				continue
+			}
+			// The contract logs the amount as a little-endian gwei value
+			var amount uint64
+			if len(event.Amount) == 8 {
+				amount = binary.LittleEndian.Uint64(event.Amount)
+			}
+			deposits[pubkey] = append(deposits[pubkey], BeaconDeposit{
+				Pubkey:                pubkey,
+				WithdrawalCredentials: common.BytesToHash(event.WithdrawalCredentials),
+				AmountGwei:            amount,
+				Signature:             types.BytesToValidatorSignature(event.Signature),
+				BlockNumber:           event.Raw.BlockNumber,
+				TxHash:                event.Raw.TxHash,
+			})
+		}
+		err = iterator.Error()
+		iterator.Close()
+		if err != nil {
+			return start, fmt.Errorf("error reading deposits in blocks %d to %d: %w", start, end, err)
+		}
+	}
+
+	return toBlock + 1, nil
+}
+
+// Get the first block to search for deposits that the Beacon chain may not have processed yet
+func GetDepositLookbackStart(latestBlock uint64) uint64 {
+	if latestBlock > DepositLookbackBlocks {
+		return latestBlock - DepositLookbackBlocks
+	}
+	return 0
+}
+
+// Check the keys about to be added to the registry, since either problem costs the operator their bond once the keys are added:
+// both deposit signatures are checked locally, and the deposit contract is searched for deposits of the keys that the Beacon
+// chain hasn't processed yet. Stader only deposits a key after it has been added, so any earlier deposit would front-run it.
+func CheckKeysBeforeSubmission(ec stader.ExecutionClient, bc beacon.Client, eventLogInterval uint64, eth2Config beacon.Eth2Config, pubKeys [][]byte, withdrawalCredentials []common.Hash, preDepositSignatures [][]byte, depositSignatures [][]byte) error {
+	pubkeys := make(map[types.ValidatorPubkey]bool, len(pubKeys))
+	for i := range pubKeys {
+		pubkey := types.BytesToValidatorPubkey(pubKeys[i])
+		pubkeys[pubkey] = true
+		err := VerifyStaderDepositSignatures(pubkey, withdrawalCredentials[i], eth2Config, types.BytesToValidatorSignature(preDepositSignatures[i]), types.BytesToValidatorSignature(depositSignatures[i]))
+		if err != nil {
+			return fmt.Errorf("%w\nThe key would be marked as having an invalid signature and the bond lost, so your funds have not been deposited.", err)
+		}
+	}
+
+	depositContract, err := bc.GetEth2DepositContract(context.Background())
+	if err != nil {
+		return fmt.Errorf("Error getting the Beacon deposit contract: %w", err)
+	}
+	dc, err := contracts.NewBeaconDeposit(depositContract.Address, ec)
+	if err != nil {
+		return err
+	}
+	latestBlock, err := ec.BlockNumber(context.Background())
+	if err != nil {
+		return err
+	}
+
+	deposits := map[types.ValidatorPubkey][]BeaconDeposit{}
+	if _, err := ScanBeaconDeposits(dc, pubkeys, GetDepositLookbackStart(latestBlock), latestBlock, eventLogInterval, deposits); err != nil {
+		return fmt.Errorf("Error checking the deposit contract for existing deposits: %w\nYour funds have not been deposited for your own safety.", err)
+	}
+	if len(deposits) == 0 {
+		return nil
+	}
+
+	var alert strings.Builder
+	alert.WriteString("**** ALERT ****\nThe following validator keys have already been deposited to the Beacon deposit contract:\n")
+	for pubkey, keyDeposits := range deposits {
+		for _, deposit := range keyDeposits {
+			alert.WriteString(fmt.Sprintf("\t%s: %.9f ETH in block %d (transaction %s) with withdrawal credentials %s\n", pubkey.Hex(), float64(deposit.AmountGwei)/1e9, deposit.BlockNumber, deposit.TxHash.Hex(), deposit.WithdrawalCredentials.Hex()))
+		}
+	}
+	alert.WriteString("Stader would mark these keys as front-run and the bond would be lost, so your funds have not been deposited.\n" +
+		"Someone else may have access to these keys. PLEASE REPORT THIS TO THE STADER DEVELOPERS.\n" +
+		"***************")
+	return errors.New(alert.String())
+}
@@ -0,0 +1,87 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	hexutil "github.com/stader-labs/stader-node/shared/utils/hex"
+)
+
+// Config
+const EncryptedKeystoreVersion = 4
+
+// An EIP-2335 keystore, as produced by staking-deposit-cli and the validator clients
+type encryptedKeystore struct {
+	Crypto  map[string]interface{} `json:"crypto"`
+	Version uint                   `json:"version"`
+	Pubkey  string                 `json:"pubkey"`
+	Path    string                 `json:"path"`
+}
+
+// Decrypt an EIP-2335 validator keystore, returning the key and the derivation path recorded in it
+func DecryptKeystore(keystoreBytes []byte, password string) (*eth2types.BLSPrivateKey, string, error) {
+
+	// Decode the keystore
+	var keystore encryptedKeystore
+	if err := json.Unmarshal(keystoreBytes, &keystore); err != nil {
+		return nil, "", fmt.Errorf("Could not decode validator keystore: %w", err)
+	}
+	if keystore.Version != EncryptedKeystoreVersion {
+		return nil, "", fmt.Errorf("Unsupported validator keystore version %d, only version %d keystores can be imported", keystore.Version, EncryptedKeystoreVersion)
+	}
+	if keystore.Crypto == nil {
+		return nil, "", fmt.Errorf("Validator keystore is missing its crypto section")
+	}
+
+	// Decrypt the key
+	keyBytes, err := eth2ks.New().Decrypt(keystore.Crypto, password)
+	if err != nil {
+		return nil, "", fmt.Errorf("Could not decrypt validator keystore %s: %w", keystore.Pubkey, err)
+	}
+	if err := initializeBLS(); err != nil {
+		return nil, "", err
+	}
+	key, err := eth2types.BLSPrivateKeyFromBytes(keyBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("Could not decode validator key in keystore %s: %w", keystore.Pubkey, err)
+	}
+
+	// Make sure the key matches the pubkey the keystore claims to hold
+	if keystore.Pubkey != "" {
+		pubkey, err := types.HexToValidatorPubkey(hexutil.RemovePrefix(keystore.Pubkey))
+		if err != nil {
+			return nil, "", fmt.Errorf("Invalid pubkey %s in validator keystore: %w", keystore.Pubkey, err)
+		}
+		if !bytes.Equal(pubkey.Bytes(), key.PublicKey().Marshal()) {
+			return nil, "", fmt.Errorf("Validator keystore %s holds the key for a different pubkey", keystore.Pubkey)
+		}
+	}
+
+	// Return
+	return key, keystore.Path, nil
+
+}
@@ -0,0 +1,77 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package validator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stader-labs/stader-node/shared/services/config"
+	lhkeystore "github.com/stader-labs/stader-node/shared/services/wallet/keystore/lighthouse"
+	nmkeystore "github.com/stader-labs/stader-node/shared/services/wallet/keystore/nimbus"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
+	hexutil "github.com/stader-labs/stader-node/shared/utils/hex"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// The comma-separated list of remote signer keys Teku, Lodestar and Prysm are started with
+const RemoteSignerKeysFileName = "remote-signer-keys.txt"
+
+// Make the validator client aware of the operator's keys the remote signer holds; the signer's other keys are left out.
+// Lighthouse reads them from its validator definitions and Nimbus from its remote keystores. Teku, Lodestar and Prysm
+// are passed the key list when they start, so they only pick up changes once the validator client is restarted.
+func UpdateRemoteSignerKeys(cfg *config.StaderConfig, signer *web3signer.Client, registeredPubkeys []types.ValidatorPubkey) ([]types.ValidatorPubkey, error) {
+	signerPubkeys, err := signer.GetPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(map[types.ValidatorPubkey]bool, len(registeredPubkeys))
+	for _, pubkey := range registeredPubkeys {
+		registered[pubkey] = true
+	}
+	pubkeys := []types.ValidatorPubkey{}
+	for _, pubkey := range signerPubkeys {
+		if registered[pubkey] {
+			pubkeys = append(pubkeys, pubkey)
+		}
+	}
+
+	keychainPath := os.ExpandEnv(cfg.StaderNode.GetValidatorKeychainPath())
+	if err := lhkeystore.NewKeystore(keychainPath, nil).StoreRemoteValidatorKeys(pubkeys, signer.GetUrl()); err != nil {
+		return nil, err
+	}
+	if err := nmkeystore.NewKeystore(keychainPath, nil).StoreRemoteValidatorKeys(pubkeys, signer.GetUrl()); err != nil {
+		return nil, err
+	}
+
+	keyList := make([]string, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		keyList[i] = hexutil.AddPrefix(pubkey.Hex())
+	}
+	if err := ioutil.WriteFile(filepath.Join(keychainPath, RemoteSignerKeysFileName), []byte(strings.Join(keyList, ",")), lhkeystore.FileMode); err != nil {
+		return nil, fmt.Errorf("Could not write the remote signer key list: %w", err)
+	}
+
+	return pubkeys, nil
+}
@@ -0,0 +1,156 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package validator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
+	"github.com/stader-labs/stader-node/shared/services/web3signer/web3signertest"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+func TestUpdateRemoteSignerKeys(t *testing.T) {
+	dataPath := t.TempDir()
+	cfg := config.NewStaderConfig(dataPath, true)
+	cfg.StaderNode.DataPath.Value = dataPath
+	keychainPath := filepath.Join(dataPath, "validators")
+
+	registered, other, deposited := fillPubkey(0xa1), fillPubkey(0xa2), fillPubkey(0xa3)
+	mock := web3signertest.NewMockServer(map[types.ValidatorPubkey]types.ValidatorSignature{
+		registered: {},
+		other:      {},
+		deposited:  {},
+	})
+	url := mock.Start()
+	defer mock.Close()
+	signer := web3signer.NewClient(url, 5*time.Second)
+
+	// A local key Lighthouse already has, and a remote key that's no longer the operator's
+	definitionsPath := filepath.Join(keychainPath, "lighthouse", "validators", "validator_definitions.yml")
+	if err := os.MkdirAll(filepath.Dir(definitionsPath), 0770); err != nil {
+		t.Fatal(err)
+	}
+	existing := "---\n" +
+		"- enabled: true\n  voting_public_key: \"0xlocal\"\n  type: local_keystore\n  voting_keystore_path: /validators/lighthouse/validators/0xlocal/voting-keystore.json\n" +
+		"- enabled: true\n  voting_public_key: \"0x" + other.Hex() + "\"\n  type: web3signer\n  url: \"" + url + "\"\n"
+	if err := ioutil.WriteFile(definitionsPath, []byte(existing), 0640); err != nil {
+		t.Fatal(err)
+	}
+	staleNimbusKey := filepath.Join(keychainPath, "nimbus", "validators", "0x"+other.Hex())
+	if err := os.MkdirAll(staleNimbusKey, 0770); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(staleNimbusKey, "remote_keystore.json"), []byte("{}"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	// The signer's other key isn't registered, and a registered key the signer doesn't hold can't be used
+	pubkeys, err := UpdateRemoteSignerKeys(cfg, signer, []types.ValidatorPubkey{registered, deposited, fillPubkey(0xa4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"0x" + registered.Hex(): true, "0x" + deposited.Hex(): true}
+	if len(pubkeys) != len(want) {
+		t.Fatalf("enabled %d keys, want %d", len(pubkeys), len(want))
+	}
+
+	// Lighthouse keeps its local key and only gets the operator's remote keys
+	definitionsBytes, err := ioutil.ReadFile(definitionsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var definitions []map[string]interface{}
+	if err := yaml.Unmarshal(definitionsBytes, &definitions); err != nil {
+		t.Fatalf("could not parse the definitions: %s\n%s", err, definitionsBytes)
+	}
+	remote := map[string]bool{}
+	local := 0
+	for _, definition := range definitions {
+		switch definition["type"] {
+		case "web3signer":
+			remote[definition["voting_public_key"].(string)] = true
+		case "local_keystore":
+			local++
+		}
+	}
+	if local != 1 {
+		t.Errorf("%d local definitions were kept, want 1", local)
+	}
+	if !sameKeys(remote, want) {
+		t.Errorf("lighthouse remote definitions are %v, want %v", remote, want)
+	}
+
+	// Nimbus gets a remote keystore for each of the operator's keys, and loses the stale one
+	nimbusKeys := map[string]bool{}
+	entries, err := ioutil.ReadDir(filepath.Join(keychainPath, "nimbus", "validators"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if _, err := os.Stat(filepath.Join(keychainPath, "nimbus", "validators", entry.Name(), "remote_keystore.json")); err == nil {
+			nimbusKeys[entry.Name()] = true
+		}
+	}
+	if !sameKeys(nimbusKeys, want) {
+		t.Errorf("nimbus remote keystores are %v, want %v", nimbusKeys, want)
+	}
+
+	// The other clients are started with the key list
+	keyList, err := ioutil.ReadFile(filepath.Join(keychainPath, RemoteSignerKeysFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := map[string]bool{}
+	for _, pubkey := range strings.Split(string(keyList), ",") {
+		listed[pubkey] = true
+	}
+	if !sameKeys(listed, want) {
+		t.Errorf("key list is %s, want %v", keyList, want)
+	}
+}
+
+func fillPubkey(b byte) types.ValidatorPubkey {
+	var pubkey types.ValidatorPubkey
+	for i := range pubkey {
+		pubkey[i] = b
+	}
+	return pubkey
+}
+
+func sameKeys(a map[string]bool, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if !b[key] {
+			return false
+		}
+	}
+	return true
+}
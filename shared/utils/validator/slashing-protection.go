@@ -0,0 +1,162 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package validator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
+	hexutil "github.com/stader-labs/stader-node/shared/utils/hex"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// The only version of the EIP-3076 interchange format the clients support
+const SlashingProtectionInterchangeVersion = "5"
+
+// The name every validator client's slashing protection history is exported under
+const SlashingProtectionFileName = "slashing_protection.json"
+
+// The genesis validators root of each network, which every interchange file is bound to
+var genesisValidatorsRoots = map[cfgtypes.Network]string{
+	cfgtypes.Network_Mainnet: "0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95",
+	cfgtypes.Network_Prater:  "0x043db0d9a83813551ee2f33450d23797757d430911a9320530ad8a0eabc43efb",
+	cfgtypes.Network_Devnet:  "0x043db0d9a83813551ee2f33450d23797757d430911a9320530ad8a0eabc43efb",
+}
+
+// An EIP-3076 slashing protection interchange file
+type SlashingProtectionInterchange struct {
+	Metadata struct {
+		InterchangeFormatVersion string `json:"interchange_format_version"`
+		GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+	} `json:"metadata"`
+	Data []SlashingProtectionRecord `json:"data"`
+}
+
+// The blocks and attestations a single validator has signed
+type SlashingProtectionRecord struct {
+	Pubkey             string                          `json:"pubkey"`
+	SignedBlocks       []SlashingProtectionBlock       `json:"signed_blocks"`
+	SignedAttestations []SlashingProtectionAttestation `json:"signed_attestations"`
+}
+
+type SlashingProtectionBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+type SlashingProtectionAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// Parse an interchange file and make sure it is well-formed.
+// If network is known, the file must also have been exported on it.
+func ParseSlashingProtectionInterchange(bytes []byte, network cfgtypes.Network) (*SlashingProtectionInterchange, error) {
+	interchange := new(SlashingProtectionInterchange)
+	if err := json.Unmarshal(bytes, interchange); err != nil {
+		return nil, fmt.Errorf("error parsing slashing protection interchange: %w", err)
+	}
+
+	if interchange.Metadata.InterchangeFormatVersion != SlashingProtectionInterchangeVersion {
+		return nil, fmt.Errorf("unsupported interchange format version [%s], expected [%s]", interchange.Metadata.InterchangeFormatVersion, SlashingProtectionInterchangeVersion)
+	}
+	if err := checkRoot(interchange.Metadata.GenesisValidatorsRoot); err != nil {
+		return nil, fmt.Errorf("invalid genesis validators root: %w", err)
+	}
+	if expectedRoot, exists := genesisValidatorsRoots[network]; exists && !strings.EqualFold(interchange.Metadata.GenesisValidatorsRoot, expectedRoot) {
+		return nil, fmt.Errorf("the interchange is for genesis validators root %s, but the %s network's is %s", interchange.Metadata.GenesisValidatorsRoot, network, expectedRoot)
+	}
+
+	pubkeys := make(map[types.ValidatorPubkey]bool, len(interchange.Data))
+	for i, record := range interchange.Data {
+		pubkey, err := types.HexToValidatorPubkey(hexutil.RemovePrefix(record.Pubkey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pubkey in record %d: %w", i, err)
+		}
+		if pubkeys[pubkey] {
+			return nil, fmt.Errorf("validator %s appears more than once", pubkey.Hex())
+		}
+		pubkeys[pubkey] = true
+
+		for _, block := range record.SignedBlocks {
+			if _, err := strconv.ParseUint(block.Slot, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid block slot [%s] for validator %s", block.Slot, pubkey.Hex())
+			}
+			if err := checkOptionalRoot(block.SigningRoot); err != nil {
+				return nil, fmt.Errorf("invalid block signing root for validator %s: %w", pubkey.Hex(), err)
+			}
+		}
+		for _, attestation := range record.SignedAttestations {
+			source, err := strconv.ParseUint(attestation.SourceEpoch, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attestation source epoch [%s] for validator %s", attestation.SourceEpoch, pubkey.Hex())
+			}
+			target, err := strconv.ParseUint(attestation.TargetEpoch, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attestation target epoch [%s] for validator %s", attestation.TargetEpoch, pubkey.Hex())
+			}
+			if source > target {
+				return nil, fmt.Errorf("attestation source epoch %d is after its target epoch %d for validator %s", source, target, pubkey.Hex())
+			}
+			if err := checkOptionalRoot(attestation.SigningRoot); err != nil {
+				return nil, fmt.Errorf("invalid attestation signing root for validator %s: %w", pubkey.Hex(), err)
+			}
+		}
+	}
+
+	return interchange, nil
+}
+
+// The total number of signed blocks and attestations in the interchange
+func (i *SlashingProtectionInterchange) CountSignatures() (int, int) {
+	blocks := 0
+	attestations := 0
+	for _, record := range i.Data {
+		blocks += len(record.SignedBlocks)
+		attestations += len(record.SignedAttestations)
+	}
+	return blocks, attestations
+}
+
+func checkRoot(root string) error {
+	if !strings.HasPrefix(root, "0x") {
+		return fmt.Errorf("[%s] is not 0x-prefixed", root)
+	}
+	bytes, err := hex.DecodeString(hexutil.RemovePrefix(root))
+	if err != nil {
+		return fmt.Errorf("[%s] is not a hex string: %w", root, err)
+	}
+	if len(bytes) != 32 {
+		return fmt.Errorf("[%s] is %d bytes long instead of 32", root, len(bytes))
+	}
+	return nil
+}
+
+func checkOptionalRoot(root string) error {
+	if root == "" {
+		return nil
+	}
+	return checkRoot(root)
+}
@@ -0,0 +1,144 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
+)
+
+const (
+	testInterchangePubkey  = "0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed"
+	testInterchangePubkey2 = "0xa1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0a1e2d8c0"
+	testPraterRoot         = "0x043db0d9a83813551ee2f33450d23797757d430911a9320530ad8a0eabc43efb"
+)
+
+func TestParseSlashingProtectionInterchange(t *testing.T) {
+	tests := []struct {
+		name    string
+		network cfgtypes.Network
+		json    string
+		blocks  int
+		atts    int
+		err     string
+	}{
+		{
+			name:    "valid",
+			network: cfgtypes.Network_Prater,
+			json: `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + testPraterRoot + `"},"data":[
+				{"pubkey":"` + testInterchangePubkey + `","signed_blocks":[{"slot":"81952"},{"slot":"81953","signing_root":"` + testPraterRoot + `"}],
+				 "signed_attestations":[{"source_epoch":"2290","target_epoch":"3007"}]},
+				{"pubkey":"` + testInterchangePubkey2 + `","signed_blocks":[],"signed_attestations":[{"source_epoch":"5","target_epoch":"6"}]}]}`,
+			blocks: 2,
+			atts:   2,
+		},
+		{
+			name:    "unknown network skips the root check",
+			network: cfgtypes.Network_Unknown,
+			json:    `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"0x` + strings.Repeat("11", 32) + `"},"data":[]}`,
+		},
+		{
+			name:    "malformed json",
+			network: cfgtypes.Network_Prater,
+			json:    `{"metadata":`,
+			err:     "error parsing slashing protection interchange",
+		},
+		{
+			name:    "unsupported version",
+			network: cfgtypes.Network_Prater,
+			json:    `{"metadata":{"interchange_format_version":"4","genesis_validators_root":"` + testPraterRoot + `"},"data":[]}`,
+			err:     "unsupported interchange format version",
+		},
+		{
+			name:    "malformed root",
+			network: cfgtypes.Network_Prater,
+			json:    `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"043db0d9"},"data":[]}`,
+			err:     "invalid genesis validators root",
+		},
+		{
+			name:    "other network",
+			network: cfgtypes.Network_Mainnet,
+			json:    `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + testPraterRoot + `"},"data":[]}`,
+			err:     "but the mainnet network's is",
+		},
+		{
+			name:    "invalid pubkey",
+			network: cfgtypes.Network_Prater,
+			json:    `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + testPraterRoot + `"},"data":[{"pubkey":"0x1234"}]}`,
+			err:     "invalid pubkey in record 0",
+		},
+		{
+			name:    "duplicate validator",
+			network: cfgtypes.Network_Prater,
+			json: `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + testPraterRoot + `"},"data":[
+				{"pubkey":"` + testInterchangePubkey + `"},{"pubkey":"` + testInterchangePubkey + `"}]}`,
+			err: "appears more than once",
+		},
+		{
+			name:    "invalid slot",
+			network: cfgtypes.Network_Prater,
+			json: `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + testPraterRoot + `"},"data":[
+				{"pubkey":"` + testInterchangePubkey + `","signed_blocks":[{"slot":"-1"}]}]}`,
+			err: "invalid block slot [-1]",
+		},
+		{
+			name:    "invalid block signing root",
+			network: cfgtypes.Network_Prater,
+			json: `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + testPraterRoot + `"},"data":[
+				{"pubkey":"` + testInterchangePubkey + `","signed_blocks":[{"slot":"1","signing_root":"0x12"}]}]}`,
+			err: "invalid block signing root",
+		},
+		{
+			name:    "source after target",
+			network: cfgtypes.Network_Prater,
+			json: `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + testPraterRoot + `"},"data":[
+				{"pubkey":"` + testInterchangePubkey + `","signed_attestations":[{"source_epoch":"7","target_epoch":"6"}]}]}`,
+			err: "attestation source epoch 7 is after its target epoch 6",
+		},
+		{
+			name:    "invalid target epoch",
+			network: cfgtypes.Network_Prater,
+			json: `{"metadata":{"interchange_format_version":"5","genesis_validators_root":"` + testPraterRoot + `"},"data":[
+				{"pubkey":"` + testInterchangePubkey + `","signed_attestations":[{"source_epoch":"7","target_epoch":"x"}]}]}`,
+			err: "invalid attestation target epoch [x]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interchange, err := ParseSlashingProtectionInterchange([]byte(test.json), test.network)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			blocks, atts := interchange.CountSignatures()
+			if blocks != test.blocks || atts != test.atts {
+				t.Errorf("expected %d blocks and %d attestations, got %d and %d", test.blocks, test.atts, blocks, atts)
+			}
+		})
+	}
+}
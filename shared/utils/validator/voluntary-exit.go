@@ -20,6 +20,10 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package validator
 
 import (
+	"fmt"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
 	"github.com/stader-labs/stader-node/shared/types/eth2"
 	"github.com/stader-labs/stader-node/stader-lib/types"
 	eth2types "github.com/wealdtech/go-eth2-types/v2"
@@ -28,6 +32,61 @@ import (
 // Get a voluntary exit message signature for a given validator key and index
 func GetSignedExitMessage(validatorKey *eth2types.BLSPrivateKey, validatorIndex uint64, epoch uint64, signatureDomain []byte) (types.ValidatorSignature, [32]byte, error) {
 
+	// Get signing root
+	srHash, err := getExitSigningRoot(validatorIndex, epoch, signatureDomain)
+	if err != nil {
+		return types.ValidatorSignature{}, [32]byte{}, err
+	}
+
+	// Sign message
+	signature := validatorKey.Sign(srHash[:]).Marshal()
+
+	// Return
+	return types.BytesToValidatorSignature(signature), srHash, nil
+
+}
+
+// Get a voluntary exit message signature from a remote signer.
+// forkInfo must be the fork the signature domain was built from, since the signer derives the domain itself.
+func GetSignedExitMessageFromRemoteSigner(signer *web3signer.Client, validatorPubkey types.ValidatorPubkey, validatorIndex uint64, epoch uint64, signatureDomain []byte, forkInfo beacon.ForkInfo) (types.ValidatorSignature, [32]byte, error) {
+
+	// Get signing root
+	srHash, err := getExitSigningRoot(validatorIndex, epoch, signatureDomain)
+	if err != nil {
+		return types.ValidatorSignature{}, [32]byte{}, err
+	}
+
+	// Sign message
+	signature, err := signer.SignVoluntaryExit(validatorPubkey, validatorIndex, epoch, forkInfo, srHash)
+	if err != nil {
+		return types.ValidatorSignature{}, [32]byte{}, err
+	}
+	if err := verifySignature(validatorPubkey, signature, srHash); err != nil {
+		return types.ValidatorSignature{}, [32]byte{}, fmt.Errorf("Remote signer returned a bad exit signature: %w", err)
+	}
+
+	// Return
+	return signature, srHash, nil
+
+}
+
+// Check a voluntary exit message signature against the validator's pubkey
+func VerifySignedExitMessage(validatorPubkey types.ValidatorPubkey, validatorIndex uint64, epoch uint64, signatureDomain []byte, signature types.ValidatorSignature) error {
+
+	// Get signing root
+	srHash, err := getExitSigningRoot(validatorIndex, epoch, signatureDomain)
+	if err != nil {
+		return err
+	}
+
+	// Verify signature
+	return verifySignature(validatorPubkey, signature, srHash)
+
+}
+
+// Get the signing root of a voluntary exit message
+func getExitSigningRoot(validatorIndex uint64, epoch uint64, signatureDomain []byte) ([32]byte, error) {
+
 	// Build voluntary exit message
 	exitMessage := eth2.VoluntaryExit{
 		Epoch:          epoch,
@@ -37,7 +96,7 @@ func GetSignedExitMessage(validatorKey *eth2types.BLSPrivateKey, validatorIndex
 	// Get object root
 	or, err := exitMessage.HashTreeRoot()
 	if err != nil {
-		return types.ValidatorSignature{}, [32]byte{}, err
+		return [32]byte{}, err
 	}
 
 	// Get signing root
@@ -46,15 +105,6 @@ func GetSignedExitMessage(validatorKey *eth2types.BLSPrivateKey, validatorIndex
 		Domain:     signatureDomain,
 	}
 
-	srHash, err := sr.HashTreeRoot()
-	if err != nil {
-		return types.ValidatorSignature{}, [32]byte{}, err
-	}
-
-	// Sign message
-	signature := validatorKey.Sign(srHash[:]).Marshal()
-
-	// Return
-	return types.BytesToValidatorSignature(signature), srHash, nil
+	return sr.HashTreeRoot()
 
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/stader-labs/stader-node/shared/services/gas"
 	"github.com/stader-labs/stader-node/shared/services/stader"
 	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
 	"github.com/stader-labs/stader-node/shared/utils/math"
 	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
 	"github.com/urfave/cli"
@@ -47,6 +48,9 @@ func ClaimSpRewards(c *cli.Context) error {
 		fmt.Println("You have no unclaimed cycles!")
 		return nil
 	}
+	if len(canClaimSpRewards.QuarantinedCycles) != 0 {
+		fmt.Printf("%sThe merkle proofs for cycles %v did not match the on-chain merkle roots and were quarantined, so those cycles can't be claimed yet. They will be downloaded again once the Stader backend serves valid proofs.%s\n\n", log.ColorYellow, canClaimSpRewards.QuarantinedCycles, log.ColorReset)
+	}
 
 	fmt.Printf("Getting the detailed cycles info...\n")
 	detailedCyclesInfo, err := staderClient.GetDetailedCyclesInfo(canClaimSpRewards.UnclaimedCycles)
@@ -191,6 +191,193 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 
 				},
 			},
+			{
+				Name:      "sign-transactions",
+				Usage:     "Sign transactions exported with --export-unsigned-tx, on the machine that holds the node wallet",
+				UsageText: "stader-cli node sign-transactions --file unsigned.json --out signed.json",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "Path to the unsigned transactions file (Required)",
+					},
+					cli.StringFlag{
+						Name:  "out, o",
+						Usage: "Path to write the signed transactions to (Required)",
+					},
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm signing",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate flags
+					if c.String("file") == "" || c.String("out") == "" {
+						return fmt.Errorf("file and out need to be set")
+					}
+
+					// Run
+					return signTransactions(c)
+
+				},
+			},
+			{
+				Name:      "broadcast",
+				Usage:     "Broadcast transactions that were signed on an offline machine",
+				UsageText: "stader-cli node broadcast --file signed.json",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "Path to the signed transactions file (Required)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate flags
+					if c.String("file") == "" {
+						return fmt.Errorf("file needs to be set")
+					}
+
+					// Run
+					return broadcastTransactions(c)
+
+				},
+			},
+			{
+				Name:      "penalties",
+				Aliases:   []string{"p"},
+				Usage:     "Show the penalty of each validator, how close it is to being force exited and the node's force exit history",
+				UsageText: "stader-cli node penalties",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return getPenalties(c)
+				},
+			},
+			{
+				Name:      "export-ledger",
+				Usage:     "Export the node's rewards, exit settlements and SD collateral movements with their fiat value, for accounting and tax reports",
+				UsageText: "stader-cli node export-ledger [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--format csv|json] [--currency usd] [--price-source coingecko|none|prices.csv] [--output path]",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "from",
+						Usage: "The first day to export, in UTC (defaults to the beginning of the ledger)",
+					},
+					cli.StringFlag{
+						Name:  "to",
+						Usage: "The last day to export, in UTC (defaults to today)",
+					},
+					cli.StringFlag{
+						Name:  "format, f",
+						Usage: "The format to export in, csv or json",
+						Value: "csv",
+					},
+					cli.StringFlag{
+						Name:  "currency, c",
+						Usage: "The fiat currency to value the records in",
+						Value: "usd",
+					},
+					cli.StringFlag{
+						Name:  "price-source, p",
+						Usage: "Where to get daily prices from: coingecko, none, or the path to a CSV file with date,asset,price rows in the chosen currency",
+						Value: "coingecko",
+					},
+					cli.StringFlag{
+						Name:  "output, o",
+						Usage: "The file to write to (defaults to stader_ledger.csv or stader_ledger.json)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return exportLedger(c)
+				},
+			},
+			{
+				Name:    "tx",
+				Aliases: []string{"t"},
+				Usage:   "Manage the transactions the node has sent",
+				Subcommands: []cli.Command{
+					{
+						Name:      "list",
+						Aliases:   []string{"l"},
+						Usage:     "List the transactions the node has sent and flag the ones that look stuck",
+						UsageText: "stader-cli node tx list",
+						Action: func(c *cli.Context) error {
+
+							// Validate args
+							if err := cliutils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run
+							return listTransactions(c)
+						},
+					},
+					{
+						Name:      "speedup",
+						Aliases:   []string{"s"},
+						Usage:     "Resend a pending transaction with higher fees",
+						UsageText: "stader-cli node tx speedup tx-hash",
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "yes, y",
+								Usage: "Automatically confirm the speedup",
+							},
+						},
+						Action: func(c *cli.Context) error {
+
+							// Validate args
+							if err := cliutils.ValidateArgCount(c, 1); err != nil {
+								return err
+							}
+							hash, err := cliutils.ValidateTxHash("tx-hash", c.Args().Get(0))
+							if err != nil {
+								return err
+							}
+
+							// Run
+							return speedupTransaction(c, hash)
+						},
+					},
+					{
+						Name:      "cancel",
+						Aliases:   []string{"c"},
+						Usage:     "Cancel a pending transaction by replacing it with a 0 ETH transfer to the node account",
+						UsageText: "stader-cli node tx cancel tx-hash",
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "yes, y",
+								Usage: "Automatically confirm the cancellation",
+							},
+						},
+						Action: func(c *cli.Context) error {
+
+							// Validate args
+							if err := cliutils.ValidateArgCount(c, 1); err != nil {
+								return err
+							}
+							hash, err := cliutils.ValidateTxHash("tx-hash", c.Args().Get(0))
+							if err != nil {
+								return err
+							}
+
+							// Run
+							return cancelTransaction(c, hash)
+						},
+					},
+				},
+			},
 			{
 				Name:      "get-contracts-info",
 				Aliases:   []string{"c"},
@@ -11,8 +11,12 @@ import (
 	"github.com/stader-labs/stader-node/shared/services/stader"
 	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
 	"github.com/stader-labs/stader-node/shared/utils/math"
+	staderCore "github.com/stader-labs/stader-node/stader-lib/stader"
 )
 
+// Gas limit for an SD deposit exported together with its approval, which can't be estimated before the approval is mined
+const exportedDepositSdGasLimit uint64 = 250000
+
 func nodeDepositSd(c *cli.Context) error {
 
 	staderClient, err := stader.NewClientFromCtx(c)
@@ -51,6 +55,7 @@ func nodeDepositSd(c *cli.Context) error {
 	maxApproval = maxApproval.Exp(maxApproval, big.NewInt(256), nil)
 	maxApproval = maxApproval.Sub(maxApproval, big.NewInt(1))
 
+	approvalExported := false
 	if allowance.Allowance.Cmp(maxApproval) < 0 {
 		fmt.Println("Before depositing SD, you must first give the collateral contract approval to interact with your SD.")
 		fmt.Println("This only needs to be done once for your node.")
@@ -81,35 +86,51 @@ func nodeDepositSd(c *cli.Context) error {
 		if err != nil {
 			return err
 		}
-		hash := response.ApproveTxHash
-		fmt.Printf("Approving SD for depositing...\n")
-		cliutils.PrintTransactionHash(staderClient, hash)
-		if _, err = staderClient.WaitForTransaction(hash); err != nil {
-			return err
-		}
-		fmt.Println("Successfully approved SD to deposit.")
 
-		// If a custom nonce is set, increment it for the next transaction
-		if c.GlobalUint64("nonce") != 0 {
-			staderClient.IncrementCustomNonce()
+		// An exported approval isn't sent, so the deposit is exported right after it; the client gives it the next nonce
+		if staderClient.IsExportingUnsignedTransactions() {
+			approvalExported = true
+		} else {
+			hash := response.ApproveTxHash
+			fmt.Printf("Approving SD for depositing...\n")
+			cliutils.PrintTransactionHash(staderClient, hash)
+			if _, err = staderClient.WaitForTransaction(hash); err != nil {
+				return err
+			}
+			fmt.Println("Successfully approved SD to deposit.")
+
+			// If a custom nonce is set, increment it for the next transaction
+			if c.GlobalUint64("nonce") != 0 {
+				staderClient.IncrementCustomNonce()
+			}
 		}
 	}
 
-	canDeposit, err := staderClient.CanNodeDepositSd(amountWei)
-	if err != nil {
-		return err
-	}
-	if canDeposit.InsufficientBalance {
-		fmt.Println("The node's SD balance is insufficient.")
-		return nil
-	}
-	if canDeposit.CollateralContractPaused {
-		fmt.Println("The collateral contract is paused.")
-		return nil
+	// The deposit can't be checked or estimated until the approval is mined, so an exported one gets a fixed gas limit
+	var gasInfo staderCore.GasInfo
+	if approvalExported {
+		maxFee, maxPrioFee, gasLimit := staderClient.GetGasSettings()
+		if gasLimit == 0 {
+			staderClient.AssignGasSettings(maxFee, maxPrioFee, exportedDepositSdGasLimit)
+		}
+	} else {
+		canDeposit, err := staderClient.CanNodeDepositSd(amountWei)
+		if err != nil {
+			return err
+		}
+		if canDeposit.InsufficientBalance {
+			fmt.Println("The node's SD balance is insufficient.")
+			return nil
+		}
+		if canDeposit.CollateralContractPaused {
+			fmt.Println("The collateral contract is paused.")
+			return nil
+		}
+		gasInfo = canDeposit.GasInfo
 	}
 
 	// Assign max fees
-	err = gas.AssignMaxFeeAndLimit(canDeposit.GasInfo, staderClient, c.Bool("yes"))
+	err = gas.AssignMaxFeeAndLimit(gasInfo, staderClient, c.Bool("yes"))
 	if err != nil {
 		return err
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/stader-labs/stader-node/shared/services/stader"
 	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
 	"github.com/urfave/cli"
 )
 
@@ -47,6 +48,9 @@ func downloadSPMerkleProofs(c *cli.Context) error {
 	}
 
 	fmt.Printf("Successfully downloaded the merkle proofs for cycles: %v\n", res.DownloadedCycles)
+	if len(res.QuarantinedCycles) != 0 {
+		fmt.Printf("%sThe merkle proofs for cycles %v did not match the on-chain merkle roots and were quarantined. They will be downloaded again once the Stader backend serves valid proofs.%s\n", log.ColorYellow, res.QuarantinedCycles, log.ColorReset)
+	}
 
 	return nil
 }
@@ -0,0 +1,112 @@
+package node
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services/ledger"
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+)
+
+const ledgerDateLayout = "2006-01-02"
+
+func exportLedger(c *cli.Context) error {
+	// Parse the flags before the ledger scan so a typo doesn't cost a long wait
+	from := time.Unix(0, 0).UTC()
+	to := time.Now().UTC()
+	var err error
+	if c.String("from") != "" {
+		from, err = time.Parse(ledgerDateLayout, c.String("from"))
+		if err != nil {
+			return fmt.Errorf("invalid from date '%s', it should be in YYYY-MM-DD format", c.String("from"))
+		}
+	}
+	if c.String("to") != "" {
+		to, err = time.Parse(ledgerDateLayout, c.String("to"))
+		if err != nil {
+			return fmt.Errorf("invalid to date '%s', it should be in YYYY-MM-DD format", c.String("to"))
+		}
+		// Include the whole of the last day
+		to = to.Add(24*time.Hour - time.Second)
+	}
+	if to.Before(from) {
+		return fmt.Errorf("the from date must not be after the to date")
+	}
+
+	format := strings.ToLower(c.String("format"))
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("invalid format '%s', it should be csv or json", c.String("format"))
+	}
+	currency := c.String("currency")
+
+	var priceSource ledger.PriceSource
+	switch c.String("price-source") {
+	case "coingecko":
+		priceSource = ledger.NewCoinGeckoPriceSource()
+	case "none":
+		priceSource = ledger.NoPriceSource{}
+	default:
+		priceSource, err = ledger.NewFilePriceSource(c.String("price-source"))
+		if err != nil {
+			return err
+		}
+	}
+
+	output := c.String("output")
+	if output == "" {
+		output = fmt.Sprintf("stader_ledger.%s", format)
+	}
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Updating the ledger, the first run scans the chain from the Stader deployment and can take a while...")
+	response, err := staderClient.NodeLedger(uint64(from.Unix()), uint64(to.Unix()))
+	if err != nil {
+		return err
+	}
+	if !response.Registered {
+		fmt.Printf("The node is not registered with Stader. Please use the %sstader-cli node register%s to register with Stader", log.ColorGreen, log.ColorReset)
+		return nil
+	}
+
+	if len(response.Records) > 0 && c.String("price-source") != "none" {
+		fmt.Printf("Getting %s prices for %d record(s)...\n", strings.ToUpper(currency), len(response.Records))
+		if err := ledger.ApplyPrices(response.Records, priceSource, currency); err != nil {
+			return fmt.Errorf("error getting prices: %w", err)
+		}
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", output, err)
+	}
+	defer file.Close()
+	if format == "json" {
+		err = ledger.WriteJSON(file, response.Records)
+	} else {
+		err = ledger.WriteCSV(file, response.Records)
+	}
+	if err != nil {
+		return fmt.Errorf("could not write %s: %w", output, err)
+	}
+
+	fmt.Printf("Exported %d record(s) from %s to %s to %s.\n", len(response.Records), from.Format(ledgerDateLayout), to.Format(ledgerDateLayout), output)
+	fmt.Printf("The ledger covers every block up to %d; records in newer blocks will be picked up once they have enough confirmations.\n", response.ScannedToBlock)
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/offline"
+)
+
+// Sign transactions exported with --export-unsigned-tx; run this on the machine that holds the wallet
+func signTransactions(c *cli.Context) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Read the unsigned transactions
+	unsignedBytes, err := ioutil.ReadFile(c.String("file"))
+	if err != nil {
+		return fmt.Errorf("error reading unsigned transactions file: %w", err)
+	}
+	var unsignedTransactions offline.UnsignedTransactions
+	if err := json.Unmarshal(unsignedBytes, &unsignedTransactions); err != nil {
+		return fmt.Errorf("error decoding unsigned transactions file: %w", err)
+	}
+
+	// Show what is being signed
+	fmt.Printf("The following %d transaction(s) will be signed:\n", len(unsignedTransactions.Transactions))
+	for _, tx := range unsignedTransactions.Transactions {
+		fmt.Printf("\tnonce %d: to %s, value %s wei, gas %d, %d bytes of call data\n", tx.Nonce, tx.To.Hex(), tx.Value.ToInt().String(), tx.Gas, len(tx.Data))
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool("yes") || cliutils.Confirm("Are you sure you want to sign these transactions?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	response, err := staderClient.SignTransactions(string(unsignedBytes))
+	if err != nil {
+		return err
+	}
+
+	return cliutils.WriteSignedTransactions(c.String("out"), response.SignedTransactions)
+
+}
+
+// Broadcast transactions that were signed on the machine that holds the wallet, and wait for them
+func broadcastTransactions(c *cli.Context) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	// Read the signed transactions
+	signedBytes, err := ioutil.ReadFile(c.String("file"))
+	if err != nil {
+		return fmt.Errorf("error reading signed transactions file: %w", err)
+	}
+
+	response, err := staderClient.BroadcastTransactions(string(signedBytes))
+	if err != nil {
+		return err
+	}
+
+	for _, txHash := range response.TxHashes {
+		cliutils.PrintTransactionHash(staderClient, txHash)
+		if _, err = staderClient.WaitForTransaction(txHash); err != nil {
+			return err
+		}
+	}
+
+	// Log & return
+	fmt.Printf("%d transaction(s) were broadcast successfully.\n", len(response.TxHashes))
+	return nil
+
+}
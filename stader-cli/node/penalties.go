@@ -0,0 +1,83 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/shared/utils/math"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+	"github.com/urfave/cli"
+)
+
+func getPenalties(c *cli.Context) error {
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	response, err := staderClient.NodePenalties()
+	if err != nil {
+		return err
+	}
+
+	if !response.Registered {
+		fmt.Printf("The node is not registered with Stader. Please use the %sstader-cli node register%s to register with Stader", log.ColorGreen, log.ColorReset)
+		return nil
+	}
+
+	fmt.Printf("%s=== Penalty Rules ===%s\n", log.ColorGreen, log.ColorReset)
+	fmt.Printf("A validator is force exited by Stader once its total penalty reaches %.6f ETH.\n", math.RoundDown(eth.WeiToEth(response.ExitPenaltyThreshold), 6))
+	fmt.Printf("Each MEV theft strike costs %.6f ETH and each missed attestation strike costs %.6f ETH.\n\n", math.RoundDown(eth.WeiToEth(response.MevTheftPenaltyPerStrike), 6), math.RoundDown(eth.WeiToEth(response.MissedAttestationPenaltyPerStrike), 6))
+
+	fmt.Printf("%s=== Validator Penalties ===%s\n", log.ColorGreen, log.ColorReset)
+	if len(response.ValidatorPenalties) == 0 {
+		fmt.Printf("The node has no registered validators.\n\n")
+	}
+	atRisk := 0
+	for i, penalty := range response.ValidatorPenalties {
+		fmt.Printf("%d) %s\n", i+1, penalty.Pubkey)
+		fmt.Printf("-Total Penalty: %.6f ETH (%.2f%% of the exit threshold)\n", math.RoundDown(eth.WeiToEth(penalty.TotalPenalty), 6), penalty.ThresholdRatio()*100)
+		fmt.Printf("-MEV Theft Penalty: %.6f ETH\n", math.RoundDown(eth.WeiToEth(penalty.MevTheftPenalty), 6))
+		fmt.Printf("-Missed Attestation Penalty: %.6f ETH\n", math.RoundDown(eth.WeiToEth(penalty.MissedAttestationPenalty), 6))
+		fmt.Printf("-Additional Penalty: %.6f ETH\n", math.RoundDown(eth.WeiToEth(penalty.AdditionalPenalty), 6))
+		if pending := penalty.PendingPenalty(); pending.Sign() > 0 {
+			fmt.Printf("-Not Yet Recorded: %.6f ETH will be added to the total on the next penalty update\n", math.RoundDown(eth.WeiToEth(pending), 6))
+		}
+		if penalty.AtForceExitRisk() {
+			atRisk++
+			fmt.Printf("%sThis validator is close to being force exited!%s\n", log.ColorYellow, log.ColorReset)
+		}
+		fmt.Println()
+	}
+	if atRisk > 0 {
+		fmt.Printf("%s%d validator(s) have reached %.0f%% of the exit threshold. Check their MEV settings and uptime to avoid a force exit.%s\n\n", log.ColorYellow, atRisk, stdr.PenaltyWarningRatio*100, log.ColorReset)
+	}
+
+	fmt.Printf("%s=== Force Exit History ===%s\n", log.ColorGreen, log.ColorReset)
+	if len(response.ForceExits) == 0 {
+		fmt.Println("None of the node's validators have been force exited.")
+		return nil
+	}
+	for i, forceExit := range response.ForceExits {
+		fmt.Printf("%d) %s\n", i+1, forceExit.Pubkey)
+		fmt.Printf("-Force Exited: %s (block %d, transaction %s)\n", forceExit.Time.Format("2006-01-02 15:04:05"), forceExit.BlockNumber, forceExit.TxHash.Hex())
+		if forceExit.SettledBlockNumber > 0 {
+			fmt.Printf("-Settled: %s (block %d)\n", forceExit.SettledTime.Format("2006-01-02 15:04:05"), forceExit.SettledBlockNumber)
+		} else {
+			fmt.Println("-Settled: not yet")
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	"github.com/stader-labs/stader-node/shared/services/txmanager"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+func listTransactions(c *cli.Context) error {
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	response, err := staderClient.NodeTxList()
+	if err != nil {
+		return err
+	}
+	if len(response.Transactions) == 0 {
+		fmt.Println("The node hasn't sent any transactions yet.")
+		return nil
+	}
+
+	stuck := 0
+	for _, tx := range response.Transactions {
+		status := string(tx.Status)
+		if tx.Status == txmanager.TxStatus_Mined || tx.Status == txmanager.TxStatus_Failed {
+			status = fmt.Sprintf("%s in block %d", tx.Status, tx.BlockNumber)
+		}
+		fmt.Printf("%s (%s)\n", tx.Hash.Hex(), tx.Kind)
+		fmt.Printf("-Sent: %s, nonce %d\n", tx.SubmittedAt.Format("2006-01-02 15:04:05"), tx.Nonce)
+		if tx.To != nil {
+			fmt.Printf("-To: %s, value %.6f ETH\n", tx.To.Hex(), eth.WeiToEth(tx.Value))
+		}
+		fmt.Printf("-Fees: max fee %.2f gwei, priority fee %.2f gwei\n", eth.WeiToGwei(tx.MaxFee), eth.WeiToGwei(tx.PriorityFee))
+		if tx.Replaces != (common.Hash{}) {
+			fmt.Printf("-Replaces: %s\n", tx.Replaces.Hex())
+		}
+		fmt.Printf("-Status: %s\n", status)
+		if tx.Stuck {
+			stuck++
+			fmt.Printf("%sThis transaction looks stuck.%s\n", log.ColorYellow, log.ColorReset)
+		}
+		fmt.Println()
+	}
+
+	if stuck > 0 {
+		fmt.Printf("%d transaction(s) look stuck. Use %sstader-cli node tx speedup%s to resend one with higher fees, or %sstader-cli node tx cancel%s to cancel it.\n", stuck, log.ColorGreen, log.ColorReset, log.ColorGreen, log.ColorReset)
+	}
+
+	return nil
+}
+
+func speedupTransaction(c *cli.Context, hash common.Hash) error {
+	return replaceTransaction(c, hash, false)
+}
+
+func cancelTransaction(c *cli.Context, hash common.Hash) error {
+	return replaceTransaction(c, hash, true)
+}
+
+func replaceTransaction(c *cli.Context, hash common.Hash, cancel bool) error {
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	// Check the transaction can be replaced
+	canReplace, err := staderClient.CanNodeTxReplace(hash)
+	if err != nil {
+		return err
+	}
+	if canReplace.NotFound {
+		fmt.Printf("Transaction %s is not in the node's transaction journal.\n", hash.Hex())
+		return nil
+	}
+	if canReplace.NotPending {
+		fmt.Printf("Transaction %s is no longer pending (%s).\n", hash.Hex(), canReplace.Transaction.Status)
+		return nil
+	}
+	if canReplace.NotNodeAccount {
+		fmt.Printf("Transaction %s was not sent from the node account.\n", hash.Hex())
+		return nil
+	}
+	if canReplace.ExceedsFeeCap {
+		fmt.Printf("Replacing transaction %s at %.2f gwei would exceed the transaction fee cap set in the config.\n", hash.Hex(), eth.WeiToGwei(canReplace.MaxFee))
+		return nil
+	}
+
+	action := "speed up"
+	if cancel {
+		action = "cancel"
+	}
+	fmt.Printf("The replacement will use nonce %d with a max fee of %.2f gwei and a priority fee of %.2f gwei (the original used %.2f and %.2f gwei).\n",
+		canReplace.Transaction.Nonce, eth.WeiToGwei(canReplace.MaxFee), eth.WeiToGwei(canReplace.PriorityFee), eth.WeiToGwei(canReplace.Transaction.MaxFee), eth.WeiToGwei(canReplace.Transaction.PriorityFee))
+	if cancel {
+		fmt.Println("It sends 0 ETH to the node account, so the original transaction will never be executed.")
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf("Are you sure you want to %s transaction %s?", action, hash.Hex()))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	var response api.ReplaceTransactionResponse
+	if cancel {
+		response, err = staderClient.NodeTxCancel(hash)
+	} else {
+		response, err = staderClient.NodeTxSpeedup(hash)
+	}
+	if err != nil {
+		return err
+	}
+	txHash := response.TxHash
+
+	cliutils.PrintTransactionHash(staderClient, txHash)
+	if _, err = staderClient.WaitForTransaction(txHash); err != nil {
+		return err
+	}
+
+	fmt.Printf("Transaction %s has been replaced.\n", hash.Hex())
+	return nil
+}
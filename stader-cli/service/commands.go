@@ -228,7 +228,7 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				Flags: []cli.Flag{
 					cli.BoolFlag{
 						Name:  "ignore-slash-timer",
-						Usage: "Bypass the safety timer that forces a delay when switching to a new ETH2 client",
+						Usage: "Bypass the safety timer that forces a delay when switching to a new ETH2 client (the slashing protection history is still carried over)",
 					},
 					cli.BoolFlag{
 						Name:  "yes, y",
@@ -513,6 +513,54 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:      "export-slashing-protection",
+				Usage:     "Exports the validator client's slashing protection history to an EIP-3076 interchange file in an external folder. Use this to back it up or to move it to another machine.",
+				UsageText: "stader-cli service export-slashing-protection target-folder",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					targetDir := c.Args().Get(0)
+
+					// Run command
+					return exportSlashingProtection(c, targetDir)
+
+				},
+			},
+
+			{
+				Name:      "import-slashing-protection",
+				Usage:     "Imports an EIP-3076 slashing protection interchange file into the configured validator client. Use this before starting the validator on a restored or new node.",
+				UsageText: "stader-cli service import-slashing-protection source-file",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					sourceFile := c.Args().Get(0)
+
+					// Run command
+					return importSlashingProtection(c, sourceFile)
+
+				},
+			},
+
 			{
 				Name:      "resync-eth1",
 				Usage:     fmt.Sprintf("%sDeletes the main ETH1 client's chain data and resyncs it from scratch. Only use this as a last resort!%s", colorRed, colorReset),
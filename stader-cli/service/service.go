@@ -235,6 +235,7 @@ func UpdateConfig(_cfg *config.StaderConfig, newSettings *pages.SettingsType) (c
 		cfg.ExternalLighthouse.HttpUrl.Value = newSettings.ConsensusClient.External.Lighthouse.HTTPUrl
 		cfg.ExternalTeku.Graffiti.Value = newSettings.ConsensusClient.Graffit
 		cfg.ExternalTeku.HttpUrl.Value = newSettings.ConsensusClient.External.Teku.HTTPUrl
+		cfg.ExternalTeku.DoppelgangerDetection.Value = ConvertStringToBool(newSettings.ConsensusClient.DoppelgangerProtection)
 		cfg.ExternalNimbus.Graffiti.Value = newSettings.ConsensusClient.Graffit
 		cfg.ExternalNimbus.HttpUrl.Value = newSettings.ConsensusClient.External.Nimbus.HTTPUrl
 		cfg.ExternalNimbus.DoppelgangerDetection.Value = ConvertStringToBool(newSettings.ConsensusClient.DoppelgangerProtection)
@@ -677,23 +678,27 @@ func startService(
 		return nil
 	}
 
-	if !c.Bool("ignore-slash-timer") {
-		// Do the client swap check
-		err := checkForValidatorChange(staderClient, cfg)
-		if err != nil {
-			fmt.Printf("%sWarning: couldn't verify that the validator container can be safely restarted:\n\t%s\n", colorYellow, err.Error())
-			fmt.Println("If you are changing to a different ETH2 client, it may resubmit an attestation you have already submitted.")
-			fmt.Println("This will slash your validator!")
-			fmt.Println("To prevent slashing, you must wait 15 minutes from the time you stopped the clients before starting them again.\n")
-			fmt.Println("**If you did NOT change clients, you can safely ignore this warning.**\n")
-			if !cliutils.Confirm(fmt.Sprintf("Press y when you understand the above warning, have waited, and are ready to start Stader:%s", colorReset)) {
-				fmt.Println("Cancelled.")
-				return nil
-			}
-		}
-	} else {
+	// Do the client swap check; the slashing protection history is carried over even if the timer is bypassed
+	ignoreSlashTimer := c.Bool("ignore-slash-timer")
+	if ignoreSlashTimer {
 		fmt.Printf("%sIgnoring anti-slashing safety delay.%s\n", colorYellow, colorReset)
 	}
+	err = checkForValidatorChange(staderClient, cfg, ignoreSlashTimer)
+	if err != nil && ignoreSlashTimer {
+		fmt.Printf("%sWarning: couldn't verify that the validator container can be safely restarted:\n\t%s\n", colorYellow, err.Error())
+		fmt.Printf("If you changed clients, carry the old client's slashing protection history over with `stader-cli service export-slashing-protection` and `stader-cli service import-slashing-protection`.%s\n\n", colorReset)
+	} else if err != nil {
+		fmt.Printf("%sWarning: couldn't verify that the validator container can be safely restarted:\n\t%s\n", colorYellow, err.Error())
+		fmt.Println("If you are changing to a different ETH2 client, it may resubmit an attestation you have already submitted.")
+		fmt.Println("This will slash your validator!")
+		fmt.Println("To prevent slashing, you must wait 15 minutes from the time you stopped the clients before starting them again.\n")
+		fmt.Printf("If you did change clients, make sure the old client's slashing protection history was carried over with `stader-cli service export-slashing-protection` and `stader-cli service import-slashing-protection`.\n\n")
+		fmt.Println("**If you did NOT change clients, you can safely ignore this warning.**\n")
+		if !cliutils.Confirm(fmt.Sprintf("Press y when you understand the above warning, have waited, and are ready to start Stader:%s", colorReset)) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
 
 	// Write a note on doppelganger protection
 	doppelgangerEnabled, err := cfg.IsDoppelgangerEnabled()
@@ -716,7 +721,7 @@ func startService(
 
 }
 
-func checkForValidatorChange(stader *stader.Client, cfg *config.StaderConfig) error {
+func checkForValidatorChange(stader *stader.Client, cfg *config.StaderConfig, ignoreSlashTimer bool) error {
 
 	// Get the container prefix
 	prefix, err := getContainerPrefix(stader)
@@ -780,6 +785,15 @@ func checkForValidatorChange(stader *stader.Client, cfg *config.StaderConfig) er
 			}
 		}
 
+		// Carry the old client's slashing protection history over before the new one can sign anything
+		err = transferSlashingProtection(stader, cfg, currentValidatorImageString)
+		if err != nil {
+			return fmt.Errorf("Error transferring slashing protection history: %w", err)
+		}
+		if ignoreSlashTimer {
+			return nil
+		}
+
 		// Print the warning and start the time lockout
 		safeStartTime := validatorFinishTime.Add(15 * time.Minute)
 		remainingTime := time.Until(safeStartTime)
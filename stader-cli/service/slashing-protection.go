@@ -0,0 +1,355 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cfgtypes "github.com/stader-labs/stader-node/shared/types/config"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+)
+
+const (
+	SlashingProtectionContainerSuffix string = "_slashing_protection"
+	slashingProtectionFolder          string = "slashing-protection"
+)
+
+// Export the current validator client's slashing protection history to a folder
+func exportSlashingProtection(c *cli.Context, targetDir string) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	cfg, isNew, err := staderClient.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if isNew {
+		return fmt.Errorf("Settings file not found. Please run `stader-cli service config` to set up your Stadernode.")
+	}
+
+	targetDir, err = filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("Error converting to absolute path: %w", err)
+	}
+	targetDirInfo, err := os.Stat(targetDir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("Target directory [%s] does not exist.", targetDir)
+	} else if err != nil {
+		return fmt.Errorf("Error reading target dir: %w", err)
+	}
+	if !targetDirInfo.IsDir() {
+		return fmt.Errorf("Target directory [%s] is not a directory.", targetDir)
+	}
+	targetFile := filepath.Join(targetDir, validator.SlashingProtectionFileName)
+	if _, err := os.Stat(targetFile); err == nil {
+		return fmt.Errorf("[%s] already exists. Please move it out of the way or choose a different folder.", targetFile)
+	}
+
+	prefix := cfg.StaderNode.ProjectName.Value.(string)
+	validatorContainerName := prefix + ValidatorContainerSuffix
+
+	// Export from the client that actually ran the validator, which isn't necessarily the configured one
+	validatorImage, err := staderClient.GetDockerImage(validatorContainerName)
+	if err != nil {
+		return fmt.Errorf("Error getting the validator image: %w", err)
+	}
+	validatorClient, err := getConsensusClientFromImage(validatorImage)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("This will export the slashing protection history of your %s validator client to %s.\n", validatorClient, targetFile)
+	fmt.Printf("If your validator client is running, it will be stopped during the export and restarted afterwards.\n\n")
+	if !(c.Bool("yes") || cliutils.Confirm("Are you sure you want to export your slashing protection history?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	err = runWithValidatorStopped(staderClient, validatorContainerName, func() error {
+		container, err := getSlashingProtectionContainer(cfg, validatorClient, getSlashingProtectionImage(cfg, validatorClient, validatorImage))
+		if err != nil {
+			return err
+		}
+		return staderClient.ExportSlashingProtection(container, targetDir)
+	})
+	if err != nil {
+		return fmt.Errorf("Error exporting slashing protection history: %w", err)
+	}
+
+	if _, err := readSlashingProtectionInterchange(targetFile, cfg.StaderNode.Network.Value.(cfgtypes.Network)); err != nil {
+		return fmt.Errorf("%sThe exported slashing protection history is invalid: %w%s", colorRed, err, colorReset)
+	}
+	fmt.Printf("%sExported the slashing protection history to %s.%s\n", colorGreen, targetFile, colorReset)
+	return nil
+
+}
+
+// Import slashing protection history into the configured validator client, e.g. after restoring the node from scratch
+func importSlashingProtection(c *cli.Context, sourceFile string) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	cfg, isNew, err := staderClient.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if isNew {
+		return fmt.Errorf("Settings file not found. Please run `stader-cli service config` to set up your Stadernode.")
+	}
+
+	sourceFile, err = filepath.Abs(sourceFile)
+	if err != nil {
+		return fmt.Errorf("Error converting to absolute path: %w", err)
+	}
+	interchange, err := readSlashingProtectionInterchange(sourceFile, cfg.StaderNode.Network.Value.(cfgtypes.Network))
+	if err != nil {
+		return err
+	}
+	if len(interchange.Data) == 0 {
+		fmt.Println("The file doesn't contain any validators, so there is nothing to import.")
+		return nil
+	}
+
+	validatorClient, validatorImage, err := getSelectedValidatorClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("This will import the slashing protection history into your %s validator client.\n", validatorClient)
+	fmt.Printf("If your validator client is running, it will be stopped during the import and restarted afterwards.\n\n")
+	if !(c.Bool("yes") || cliutils.Confirm("Are you sure you want to import this slashing protection history?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// The validator container won't exist yet on a freshly restored node, which is exactly when the history matters most
+	validatorContainerName := cfg.StaderNode.ProjectName.Value.(string) + ValidatorContainerSuffix
+	status, err := staderClient.GetDockerStatus(validatorContainerName)
+	if err != nil {
+		status = ""
+	}
+	importHistory := func() error {
+		container, err := getSlashingProtectionContainer(cfg, validatorClient, getSlashingProtectionImage(cfg, validatorClient, validatorImage))
+		if err != nil {
+			return err
+		}
+		return staderClient.ImportSlashingProtection(container, sourceFile)
+	}
+	if status == "" {
+		err = importHistory()
+	} else {
+		err = runWithValidatorStopped(staderClient, validatorContainerName, importHistory)
+	}
+	if err != nil {
+		return fmt.Errorf("Error importing slashing protection history: %w", err)
+	}
+
+	fmt.Printf("%sImported the slashing protection history.%s\n", colorGreen, colorReset)
+	return nil
+
+}
+
+// Carry the slashing protection history of the outgoing validator client over to the incoming one.
+// The outgoing validator must already be stopped. The exported history is kept in the data folder.
+func transferSlashingProtection(staderClient *stader.Client, cfg *config.StaderConfig, currentValidatorImage string) error {
+
+	fromClient, err := getConsensusClientFromImage(currentValidatorImage)
+	if err != nil {
+		return err
+	}
+	toClient, toImage, err := getSelectedValidatorClient(cfg)
+	if err != nil {
+		return err
+	}
+	network := cfg.StaderNode.Network.Value.(cfgtypes.Network)
+
+	dataPath, err := homedir.Expand(cfg.StaderNode.DataPath.Value.(string))
+	if err != nil {
+		return fmt.Errorf("Error expanding data path: %w", err)
+	}
+	backupDir := filepath.Join(dataPath, slashingProtectionFolder, fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), fromClient))
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return fmt.Errorf("Error creating slashing protection folder [%s]: %w", backupDir, err)
+	}
+
+	fmt.Printf("Exporting the slashing protection history from %s...\n", fromClient)
+	fromContainer, err := getSlashingProtectionContainer(cfg, fromClient, getSlashingProtectionImage(cfg, fromClient, currentValidatorImage))
+	if err != nil {
+		return err
+	}
+	if err := staderClient.ExportSlashingProtection(fromContainer, backupDir); err != nil {
+		return fmt.Errorf("Error exporting slashing protection history from %s: %w", fromClient, err)
+	}
+
+	interchangeFile := filepath.Join(backupDir, validator.SlashingProtectionFileName)
+	interchange, err := readSlashingProtectionInterchange(interchangeFile, network)
+	if err != nil {
+		return fmt.Errorf("The slashing protection history exported from %s is invalid: %w", fromClient, err)
+	}
+	if len(interchange.Data) == 0 {
+		fmt.Printf("%s has no slashing protection history, so there is nothing to import.\n", fromClient)
+		return nil
+	}
+
+	fmt.Printf("Importing the slashing protection history into %s...\n", toClient)
+	toContainer, err := getSlashingProtectionContainer(cfg, toClient, getSlashingProtectionImage(cfg, toClient, toImage))
+	if err != nil {
+		return err
+	}
+	if err := staderClient.ImportSlashingProtection(toContainer, interchangeFile); err != nil {
+		return fmt.Errorf("Error importing slashing protection history into %s: %w", toClient, err)
+	}
+
+	fmt.Printf("%sCarried the slashing protection history over from %s to %s. A copy is kept in %s.%s\n", colorGreen, fromClient, toClient, interchangeFile, colorReset)
+	return nil
+
+}
+
+// Read and check an interchange file, printing a summary of its contents
+func readSlashingProtectionInterchange(path string, network cfgtypes.Network) (*validator.SlashingProtectionInterchange, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading [%s]: %w", path, err)
+	}
+	interchange, err := validator.ParseSlashingProtectionInterchange(bytes, network)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, attestations := interchange.CountSignatures()
+	fmt.Printf("%sSlashing protection history: %d validator(s), %d signed block(s), %d signed attestation(s).%s\n", colorLightBlue, len(interchange.Data), blocks, attestations, colorReset)
+	return interchange, nil
+}
+
+// Stop the validator container if it's running, run the operation and start it back up again
+func runWithValidatorStopped(staderClient *stader.Client, validatorContainerName string, operation func() error) error {
+	status, err := staderClient.GetDockerStatus(validatorContainerName)
+	if err != nil {
+		return fmt.Errorf("Error getting container [%s] status: %w", validatorContainerName, err)
+	}
+
+	if status == "running" {
+		fmt.Printf("Stopping %s...\n", validatorContainerName)
+		result, err := staderClient.StopContainer(validatorContainerName)
+		if err != nil {
+			return fmt.Errorf("Error stopping container [%s]: %w", validatorContainerName, err)
+		}
+		if result != validatorContainerName {
+			return fmt.Errorf("Unexpected output while stopping container [%s]: %s", validatorContainerName, result)
+		}
+	}
+
+	operationErr := operation()
+
+	if status == "running" {
+		fmt.Printf("Starting %s...\n", validatorContainerName)
+		result, err := staderClient.StartContainer(validatorContainerName)
+		if err != nil {
+			return fmt.Errorf("Error starting container [%s]: %w", validatorContainerName, err)
+		}
+		if result != validatorContainerName {
+			return fmt.Errorf("Unexpected output while starting container [%s]: %s", validatorContainerName, result)
+		}
+	}
+
+	return operationErr
+}
+
+// Get the validator client that will run once the service is started, and its image
+func getSelectedValidatorClient(cfg *config.StaderConfig) (cfgtypes.ConsensusClient, string, error) {
+	selectedConsensusClientConfig, err := cfg.GetSelectedConsensusClientConfig()
+	if err != nil {
+		return cfgtypes.ConsensusClient_Unknown, "", fmt.Errorf("Error getting selected consensus client config: %w", err)
+	}
+	image := selectedConsensusClientConfig.GetValidatorImage()
+	client, err := getConsensusClientFromImage(image)
+	if err != nil {
+		return cfgtypes.ConsensusClient_Unknown, "", err
+	}
+	return client, image, nil
+}
+
+// Work out which client a validator image such as sigp/lighthouse or statusim/nimbus-validator-client belongs to
+func getConsensusClientFromImage(image string) (cfgtypes.ConsensusClient, error) {
+	imageName, err := getDockerImageName(image)
+	if err != nil {
+		return cfgtypes.ConsensusClient_Unknown, err
+	}
+	for _, client := range []cfgtypes.ConsensusClient{
+		cfgtypes.ConsensusClient_Lighthouse,
+		cfgtypes.ConsensusClient_Nimbus,
+		cfgtypes.ConsensusClient_Prysm,
+		cfgtypes.ConsensusClient_Teku,
+		cfgtypes.ConsensusClient_Lodestar,
+	} {
+		if strings.Contains(imageName, string(client)) {
+			return client, nil
+		}
+	}
+	return cfgtypes.ConsensusClient_Unknown, fmt.Errorf("Couldn't determine the validator client of image [%s]", image)
+}
+
+// Nimbus only ships its slashing protection tooling with the beacon node, every other client's validator image has it
+func getSlashingProtectionImage(cfg *config.StaderConfig, client cfgtypes.ConsensusClient, validatorImage string) string {
+	if client == cfgtypes.ConsensusClient_Nimbus {
+		return cfg.Nimbus.BnContainerTag.Value.(string)
+	}
+	return validatorImage
+}
+
+func getSlashingProtectionContainer(cfg *config.StaderConfig, client cfgtypes.ConsensusClient, image string) (stader.SlashingProtectionContainer, error) {
+	dataPath, err := homedir.Expand(cfg.StaderNode.DataPath.Value.(string))
+	if err != nil {
+		return stader.SlashingProtectionContainer{}, fmt.Errorf("Error expanding data path: %w", err)
+	}
+
+	prefix := cfg.StaderNode.ProjectName.Value.(string)
+	container := stader.SlashingProtectionContainer{
+		Name:          prefix + SlashingProtectionContainerSuffix,
+		Client:        client,
+		Image:         image,
+		ValidatorsDir: filepath.Join(dataPath, "validators"),
+		Network:       cfg.StaderNode.Network.Value.(cfgtypes.Network),
+	}
+	if client == cfgtypes.ConsensusClient_Lodestar {
+		container.DockerNetwork = prefix + "_net"
+		container.BeaconNodeUrl = cfg.GenerateEnvironmentVariables()["CC_API_ENDPOINT"]
+	}
+	return container, nil
+}
@@ -760,6 +760,14 @@ Public License instead of this License. But first, please read
 			Name:  "nonce",
 			Usage: "Use this flag to explicitly specify the nonce that this transaction should use, so it can override an existing 'stuck' transaction",
 		},
+		cli.StringFlag{
+			Name:  "node-address",
+			Usage: "Use this node `address` instead of the node wallet, for a node whose wallet is kept on an offline machine",
+		},
+		cli.StringFlag{
+			Name:  "export-unsigned-tx",
+			Usage: "Write the transactions a command would send to this `file` instead of signing and sending them, so they can be signed offline",
+		},
 		cli.BoolFlag{
 			Name:  "debug",
 			Usage: "Enable debug printing of API commands",
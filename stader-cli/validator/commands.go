@@ -66,15 +66,117 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 
 				},
 			},
+			{
+				Name:      "import-keys",
+				Aliases:   []string{"i"},
+				Usage:     "Import validator keystores generated elsewhere (e.g. with staking-deposit-cli) and deposit them",
+				UsageText: "stader-cli validator import-keys --keystores path [options]",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "keystores, k",
+						Usage: "Path to an EIP-2335 keystore file, or to a directory of keystore*.json files (Required)",
+					},
+					cli.StringFlag{
+						Name:  "password-file",
+						Usage: "Path to a file holding the keystore password; you will be prompted for it if this isn't set",
+					},
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm deposit",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate flags
+					if c.String("keystores") == "" {
+						return fmt.Errorf("keystores needs to be set")
+					}
+
+					// Run
+					return importValidatorKeys(c)
+
+				},
+			},
+			{
+				Name:      "export-deposit",
+				Usage:     "Write a deposit request to be signed on the machine that holds the node wallet",
+				UsageText: "stader-cli --node-address address validator export-deposit --num-validators count --file request.json",
+				Flags: []cli.Flag{
+					cli.Uint64Flag{
+						Name:  "num-validators, nv",
+						Usage: "Number of validators you want to create (Required)",
+					},
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "Path to write the deposit request to (Required)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate flags
+					if c.Uint64("num-validators") == 0 {
+						return fmt.Errorf("num-validator needs to be > 0")
+					}
+					if c.String("file") == "" {
+						return fmt.Errorf("file needs to be set")
+					}
+
+					// Run
+					return exportDepositRequest(c)
+
+				},
+			},
+			{
+				Name:      "sign-deposit",
+				Usage:     "Create the validator keys for a deposit request and sign the deposit, on the machine that holds the node wallet",
+				UsageText: "stader-cli validator sign-deposit --file request.json --out signed.json",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "Path to the deposit request (Required)",
+					},
+					cli.StringFlag{
+						Name:  "out, o",
+						Usage: "Path to write the signed deposit transaction to (Required)",
+					},
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm signing the deposit",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate flags
+					if c.String("file") == "" || c.String("out") == "" {
+						return fmt.Errorf("file and out need to be set")
+					}
+
+					// Run
+					return signDepositRequest(c)
+
+				},
+			},
 			{
 				Name:      "exit-validator",
 				Aliases:   []string{"e"},
 				Usage:     "Exit validator",
-				UsageText: "stader-cli validator exit-validator --validator-pub-key",
+				UsageText: "stader-cli validator exit-validator [--validator-pub-key | --file | --all-active | --operator-share-above]",
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:  "validator-pub-key, vpk",
-						Usage: "Public key of validator we want to exit",
+						Usage: "Public key of validator we want to exit, or a comma-separated list of public keys",
+					},
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "Path to a file with one validator public key per line",
+					},
+					cli.BoolFlag{
+						Name:  "all-active",
+						Usage: "Select all of the node's active validators",
+					},
+					cli.Float64Flag{
+						Name:  "operator-share-above",
+						Usage: "Select the node's validators whose withdraw vault holds more than this operator share (in ETH)",
 					},
 					cli.BoolFlag{
 						Name:  "yes, y",
@@ -83,6 +185,11 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 				Action: func(c *cli.Context) error {
 
+					if isBatchSelection(c) {
+						// Run
+						return ExitValidators(c)
+					}
+
 					//// Validate args
 					validatorPubKey, err := cliutils.ValidatePubkey("validator-pub-key", c.String("validator-pub-key"))
 					if err != nil {
@@ -97,11 +204,23 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				Name:      "send-cl-rewards",
 				Aliases:   []string{"wcr"},
 				Usage:     "Send all Consensus Layer rewards to the operator claim vault",
-				UsageText: "stader-cli validator send-cl-rewards --validator-pub-key",
+				UsageText: "stader-cli validator send-cl-rewards [--validator-pub-key | --file | --all-active | --operator-share-above]",
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:  "validator-pub-key, vpk",
-						Usage: "Public key of the validator whose CL rewards we want to send to operator claim vault",
+						Usage: "Public key of the validator whose CL rewards we want to send to operator claim vault, or a comma-separated list of public keys",
+					},
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "Path to a file with one validator public key per line",
+					},
+					cli.BoolFlag{
+						Name:  "all-active",
+						Usage: "Select all of the node's active validators",
+					},
+					cli.Float64Flag{
+						Name:  "operator-share-above",
+						Usage: "Select the node's validators whose withdraw vault holds more than this operator share (in ETH)",
 					},
 					cli.BoolFlag{
 						Name:  "yes, y",
@@ -110,6 +229,11 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 				Action: func(c *cli.Context) error {
 
+					if isBatchSelection(c) {
+						// Run
+						return SendClRewardsBatch(c)
+					}
+
 					validatorPubKey, err := cliutils.ValidatePubkey("validator-pub-key", c.String("validator-pub-key"))
 					if err != nil {
 						return err
@@ -118,6 +242,27 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 					return SendClRewards(c, validatorPubKey)
 				},
 			},
+			{
+				Name:      "settle-exit-funds",
+				Aliases:   []string{"sef"},
+				Usage:     "Check the settlement of a fully withdrawn validator's withdraw vault, which the Stader node registry settles once the oracle reports the validator as withdrawn",
+				UsageText: "stader-cli validator settle-exit-funds --validator-pub-key",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "validator-pub-key, vpk",
+						Usage: "Public key of the withdrawn validator whose exit funds we want to check",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					validatorPubKey, err := cliutils.ValidatePubkey("validator-pub-key", c.String("validator-pub-key"))
+					if err != nil {
+						return err
+					}
+					// Run
+					return SettleExitFunds(c, validatorPubKey)
+				},
+			},
 			{
 				Name:      "status",
 				Aliases:   []string{"s"},
@@ -130,6 +275,49 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 					return getValidatorStatus(c)
 				},
 			},
+			{
+				Name:      "presign-status",
+				Usage:     "Show the presigned exit messages sent to Stader for each validator",
+				UsageText: "stader-cli validator presign-status",
+				Flags:     []cli.Flag{},
+				Action: func(c *cli.Context) error {
+
+					// Run
+					return getPresignStatus(c)
+				},
+			},
+			{
+				Name:      "doppelganger-status",
+				Usage:     "Show the validator keys held back from the validator client while they are watched for doppelgangers",
+				UsageText: "stader-cli validator doppelganger-status",
+				Flags:     []cli.Flag{},
+				Action: func(c *cli.Context) error {
+
+					// Run
+					return getDoppelgangerStatus(c)
+				},
+			},
+			{
+				Name:      "doppelganger-retry",
+				Usage:     "Watch the validator keys a doppelganger was detected for again, after the other machine running them has been shut down",
+				UsageText: "stader-cli validator doppelganger-retry [--yes]",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm watching the keys again",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return retryDoppelgangerWatch(c)
+				},
+			},
 			{
 				Name:      "export",
 				Aliases:   []string{"e"},
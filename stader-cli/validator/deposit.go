@@ -114,6 +114,9 @@ func nodeDeposit(c *cli.Context) error {
 	fmt.Println("Your validators are now in Initialized status.")
 	fmt.Println("Once the ETH deposits have been matched by the remaining 28ETH, it will move to Deposited status.")
 	fmt.Println("You can check the status of your validator with `stader-cli validator status`.")
+	if response.DoppelgangerWatch {
+		fmt.Printf("%sSome of the new keys are already active on the Beacon chain, so they are held back from the validator client while the node watches them for activity from another machine. You can follow it with `stader-cli validator doppelganger-status`.%s\n", log.ColorYellow, log.ColorReset)
+	}
 
 	return nil
 
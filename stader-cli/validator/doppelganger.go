@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services/doppelganger"
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+)
+
+func getDoppelgangerStatus(c *cli.Context) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	status, err := staderClient.GetDoppelgangerStatus()
+	if err != nil {
+		return err
+	}
+
+	if status.WatchEpochs == 0 {
+		fmt.Printf("%sThe doppelganger watch is disabled, newly added validator keys are loaded right away.%s\n\n", log.ColorYellow, log.ColorReset)
+	} else {
+		fmt.Printf("Validator keys that are already active on the Beacon chain are watched for %d epochs before the validator client loads them.\n\n", status.WatchEpochs)
+	}
+	if len(status.Keys) == 0 {
+		fmt.Println("No validator keys are being held back from the validator client.")
+		return nil
+	}
+
+	detected := 0
+	fmt.Printf("%s=== Held Back Validator Keys ===%s\n", log.ColorGreen, log.ColorReset)
+	for i, key := range status.Keys {
+		fmt.Printf("%d) %s (validator %d, added by %s at %s)\n", i+1, key.Pubkey.Hex(), key.Index, key.Reason, key.QueuedAt.Format(time.RFC822))
+		switch {
+		case key.Status == doppelganger.KeyStatus_Detected:
+			detected++
+			fmt.Printf("-Status: %sDOPPELGANGER DETECTED%s in epoch %d: %s\n", log.ColorRed, log.ColorReset, key.DetectedEpoch, key.Activity)
+		case key.NextEpoch > key.EndEpoch:
+			fmt.Printf("-Status: %sclear%s, the validator client will load it on the next check\n", log.ColorGreen, log.ColorReset)
+		default:
+			fmt.Printf("-Status: watching epochs %d to %d, next to check is %d\n", key.StartEpoch, key.EndEpoch, key.NextEpoch)
+		}
+		fmt.Println()
+	}
+
+	if detected > 0 {
+		fmt.Printf("%s%d validator key(s) are running on another machine, so they are kept out of the validator client. Running them here as well will get them slashed.\n", log.ColorRed, detected)
+		fmt.Printf("Shut the other machine down, wait for at least one epoch, then run `stader-cli validator doppelganger-retry` to watch them again.%s\n", log.ColorReset)
+	}
+	return nil
+
+}
+
+func retryDoppelgangerWatch(c *cli.Context) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf(
+		"The keys a doppelganger was seen for will be watched again, and the validator client will load them if they stay quiet.\n"+
+			"%sOnly do this once the other machine running them is shut down for good, or you WILL be slashed. Are you sure?%s",
+		log.ColorYellow,
+		log.ColorReset))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	response, err := staderClient.RetryDoppelgangerWatch()
+	if err != nil {
+		return err
+	}
+	if len(response.Pubkeys) == 0 {
+		fmt.Println("No doppelgangers were detected, there is nothing to watch again.")
+		return nil
+	}
+
+	fmt.Printf("Watching %d validator key(s) again until epoch %d:\n", len(response.Pubkeys), response.EndEpoch)
+	for _, pubkey := range response.Pubkeys {
+		fmt.Println(pubkey.Hex())
+	}
+	return nil
+
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"github.com/stader-labs/stader-node/shared/services/stader"
+	"github.com/stader-labs/stader-node/shared/types/api"
 	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
 	"github.com/stader-labs/stader-node/stader-lib/types"
 	"github.com/urfave/cli"
@@ -60,3 +61,95 @@ func ExitValidator(c *cli.Context, validatorPubKey types.ValidatorPubkey) error
 
 	return nil
 }
+
+func ExitValidators(c *cli.Context) error {
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	validatorPubKeys, err := getSelectedValidators(c, staderClient)
+	if err != nil {
+		return err
+	}
+	if len(validatorPubKeys) == 0 {
+		fmt.Println("No validators matched the selection.")
+		return nil
+	}
+
+	// check canExit for every validator
+	response, err := staderClient.CanExitValidators(validatorPubKeys)
+	if err != nil {
+		return err
+	}
+
+	eligible := []types.ValidatorPubkey{}
+	fmt.Printf("Checked %d validators:\n", len(response.Validators))
+	for _, result := range response.Validators {
+		reason := result.Error
+		if reason == "" {
+			reason = getExitIneligibleReason(result.CanExit)
+		}
+		if reason != "" {
+			fmt.Printf("  %s: skipped (%s)\n", result.Pubkey, reason)
+			continue
+		}
+		fmt.Printf("  %s: ready to exit\n", result.Pubkey)
+		eligible = append(eligible, result.Pubkey)
+	}
+	fmt.Println()
+
+	if len(eligible) == 0 {
+		fmt.Println("None of the selected validators can be exited.")
+		return nil
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf(
+		"Are you sure you want to exit %d validators?", len(eligible)))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// now exit
+	exitResponse, err := staderClient.ExitValidators(eligible)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range exitResponse.Results {
+		if !result.Success {
+			failed++
+			fmt.Printf("Failed to exit validator %s: %s\n", result.Pubkey, result.Error)
+			continue
+		}
+		fmt.Printf("Exiting validator %s, you check check the validator status at %s\n", result.Pubkey, fmt.Sprintf("%s/validator/%s#withdrawals", exitResponse.BeaconChainUrl, result.Pubkey))
+	}
+
+	fmt.Printf("\n%d of %d validators exiting, %d failed, %d skipped.\n", len(eligible)-failed, len(validatorPubKeys), failed, len(validatorPubKeys)-len(eligible))
+
+	return nil
+}
+
+// Get a short reason why a validator can't be exited, or an empty string if it can
+func getExitIneligibleReason(response api.CanExitValidatorResponse) string {
+	switch {
+	case response.ValidatorNotRegistered:
+		return "validator not registered"
+	case response.ValidatorTooYoung:
+		return "validator too young"
+	case response.ValidatorExiting:
+		return "validator already exiting"
+	case response.ValidatorNotActive:
+		return "validator not active"
+	}
+	return ""
+}
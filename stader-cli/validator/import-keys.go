@@ -0,0 +1,168 @@
+package validator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services/gas"
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+func importValidatorKeys(c *cli.Context) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	keystores, err := readKeystores(c.String("keystores"))
+	if err != nil {
+		return err
+	}
+
+	// Get the keystore password
+	password := ""
+	if c.String("password-file") != "" {
+		passwordBytes, err := ioutil.ReadFile(c.String("password-file"))
+		if err != nil {
+			return fmt.Errorf("error reading keystore password file: %w", err)
+		}
+		password = strings.TrimRight(string(passwordBytes), "\r\n")
+	} else {
+		password = cliutils.PromptPassword("Please enter the password for the keystores:", "^.*$", "")
+	}
+
+	baseAmountInEth := 4
+	baseAmount := eth.EthToWei(4.0)
+
+	canImportResponse, err := staderClient.CanImportValidatorKeys(baseAmount, password, keystores)
+	if err != nil {
+		return err
+	}
+	if len(canImportResponse.ExistingOnBeaconChain) > 0 || len(canImportResponse.RegisteredWithStader) > 0 {
+		fmt.Println("The following keys are already in use and can't be imported:")
+		for _, pubkey := range canImportResponse.ExistingOnBeaconChain {
+			fmt.Printf("\t%s: already exists on the Beacon chain\n", pubkey.Hex())
+		}
+		for _, pubkey := range canImportResponse.RegisteredWithStader {
+			fmt.Printf("\t%s: already registered with Stader\n", pubkey.Hex())
+		}
+		return nil
+	}
+	if canImportResponse.InsufficientBalance {
+		fmt.Printf("Account does not have enough balance!")
+		return nil
+	}
+	if canImportResponse.DepositPaused {
+		fmt.Printf("Deposit is paused")
+		return nil
+	}
+	if canImportResponse.NotEnoughSdCollateral {
+		fmt.Printf("Not enough SD as collateral")
+		return nil
+	}
+	if canImportResponse.MaxValidatorLimitReached {
+		fmt.Printf("Max validator limit reached")
+		return nil
+	}
+	if canImportResponse.InputKeyLimitReached {
+		fmt.Printf("You can only add %d keys at a time\n", canImportResponse.InputKeyLimit)
+		return nil
+	}
+
+	numValidators := len(canImportResponse.Pubkeys)
+	fmt.Printf("The following %d validator keys will be imported:\n", numValidators)
+	for _, pubkey := range canImportResponse.Pubkeys {
+		fmt.Printf("\t%s\n", pubkey.Hex())
+	}
+
+	//Assign max fees
+	err = gas.AssignMaxFeeAndLimit(canImportResponse.GasInfo, staderClient, c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf(
+		"You are about to deposit %d ETH to create %d validators from the imported keys.\n"+
+			"%sMake sure these keys are not running in any other validator client, or you WILL be slashed. This action cannot be undone!%s",
+		baseAmountInEth*numValidators, numValidators,
+		log.ColorYellow,
+		log.ColorReset))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Import the keys and make the deposit
+	response, err := staderClient.ImportValidatorKeys(baseAmount, true, password, keystores)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Importing %d validators...\n", numValidators)
+	cliutils.PrintTransactionHash(staderClient, response.TxHash)
+	_, err = staderClient.WaitForTransaction(response.TxHash)
+	if err != nil {
+		return err
+	}
+
+	// Log & return
+	fmt.Printf("The node deposit of %d ETH was made successfully!\n", baseAmountInEth*numValidators)
+	fmt.Printf("Total %d validators were imported\n", numValidators)
+
+	fmt.Println("Your validators are now in Initialized status.")
+	fmt.Println("You can check the status of your validator with `stader-cli validator status`.")
+	if response.DoppelgangerWatch {
+		fmt.Printf("%sSome of the new keys are already active on the Beacon chain, so they are held back from the validator client while the node watches them for activity from another machine. You can follow it with `stader-cli validator doppelganger-status`.%s\n", log.ColorYellow, log.ColorReset)
+	}
+
+	return nil
+
+}
+
+// Read the keystores at a path; a directory (such as staking-deposit-cli's validator_keys) is searched for keystore*.json files
+func readKeystores(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading keystores at %s: %w", path, err)
+	}
+
+	paths := []string{path}
+	if info.IsDir() {
+		paths, err = filepath.Glob(filepath.Join(path, "keystore*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("error searching %s for keystores: %w", path, err)
+		}
+		sort.Strings(paths)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no keystore*.json files found in %s", path)
+	}
+
+	keystores := make([]string, 0, len(paths))
+	for _, keystorePath := range paths {
+		keystoreBytes, err := ioutil.ReadFile(keystorePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading keystore %s: %w", keystorePath, err)
+		}
+		keystores = append(keystores, string(keystoreBytes))
+	}
+
+	return keystores, nil
+}
@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/shared/utils/offline"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+// Write a deposit request for the machine that holds the wallet to sign; run this on the online node
+func exportDepositRequest(c *cli.Context) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	numValidators := c.Uint64("num-validators")
+	baseAmount := eth.EthToWei(4.0)
+
+	response, err := staderClient.ExportDepositRequest(baseAmount, big.NewInt(int64(numValidators)))
+	if err != nil {
+		return err
+	}
+	if response.InsufficientBalance {
+		fmt.Printf("Account does not have enough balance!")
+		return nil
+	}
+	if response.DepositPaused {
+		fmt.Printf("Deposit is paused")
+		return nil
+	}
+	if response.NotEnoughSdCollateral {
+		fmt.Printf("Not enough SD as collateral")
+		return nil
+	}
+	if response.MaxValidatorLimitReached {
+		fmt.Printf("Max validator limit reached")
+		return nil
+	}
+	if response.InputKeyLimitReached {
+		fmt.Printf("You can only add %d keys at a time\n", response.InputKeyLimit)
+		return nil
+	}
+
+	requestBytes, err := json.MarshalIndent(response.Request, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding deposit request: %w", err)
+	}
+	if err := ioutil.WriteFile(c.String("file"), requestBytes, 0600); err != nil {
+		return fmt.Errorf("error writing deposit request to %s: %w", c.String("file"), err)
+	}
+
+	fmt.Printf("The deposit request for %d validators was written to %s.\n", numValidators, c.String("file"))
+	fmt.Println("Copy it to the machine that holds your wallet and sign it with `stader-cli validator sign-deposit`.")
+	fmt.Printf("%sThe request uses nonce %d; sign and broadcast it before sending any other transaction from this node.%s\n", log.ColorYellow, response.Request.Transaction.Nonce, log.ColorReset)
+	return nil
+
+}
+
+// Create the validator keys for a deposit request and sign the deposit; run this on the machine that holds the wallet
+func signDepositRequest(c *cli.Context) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Read the deposit request
+	requestBytes, err := ioutil.ReadFile(c.String("file"))
+	if err != nil {
+		return fmt.Errorf("error reading deposit request file: %w", err)
+	}
+	var request offline.DepositRequest
+	if err := json.Unmarshal(requestBytes, &request); err != nil {
+		return fmt.Errorf("error decoding deposit request file: %w", err)
+	}
+
+	// Prompt for confirmation
+	numValidators := len(request.Validators)
+	if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf(
+		"You are about to create %d validator keys and sign a deposit of %.6f ETH for node %s.\n"+
+			"%sARE YOU SURE YOU WANT TO DO THIS? Running a validator is a long-term commitment, and this action cannot be undone once broadcast!%s",
+		numValidators, eth.WeiToEth(request.Transaction.Value.ToInt()), request.NodeAddress.Hex(),
+		log.ColorYellow,
+		log.ColorReset))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	response, err := staderClient.SignDepositRequest(string(requestBytes))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Created validator keys:")
+	for _, pubkey := range response.SignedTransactions.ValidatorPubkeys {
+		fmt.Printf("\t%s\n", pubkey)
+	}
+	return cliutils.WriteSignedTransactions(c.String("out"), response.SignedTransactions)
+
+}
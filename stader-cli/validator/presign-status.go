@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/urfave/cli"
+)
+
+func getPresignStatus(c *cli.Context) error {
+
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	// Get the presign history
+	status, err := staderClient.GetPresignStatus()
+	if err != nil {
+		return err
+	}
+
+	if len(status.Validators) == 0 {
+		fmt.Println("The node has no validators that need a presigned exit message.")
+		return nil
+	}
+
+	pending := 0
+	for _, record := range status.Validators {
+		if !record.Accepted {
+			pending++
+		}
+	}
+	fmt.Printf("%d of %d validators have a presigned exit message accepted by Stader.\n\n", len(status.Validators)-pending, len(status.Validators))
+
+	fmt.Printf("%s=== Presigned Exit Messages ===%s\n", log.ColorGreen, log.ColorReset)
+	for i, record := range status.Validators {
+		fmt.Printf("%d) %s\n", i+1, record.Pubkey)
+		if record.Accepted {
+			fmt.Printf("-Status: %saccepted%s\n", log.ColorGreen, log.ColorReset)
+		} else if len(record.Submissions) == 0 {
+			fmt.Printf("-Status: %snot submitted yet%s\n", log.ColorYellow, log.ColorReset)
+		} else {
+			fmt.Printf("-Status: %snot accepted%s (%d failed attempts, next attempt after %s)\n", log.ColorRed, log.ColorReset, record.Retries, record.NextAttempt.Format(time.RFC822))
+		}
+		for _, submission := range record.Submissions {
+			fmt.Printf("  %s: exit epoch %d, validator index %d: %s\n", submission.SubmittedAt.Format(time.RFC822), submission.ExitEpoch, submission.ValidatorIndex, submission.Response)
+		}
+		fmt.Println()
+	}
+
+	return nil
+
+}
@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+	"github.com/urfave/cli"
+)
+
+// Check if any of the batch selection flags were used
+func isBatchSelection(c *cli.Context) bool {
+	return c.String("file") != "" ||
+		c.Bool("all-active") ||
+		c.IsSet("operator-share-above") ||
+		strings.Contains(c.String("validator-pub-key"), ",")
+}
+
+// Get the validators picked by the --validator-pub-key, --file, --all-active and --operator-share-above flags.
+// Explicit keys and keys from the file are combined; the selectors then narrow down or fill in the list from the node's validators.
+func getSelectedValidators(c *cli.Context, staderClient *stader.Client) ([]types.ValidatorPubkey, error) {
+	pubkeys := []types.ValidatorPubkey{}
+
+	if c.String("validator-pub-key") != "" {
+		flagPubkeys, err := cliutils.ValidatePubkeys("validator-pub-key", c.String("validator-pub-key"))
+		if err != nil {
+			return nil, err
+		}
+		pubkeys = append(pubkeys, flagPubkeys...)
+	}
+
+	if c.String("file") != "" {
+		filePubkeys, err := readPubkeysFile(c.String("file"))
+		if err != nil {
+			return nil, err
+		}
+		pubkeys = append(pubkeys, filePubkeys...)
+	}
+
+	allActive := c.Bool("all-active")
+	useShareFilter := c.IsSet("operator-share-above")
+	if allActive || useShareFilter {
+		minOperatorShare := eth.EthToWei(c.Float64("operator-share-above"))
+
+		status, err := staderClient.NodeStatus()
+		if err != nil {
+			return nil, err
+		}
+		if !status.Registered {
+			return nil, fmt.Errorf("the node is not registered with Stader")
+		}
+
+		explicit := map[types.ValidatorPubkey]bool{}
+		for _, pubkey := range pubkeys {
+			explicit[pubkey] = true
+		}
+
+		selected := []types.ValidatorPubkey{}
+		for _, validatorInfo := range status.ValidatorInfos {
+			pubkey := types.BytesToValidatorPubkey(validatorInfo.Pubkey)
+			if len(explicit) > 0 && !explicit[pubkey] {
+				continue
+			}
+			if allActive && !(validatorInfo.Status == 4 && validatorInfo.BeaconStatus == beacon.ValidatorState_ActiveOngoing) {
+				continue
+			}
+			if useShareFilter && (validatorInfo.WithdrawVaultRewardBalance == nil || validatorInfo.WithdrawVaultRewardBalance.Cmp(minOperatorShare) <= 0) {
+				continue
+			}
+			selected = append(selected, pubkey)
+		}
+		pubkeys = selected
+	}
+
+	// Drop duplicates but keep the order the keys were given in
+	seen := map[types.ValidatorPubkey]bool{}
+	uniquePubkeys := []types.ValidatorPubkey{}
+	for _, pubkey := range pubkeys {
+		if seen[pubkey] {
+			continue
+		}
+		seen[pubkey] = true
+		uniquePubkeys = append(uniquePubkeys, pubkey)
+	}
+
+	return uniquePubkeys, nil
+}
+
+// Read validator pubkeys from a file, one per line (commas are also accepted); blank lines and lines starting with # are ignored
+func readPubkeysFile(path string) ([]types.ValidatorPubkey, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading validator pubkeys file %s: %w", path, err)
+	}
+
+	pubkeys := []types.ValidatorPubkey{}
+	for _, line := range strings.Split(string(bytes), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		linePubkeys, err := cliutils.ValidatePubkeys("validator pubkey in file", line)
+		if err != nil {
+			return nil, err
+		}
+		pubkeys = append(pubkeys, linePubkeys...)
+	}
+
+	return pubkeys, nil
+}
@@ -1,10 +1,14 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
+
 	"github.com/stader-labs/stader-node/shared/services/gas"
 
 	"github.com/stader-labs/stader-node/shared/services/stader"
+	"github.com/stader-labs/stader-node/shared/types/api"
 	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
 	"github.com/stader-labs/stader-node/shared/utils/math"
 	"github.com/stader-labs/stader-node/stader-lib/types"
@@ -27,6 +31,9 @@ func SendClRewards(c *cli.Context, validatorPubKey types.ValidatorPubkey) error
 
 	// Print what network we're on
 	err = cliutils.PrintNetwork(staderClient)
+	if err != nil {
+		return err
+	}
 
 	canClaimClRewardsResponse, err := staderClient.CanSendClRewards(validatorPubKey)
 	if err != nil {
@@ -79,3 +86,128 @@ func SendClRewards(c *cli.Context, validatorPubKey types.ValidatorPubkey) error
 
 	return nil
 }
+
+func SendClRewardsBatch(c *cli.Context) error {
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	// Print what network we're on
+	err = cliutils.PrintNetwork(staderClient)
+	if err != nil {
+		return err
+	}
+
+	validatorPubKeys, err := getSelectedValidators(c, staderClient)
+	if err != nil {
+		return err
+	}
+	if len(validatorPubKeys) == 0 {
+		fmt.Println("No validators matched the selection.")
+		return nil
+	}
+
+	canSendResponse, err := staderClient.CanSendClRewardsBatch(validatorPubKeys)
+	if err != nil {
+		return err
+	}
+
+	eligible := []types.ValidatorPubkey{}
+	totalClRewards := big.NewInt(0)
+	tooManyClRewards := false
+	fmt.Printf("Checked %d validators:\n", len(canSendResponse.Validators))
+	for _, result := range canSendResponse.Validators {
+		reason := result.Error
+		if reason == "" {
+			reason = getSendClRewardsIneligibleReason(result.CanSend)
+		}
+		if result.CanSend.TooManyClRewards {
+			tooManyClRewards = true
+		}
+		if reason != "" {
+			fmt.Printf("  %s: skipped (%s)\n", result.Pubkey, reason)
+			continue
+		}
+		fmt.Printf("  %s: %.6f ETH\n", result.Pubkey, math.RoundDown(eth.WeiToEth(result.CanSend.ClRewardsAmount), 6))
+		eligible = append(eligible, result.Pubkey)
+		totalClRewards.Add(totalClRewards, result.CanSend.ClRewardsAmount)
+	}
+	fmt.Println()
+	if tooManyClRewards {
+		fmt.Printf("If you have exited a skipped validator, Please wait for Stader Oracles to settle your funds!\n")
+		fmt.Printf("If you have not exited it, Please reach out to the Stader Team on discord!\n\n")
+	}
+
+	if len(eligible) == 0 {
+		fmt.Println("None of the selected validators have CL rewards to send.")
+		return nil
+	}
+
+	fmt.Printf("%d validators will send a total of %.6f CL Rewards to Claim vault\n\n", len(eligible), math.RoundDown(eth.WeiToEth(totalClRewards), 6))
+
+	err = gas.AssignMaxFeeAndLimit(canSendResponse.TotalGasInfo, staderClient, c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf(
+		"Are you sure you want to send CL rewards for %d validators to claim vault?", len(eligible)))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	res, err := staderClient.SendClRewardsBatch(eligible)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	sentClRewards := big.NewInt(0)
+	for _, result := range res.Results {
+		if !result.Success {
+			failed++
+			fmt.Printf("Failed to send CL rewards for validator %s: %s\n", result.Pubkey, result.Error)
+			continue
+		}
+		fmt.Printf("Sending %.6f CL Rewards for validator %s\n", math.RoundDown(eth.WeiToEth(result.Amount), 6), result.Pubkey)
+		cliutils.PrintTransactionHash(staderClient, result.TxHash)
+		if _, err = staderClient.WaitForTransaction(result.TxHash); err != nil {
+			if errors.Is(err, stader.ErrTransactionsExported) {
+				return err
+			}
+			failed++
+			fmt.Printf("Transaction for validator %s failed: %s\n", result.Pubkey, err)
+			continue
+		}
+		sentClRewards.Add(sentClRewards, result.Amount)
+	}
+
+	// Log & return
+	fmt.Printf("\nSent %.6f CL Rewards to Claim vault for %d validators, %d failed, %d skipped.\n", math.RoundDown(eth.WeiToEth(sentClRewards), 6), len(eligible)-failed, failed, len(validatorPubKeys)-len(eligible))
+
+	return nil
+}
+
+// Get a short reason why a validator's CL rewards can't be sent, or an empty string if they can
+func getSendClRewardsIneligibleReason(response api.CanSendClRewardsResponse) string {
+	switch {
+	case response.ValidatorNotFound:
+		return "validator not found"
+	case response.VaultAlreadySettled:
+		return "vault already settled"
+	case response.NoClRewards:
+		return "no CL rewards to withdraw"
+	case response.TooManyClRewards:
+		return "too many CL rewards to withdraw"
+	}
+	return ""
+}
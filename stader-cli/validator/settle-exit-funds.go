@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/stader-labs/stader-node/shared/services/stader"
+	cliutils "github.com/stader-labs/stader-node/shared/utils/cli"
+	"github.com/stader-labs/stader-node/shared/utils/math"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+	"github.com/urfave/cli"
+)
+
+func SettleExitFunds(c *cli.Context, validatorPubKey types.ValidatorPubkey) error {
+	staderClient, err := stader.NewClientFromCtx(c)
+	if err != nil {
+		return err
+	}
+	defer staderClient.Close()
+
+	// Check and assign the EC status
+	err = cliutils.CheckClientStatus(staderClient)
+	if err != nil {
+		return err
+	}
+
+	// Print what network we're on
+	err = cliutils.PrintNetwork(staderClient)
+	if err != nil {
+		return err
+	}
+
+	canSettleExitFundsResponse, err := staderClient.CanSettleExitFunds(validatorPubKey)
+	if err != nil {
+		return err
+	}
+	if canSettleExitFundsResponse.ValidatorNotRegistered {
+		fmt.Printf("Validator %s not found\n", validatorPubKey.String())
+		return nil
+	}
+	if canSettleExitFundsResponse.ValidatorNotOwned {
+		fmt.Printf("Validator %s does not belong to this node's operator\n", validatorPubKey.String())
+		return nil
+	}
+	if canSettleExitFundsResponse.VaultAlreadySettled {
+		fmt.Printf("The vault of validator %s has been settled and its operator share was paid to the operator reward address.\n", validatorPubKey.String())
+		return nil
+	}
+	if canSettleExitFundsResponse.ValidatorNotWithdrawn {
+		fmt.Printf("Validator %s has not been fully withdrawn from the beacon chain yet. Its vault will be settled after its status is withdrawal_done.\n", validatorPubKey.String())
+		return nil
+	}
+	if canSettleExitFundsResponse.NoEthToWithdraw {
+		fmt.Printf("The withdraw vault %s of validator %s holds no ETH to settle\n", canSettleExitFundsResponse.WithdrawVaultAddress.Hex(), validatorPubKey.String())
+		return nil
+	}
+
+	// Only the node registry can settle the vault, so there's no transaction to send
+	fmt.Printf("The withdraw vault %s holds %.6f ETH, of which %.6f ETH is the operator share.\n", canSettleExitFundsResponse.WithdrawVaultAddress.Hex(), math.RoundDown(eth.WeiToEth(canSettleExitFundsResponse.WithdrawVaultBalance), 6), math.RoundDown(eth.WeiToEth(canSettleExitFundsResponse.OperatorShare), 6))
+	fmt.Println("The vault is settled by the Stader node registry, not by the operator: once the oracle reports the validator as withdrawn, its status becomes Funds Settled (5) and the operator share is paid to the operator reward address.")
+
+	return nil
+}
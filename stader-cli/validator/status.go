@@ -92,6 +92,15 @@ func getValidatorStatus(c *cli.Context) error {
 			fmt.Printf("-Withdraw Time: %s\n\n", validatorInfo.WithdrawnTime.Format("2006-01-02 15:04:05"))
 		}
 
+		penalty := validatorInfo.Penalty
+		if penalty.TotalPenalty != nil && (penalty.TotalPenalty.Sign() > 0 || penalty.PendingPenalty().Sign() > 0) {
+			fmt.Printf("-Penalty: %.6f ETH (%.2f%% of the exit threshold)\n", math.RoundDown(eth.WeiToEth(penalty.TotalPenalty), 6), penalty.ThresholdRatio()*100)
+			fmt.Printf("  MEV Theft: %.6f ETH, Missed Attestations: %.6f ETH, Additional: %.6f ETH\n\n", math.RoundDown(eth.WeiToEth(penalty.MevTheftPenalty), 6), math.RoundDown(eth.WeiToEth(penalty.MissedAttestationPenalty), 6), math.RoundDown(eth.WeiToEth(penalty.AdditionalPenalty), 6))
+			if penalty.AtForceExitRisk() {
+				fmt.Printf("%sThis validator is close to being force exited! Use the %sstader-cli node penalties%s%s command for details.%s\n\n", log.ColorYellow, log.ColorGreen, log.ColorReset, log.ColorYellow, log.ColorReset)
+			}
+		}
+
 		fmt.Printf("\n\n")
 	}
 
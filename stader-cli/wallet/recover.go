@@ -172,6 +172,9 @@ func recoverWallet(c *cli.Context) error {
 			} else {
 				fmt.Println("No validator keys were found.")
 			}
+			if response.DoppelgangerWatch {
+				fmt.Printf("%sSome of these keys are already active, so they are held back from the validator client while the node watches them for activity from another machine. You can follow it with `stader-cli validator doppelganger-status`.%s\n", log.ColorYellow, log.ColorReset)
+			}
 		}
 	}
 
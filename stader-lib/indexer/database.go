@@ -0,0 +1,335 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Config
+const (
+	FileMode = 0600
+	DirMode  = 0700
+
+	// How far back block hashes are kept to find where a reorg forked off
+	MaxReorgDepth uint64 = 128
+
+	// The database is only held open for one transaction at a time so other processes can read it while the indexer runs
+	openTimeout = 10 * time.Second
+
+	checkpointSeparator = "/"
+)
+
+var (
+	eventsBucket      = []byte("events")
+	checkpointsBucket = []byte("checkpoints")
+	blocksBucket      = []byte("blocks")
+)
+
+type recordedBlock struct {
+	number uint64
+	hash   common.Hash
+}
+
+// Events are kept in a bucket per source keyed by block and log index, so cursors walk them in chain order
+type database struct {
+	path string
+	lock sync.Mutex
+}
+
+func newDatabase(path string) *database {
+	return &database{
+		path: path,
+	}
+}
+
+func checkpointKey(sourceName string, address common.Address) string {
+	return sourceName + checkpointSeparator + address.Hex()
+}
+
+// Get the next block to index for every source contract that has been indexed
+func (db *database) getCheckpoints() (map[string]uint64, error) {
+	checkpoints := map[string]uint64{}
+	err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(checkpointsBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			checkpoints[string(k)] = binary.BigEndian.Uint64(v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+// Get the recorded block hashes, newest first
+func (db *database) getRecordedBlocks() ([]recordedBlock, error) {
+	blocks := []recordedBlock{}
+	err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(blocksBucket)
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			blocks = append(blocks, recordedBlock{
+				number: binary.BigEndian.Uint64(k),
+				hash:   common.BytesToHash(v),
+			})
+		}
+		return nil
+	})
+	return blocks, err
+}
+
+// Record the hash of a block the index was synced against and forget the ones older than depth
+func (db *database) recordBlock(number uint64, hash common.Hash, depth uint64) error {
+	return db.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(blocksBucket)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put(uint64Key(number), hash.Bytes()); err != nil {
+			return err
+		}
+		if number <= depth {
+			return nil
+		}
+		cutoff := number - depth
+		oldKeys := [][]byte{}
+		cursor := bucket.Cursor()
+		for k, _ := cursor.First(); k != nil && binary.BigEndian.Uint64(k) < cutoff; k, _ = cursor.Next() {
+			oldKeys = append(oldKeys, append([]byte{}, k...))
+		}
+		return deleteKeys(bucket, oldKeys)
+	})
+}
+
+// Store a block range's events for a source and move its contracts' checkpoints to next, in one transaction
+func (db *database) addEvents(sourceName string, addresses []common.Address, next uint64, events []Event) error {
+	return db.update(func(tx *bolt.Tx) error {
+		eventsRoot, err := tx.CreateBucketIfNotExists(eventsBucket)
+		if err != nil {
+			return err
+		}
+		bucket, err := eventsRoot.CreateBucketIfNotExists([]byte(sourceName))
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("Could not encode '%s' event in block %d: %w", event.Name, event.BlockNumber, err)
+			}
+			if err := bucket.Put(eventKey(event.BlockNumber, event.LogIndex), data); err != nil {
+				return err
+			}
+		}
+
+		checkpoints, err := tx.CreateBucketIfNotExists(checkpointsBucket)
+		if err != nil {
+			return err
+		}
+		for _, address := range addresses {
+			if err := checkpoints.Put([]byte(checkpointKey(sourceName, address)), uint64Key(next)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Drop every event and recorded block from fromBlock on and move the checkpoints back so those blocks are indexed again
+func (db *database) rewind(fromBlock uint64) error {
+	return db.update(func(tx *bolt.Tx) error {
+		if eventsRoot := tx.Bucket(eventsBucket); eventsRoot != nil {
+			err := eventsRoot.ForEach(func(name, v []byte) error {
+				bucket := eventsRoot.Bucket(name)
+				if bucket == nil {
+					return nil
+				}
+				keys := [][]byte{}
+				cursor := bucket.Cursor()
+				for k, _ := cursor.Seek(eventKey(fromBlock, 0)); k != nil; k, _ = cursor.Next() {
+					keys = append(keys, append([]byte{}, k...))
+				}
+				return deleteKeys(bucket, keys)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if checkpoints := tx.Bucket(checkpointsBucket); checkpoints != nil {
+			keys := [][]byte{}
+			err := checkpoints.ForEach(func(k, v []byte) error {
+				if binary.BigEndian.Uint64(v) > fromBlock {
+					keys = append(keys, append([]byte{}, k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range keys {
+				if err := checkpoints.Put(k, uint64Key(fromBlock)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if blocks := tx.Bucket(blocksBucket); blocks != nil {
+			keys := [][]byte{}
+			cursor := blocks.Cursor()
+			for k, _ := cursor.Seek(uint64Key(fromBlock)); k != nil; k, _ = cursor.Next() {
+				keys = append(keys, append([]byte{}, k...))
+			}
+			if err := deleteKeys(blocks, keys); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Get the events matching a query in chain order
+func (db *database) getEvents(q Query) ([]Event, error) {
+	eventNames := toSet(q.Events)
+	addresses := map[common.Address]bool{}
+	for _, address := range q.Addresses {
+		addresses[address] = true
+	}
+
+	events := []Event{}
+	err := db.view(func(tx *bolt.Tx) error {
+		eventsRoot := tx.Bucket(eventsBucket)
+		if eventsRoot == nil {
+			return nil
+		}
+		sourceNames := q.Sources
+		if len(sourceNames) == 0 {
+			err := eventsRoot.ForEach(func(name, v []byte) error {
+				sourceNames = append(sourceNames, string(name))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, sourceName := range sourceNames {
+			bucket := eventsRoot.Bucket([]byte(sourceName))
+			if bucket == nil {
+				continue
+			}
+			cursor := bucket.Cursor()
+			for k, v := cursor.Seek(eventKey(q.FromBlock, 0)); k != nil; k, v = cursor.Next() {
+				if q.ToBlock != 0 && binary.BigEndian.Uint64(k[:8]) > q.ToBlock {
+					break
+				}
+				var event Event
+				if err := json.Unmarshal(v, &event); err != nil {
+					return fmt.Errorf("Could not decode event of source '%s': %w", sourceName, err)
+				}
+				if len(eventNames) > 0 && !eventNames[event.Name] {
+					continue
+				}
+				if len(addresses) > 0 && !addresses[event.Address] {
+					continue
+				}
+				events = append(events, event)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return events[i].LogIndex < events[j].LogIndex
+	})
+	return events, nil
+}
+
+// Run a write transaction, creating the database if it doesn't exist yet
+func (db *database) update(fn func(tx *bolt.Tx) error) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(db.path), DirMode); err != nil {
+		return fmt.Errorf("Could not create event index directory: %w", err)
+	}
+	bdb, err := bolt.Open(db.path, FileMode, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return fmt.Errorf("Could not open event index %s: %w", db.path, err)
+	}
+	defer bdb.Close()
+
+	return bdb.Update(fn)
+}
+
+// Run a read transaction, treating a missing database as empty
+func (db *database) view(fn func(tx *bolt.Tx) error) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if _, err := os.Stat(db.path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	bdb, err := bolt.Open(db.path, FileMode, &bolt.Options{Timeout: openTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("Could not open event index %s: %w", db.path, err)
+	}
+	defer bdb.Close()
+
+	return bdb.View(fn)
+}
+
+func deleteKeys(bucket *bolt.Bucket, keys [][]byte) error {
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// Encode a block number as a database key
+func uint64Key(value uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, value)
+	return key
+}
+
+// Encode a log's position in the chain as a database key
+func eventKey(blockNumber uint64, logIndex uint) []byte {
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint64(key[:8], blockNumber)
+	binary.BigEndian.PutUint32(key[8:], uint32(logIndex))
+	return key
+}
@@ -0,0 +1,479 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// The subset of the execution client the indexer needs.
+// Both stader.ExecutionClient and go-ethereum's simulated backend satisfy it.
+type Client interface {
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Decides whether a decoded event belongs in the index; args holds both the indexed and the data arguments
+type MatchFunc func(name string, args map[string]interface{}) bool
+
+// An event to read from a source, with optional filters on its indexed arguments in order (a nil entry matches anything)
+type EventFilter struct {
+	Name  string
+	Query [][]interface{}
+}
+
+// A set of contracts sharing an ABI whose events are indexed together
+type Source struct {
+	// Unique name the source's events and checkpoints are stored under
+	Name string
+	ABI  *abi.ABI
+	// The contracts to read, each from the block it was deployed or became relevant in
+	Addresses map[common.Address]uint64
+	// The events to read; every event in the ABI if empty
+	Events []EventFilter
+	// Drops events that the topic filters can't, such as ones for other operators' validators; everything is kept if nil
+	Match MatchFunc
+}
+
+// A decoded contract log stored in the index
+type Event struct {
+	Source      string         `json:"source"`
+	Name        string         `json:"name"`
+	Address     common.Address `json:"address"`
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	TxHash      common.Hash    `json:"txHash"`
+	TxIndex     uint           `json:"txIndex"`
+	LogIndex    uint           `json:"logIndex"`
+	Topics      []common.Hash  `json:"topics"`
+	Data        hexutil.Bytes  `json:"data"`
+}
+
+// The log the event was decoded from
+func (e Event) Log() types.Log {
+	return types.Log{
+		Address:     e.Address,
+		Topics:      e.Topics,
+		Data:        e.Data,
+		BlockNumber: e.BlockNumber,
+		TxHash:      e.TxHash,
+		TxIndex:     e.TxIndex,
+		BlockHash:   e.BlockHash,
+		Index:       e.LogIndex,
+	}
+}
+
+// Filters for reading events back out of the index; empty fields match everything
+type Query struct {
+	Sources   []string
+	Events    []string
+	Addresses []common.Address
+	FromBlock uint64
+	// Inclusive; 0 for no limit
+	ToBlock uint64
+}
+
+type source struct {
+	Source
+	contract *bind.BoundContract
+}
+
+// Indexes contract events into a local database, backfilling each source from its start block and then following the chain.
+// Events from blocks that get reorged out are dropped and their blocks scanned again.
+type Indexer struct {
+	client    Client
+	db        *database
+	chunkSize uint64
+
+	sources     []*source
+	sourceMap   map[string]*source
+	sourcesLock sync.Mutex
+	syncLock    sync.Mutex
+}
+
+// Create an indexer for the given sources, storing its database at path.
+// chunkSize is the largest block range requested from the client at once.
+func NewIndexer(client Client, path string, chunkSize uint64, sources []Source) (*Indexer, error) {
+	if chunkSize == 0 {
+		return nil, fmt.Errorf("the chunk size must be greater than 0")
+	}
+	ix := &Indexer{
+		client:    client,
+		db:        newDatabase(path),
+		chunkSize: chunkSize,
+		sourceMap: map[string]*source{},
+	}
+	for _, s := range sources {
+		if err := ix.AddSource(s); err != nil {
+			return nil, err
+		}
+	}
+	return ix, nil
+}
+
+// Add a source to index from the next sync on
+func (ix *Indexer) AddSource(s Source) error {
+	if s.Name == "" {
+		return fmt.Errorf("source name cannot be blank")
+	}
+	if strings.Contains(s.Name, checkpointSeparator) {
+		return fmt.Errorf("source name '%s' cannot contain '%s'", s.Name, checkpointSeparator)
+	}
+	if s.ABI == nil {
+		return fmt.Errorf("source '%s' has no ABI", s.Name)
+	}
+	for _, filter := range s.Events {
+		if _, exists := s.ABI.Events[filter.Name]; !exists {
+			return fmt.Errorf("event '%s' does not exist on source '%s'", filter.Name, s.Name)
+		}
+	}
+
+	ix.sourcesLock.Lock()
+	defer ix.sourcesLock.Unlock()
+	if _, exists := ix.sourceMap[s.Name]; exists {
+		return fmt.Errorf("source '%s' has already been added", s.Name)
+	}
+	added := &source{
+		Source:   s,
+		contract: bind.NewBoundContract(common.Address{}, *s.ABI, nil, nil, nil),
+	}
+	ix.sources = append(ix.sources, added)
+	ix.sourceMap[s.Name] = added
+	return nil
+}
+
+// Add a contract to an existing source, read from startBlock; used for contracts that appear over time such as new withdraw vaults
+func (ix *Indexer) AddAddress(sourceName string, address common.Address, startBlock uint64) error {
+	ix.sourcesLock.Lock()
+	defer ix.sourcesLock.Unlock()
+	s, exists := ix.sourceMap[sourceName]
+	if !exists {
+		return fmt.Errorf("unknown source '%s'", sourceName)
+	}
+	if s.Addresses == nil {
+		s.Addresses = map[common.Address]uint64{}
+	}
+	if _, exists := s.Addresses[address]; !exists {
+		s.Addresses[address] = startBlock
+	}
+	return nil
+}
+
+// Bring every source up to the head of the chain, after dropping anything that was reorged out.
+// Progress is saved as each block range is indexed, so a failed sync resumes where it stopped. Returns the block synced to.
+func (ix *Indexer) Sync(ctx context.Context) (uint64, error) {
+	ix.syncLock.Lock()
+	defer ix.syncLock.Unlock()
+
+	if err := ix.handleReorg(ctx); err != nil {
+		return 0, fmt.Errorf("error checking for reorgs: %w", err)
+	}
+
+	head, err := ix.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error getting the latest block: %w", err)
+	}
+	target := head.Number.Uint64()
+
+	// Record the head before scanning so a reorg while scanning is caught on the next sync
+	if err := ix.db.recordBlock(target, head.Hash(), MaxReorgDepth); err != nil {
+		return 0, err
+	}
+
+	checkpoints, err := ix.db.getCheckpoints()
+	if err != nil {
+		return 0, err
+	}
+	ix.sourcesLock.Lock()
+	sources := make([]*source, len(ix.sources))
+	copy(sources, ix.sources)
+	ix.sourcesLock.Unlock()
+
+	for _, s := range sources {
+		if err := ix.syncSource(ctx, s, checkpoints, target); err != nil {
+			return 0, fmt.Errorf("error indexing source '%s': %w", s.Name, err)
+		}
+	}
+	return target, nil
+}
+
+// Sync every interval until the context is cancelled; errors are passed to onError and retried on the next interval
+func (ix *Indexer) Follow(ctx context.Context, interval time.Duration, onError func(error)) {
+	for {
+		if _, err := ix.Sync(ctx); err != nil && onError != nil && !errors.Is(err, context.Canceled) {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Get the indexed events that match the query, in chain order
+func (ix *Indexer) Query(q Query) ([]Event, error) {
+	return ix.db.getEvents(q)
+}
+
+// Get the first block that hasn't been indexed yet for one of a source's contracts
+func (ix *Indexer) GetCheckpoint(sourceName string, address common.Address) (uint64, error) {
+	ix.sourcesLock.Lock()
+	s, exists := ix.sourceMap[sourceName]
+	ix.sourcesLock.Unlock()
+	if !exists {
+		return 0, fmt.Errorf("unknown source '%s'", sourceName)
+	}
+	checkpoints, err := ix.db.getCheckpoints()
+	if err != nil {
+		return 0, err
+	}
+	if next, exists := checkpoints[checkpointKey(sourceName, address)]; exists {
+		return next, nil
+	}
+	return s.Addresses[address], nil
+}
+
+// Decode an indexed event into a generated binding struct such as contracts.SdCollateralSDDeposited, including its Raw log
+func (ix *Indexer) Unpack(event Event, out interface{}) error {
+	s, err := ix.getSource(event.Source)
+	if err != nil {
+		return err
+	}
+	if err := s.contract.UnpackLog(out, event.Name, event.Log()); err != nil {
+		return fmt.Errorf("could not unpack '%s' event: %w", event.Name, err)
+	}
+	setRawLog(out, event.Log())
+	return nil
+}
+
+// Decode the arguments of an indexed event by name
+func (ix *Indexer) UnpackIntoMap(event Event) (map[string]interface{}, error) {
+	s, err := ix.getSource(event.Source)
+	if err != nil {
+		return nil, err
+	}
+	return s.unpackIntoMap(event.Name, event.Log())
+}
+
+func (ix *Indexer) getSource(name string) (*source, error) {
+	ix.sourcesLock.Lock()
+	defer ix.sourcesLock.Unlock()
+	s, exists := ix.sourceMap[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown source '%s'", name)
+	}
+	return s, nil
+}
+
+// Walk back through the recorded block hashes to the newest one still on the chain and drop everything after it
+func (ix *Indexer) handleReorg(ctx context.Context) error {
+	blocks, err := ix.db.getRecordedBlocks()
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	// Newest first; the newest usually matches so this is one request
+	var ancestor uint64
+	found := false
+	for _, block := range blocks {
+		header, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(block.number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		if err == nil && header != nil && header.Hash() == block.hash {
+			ancestor = block.number
+			found = true
+			break
+		}
+	}
+	if found && ancestor == blocks[0].number {
+		return nil
+	}
+	if !found {
+		// The reorg is deeper than the recorded blocks go back, so drop everything from the oldest of them on
+		return ix.db.rewind(blocks[len(blocks)-1].number)
+	}
+	return ix.db.rewind(ancestor + 1)
+}
+
+// Index a source's contracts up to target. Contracts that have been indexed up to the same block are read together,
+// so a newly added contract is read on its own until it catches up with the rest.
+func (ix *Indexer) syncSource(ctx context.Context, s *source, checkpoints map[string]uint64, target uint64) error {
+	ix.sourcesLock.Lock()
+	groups := map[uint64][]common.Address{}
+	for address, start := range s.Addresses {
+		next, exists := checkpoints[checkpointKey(s.Name, address)]
+		if !exists {
+			next = start
+		}
+		if next > target {
+			continue
+		}
+		groups[next] = append(groups[next], address)
+	}
+	ix.sourcesLock.Unlock()
+
+	starts := make([]uint64, 0, len(groups))
+	for start := range groups {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	for _, groupStart := range starts {
+		addresses := groups[groupStart]
+		sort.Slice(addresses, func(i, j int) bool { return addresses[i].Hex() < addresses[j].Hex() })
+		for start := groupStart; start <= target; start += ix.chunkSize {
+			end := start + ix.chunkSize - 1
+			if end > target {
+				end = target
+			}
+			events, err := ix.readEvents(ctx, s, addresses, start, end)
+			if err != nil {
+				return fmt.Errorf("error reading blocks %d to %d: %w", start, end, err)
+			}
+			if err := ix.db.addEvents(s.Name, addresses, end+1, events); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Read and decode a source's events in a block range
+func (ix *Indexer) readEvents(ctx context.Context, s *source, addresses []common.Address, fromBlock uint64, toBlock uint64) ([]Event, error) {
+	filters := s.Events
+	if len(filters) == 0 {
+		// No topics reads every log of the contracts
+		filters = []EventFilter{{}}
+	}
+
+	// Events without argument filters are read in one request by matching any of their IDs
+	queries := [][][]common.Hash{}
+	unfilteredIds := []interface{}{}
+	for _, filter := range filters {
+		if filter.Name == "" {
+			queries = append(queries, nil)
+			continue
+		}
+		eventId := s.ABI.Events[filter.Name].ID
+		if len(filter.Query) == 0 {
+			unfilteredIds = append(unfilteredIds, eventId)
+			continue
+		}
+		topics, err := abi.MakeTopics(append([][]interface{}{{eventId}}, filter.Query...)...)
+		if err != nil {
+			return nil, fmt.Errorf("could not build the topics for event '%s': %w", filter.Name, err)
+		}
+		queries = append(queries, topics)
+	}
+	if len(unfilteredIds) > 0 {
+		topics, err := abi.MakeTopics(unfilteredIds)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, topics)
+	}
+
+	logs := []types.Log{}
+	for _, topics := range queries {
+		filterLogs, err := ix.client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Addresses: addresses,
+			Topics:    topics,
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, filterLogs...)
+	}
+
+	// Match functions can rely on seeing events in the order they were emitted
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	// The queries can overlap, such as an unnamed filter reading every log alongside named ones, so each log is only kept once
+	type logKey struct {
+		txHash   common.Hash
+		logIndex uint
+	}
+	seen := make(map[logKey]bool, len(logs))
+	events := make([]Event, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) == 0 {
+			continue
+		}
+		key := logKey{txHash: log.TxHash, logIndex: log.Index}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		abiEvent, err := s.ABI.EventByID(log.Topics[0])
+		if err != nil {
+			// Anonymous or unknown event
+			continue
+		}
+		if s.Match != nil {
+			args, err := s.unpackIntoMap(abiEvent.Name, log)
+			if err != nil {
+				return nil, err
+			}
+			if !s.Match(abiEvent.Name, args) {
+				continue
+			}
+		}
+		events = append(events, Event{
+			Source:      s.Name,
+			Name:        abiEvent.Name,
+			Address:     log.Address,
+			BlockNumber: log.BlockNumber,
+			BlockHash:   log.BlockHash,
+			TxHash:      log.TxHash,
+			TxIndex:     log.TxIndex,
+			LogIndex:    log.Index,
+			Topics:      log.Topics,
+			Data:        log.Data,
+		})
+	}
+	return events, nil
+}
+
+func (s *source) unpackIntoMap(name string, log types.Log) (map[string]interface{}, error) {
+	args := map[string]interface{}{}
+	if err := s.contract.UnpackLogIntoMap(args, name, log); err != nil {
+		return nil, fmt.Errorf("could not unpack '%s' event: %w", name, err)
+	}
+	return args, nil
+}
+
+// The generated bindings set the Raw log by hand since it isn't part of the ABI
+func setRawLog(out interface{}, log types.Log) {
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+	if raw := value.Elem().FieldByName("Raw"); raw.IsValid() && raw.CanSet() && raw.Type() == reflect.TypeOf(log) {
+		raw.Set(reflect.ValueOf(log))
+	}
+}
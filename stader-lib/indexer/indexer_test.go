@@ -0,0 +1,239 @@
+package indexer
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+)
+
+// A simulated chain with a contract that emits whatever log it's called with: the call data is topicCount topics
+// followed by the log data
+type testChain struct {
+	t       *testing.T
+	sim     *backends.SimulatedBackend
+	opts    *bind.TransactOpts
+	emitter common.Address
+}
+
+func newTestChain(t *testing.T, topicCount int) *testChain {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{opts.From: {Balance: big.NewInt(1e18)}}, 10000000)
+	t.Cleanup(func() { sim.Close() })
+
+	// Push the topics in reverse, copy the rest of the call data into memory and log it
+	runtime := []byte{}
+	for i := topicCount - 1; i >= 0; i-- {
+		runtime = append(runtime, 0x60, byte(32*i), 0x35) // PUSH1 offset, CALLDATALOAD
+	}
+	dataOffset := byte(32 * topicCount)
+	runtime = append(runtime,
+		0x60, dataOffset, 0x36, 0x03, // PUSH1 dataOffset, CALLDATASIZE, SUB
+		0x80, 0x60, dataOffset, 0x60, 0x00, 0x37, // DUP1, PUSH1 dataOffset, PUSH1 0, CALLDATACOPY
+		0x60, 0x00, 0xa0+byte(topicCount), 0x00, // PUSH1 0, LOGn, STOP
+	)
+	initCode := append([]byte{
+		0x60, byte(len(runtime)), 0x60, 0x0c, 0x60, 0x00, 0x39, // CODECOPY(0, 12, len)
+		0x60, byte(len(runtime)), 0x60, 0x00, 0xf3, // RETURN(0, len)
+	}, runtime...)
+
+	chain := &testChain{t: t, sim: sim, opts: opts}
+	nonce, err := sim.PendingNonceAt(context.Background(), opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.send(types.NewContractCreation(nonce, big.NewInt(0), 100000, big.NewInt(1e9), initCode))
+	sim.Commit()
+	chain.emitter = crypto.CreateAddress(opts.From, nonce)
+	return chain
+}
+
+func (c *testChain) send(tx *types.Transaction) {
+	signedTx, err := c.opts.Signer(c.opts.From, tx)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if err := c.sim.SendTransaction(context.Background(), signedTx); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// Emit a log from the emitter in the pending block
+func (c *testChain) emit(topics []common.Hash, data []byte) {
+	callData := []byte{}
+	for _, topic := range topics {
+		callData = append(callData, topic.Bytes()...)
+	}
+	callData = append(callData, data...)
+	nonce, err := c.sim.PendingNonceAt(context.Background(), c.opts.From)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.send(types.NewTransaction(nonce, c.emitter, big.NewInt(0), 100000, big.NewInt(1e9), callData))
+}
+
+// Emit an SdCollateral event for an operator
+func (c *testChain) emitSd(name string, operator common.Address, amount int64) {
+	sdcAbi, err := contracts.SdCollateralMetaData.GetAbi()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	data, err := sdcAbi.Events[name].Inputs.NonIndexed().Pack(big.NewInt(amount))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.emit([]common.Hash{sdcAbi.Events[name].ID, common.BytesToHash(operator.Bytes())}, data)
+}
+
+func newSdSource(t *testing.T, emitter common.Address, operator common.Address) Source {
+	sdcAbi, err := contracts.SdCollateralMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Source{
+		Name:      SdCollateralSource,
+		ABI:       sdcAbi,
+		Addresses: map[common.Address]uint64{emitter: 0},
+		Events: []EventFilter{
+			// The unfiltered SDDeposited query also returns the operator's deposits
+			{Name: "SDDeposited", Query: [][]interface{}{{operator}}},
+			{Name: "SDDeposited"},
+			{Name: "SDWithdrawn", Query: [][]interface{}{{operator}}},
+		},
+	}
+}
+
+func TestSyncOverlappingFilters(t *testing.T) {
+	chain := newTestChain(t, 2)
+	operator := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	chain.emitSd("SDDeposited", operator, 5)
+	chain.emitSd("SDDeposited", other, 7)
+	chain.sim.Commit()
+	chain.emitSd("SDWithdrawn", other, 1)
+	chain.emitSd("SDWithdrawn", operator, 2)
+	chain.sim.Commit()
+
+	ix, err := NewIndexer(chain.sim, filepath.Join(t.TempDir(), "index.db"), 2, []Source{newSdSource(t, chain.emitter, operator)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each log is read once even though two of the queries return the operator's deposit
+	s, err := ix.getSource(SdCollateralSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := ix.readEvents(context.Background(), s, []common.Address{chain.emitter}, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+
+	if _, err := ix.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	events, err = ix.Query(Query{Sources: []string{SdCollateralSource}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		name     string
+		operator common.Address
+		amount   int64
+	}{
+		{"SDDeposited", operator, 5},
+		{"SDDeposited", other, 7},
+		{"SDWithdrawn", operator, 2},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(events))
+	}
+	for i, event := range events {
+		args, err := ix.UnpackIntoMap(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Name != expected[i].name || args["operator"] != expected[i].operator || args["sdAmount"].(*big.Int).Int64() != expected[i].amount {
+			t.Errorf("event %d: expected %s of %d for %s, got %s %v", i, expected[i].name, expected[i].amount, expected[i].operator.Hex(), event.Name, args)
+		}
+	}
+
+	var deposit contracts.SdCollateralSDDeposited
+	if err := ix.Unpack(events[0], &deposit); err != nil {
+		t.Fatal(err)
+	}
+	if deposit.Operator != operator || deposit.SdAmount.Int64() != 5 || deposit.Raw.TxHash != events[0].TxHash {
+		t.Errorf("unexpected unpacked deposit %+v", deposit)
+	}
+}
+
+func TestSyncReorg(t *testing.T) {
+	chain := newTestChain(t, 2)
+	operator := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	chain.emitSd("SDDeposited", operator, 5)
+	chain.sim.Commit()
+	forkPoint, err := chain.sim.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.emitSd("SDDeposited", operator, 6)
+	chain.sim.Commit()
+
+	ix, err := NewIndexer(chain.sim, filepath.Join(t.TempDir(), "index.db"), 100, []Source{newSdSource(t, chain.emitter, operator)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ix.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	events, err := ix.Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events before the reorg, got %d", len(events))
+	}
+
+	// Replace the last block with a longer chain that withdraws instead
+	if err := chain.sim.Fork(context.Background(), forkPoint.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	chain.emitSd("SDWithdrawn", operator, 3)
+	chain.sim.Commit()
+	chain.sim.Commit()
+
+	if _, err := ix.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	events, err = ix.Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Name != "SDDeposited" || events[1].Name != "SDWithdrawn" {
+		t.Fatalf("expected the deposit and the withdrawal after the reorg, got %+v", events)
+	}
+	if events[1].BlockNumber != forkPoint.Number.Uint64()+1 {
+		t.Errorf("expected the withdrawal in block %d, got %d", forkPoint.Number.Uint64()+1, events[1].BlockNumber)
+	}
+}
@@ -0,0 +1,294 @@
+package indexer
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Names of the sources indexed for an operator
+const (
+	PermissionlessNodeRegistrySource = "permissionlessNodeRegistry"
+	SocializingPoolSource            = "socializingPool"
+	SdCollateralSource               = "sdCollateral"
+	PenaltySource                    = "penalty"
+	OperatorRewardsCollectorSource   = "operatorRewardsCollector"
+	NodeElRewardVaultSource          = "nodeElRewardVault"
+	ValidatorWithdrawVaultSource     = "validatorWithdrawVault"
+)
+
+// The contracts an operator's events are read from
+type OperatorContracts struct {
+	PermissionlessNodeRegistry common.Address
+	SocializingPool            common.Address
+	SdCollateral               common.Address
+	Penalty                    common.Address
+	// The zero address if the network doesn't have one
+	OperatorRewardsCollector common.Address
+	// The zero address if the operator doesn't have one
+	NodeElRewardVault common.Address
+	// Each of the operator's validator withdraw vaults and the block its validator was deposited in
+	ValidatorWithdrawVaults map[common.Address]uint64
+	// The block the Stader contracts were deployed in
+	StartBlock uint64
+}
+
+// Keeps the events of the shared contracts to the ones about one operator.
+// The operator's validators are learned from its AddedValidatorKey events as they are indexed.
+type OperatorFilter struct {
+	NodeAddress   common.Address
+	RewardAddress common.Address
+	OperatorId    *big.Int
+
+	pubkeys      map[types.ValidatorPubkey]bool
+	validatorIds map[string]bool
+	lock         sync.Mutex
+}
+
+func NewOperatorFilter(nodeAddress common.Address, rewardAddress common.Address, operatorId *big.Int, pubkeys []types.ValidatorPubkey) *OperatorFilter {
+	filter := &OperatorFilter{
+		NodeAddress:   nodeAddress,
+		RewardAddress: rewardAddress,
+		OperatorId:    operatorId,
+		pubkeys:       map[types.ValidatorPubkey]bool{},
+		validatorIds:  map[string]bool{},
+	}
+	for _, pubkey := range pubkeys {
+		filter.pubkeys[pubkey] = true
+	}
+	return filter
+}
+
+// Add one of the operator's validators; the ID may be nil if it isn't known
+func (f *OperatorFilter) AddValidator(pubkey types.ValidatorPubkey, validatorId *big.Int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.pubkeys[pubkey] = true
+	if validatorId != nil {
+		f.validatorIds[validatorId.String()] = true
+	}
+}
+
+func (f *OperatorFilter) hasPubkey(value interface{}) bool {
+	pubkey, ok := value.([]byte)
+	if !ok {
+		return false
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.pubkeys[types.BytesToValidatorPubkey(pubkey)]
+}
+
+func (f *OperatorFilter) hasValidatorId(value interface{}) bool {
+	validatorId, ok := value.(*big.Int)
+	if !ok {
+		return false
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.validatorIds[validatorId.String()]
+}
+
+// The validator events of the registry don't index the operator, so they're matched on the learned pubkeys and IDs
+func (f *OperatorFilter) matchRegistry(name string, args map[string]interface{}) bool {
+	switch name {
+	case "AddedValidatorKey":
+		// Already filtered to the node's address on the topic
+		if pubkey, ok := args["pubkey"].([]byte); ok {
+			validatorId, _ := args["validatorId"].(*big.Int)
+			f.AddValidator(types.BytesToValidatorPubkey(pubkey), validatorId)
+		}
+		return true
+	case "OnboardedOperator", "UpdatedOperatorDetails":
+		return true
+	case "ValidatorMarkedAsFrontRunned", "ValidatorMarkedReadyToDeposit", "ValidatorStatusMarkedAsInvalidSignature", "ValidatorWithdrawn":
+		if !f.hasPubkey(args["pubkey"]) {
+			return false
+		}
+		// Validators passed in up front don't have their IDs yet
+		validatorId, _ := args["validatorId"].(*big.Int)
+		f.AddValidator(types.BytesToValidatorPubkey(args["pubkey"].([]byte)), validatorId)
+		return true
+	case "UpdatedValidatorDepositBlock":
+		return f.hasValidatorId(args["validatorId"])
+	case "UpdatedSocializingPoolState":
+		operatorId, ok := args["operatorId"].(*big.Int)
+		return ok && f.OperatorId != nil && operatorId.Cmp(f.OperatorId) == 0
+	}
+	return false
+}
+
+func (f *OperatorFilter) matchPenalty(name string, args map[string]interface{}) bool {
+	return f.hasPubkey(args["pubkey"])
+}
+
+// Learn the validators from events that were indexed before a restart
+func (f *OperatorFilter) restore(ix *Indexer) error {
+	events, err := ix.Query(Query{
+		Sources: []string{PermissionlessNodeRegistrySource},
+		Events:  []string{"AddedValidatorKey", "ValidatorMarkedAsFrontRunned", "ValidatorMarkedReadyToDeposit", "ValidatorStatusMarkedAsInvalidSignature", "ValidatorWithdrawn"},
+	})
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		args, err := ix.UnpackIntoMap(event)
+		if err != nil {
+			return err
+		}
+		pubkey, ok := args["pubkey"].([]byte)
+		if !ok {
+			continue
+		}
+		validatorId, _ := args["validatorId"].(*big.Int)
+		f.AddValidator(types.BytesToValidatorPubkey(pubkey), validatorId)
+	}
+	return nil
+}
+
+// Get the sources for an operator's events in the PermissionlessNodeRegistry, SocializingPool, SdCollateral, Penalty and
+// OperatorRewardsCollector contracts and its NodeElRewardVault and ValidatorWithdrawVaults
+func NewOperatorSources(addresses OperatorContracts, filter *OperatorFilter) ([]Source, error) {
+	pnrAbi, err := getAbi(contracts.PermissionlessNodeRegistryMetaData)
+	if err != nil {
+		return nil, err
+	}
+	spAbi, err := getAbi(contracts.SocializingPoolMetaData)
+	if err != nil {
+		return nil, err
+	}
+	sdcAbi, err := getAbi(contracts.SdCollateralMetaData)
+	if err != nil {
+		return nil, err
+	}
+	penaltyAbi, err := getAbi(contracts.PenaltyTrackerMetaData)
+	if err != nil {
+		return nil, err
+	}
+	nevAbi, err := getAbi(contracts.NodeElRewardVaultMetaData)
+	if err != nil {
+		return nil, err
+	}
+	vwvAbi, err := getAbi(contracts.ValidatorWithdrawVaultMetaData)
+	if err != nil {
+		return nil, err
+	}
+	orcAbi, err := getAbi(contracts.OperatorRewardsCollectorMetaData)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeQuery := [][]interface{}{{filter.NodeAddress}}
+	recipients := []interface{}{filter.NodeAddress}
+	if filter.RewardAddress != (common.Address{}) && filter.RewardAddress != filter.NodeAddress {
+		recipients = append(recipients, filter.RewardAddress)
+	}
+
+	sources := []Source{
+		{
+			// The registry comes first so the operator's validators are known before the penalty events are matched
+			Name:      PermissionlessNodeRegistrySource,
+			ABI:       pnrAbi,
+			Addresses: map[common.Address]uint64{addresses.PermissionlessNodeRegistry: addresses.StartBlock},
+			Events: []EventFilter{
+				{Name: "OnboardedOperator", Query: nodeQuery},
+				{Name: "UpdatedOperatorDetails", Query: nodeQuery},
+				{Name: "AddedValidatorKey", Query: nodeQuery},
+				{Name: "ValidatorMarkedAsFrontRunned"},
+				{Name: "ValidatorMarkedReadyToDeposit"},
+				{Name: "ValidatorStatusMarkedAsInvalidSignature"},
+				{Name: "ValidatorWithdrawn"},
+				{Name: "UpdatedValidatorDepositBlock"},
+				{Name: "UpdatedSocializingPoolState"},
+			},
+			Match: filter.matchRegistry,
+		},
+		{
+			Name:      SocializingPoolSource,
+			ABI:       spAbi,
+			Addresses: map[common.Address]uint64{addresses.SocializingPool: addresses.StartBlock},
+			Events: []EventFilter{
+				{Name: "OperatorRewardsClaimed", Query: [][]interface{}{recipients}},
+			},
+		},
+		{
+			Name:      SdCollateralSource,
+			ABI:       sdcAbi,
+			Addresses: map[common.Address]uint64{addresses.SdCollateral: addresses.StartBlock},
+			Events: []EventFilter{
+				{Name: "SDDeposited", Query: nodeQuery},
+				{Name: "SDWithdrawn", Query: nodeQuery},
+				{Name: "SDSlashed", Query: nodeQuery},
+			},
+		},
+		{
+			Name:      PenaltySource,
+			ABI:       penaltyAbi,
+			Addresses: map[common.Address]uint64{addresses.Penalty: addresses.StartBlock},
+			Events: []EventFilter{
+				{Name: "ForceExitValidator"},
+				{Name: "ValidatorMarkedAsSettled"},
+				{Name: "UpdatedAdditionalPenaltyAmount"},
+			},
+			Match: filter.matchPenalty,
+		},
+		{
+			// Every event of the operator's own vaults is indexed
+			Name:      ValidatorWithdrawVaultSource,
+			ABI:       vwvAbi,
+			Addresses: map[common.Address]uint64{},
+		},
+	}
+	for vault, startBlock := range addresses.ValidatorWithdrawVaults {
+		sources[len(sources)-1].Addresses[vault] = startBlock
+	}
+	if addresses.OperatorRewardsCollector != (common.Address{}) {
+		sources = append(sources, Source{
+			Name:      OperatorRewardsCollectorSource,
+			ABI:       orcAbi,
+			Addresses: map[common.Address]uint64{addresses.OperatorRewardsCollector: addresses.StartBlock},
+			Events: []EventFilter{
+				{Name: "Claimed", Query: [][]interface{}{recipients}},
+			},
+		})
+	}
+	if addresses.NodeElRewardVault != (common.Address{}) {
+		sources = append(sources, Source{
+			Name:      NodeElRewardVaultSource,
+			ABI:       nevAbi,
+			Addresses: map[common.Address]uint64{addresses.NodeElRewardVault: addresses.StartBlock},
+		})
+	}
+	return sources, nil
+}
+
+// Create an indexer for an operator's events, relearning the validators it has already indexed.
+// New withdraw vaults can be added later with AddAddress on ValidatorWithdrawVaultSource.
+func NewOperatorIndexer(client Client, path string, chunkSize uint64, addresses OperatorContracts, filter *OperatorFilter) (*Indexer, error) {
+	sources, err := NewOperatorSources(addresses, filter)
+	if err != nil {
+		return nil, err
+	}
+	ix, err := NewIndexer(client, path, chunkSize, sources)
+	if err != nil {
+		return nil, err
+	}
+	if err := filter.restore(ix); err != nil {
+		return nil, fmt.Errorf("error restoring the operator's validators from the index: %w", err)
+	}
+	return ix, nil
+}
+
+func getAbi(metaData *bind.MetaData) (*abi.ABI, error) {
+	parsed, err := metaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("could not parse contract ABI: %w", err)
+	}
+	return parsed, nil
+}
@@ -139,3 +139,39 @@ func GetAllValidatorsInfoByOperator(pnr *stader.PermissionlessNodeRegistryContra
 
 	return finalValidators, nil
 }
+
+// Get the Withdrawal events emitted by a node EL reward vault in a block range
+func GetElRewardWithdrawalEvents(client stader.ExecutionClient, nevAddress common.Address, fromBlock uint64, toBlock uint64) ([]contracts.NodeElRewardVaultWithdrawal, error) {
+	nev, err := stader.NewNodeElRewardVaultFactory(client, nevAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := nev.NodeElRewardVaultContract.FilterEvents([]common.Address{nevAddress}, "Withdrawal", contracts.NodeElRewardVaultWithdrawal{}, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	withdrawals := make([]contracts.NodeElRewardVaultWithdrawal, len(events))
+	for i, event := range events {
+		withdrawals[i] = event.(contracts.NodeElRewardVaultWithdrawal)
+	}
+	return withdrawals, nil
+}
+
+// Get the Claimed events of the operator rewards collector paid out to any of the given receivers in a block range
+func GetOperatorRewardsCollectorClaimedEvents(orc *stader.OperatorRewardsCollectorContractManager, receivers []common.Address, fromBlock uint64, toBlock uint64) ([]contracts.OperatorRewardsCollectorClaimed, error) {
+	receiverQuery := make([]interface{}, len(receivers))
+	for i, receiver := range receivers {
+		receiverQuery[i] = receiver
+	}
+
+	events, err := orc.OperatorRewardsCollectorContract.FilterEvents([]common.Address{*orc.OperatorRewardsCollectorContract.Address}, "Claimed", contracts.OperatorRewardsCollectorClaimed{}, fromBlock, toBlock, receiverQuery)
+	if err != nil {
+		return nil, err
+	}
+	claims := make([]contracts.OperatorRewardsCollectorClaimed, len(events))
+	for i, event := range events {
+		claims[i] = event.(contracts.OperatorRewardsCollectorClaimed)
+	}
+	return claims, nil
+}
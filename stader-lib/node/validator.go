@@ -1,6 +1,7 @@
 package node
 
 import (
+	"errors"
 	"fmt"
 	"github.com/stader-labs/stader-node/stader-lib/contracts"
 	types2 "github.com/stader-labs/stader-node/stader-lib/types"
@@ -25,23 +26,44 @@ func AddValidatorKeys(pnr *stader.PermissionlessNodeRegistryContractManager, pub
 	return tx, nil
 }
 
+// A validator withdraw vault's settleFunds reverts with CallerNotNodeRegistryContract for anyone but the node registry,
+// which settles the vault when the oracle reports the validator as withdrawn
+var ErrCallerNotNodeRegistryContract = errors.New("CallerNotNodeRegistryContract: only the node registry can settle a validator withdraw vault, which it does once the oracle reports the validator as withdrawn")
+
 func EstimateSettleFunds(executionClient stader.ExecutionClient, validatorWithdrawVaultAddress common.Address, opts *bind.TransactOpts) (stader.GasInfo, error) {
 	vwv, err := stader.NewValidatorWithdrawVaultFactory(executionClient, validatorWithdrawVaultAddress)
 	if err != nil {
 		return stader.GasInfo{}, err
 	}
 
-	return vwv.ValidatorWithdrawVaultContract.GetTransactionGasInfo(opts, "settleFunds")
+	gasInfo, err := vwv.ValidatorWithdrawVaultContract.GetTransactionGasInfo(opts, "settleFunds")
+	if err != nil {
+		return stader.GasInfo{}, getSettleFundsError(vwv, err)
+	}
+	return gasInfo, nil
 
 }
 
+// The transaction is sent through the contract wrapper so a failed gas estimate keeps its revert data
 func SettleFunds(executionClient stader.ExecutionClient, validatorWithdrawVaultAddress common.Address, opts *bind.TransactOpts) (*types.Transaction, error) {
 	vwv, err := stader.NewValidatorWithdrawVaultFactory(executionClient, validatorWithdrawVaultAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	return vwv.ValidatorWithdrawVault.SettleFunds(opts)
+	tx, err := vwv.ValidatorWithdrawVaultContract.Transact(opts, "settleFunds")
+	if err != nil {
+		return nil, getSettleFundsError(vwv, err)
+	}
+	return tx, nil
+}
+
+// Replace a CallerNotNodeRegistryContract revert with ErrCallerNotNodeRegistryContract
+func getSettleFundsError(vwv *stader.ValidatorWithdrawVaultContractManager, err error) error {
+	if name, ok := vwv.ValidatorWithdrawVaultContract.GetRevertErrorName(err); ok && name == "CallerNotNodeRegistryContract" {
+		return fmt.Errorf("%w (%s)", ErrCallerNotNodeRegistryContract, err.Error())
+	}
+	return err
 }
 
 func EstimateDistributeRewards(executionClient stader.ExecutionClient, validatorWithdrawVaultAddress common.Address, opts *bind.TransactOpts) (stader.GasInfo, error) {
@@ -123,3 +145,39 @@ func GetTotalQueuedValidators(pnr *stader.PermissionlessNodeRegistryContractMana
 func GetInputKeyLimitCount(pnr *stader.PermissionlessNodeRegistryContractManager, opts *bind.CallOpts) (uint16, error) {
 	return pnr.PermissionlessNodeRegistry.InputKeyCountLimit(opts)
 }
+
+// Get the DistributedRewards events emitted by any of the given validator withdraw vaults in a block range
+func GetDistributedRewardsEvents(executionClient stader.ExecutionClient, vaultAddresses []common.Address, fromBlock uint64, toBlock uint64) ([]contracts.ValidatorWithdrawVaultDistributedRewards, error) {
+	vwv, err := stader.NewValidatorWithdrawVaultFactory(executionClient, common.Address{})
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := vwv.ValidatorWithdrawVaultContract.FilterEvents(vaultAddresses, "DistributedRewards", contracts.ValidatorWithdrawVaultDistributedRewards{}, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	distributions := make([]contracts.ValidatorWithdrawVaultDistributedRewards, len(events))
+	for i, event := range events {
+		distributions[i] = event.(contracts.ValidatorWithdrawVaultDistributedRewards)
+	}
+	return distributions, nil
+}
+
+// Get the SettledFunds events emitted by any of the given validator withdraw vaults in a block range
+func GetSettledFundsEvents(executionClient stader.ExecutionClient, vaultAddresses []common.Address, fromBlock uint64, toBlock uint64) ([]contracts.ValidatorWithdrawVaultSettledFunds, error) {
+	vwv, err := stader.NewValidatorWithdrawVaultFactory(executionClient, common.Address{})
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := vwv.ValidatorWithdrawVaultContract.FilterEvents(vaultAddresses, "SettledFunds", contracts.ValidatorWithdrawVaultSettledFunds{}, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	settlements := make([]contracts.ValidatorWithdrawVaultSettledFunds, len(events))
+	for i, event := range events {
+		settlements[i] = event.(contracts.ValidatorWithdrawVaultSettledFunds)
+	}
+	return settlements, nil
+}
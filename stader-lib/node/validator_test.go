@@ -0,0 +1,85 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+)
+
+// The simulated backend with the ExecutionClient methods it lacks
+type simulatedClient struct {
+	*backends.SimulatedBackend
+}
+
+func (c *simulatedClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.Blockchain().CurrentBlock().NumberU64(), nil
+}
+
+func (c *simulatedClient) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	return nil, nil
+}
+
+var vaultAddress = common.HexToAddress("0x0000000000000000000000000000000000005e77")
+
+// A vault that reverts with the given 4-byte error selector on every call
+func revertingVaultCode(selector []byte) []byte {
+	code := append([]byte{0x63}, selector...) // PUSH4 selector
+	return append(code,
+		0x60, 0xe0, 0x1b, // SHL 224
+		0x60, 0x00, 0x52, // MSTORE(0)
+		0x60, 0x04, 0x60, 0x00, 0xfd, // REVERT(0, 4)
+	)
+}
+
+func newSettleFundsTest(t *testing.T, errorName string) (*simulatedClient, *bind.TransactOpts) {
+	vaultAbi, err := contracts.ValidatorWithdrawVaultMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selector := vaultAbi.Errors[errorName].ID
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{
+		opts.From:    {Balance: big.NewInt(1e18)},
+		vaultAddress: {Code: revertingVaultCode(selector[:4]), Balance: big.NewInt(0)},
+	}, 10000000)
+	t.Cleanup(func() { sim.Close() })
+	return &simulatedClient{SimulatedBackend: sim}, opts
+}
+
+func TestSettleFundsCallerNotNodeRegistry(t *testing.T) {
+	client, opts := newSettleFundsTest(t, "CallerNotNodeRegistryContract")
+
+	if _, err := EstimateSettleFunds(client, vaultAddress, opts); !errors.Is(err, ErrCallerNotNodeRegistryContract) {
+		t.Errorf("estimating settleFunds returned %v, want ErrCallerNotNodeRegistryContract", err)
+	}
+	if _, err := SettleFunds(client, vaultAddress, opts); !errors.Is(err, ErrCallerNotNodeRegistryContract) {
+		t.Errorf("sending settleFunds returned %v, want ErrCallerNotNodeRegistryContract", err)
+	}
+}
+
+func TestSettleFundsOtherRevert(t *testing.T) {
+	client, opts := newSettleFundsTest(t, "TransferFailed")
+
+	_, err := EstimateSettleFunds(client, vaultAddress, opts)
+	if err == nil || errors.Is(err, ErrCallerNotNodeRegistryContract) {
+		t.Errorf("estimating settleFunds returned %v, want the original revert", err)
+	}
+}
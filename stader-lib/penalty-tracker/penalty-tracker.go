@@ -1,12 +1,52 @@
 package penalty_tracker
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/stader-labs/stader-node/stader-lib/stader"
 	"github.com/stader-labs/stader-node/stader-lib/types"
-	"math/big"
 )
 
+// The penalty contract keys its per-validator strike data by sha256(pubkey ++ bytes16(0))
+func GetPubkeyRoot(validatorPubKey types.ValidatorPubkey) [32]byte {
+	data := make([]byte, 0, types.ValidatorPubkeyLength+16)
+	data = append(data, validatorPubKey.Bytes()...)
+	data = append(data, make([]byte, 16)...)
+	return sha256.Sum256(data)
+}
+
 func GetCumulativeValidatorPenalty(pt *stader.PenaltyTrackerContractManager, validatorPubKey types.ValidatorPubkey, opts *bind.CallOpts) (*big.Int, error) {
 	return pt.Penalty.TotalPenaltyAmount(opts, validatorPubKey.Bytes())
 }
+
+func GetMissedAttestationPenalty(pt *stader.PenaltyTrackerContractManager, validatorPubKey types.ValidatorPubkey, opts *bind.CallOpts) (*big.Int, error) {
+	return pt.Penalty.CalculateMissedAttestationPenalty(opts, GetPubkeyRoot(validatorPubKey))
+}
+
+// calculateMEVTheftPenalty isn't marked as view in the ABI, but it doesn't write any state so it can be eth_call'd
+func GetMevTheftPenalty(pt *stader.PenaltyTrackerContractManager, validatorPubKey types.ValidatorPubkey, opts *bind.CallOpts) (*big.Int, error) {
+	penalty := new(*big.Int)
+	if err := pt.PenaltyContract.Call(opts, penalty, "calculateMEVTheftPenalty", GetPubkeyRoot(validatorPubKey)); err != nil {
+		return nil, fmt.Errorf("Could not get MEV theft penalty: %w", err)
+	}
+	return *penalty, nil
+}
+
+func GetAdditionalPenalty(pt *stader.PenaltyTrackerContractManager, validatorPubKey types.ValidatorPubkey, opts *bind.CallOpts) (*big.Int, error) {
+	return pt.Penalty.GetAdditionalPenaltyAmount(opts, validatorPubKey.Bytes())
+}
+
+func GetMevTheftPenaltyPerStrike(pt *stader.PenaltyTrackerContractManager, opts *bind.CallOpts) (*big.Int, error) {
+	return pt.Penalty.MevTheftPenaltyPerStrike(opts)
+}
+
+func GetMissedAttestationPenaltyPerStrike(pt *stader.PenaltyTrackerContractManager, opts *bind.CallOpts) (*big.Int, error) {
+	return pt.Penalty.MissedAttestationPenaltyPerStrike(opts)
+}
+
+func GetValidatorExitPenaltyThreshold(pt *stader.PenaltyTrackerContractManager, opts *bind.CallOpts) (*big.Int, error) {
+	return pt.Penalty.ValidatorExitPenaltyThreshold(opts)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
 	"github.com/stader-labs/stader-node/stader-lib/stader"
 	types2 "github.com/stader-labs/stader-node/stader-lib/types"
 	"math/big"
@@ -98,3 +99,42 @@ func GetPoolThreshold(sdc *stader.SdCollateralContractManager, poolType uint8, o
 
 	return poolThreshold, nil
 }
+
+// Get the SDDeposited events of the given operator in a block range
+func GetSdDepositedEvents(sdc *stader.SdCollateralContractManager, operatorAddress common.Address, fromBlock uint64, toBlock uint64) ([]contracts.SdCollateralSDDeposited, error) {
+	events, err := sdc.SdCollateralContract.FilterEvents([]common.Address{*sdc.SdCollateralContract.Address}, "SDDeposited", contracts.SdCollateralSDDeposited{}, fromBlock, toBlock, []interface{}{operatorAddress})
+	if err != nil {
+		return nil, err
+	}
+	deposits := make([]contracts.SdCollateralSDDeposited, len(events))
+	for i, event := range events {
+		deposits[i] = event.(contracts.SdCollateralSDDeposited)
+	}
+	return deposits, nil
+}
+
+// Get the SDWithdrawn events of the given operator in a block range
+func GetSdWithdrawnEvents(sdc *stader.SdCollateralContractManager, operatorAddress common.Address, fromBlock uint64, toBlock uint64) ([]contracts.SdCollateralSDWithdrawn, error) {
+	events, err := sdc.SdCollateralContract.FilterEvents([]common.Address{*sdc.SdCollateralContract.Address}, "SDWithdrawn", contracts.SdCollateralSDWithdrawn{}, fromBlock, toBlock, []interface{}{operatorAddress})
+	if err != nil {
+		return nil, err
+	}
+	withdrawals := make([]contracts.SdCollateralSDWithdrawn, len(events))
+	for i, event := range events {
+		withdrawals[i] = event.(contracts.SdCollateralSDWithdrawn)
+	}
+	return withdrawals, nil
+}
+
+// Get the SDSlashed events of the given operator in a block range
+func GetSdSlashedEvents(sdc *stader.SdCollateralContractManager, operatorAddress common.Address, fromBlock uint64, toBlock uint64) ([]contracts.SdCollateralSDSlashed, error) {
+	events, err := sdc.SdCollateralContract.FilterEvents([]common.Address{*sdc.SdCollateralContract.Address}, "SDSlashed", contracts.SdCollateralSDSlashed{}, fromBlock, toBlock, []interface{}{operatorAddress})
+	if err != nil {
+		return nil, err
+	}
+	slashes := make([]contracts.SdCollateralSDSlashed, len(events))
+	for i, event := range events {
+		slashes[i] = event.(contracts.SdCollateralSDSlashed)
+	}
+	return slashes, nil
+}
@@ -0,0 +1,29 @@
+package socializing_pool
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Compute an operator's rewards leaf the way the socializing pool contract does:
+// keccak256(abi.encodePacked(operator, amountSd, amountEth))
+func ComputeRewardsLeaf(operatorAddress common.Address, amountSd *big.Int, amountEth *big.Int) [32]byte {
+	return crypto.Keccak256Hash(operatorAddress.Bytes(), math.U256Bytes(new(big.Int).Set(amountSd)), math.U256Bytes(new(big.Int).Set(amountEth)))
+}
+
+// Compute the merkle root a proof leads to from a leaf, hashing each pair in sorted order like OpenZeppelin's MerkleProof
+func ComputeMerkleRoot(leaf [32]byte, merkleProof [][32]byte) [32]byte {
+	computedHash := leaf
+	for _, proofElement := range merkleProof {
+		if bytes.Compare(computedHash[:], proofElement[:]) <= 0 {
+			computedHash = crypto.Keccak256Hash(computedHash[:], proofElement[:])
+		} else {
+			computedHash = crypto.Keccak256Hash(proofElement[:], computedHash[:])
+		}
+	}
+	return computedHash
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
 	"github.com/stader-labs/stader-node/stader-lib/stader"
 	types2 "github.com/stader-labs/stader-node/stader-lib/types"
 	"math/big"
@@ -45,3 +46,29 @@ func IsSocializingPoolPaused(sp *stader.SocializingPoolContractManager, opts *bi
 func VerifyProof(sp *stader.SocializingPoolContractManager, operatorAddress common.Address, index *big.Int, amountSd *big.Int, amountEth *big.Int, merkleProof [][32]byte, opts *bind.CallOpts) (bool, error) {
 	return sp.SocializingPool.VerifyProof(opts, index, operatorAddress, amountSd, amountEth, merkleProof)
 }
+
+func GetCycleMerkleRoot(sp *stader.SocializingPoolContractManager, index *big.Int, opts *bind.CallOpts) ([32]byte, error) {
+	rewardsData, err := sp.SocializingPool.RewardsDataMap(opts, index)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return rewardsData.MerkleRoot, nil
+}
+
+// Get the OperatorRewardsClaimed events paid out to any of the given recipients in a block range
+func GetOperatorRewardsClaimedEvents(sp *stader.SocializingPoolContractManager, recipients []common.Address, fromBlock uint64, toBlock uint64) ([]contracts.SocializingPoolOperatorRewardsClaimed, error) {
+	recipientQuery := make([]interface{}, len(recipients))
+	for i, recipient := range recipients {
+		recipientQuery[i] = recipient
+	}
+
+	events, err := sp.SocializingPoolContract.FilterEvents([]common.Address{*sp.SocializingPoolContract.Address}, "OperatorRewardsClaimed", contracts.SocializingPoolOperatorRewardsClaimed{}, fromBlock, toBlock, recipientQuery)
+	if err != nil {
+		return nil, err
+	}
+	claims := make([]contracts.SocializingPoolOperatorRewardsClaimed, len(events))
+	for i, event := range events {
+		claims[i] = event.(contracts.SocializingPoolOperatorRewardsClaimed)
+	}
+	return claims, nil
+}
@@ -31,7 +31,9 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // Transaction settings
@@ -177,6 +179,33 @@ func (c *Contract) estimateGasLimit(opts *bind.TransactOpts, input []byte) (uint
 
 }
 
+// Get the name of the contract's custom error that a call or gas estimate reverted with, if it was one
+func (c *Contract) GetRevertErrorName(err error) (string, bool) {
+
+	// The Execution client returns the revert data alongside the error
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return "", false
+	}
+	data, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return "", false
+	}
+	revert, decodeErr := hexutil.Decode(data)
+	if decodeErr != nil || len(revert) < 4 {
+		return "", false
+	}
+
+	// Custom errors start with their selector, like function calls
+	for name, abiError := range c.ABI.Errors {
+		if bytes.Equal(abiError.ID[:4], revert[:4]) {
+			return name, true
+		}
+	}
+	return "", false
+
+}
+
 // Wait for a transaction to be mined and get a tx receipt
 func (c *Contract) getTransactionReceipt(tx *types.Transaction) (*types.Receipt, error) {
 
@@ -240,3 +269,54 @@ func (c *Contract) GetTransactionEvents(txReceipt *types.Receipt, eventName stri
 	return events, nil
 
 }
+
+// Get the events emitted in a block range by any of the given contracts, which must share this contract's ABI.
+// query filters on the event's indexed arguments in order, a nil entry matches anything.
+func (c *Contract) FilterEvents(addresses []common.Address, eventName string, eventPrototype interface{}, fromBlock uint64, toBlock uint64, query ...[]interface{}) ([]interface{}, error) {
+
+	// Get event type
+	eventType := reflect.TypeOf(eventPrototype)
+	if eventType.Kind() != reflect.Struct {
+		return nil, errors.New("Invalid event type")
+	}
+
+	// Get ABI event
+	abiEvent, ok := c.ABI.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("Event '%s' does not exist on contract", eventName)
+	}
+	topics, err := abi.MakeTopics(append([][]interface{}{{abiEvent.ID}}, query...)...)
+	if err != nil {
+		return nil, fmt.Errorf("Could not build the topics for event '%s': %w", eventName, err)
+	}
+
+	logs, err := c.Client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: addresses,
+		Topics:    topics,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Could not get '%s' events: %w", eventName, err)
+	}
+
+	// Unpack the events
+	events := make([]interface{}, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		event := reflect.New(eventType)
+		if err := c.Contract.UnpackLog(event.Interface(), eventName, log); err != nil {
+			return nil, fmt.Errorf("Could not unpack event data: %w", err)
+		}
+		// The raw log isn't part of the ABI, so it has to be set by hand like the generated bindings do
+		if raw := reflect.Indirect(event).FieldByName("Raw"); raw.IsValid() && raw.CanSet() {
+			raw.Set(reflect.ValueOf(log))
+		}
+		events = append(events, reflect.Indirect(event).Interface())
+	}
+
+	return events, nil
+
+}
@@ -0,0 +1,280 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package stader
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Multicall3 is deployed at the same address on mainnet and the public testnets
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// Multicall settings
+const (
+	DefaultMulticallBatchSize int = 500
+)
+
+const multicall3Abi = `[
+	{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"},
+	{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"getEthBalance","outputs":[{"internalType":"uint256","name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"}
+]`
+
+// The subset of the execution client a MultiCaller needs.
+// Both ExecutionClient and go-ethereum's simulated backend satisfy it.
+type MulticallClient interface {
+	bind.ContractCaller
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+type multicall3Call struct {
+	Target       common.Address `json:"target"`
+	AllowFailure bool           `json:"allowFailure"`
+	CallData     []byte         `json:"callData"`
+}
+
+type multicall3Result struct {
+	Success    bool   `json:"success"`
+	ReturnData []byte `json:"returnData"`
+}
+
+// A single queued view call
+type multicallEntry struct {
+	contract *Contract
+	method   string
+	params   []interface{}
+	input    []byte
+	result   interface{}
+
+	// Set for ETH balance lookups instead of contract calls
+	balanceOf *common.Address
+}
+
+// MultiCaller batches view calls into Multicall3 aggregate3 calls.
+// If the multicall contract isn't deployed on the chain, the calls are made one by one instead.
+type MultiCaller struct {
+	Client    MulticallClient
+	Address   common.Address
+	BatchSize int
+
+	abi   *abi.ABI
+	calls []multicallEntry
+}
+
+// Create a new MultiCaller for the multicall contract at the given address
+func NewMultiCaller(client MulticallClient, multicallAddress common.Address) (*MultiCaller, error) {
+	multicallAbi, err := abi.JSON(strings.NewReader(multicall3Abi))
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse multicall ABI: %w", err)
+	}
+
+	return &MultiCaller{
+		Client:    client,
+		Address:   multicallAddress,
+		BatchSize: DefaultMulticallBatchSize,
+		abi:       &multicallAbi,
+	}, nil
+}
+
+// Queue a contract view call; result is filled in the same way as Contract.Call once Execute returns
+func (mc *MultiCaller) AddCall(contract *Contract, result interface{}, method string, params ...interface{}) error {
+	input, err := contract.ABI.Pack(method, params...)
+	if err != nil {
+		return fmt.Errorf("Could not encode input data for %s: %w", method, err)
+	}
+
+	mc.calls = append(mc.calls, multicallEntry{
+		contract: contract,
+		method:   method,
+		params:   params,
+		input:    input,
+		result:   result,
+	})
+	return nil
+}
+
+// Queue an ETH balance lookup; result is set once Execute returns
+func (mc *MultiCaller) AddEthBalance(address common.Address, result **big.Int) error {
+	input, err := mc.abi.Pack("getEthBalance", address)
+	if err != nil {
+		return fmt.Errorf("Could not encode input data for getEthBalance: %w", err)
+	}
+
+	mc.calls = append(mc.calls, multicallEntry{
+		method:    "getEthBalance",
+		input:     input,
+		result:    result,
+		balanceOf: &address,
+	})
+	return nil
+}
+
+// Get the number of queued calls
+func (mc *MultiCaller) Len() int {
+	return len(mc.calls)
+}
+
+// Check if the multicall contract is deployed at the block in opts
+func (mc *MultiCaller) IsSupported(opts *bind.CallOpts) (bool, error) {
+	code, err := mc.Client.CodeAt(callContext(opts), mc.Address, callBlockNumber(opts))
+	if err != nil {
+		return false, fmt.Errorf("Could not get multicall contract code: %w", err)
+	}
+	return len(code) > 0, nil
+}
+
+// Run all of the queued calls and fill in their results.
+// The queue is cleared afterwards, so the MultiCaller can be reused.
+func (mc *MultiCaller) Execute(opts *bind.CallOpts) error {
+	calls := mc.calls
+	mc.calls = nil
+	if len(calls) == 0 {
+		return nil
+	}
+
+	supported, err := mc.IsSupported(opts)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return mc.executeIndividually(calls, opts)
+	}
+
+	batchSize := mc.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultMulticallBatchSize
+	}
+	for start := 0; start < len(calls); start += batchSize {
+		end := start + batchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+		if err := mc.executeBatch(calls[start:end], opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run one batch of calls through aggregate3
+func (mc *MultiCaller) executeBatch(calls []multicallEntry, opts *bind.CallOpts) error {
+	aggregateCalls := make([]multicall3Call, len(calls))
+	for i, call := range calls {
+		target := mc.Address
+		if call.contract != nil {
+			target = *call.contract.Address
+		}
+		aggregateCalls[i] = multicall3Call{
+			Target:       target,
+			AllowFailure: true,
+			CallData:     call.input,
+		}
+	}
+
+	input, err := mc.abi.Pack("aggregate3", aggregateCalls)
+	if err != nil {
+		return fmt.Errorf("Could not encode multicall input data: %w", err)
+	}
+
+	msg := ethereum.CallMsg{
+		To:   &mc.Address,
+		Data: input,
+	}
+	if opts != nil {
+		msg.From = opts.From
+	}
+	output, err := mc.Client.CallContract(callContext(opts), msg, callBlockNumber(opts))
+	if err != nil {
+		return fmt.Errorf("Could not run multicall: %w", err)
+	}
+
+	var results []multicall3Result
+	if err := mc.abi.UnpackIntoInterface(&results, "aggregate3", output); err != nil {
+		return fmt.Errorf("Could not decode multicall results: %w", err)
+	}
+	if len(results) != len(calls) {
+		return fmt.Errorf("multicall returned %d results for %d calls", len(results), len(calls))
+	}
+
+	for i, call := range calls {
+		if !results[i].Success {
+			return fmt.Errorf("multicall: call to %s failed", call.description())
+		}
+
+		unpackAbi := mc.abi
+		if call.contract != nil {
+			unpackAbi = call.contract.ABI
+		}
+		if err := unpackAbi.UnpackIntoInterface(call.result, call.method, results[i].ReturnData); err != nil {
+			return fmt.Errorf("Could not decode result of %s: %w", call.description(), err)
+		}
+	}
+
+	return nil
+}
+
+// Fallback for chains without the multicall contract
+func (mc *MultiCaller) executeIndividually(calls []multicallEntry, opts *bind.CallOpts) error {
+	for _, call := range calls {
+		if call.balanceOf != nil {
+			balance, err := mc.Client.BalanceAt(callContext(opts), *call.balanceOf, callBlockNumber(opts))
+			if err != nil {
+				return fmt.Errorf("Could not get ETH balance of %s: %w", call.balanceOf.Hex(), err)
+			}
+			*call.result.(**big.Int) = balance
+			continue
+		}
+
+		if err := call.contract.Call(opts, call.result, call.method, call.params...); err != nil {
+			return fmt.Errorf("Could not run %s: %w", call.description(), err)
+		}
+	}
+
+	return nil
+}
+
+func (e multicallEntry) description() string {
+	if e.balanceOf != nil {
+		return fmt.Sprintf("getEthBalance(%s)", e.balanceOf.Hex())
+	}
+	return fmt.Sprintf("%s on %s", e.method, e.contract.Address.Hex())
+}
+
+func callContext(opts *bind.CallOpts) context.Context {
+	if opts != nil && opts.Context != nil {
+		return opts.Context
+	}
+	return context.Background()
+}
+
+func callBlockNumber(opts *bind.CallOpts) *big.Int {
+	if opts != nil {
+		return opts.BlockNumber
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package stader
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+// A contract that returns twice its uint256 argument for any selector, and reverts if the argument is 0
+const doublerAbi = `[{"inputs":[{"internalType":"uint256","name":"value","type":"uint256"}],"name":"double","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+var doublerCode = assemble(
+	push1(4), op(0x35), op(0x80), op(0x15), pushLabel("revert"), op(0x57), // CALLDATALOAD(4), DUP1, ISZERO, JUMPI
+	push1(1), op(0x1b), push1(0), op(0x52), // SHL 1, MSTORE(0)
+	push1(0x20), push1(0), op(0xf3), // RETURN(0, 32)
+	label("revert"), push1(0), op(0x80), op(0xfd), // REVERT(0, 0)
+)
+
+// Enough of Multicall3 for the MultiCaller: aggregate3 for any selector other than getEthBalance's.
+// Scratch variables are kept in memory: [0x00] the call count, [0x20] the loop index, [0x40] the end of the
+// results relative to their heads, [0x60] the calldata position of the call heads. The results are built at 0x1000.
+var multicall3Code = assemble(
+	push1(0), op(0x35), push1(0xe0), op(0x1c), // CALLDATALOAD(0) >> 224
+	[]byte{0x63, 0x4d, 0x23, 0x01, 0xcc}, op(0x14), pushLabel("balance"), op(0x57), // == getEthBalance, JUMPI
+
+	push1(4), op(0x35), push1(4), op(0x01), // array position = CALLDATALOAD(4) + 4
+	op(0x80), op(0x35), push1(0x00), op(0x52), // [0x00] = count
+	push1(0x20), op(0x01), push1(0x60), op(0x52), // [0x60] = array position + 32
+	push1(0x00), op(0x51), push1(5), op(0x1b), push1(0x40), op(0x52), // [0x40] = count * 32
+	push1(0), push1(0x20), op(0x52), // [0x20] = 0
+
+	label("loop"),
+	push1(0x00), op(0x51), push1(0x20), op(0x51), op(0x10), op(0x15), pushLabel("done"), op(0x57), // if !(i < count) done
+	push1(0x40), op(0x51), push1(0x20), op(0x51), push1(5), op(0x1b), push2(0x1040), op(0x01), op(0x52), // result head i = end
+	push1(0x20), op(0x51), push1(5), op(0x1b), push1(0x60), op(0x51), op(0x01), op(0x35), push1(0x60), op(0x51), op(0x01), // call position
+	op(0x80), push1(0x40), op(0x01), op(0x35), op(0x81), op(0x01), // call data position
+	push1(0x40), op(0x51), push2(0x1040), op(0x01), // result position
+	op(0x81), op(0x35), // call data length
+	op(0x80), op(0x83), push1(0x20), op(0x01), op(0x83), push1(0x60), op(0x01), op(0x37), // copy the call data into the result's data
+	push1(0), push1(0), op(0x82), op(0x84), push1(0x60), op(0x01), push1(0), op(0x88), op(0x35), op(0x5a), op(0xf1), // CALL
+	op(0x82), op(0x52), op(0x50), // result success
+	push1(0x40), op(0x81), push1(0x20), op(0x01), op(0x52), // result data offset
+	op(0x3d), op(0x81), push1(0x40), op(0x01), op(0x52), // result data length
+	op(0x3d), push1(0), op(0x82), push1(0x60), op(0x01), op(0x3e), // result data
+	op(0x50), op(0x50), op(0x50),
+	op(0x3d), push1(0x1f), op(0x01), push1(5), op(0x1c), push1(5), op(0x1b), push1(0x60), op(0x01), push1(0x40), op(0x51), op(0x01), push1(0x40), op(0x52), // end += 96 + padded length
+	push1(0x20), op(0x51), push1(1), op(0x01), push1(0x20), op(0x52), // i++
+	pushLabel("loop"), op(0x56),
+
+	label("done"),
+	push1(0x20), push2(0x1000), op(0x52), // offset of the results array
+	push1(0x00), op(0x51), push2(0x1020), op(0x52), // results count
+	push1(0x40), op(0x51), push1(0x40), op(0x01), push2(0x1000), op(0xf3), // RETURN
+
+	label("balance"),
+	push1(4), op(0x35), op(0x31), push1(0), op(0x52), push1(0x20), push1(0), op(0xf3), // RETURN(BALANCE(CALLDATALOAD(4)))
+)
+
+// A bytecode fragment: plain bytes, a jump destination or a jump to one
+type asmPart struct {
+	code     []byte
+	label    string
+	labelRef string
+}
+
+func op(code byte) asmPart     { return asmPart{code: []byte{code}} }
+func push1(value byte) asmPart { return asmPart{code: []byte{0x60, value}} }
+func push2(value uint16) asmPart {
+	return asmPart{code: []byte{0x61, byte(value >> 8), byte(value)}}
+}
+func label(name string) asmPart     { return asmPart{label: name} }
+func pushLabel(name string) asmPart { return asmPart{labelRef: name} }
+
+// Lay out the fragments, resolving labels to 2-byte pushes of their JUMPDESTs
+func assemble(parts ...interface{}) []byte {
+	flat := []asmPart{}
+	for _, part := range parts {
+		switch p := part.(type) {
+		case asmPart:
+			flat = append(flat, p)
+		case []byte:
+			flat = append(flat, asmPart{code: p})
+		}
+	}
+
+	labels := map[string]uint16{}
+	position := 0
+	for _, part := range flat {
+		switch {
+		case part.label != "":
+			labels[part.label] = uint16(position)
+			position++
+		case part.labelRef != "":
+			position += 3
+		default:
+			position += len(part.code)
+		}
+	}
+
+	code := []byte{}
+	for _, part := range flat {
+		switch {
+		case part.label != "":
+			code = append(code, 0x5b)
+		case part.labelRef != "":
+			target := make([]byte, 2)
+			binary.BigEndian.PutUint16(target, labels[part.labelRef])
+			code = append(code, 0x61, target[0], target[1])
+		default:
+			code = append(code, part.code...)
+		}
+	}
+	return code
+}
+
+// Counts the calls made through the simulated backend
+type countingClient struct {
+	*backends.SimulatedBackend
+	calls int
+}
+
+func (c *countingClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	return c.SimulatedBackend.CallContract(ctx, call, blockNumber)
+}
+
+var (
+	doublerAddress = common.HexToAddress("0x00000000000000000000000000000000000d0b1e")
+	holderAddress  = common.HexToAddress("0x0000000000000000000000000000000000001234")
+	holderBalance  = big.NewInt(123456789)
+)
+
+func newMulticallTestClient(t *testing.T, withMulticall bool) (*countingClient, *Contract) {
+	alloc := core.GenesisAlloc{
+		doublerAddress: {Code: doublerCode, Balance: big.NewInt(0)},
+		holderAddress:  {Balance: holderBalance},
+	}
+	if withMulticall {
+		alloc[Multicall3Address] = core.GenesisAccount{Code: multicall3Code, Balance: big.NewInt(0)}
+	}
+	sim := backends.NewSimulatedBackend(alloc, 10000000)
+	t.Cleanup(func() { sim.Close() })
+	client := &countingClient{SimulatedBackend: sim}
+
+	parsed, err := abi.JSON(strings.NewReader(doublerAbi))
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := doublerAddress
+	doubler := &Contract{
+		Contract: bind.NewBoundContract(address, parsed, client, nil, nil),
+		Address:  &address,
+		ABI:      &parsed,
+	}
+	return client, doubler
+}
+
+// Queue five doubler calls and a balance lookup
+func addTestCalls(t *testing.T, mc *MultiCaller, doubler *Contract) ([]*big.Int, **big.Int) {
+	results := make([]*big.Int, 5)
+	for i := range results {
+		if err := mc.AddCall(doubler, &results[i], "double", big.NewInt(int64(i+1))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	balance := new(*big.Int)
+	if err := mc.AddEthBalance(holderAddress, balance); err != nil {
+		t.Fatal(err)
+	}
+	return results, balance
+}
+
+func checkTestResults(t *testing.T, results []*big.Int, balance **big.Int) {
+	for i, result := range results {
+		if result == nil || result.Int64() != int64(2*(i+1)) {
+			t.Errorf("call %d returned %v, want %d", i, result, 2*(i+1))
+		}
+	}
+	if *balance == nil || (*balance).Cmp(holderBalance) != 0 {
+		t.Errorf("balance is %v, want %s", *balance, holderBalance)
+	}
+}
+
+func TestMultiCallerBatches(t *testing.T) {
+	client, doubler := newMulticallTestClient(t, true)
+	mc, err := NewMultiCaller(client, Multicall3Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc.BatchSize = 4
+
+	results, balance := addTestCalls(t, mc, doubler)
+	if err := mc.Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	checkTestResults(t, results, balance)
+	if client.calls != 2 {
+		t.Errorf("made %d calls for 6 queued calls in batches of 4, want 2", client.calls)
+	}
+	if mc.Len() != 0 {
+		t.Errorf("%d calls are still queued after Execute", mc.Len())
+	}
+}
+
+func TestMultiCallerFallback(t *testing.T) {
+	client, doubler := newMulticallTestClient(t, false)
+	mc, err := NewMultiCaller(client, Multicall3Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	supported, err := mc.IsSupported(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if supported {
+		t.Fatal("multicall is supported on a chain without the contract")
+	}
+
+	results, balance := addTestCalls(t, mc, doubler)
+	if err := mc.Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	checkTestResults(t, results, balance)
+	// The balance lookup doesn't go through CallContract
+	if client.calls != 5 {
+		t.Errorf("made %d contract calls for 5 queued calls, want 5", client.calls)
+	}
+}
+
+func TestMultiCallerFailedCall(t *testing.T) {
+	for _, withMulticall := range []bool{true, false} {
+		client, doubler := newMulticallTestClient(t, withMulticall)
+		mc, err := NewMultiCaller(client, Multicall3Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var first, second *big.Int
+		if err := mc.AddCall(doubler, &first, "double", big.NewInt(1)); err != nil {
+			t.Fatal(err)
+		}
+		if err := mc.AddCall(doubler, &second, "double", big.NewInt(0)); err != nil {
+			t.Fatal(err)
+		}
+		if err := mc.Execute(nil); err == nil {
+			t.Errorf("a reverted call didn't fail the batch (multicall deployed: %t)", withMulticall)
+		}
+	}
+}
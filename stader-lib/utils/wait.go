@@ -33,6 +33,11 @@ import (
 
 // Wait for a transaction to get mined
 func WaitForTransaction(client stader.ExecutionClient, hash common.Hash) (*types.Receipt, error) {
+	return WaitForTransactionWithContext(context.Background(), client, hash)
+}
+
+// Wait for a transaction to get mined, giving up once the context is done
+func WaitForTransactionWithContext(ctx context.Context, client stader.ExecutionClient, hash common.Hash) (*types.Receipt, error) {
 
 	var tx *types.Transaction
 	var err error
@@ -43,7 +48,7 @@ func WaitForTransaction(client stader.ExecutionClient, hash common.Hash) (*types
 			return nil, fmt.Errorf("Transaction not found after 30 seconds.")
 		}
 
-		tx, _, err = client.TransactionByHash(context.Background(), hash)
+		tx, _, err = client.TransactionByHash(ctx, hash)
 		if err != nil {
 			if err.Error() == "not found" {
 				time.Sleep(1 * time.Second)
@@ -56,7 +61,7 @@ func WaitForTransaction(client stader.ExecutionClient, hash common.Hash) (*types
 	}
 
 	// Wait for transaction to be mined
-	txReceipt, err := bind.WaitMined(context.Background(), client, tx)
+	txReceipt, err := bind.WaitMined(ctx, client, tx)
 	if err != nil {
 		return nil, err
 	}
@@ -1,11 +1,16 @@
 package node
 
 import (
+	"fmt"
 	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
 	"github.com/stader-labs/stader-node/shared/types/api"
 	"github.com/stader-labs/stader-node/shared/utils/eth1"
+	stader_utils "github.com/stader-labs/stader-node/shared/utils/stader"
 	string_utils "github.com/stader-labs/stader-node/shared/utils/string-utils"
 	socializing_pool "github.com/stader-labs/stader-node/stader-lib/socializing-pool"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
 	"github.com/urfave/cli"
 	"math/big"
 )
@@ -129,9 +134,29 @@ func canClaimSpRewards(c *cli.Context) (*api.CanClaimSpRewardsResponse, error) {
 		}
 	}
 
+	// unclaimed cycles whose downloaded proofs didn't match the on-chain root
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	quarantinedCycles, err := cfg.StaderNode.GetQuarantinedCycles(true)
+	if err != nil {
+		return nil, err
+	}
+	unclaimedQuarantinedCycles := []int64{}
+	for _, cycle := range quarantinedCycles {
+		for _, unclaimedCycle := range unclaimedCycles {
+			if unclaimedCycle.Int64() == cycle {
+				unclaimedQuarantinedCycles = append(unclaimedQuarantinedCycles, cycle)
+				break
+			}
+		}
+	}
+
 	response.ClaimedCycles = claimedCycles
 	response.UnclaimedCycles = unclaimedCycles
 	response.CyclesToDownload = cyclesToDownload
+	response.QuarantinedCycles = unclaimedQuarantinedCycles
 
 	return &response, nil
 }
@@ -156,6 +181,9 @@ func estimateSpRewardsGas(c *cli.Context, stringifiedCycles string) (*api.Estima
 		return nil, err
 	}
 
+	if err := verifyClaimCycles(cfg, sp, w, cycles); err != nil {
+		return nil, err
+	}
 	amountSd, amountEth, merkleProofs, err := cfg.StaderNode.GetClaimData(cycles)
 	if err != nil {
 		return nil, err
@@ -197,6 +225,9 @@ func claimSpRewards(c *cli.Context, stringifiedCycles string) (*api.ClaimSpRewar
 	}
 
 	response := api.ClaimSpRewardsResponse{}
+	if err := verifyClaimCycles(cfg, sp, w, cycles); err != nil {
+		return nil, err
+	}
 	amountSd, amountEth, merkleProofs, err := cfg.StaderNode.GetClaimData(cycles)
 	if err != nil {
 		return nil, err
@@ -216,3 +247,25 @@ func claimSpRewards(c *cli.Context, stringifiedCycles string) (*api.ClaimSpRewar
 
 	return &response, nil
 }
+
+// Check the stored proof of each cycle against the on-chain root, so an invalid proof never reaches a claim transaction.
+// This also covers proofs that were downloaded before they were verified on download.
+func verifyClaimCycles(cfg *config.StaderConfig, sp *stader.SocializingPoolContractManager, w *wallet.Wallet, cycles []*big.Int) error {
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return err
+	}
+	for _, cycle := range cycles {
+		cycleMerkleProof, exists, err := cfg.StaderNode.ReadCycleCache(cycle.Int64())
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("merkle proof for cycle %d has not been downloaded", cycle.Int64())
+		}
+		if err := stader_utils.VerifyCycleMerkleProof(sp, nodeAccount.Address, &cycleMerkleProof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
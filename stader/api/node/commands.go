@@ -316,6 +316,131 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:      "penalties",
+				Usage:     "Get the penalty breakdown of the node's validators and their force exit history",
+				UsageText: "stader-cli api node penalties",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getPenalties(c))
+					return nil
+
+				},
+			},
+
+			{
+				Name:      "ledger",
+				Usage:     "Update the node's income ledger and get its records between two unix timestamps",
+				UsageText: "stader-cli api node ledger from to",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 2); err != nil {
+						return err
+					}
+					from, err := cliutils.ValidateUint("from", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+					to, err := cliutils.ValidateUint("to", c.Args().Get(1))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getLedger(c, from, to))
+					return nil
+
+				},
+			},
+
+			{
+				Name:      "tx-list",
+				Usage:     "List the transactions the node has sent and whether any are stuck",
+				UsageText: "stader-cli api node tx-list",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getTransactions(c))
+					return nil
+
+				},
+			},
+			{
+				Name:      "can-tx-replace",
+				Usage:     "Check whether a pending transaction can be sped up or cancelled, and get the fees its replacement would pay",
+				UsageText: "stader-cli api node can-tx-replace tx-hash",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					hash, err := cliutils.ValidateTxHash("tx-hash", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(canReplaceTransaction(c, hash))
+					return nil
+
+				},
+			},
+			{
+				Name:      "tx-speedup",
+				Usage:     "Resend a pending transaction with higher fees",
+				UsageText: "stader-cli api node tx-speedup tx-hash",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					hash, err := cliutils.ValidateTxHash("tx-hash", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(replaceTransaction(c, hash, false))
+					return nil
+
+				},
+			},
+			{
+				Name:      "tx-cancel",
+				Usage:     "Cancel a pending transaction by replacing it with a 0 ETH transfer to the node account",
+				UsageText: "stader-cli api node tx-cancel tx-hash",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					hash, err := cliutils.ValidateTxHash("tx-hash", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(replaceTransaction(c, hash, true))
+					return nil
+
+				},
+			},
+
 			{
 				Name:      "get-contracts-info",
 				Usage:     "Get information about the deposit contract and stader contract on the current network",
@@ -354,6 +479,42 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:      "sign-transactions",
+				Usage:     "Signs transactions exported with --export-unsigned-tx with the node's private key",
+				UsageText: "stader-cli api node sign-transactions unsigned-transactions-json",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(signTransactions(c, c.Args().Get(0)))
+					return nil
+
+				},
+			},
+
+			{
+				Name:      "broadcast-transactions",
+				Usage:     "Broadcasts transactions that were signed on an offline machine",
+				UsageText: "stader-cli api node broadcast-transactions signed-transactions-json",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(broadcastTransactions(c, c.Args().Get(0)))
+					return nil
+
+				},
+			},
+
 			{
 				Name:      "sign-message",
 				Usage:     "Signs an arbitrary message with the node's private key.",
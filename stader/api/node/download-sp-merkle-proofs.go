@@ -1,9 +1,7 @@
 package node
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/mitchellh/go-homedir"
+	"errors"
 	"github.com/stader-labs/stader-node/shared/services"
 	"github.com/stader-labs/stader-node/shared/types/api"
 	"github.com/stader-labs/stader-node/shared/utils/stader"
@@ -72,6 +70,10 @@ func downloadSpMerkleProofs(c *cli.Context) (*api.DownloadSpMerkleProofsResponse
 	if err != nil {
 		return nil, err
 	}
+	sp, err := services.GetSocializingPoolContract(c)
+	if err != nil {
+		return nil, err
+	}
 	nodeAccount, err := w.GetNodeAccount()
 	if err != nil {
 		return nil, err
@@ -85,14 +87,11 @@ func downloadSpMerkleProofs(c *cli.Context) (*api.DownloadSpMerkleProofsResponse
 	}
 
 	downloadedCycles := []int64{}
+	quarantinedCycles := []int64{}
 
 	for _, cycleMerkleProof := range allMerkleProofs {
 
 		cycleMerkleProofFile := cfg.StaderNode.GetSpRewardCyclePath(cycleMerkleProof.Cycle, true)
-		absolutePathOfProofFile, err := homedir.Expand(cycleMerkleProofFile)
-		if err != nil {
-			return nil, err
-		}
 
 		// proof has already been downloaded
 		_, err = os.Stat(cycleMerkleProofFile)
@@ -103,21 +102,22 @@ func downloadSpMerkleProofs(c *cli.Context) (*api.DownloadSpMerkleProofsResponse
 			continue
 		}
 
-		file, err := os.Create(absolutePathOfProofFile)
-		if err != nil {
-			return nil, err
+		// proofs that don't match the on-chain root are quarantined instead of saved
+		err = stader.SaveCycleMerkleProof(cfg, sp, nodeAccount.Address, cycleMerkleProof)
+		var invalidProofErr *stader.InvalidMerkleProofError
+		if errors.As(err, &invalidProofErr) {
+			quarantinedCycles = append(quarantinedCycles, cycleMerkleProof.Cycle)
+			continue
 		}
-
-		encoder := json.NewEncoder(file)
-		err = encoder.Encode(cycleMerkleProof)
 		if err != nil {
-			return nil, fmt.Errorf("error encoding JSON: %v", err)
+			return nil, err
 		}
 
 		downloadedCycles = append(downloadedCycles, cycleMerkleProof.Cycle)
 	}
 
 	response.DownloadedCycles = downloadedCycles
+	response.QuarantinedCycles = quarantinedCycles
 
 	return &response, nil
 }
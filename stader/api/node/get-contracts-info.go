@@ -1,6 +1,7 @@
 package node
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/urfave/cli"
@@ -27,7 +28,7 @@ func getContractsInfo(c *cli.Context) (*api.ContractsInfoResponse, error) {
 		return nil, fmt.Errorf("Error getting beacon client: %w", err)
 	}
 
-	eth2DepositContract, err := bc.GetEth2DepositContract()
+	eth2DepositContract, err := bc.GetEth2DepositContract(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("Error getting beacon client deposit contract: %w", err)
 	}
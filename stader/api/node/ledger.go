@@ -0,0 +1,81 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/ledger"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+)
+
+// Bring the node's ledger up to date and get its records between the given unix timestamps
+func getLedger(c *cli.Context, from uint64, to uint64) (*api.NodeLedgerResponse, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	store, err := services.GetLedgerStore(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.NodeLedgerResponse{}
+
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	operatorId, err := node.GetOperatorId(pnr, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if operatorId.Int64() == 0 {
+		return &response, nil
+	}
+	response.Registered = true
+
+	operatorInfo, err := node.GetOperatorInfo(pnr, operatorId, nil)
+	if err != nil {
+		return nil, err
+	}
+	validators, _, err := stdr.GetAllValidatorsRegisteredWithOperator(pnr, operatorId, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+	ix, syncedBlock, err := services.SyncOperatorIndex(c, nodeAccount.Address, operatorId, operatorInfo, validators)
+	if err != nil {
+		return nil, err
+	}
+	if syncedBlock > ledger.Confirmations {
+		if err := store.Update(ix, ec, syncedBlock-ledger.Confirmations); err != nil {
+			return nil, fmt.Errorf("error updating the ledger: %w", err)
+		}
+	}
+
+	response.ScannedToBlock, err = store.GetScannedBlock()
+	if err != nil {
+		return nil, err
+	}
+	response.Records, err = store.GetRecords(time.Unix(int64(from), 0), time.Unix(int64(to), 0))
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,209 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/offline"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+)
+
+// Sign exported transactions with the node wallet. This runs on the offline machine, so it can't touch the chain.
+func signTransactions(c *cli.Context, unsignedTransactions string) (*api.SignTransactionsResponse, error) {
+
+	// Get services
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	if w.IsWatchOnly() {
+		return nil, fmt.Errorf("The node wallet is watch-only; transactions must be signed on the machine that holds the wallet")
+	}
+
+	// Response
+	response := api.SignTransactionsResponse{}
+
+	// Decode the transactions
+	var request offline.UnsignedTransactions
+	if err := json.Unmarshal([]byte(unsignedTransactions), &request); err != nil {
+		return nil, fmt.Errorf("Error decoding unsigned transactions: %w", err)
+	}
+	if len(request.Transactions) == 0 {
+		return nil, fmt.Errorf("There are no transactions to sign")
+	}
+
+	// Get node account
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, unsignedTx := range request.Transactions {
+		signedTx, err := offline.SignTransaction(w.Sign, nodeAccount.Address, w.GetChainID(), unsignedTx)
+		if err != nil {
+			return nil, fmt.Errorf("Error signing transaction %d: %w", i+1, err)
+		}
+		response.SignedTransactions.Transactions = append(response.SignedTransactions.Transactions, signedTx)
+	}
+
+	// Return response
+	return &response, nil
+
+}
+
+// Broadcast transactions that were signed on the offline machine
+func broadcastTransactions(c *cli.Context, signedTransactions string) (*api.BroadcastTransactionsResponse, error) {
+
+	// Get services
+	if err := services.RequireEthClientSynced(c); err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.BroadcastTransactionsResponse{}
+
+	// Decode the transactions
+	var request offline.SignedTransactions
+	if err := json.Unmarshal([]byte(signedTransactions), &request); err != nil {
+		return nil, fmt.Errorf("Error decoding signed transactions: %w", err)
+	}
+	if len(request.Transactions) == 0 {
+		return nil, fmt.Errorf("There are no transactions to broadcast")
+	}
+
+	// Decode them all and check any deposits before sending the first one
+	txs := make([]*types.Transaction, len(request.Transactions))
+	for i, signedTx := range request.Transactions {
+		txs[i], err = signedTx.ToTransaction()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkDepositTransaction(c, signedTx.From, txs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Send them in order so sequential nonces are accepted
+	for _, tx := range txs {
+		if err := ec.SendTransaction(context.Background(), tx); err != nil {
+			return nil, fmt.Errorf("Error broadcasting transaction %s: %w", tx.Hash().Hex(), err)
+		}
+		response.TxHashes = append(response.TxHashes, tx.Hash())
+	}
+
+	// Return response
+	return &response, nil
+
+}
+
+// Check the keys of an addValidatorKeys transaction signed on the offline machine. It could only check their signatures,
+// so this searches the chain for earlier deposits of the keys and checks the signatures against the withdrawal credentials
+// the registry will give the keys now.
+func checkDepositTransaction(c *cli.Context, from common.Address, tx *types.Transaction) error {
+	prn, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return err
+	}
+	registryContract := prn.PermissionlessNodeRegistryContract
+	if tx.To() == nil || *tx.To() != *registryContract.Address {
+		return nil
+	}
+	method, err := registryContract.ABI.MethodById(tx.Data())
+	if err != nil || method.Name != "addValidatorKeys" {
+		return nil
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		return fmt.Errorf("Error decoding the addValidatorKeys call of transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	pubKeys, ok1 := args[0].([][]byte)
+	preDepositSignatures, ok2 := args[1].([][]byte)
+	depositSignatures, ok3 := args[2].([][]byte)
+	if !ok1 || !ok2 || !ok3 || len(preDepositSignatures) != len(pubKeys) || len(depositSignatures) != len(pubKeys) {
+		return fmt.Errorf("The addValidatorKeys call of transaction %s is malformed", tx.Hash().Hex())
+	}
+
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return err
+	}
+	vfc, err := services.GetVaultFactory(c)
+	if err != nil {
+		return err
+	}
+	eth2Config, err := bc.GetEth2Config(context.Background())
+	if err != nil {
+		return err
+	}
+	eventLogInterval, err := cfg.GetEventLogInterval()
+	if err != nil {
+		return err
+	}
+
+	// The keys get the operator's next key indices, which decide their withdrawal credentials
+	operatorId, err := node.GetOperatorId(prn, from, nil)
+	if err != nil {
+		return err
+	}
+	operatorKeyCount, err := node.GetTotalValidatorKeys(prn, operatorId, nil)
+	if err != nil {
+		return err
+	}
+	withdrawalCredentials := make([]common.Hash, len(pubKeys))
+	for i := range pubKeys {
+		operatorKeyIndex := new(big.Int).Add(operatorKeyCount, big.NewInt(int64(i)))
+		rewardWithdrawVault, err := node.ComputeWithdrawVaultAddress(vfc, 1, operatorId, operatorKeyIndex, nil)
+		if err != nil {
+			return err
+		}
+		withdrawalCredentials[i], err = node.GetValidatorWithdrawalCredential(vfc, rewardWithdrawVault, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return validator.CheckKeysBeforeSubmission(ec, bc, uint64(eventLogInterval), eth2Config, pubKeys, withdrawalCredentials, preDepositSignatures, depositSignatures)
+}
@@ -0,0 +1,109 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/eth1"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	penalty_tracker "github.com/stader-labs/stader-node/stader-lib/penalty-tracker"
+)
+
+func getPenalties(c *cli.Context) (*api.NodePenaltiesResponse, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	pt, err := services.GetPenaltyTrackerContract(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.NodePenaltiesResponse{}
+
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	operatorId, err := node.GetOperatorId(pnr, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if operatorId.Int64() == 0 {
+		return &response, nil
+	}
+	response.Registered = true
+
+	response.ExitPenaltyThreshold, err = penalty_tracker.GetValidatorExitPenaltyThreshold(pt, nil)
+	if err != nil {
+		return nil, err
+	}
+	response.MevTheftPenaltyPerStrike, err = penalty_tracker.GetMevTheftPenaltyPerStrike(pt, nil)
+	if err != nil {
+		return nil, err
+	}
+	response.MissedAttestationPenaltyPerStrike, err = penalty_tracker.GetMissedAttestationPenaltyPerStrike(pt, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	validatorInfoMap, validatorPubKeys, err := stdr.GetAllValidatorsRegisteredWithOperator(pnr, operatorId, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	validatorPenalties, err := stdr.GetValidatorPenalties(pt, validatorPubKeys, nil)
+	if err != nil {
+		return nil, err
+	}
+	response.ValidatorPenalties = make([]stdr.ValidatorPenalty, 0, len(validatorPubKeys))
+	for _, pubkey := range validatorPubKeys {
+		response.ValidatorPenalties = append(response.ValidatorPenalties, validatorPenalties[pubkey])
+	}
+
+	response.ForceExits = []api.ForceExitDetails{}
+	operatorInfo, err := node.GetOperatorInfo(pnr, operatorId, nil)
+	if err != nil {
+		return nil, err
+	}
+	ix, _, err := services.SyncOperatorIndex(c, nodeAccount.Address, operatorId, operatorInfo, validatorInfoMap)
+	if err != nil {
+		return nil, err
+	}
+	forceExits, err := stdr.GetIndexedForceExits(ix)
+	if err != nil {
+		return nil, fmt.Errorf("error getting force exit history: %w", err)
+	}
+
+	for _, forceExit := range forceExits {
+		details := api.ForceExitDetails{ForceExitEvent: forceExit}
+		details.Time, err = eth1.ConvertBlockToTimestamp(c, int64(forceExit.BlockNumber))
+		if err != nil {
+			return nil, err
+		}
+		if forceExit.SettledBlockNumber > 0 {
+			details.SettledTime, err = eth1.ConvertBlockToTimestamp(c, int64(forceExit.SettledBlockNumber))
+			if err != nil {
+				return nil, err
+			}
+		}
+		response.ForceExits = append(response.ForceExits, details)
+	}
+	sort.Slice(response.ForceExits, func(i, j int) bool {
+		return response.ForceExits[i].BlockNumber < response.ForceExits[j].BlockNumber
+	})
+
+	return &response, nil
+}
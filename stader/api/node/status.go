@@ -1,6 +1,7 @@
 package node
 
 import (
+	"context"
 	stader_backend "github.com/stader-labs/stader-node/shared/types/stader-backend"
 	"github.com/stader-labs/stader-node/shared/utils/eth1"
 	pool_utils "github.com/stader-labs/stader-node/stader-lib/pool-utils"
@@ -109,6 +110,10 @@ func getStatus(c *cli.Context) (*api.NodeStatusResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	pt, err := services.GetPenaltyTrackerContract(c)
+	if err != nil {
+		return nil, err
+	}
 
 	// Response
 	response := api.NodeStatusResponse{}
@@ -237,7 +242,12 @@ func getStatus(c *cli.Context) (*api.NodeStatusResponse, error) {
 		totalValidatorClRewards := big.NewInt(0)
 		validatorInfoArray := make([]stdr.ValidatorInfo, totalValidatorKeys.Int64())
 
-		validatorInfoMap, _, err := stdr.GetAllValidatorsRegisteredWithOperator(pnr, operatorId, nodeAccount.Address, nil)
+		validatorInfoMap, validatorPubKeys, err := stdr.GetAllValidatorsRegisteredWithOperator(pnr, operatorId, nodeAccount.Address, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		validatorPenalties, err := stdr.GetValidatorPenalties(pt, validatorPubKeys, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -269,7 +279,7 @@ func getStatus(c *cli.Context) (*api.NodeStatusResponse, error) {
 			}
 			validatorWithdrawVaultWithdrawShares := withdrawVaultWithdrawShares.OperatorShare
 
-			validatorBeaconStatus, err := bc.GetValidatorStatus(types.BytesToValidatorPubkey(validatorContractInfo.Pubkey), nil)
+			validatorBeaconStatus, err := bc.GetValidatorStatus(context.Background(), types.BytesToValidatorPubkey(validatorContractInfo.Pubkey), nil)
 			if err != nil {
 				return nil, err
 			}
@@ -291,6 +301,7 @@ func getStatus(c *cli.Context) (*api.NodeStatusResponse, error) {
 			validatorInfo := stdr.ValidatorInfo{
 				Status:                           validatorContractInfo.Status,
 				StatusToDisplay:                  validatorDisplayStatus,
+				BeaconStatus:                     validatorBeaconStatus.Status,
 				Pubkey:                           validatorContractInfo.Pubkey,
 				PreDepositSignature:              validatorContractInfo.PreDepositSignature,
 				DepositSignature:                 validatorContractInfo.DepositSignature,
@@ -303,6 +314,7 @@ func getStatus(c *cli.Context) (*api.NodeStatusResponse, error) {
 				DepositTime:                      depositTime,
 				WithdrawnBlock:                   validatorContractInfo.WithdrawnBlock,
 				WithdrawnTime:                    withdrawTime,
+				Penalty:                          validatorPenalties[types.BytesToValidatorPubkey(validatorContractInfo.Pubkey)],
 			}
 
 			validatorInfoArray[i] = validatorInfo
@@ -0,0 +1,182 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/gas/feehistory"
+	"github.com/stader-labs/stader-node/shared/services/txmanager"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+func getTransactions(c *cli.Context) (*api.NodeTransactionsResponse, error) {
+	journal, err := services.GetTxJournal(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.NodeTransactionsResponse{}
+
+	records, err := journal.Refresh(ec)
+	if err != nil {
+		return nil, err
+	}
+	estimate, err := feehistory.GetFeeEstimate(ec)
+	if err != nil {
+		return nil, err
+	}
+	nextBaseFee := estimate.PredictedBaseFeesWei[0]
+
+	response.Transactions = make([]api.NodeTransaction, 0, len(records))
+	for _, record := range records {
+		response.Transactions = append(response.Transactions, api.NodeTransaction{
+			TxRecord: record,
+			Stuck:    txmanager.IsStuck(record, nextBaseFee),
+		})
+	}
+
+	return &response, nil
+}
+
+func canReplaceTransaction(c *cli.Context, hash common.Hash) (*api.CanReplaceTransactionResponse, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	journal, err := services.GetTxJournal(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.CanReplaceTransactionResponse{}
+
+	// Make sure the transaction is still waiting to be mined
+	if _, err := journal.Refresh(ec); err != nil {
+		return nil, err
+	}
+	record, exists, err := journal.GetRecord(hash)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		response.NotFound = true
+		return &response, nil
+	}
+	response.Transaction = record
+	if record.Status != txmanager.TxStatus_Pending {
+		response.NotPending = true
+		return &response, nil
+	}
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	if record.From != nodeAccount.Address {
+		response.NotNodeAccount = true
+		return &response, nil
+	}
+
+	estimate, err := feehistory.GetFeeEstimate(ec)
+	if err != nil {
+		return nil, err
+	}
+	priorityFee := eth.GweiToWei(cfg.StaderNode.PriorityFee.Value.(float64))
+	response.MaxFee, response.PriorityFee = txmanager.GetReplacementFees(record, estimate, priorityFee)
+	response.GasLimit = record.GasLimit
+
+	maxCost := new(big.Int).Mul(response.MaxFee, new(big.Int).SetUint64(record.GasLimit))
+	if maxCost.Cmp(eth.EthToWei(cfg.StaderNode.TxFeeCap.Value.(float64))) > 0 {
+		response.ExceedsFeeCap = true
+	}
+
+	return &response, nil
+}
+
+// Replace a pending transaction with the same call at higher fees, or with a 0 ETH transfer to the node account if cancelling
+func replaceTransaction(c *cli.Context, hash common.Hash, cancel bool) (*api.ReplaceTransactionResponse, error) {
+	canReplace, err := canReplaceTransaction(c, hash)
+	if err != nil {
+		return nil, err
+	}
+	if canReplace.NotFound || canReplace.NotPending || canReplace.NotNodeAccount || canReplace.ExceedsFeeCap {
+		return nil, errCannotReplace(hash, canReplace)
+	}
+
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	journal, err := services.GetTxJournal(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.ReplaceTransactionResponse{}
+
+	opts, err := w.GetNodeAccountTransactor()
+	if err != nil {
+		return nil, err
+	}
+	tx := txmanager.BuildReplacement(canReplace.Transaction, canReplace.MaxFee, canReplace.PriorityFee, cancel)
+	signedTx, err := opts.Signer(opts.From, tx)
+	if err != nil {
+		return nil, err
+	}
+	response.TxHash = signedTx.Hash()
+
+	// The transaction was only recorded for export, the same as a contract call would have been
+	if opts.NoSend {
+		return &response, nil
+	}
+	if err := ec.SendTransaction(context.Background(), signedTx); err != nil {
+		return nil, err
+	}
+
+	kind := txmanager.TxKind_Speedup
+	if cancel {
+		kind = txmanager.TxKind_Cancel
+	}
+	if err := journal.SetReplacement(signedTx.Hash(), kind, hash); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func errCannotReplace(hash common.Hash, canReplace *api.CanReplaceTransactionResponse) error {
+	switch {
+	case canReplace.NotFound:
+		return fmt.Errorf("transaction %s is not in the node's transaction journal", hash.Hex())
+	case canReplace.NotPending:
+		return fmt.Errorf("transaction %s is no longer pending", hash.Hex())
+	case canReplace.NotNodeAccount:
+		return fmt.Errorf("transaction %s was not sent from the node account", hash.Hex())
+	default:
+		return fmt.Errorf("replacing transaction %s would exceed the transaction fee cap", hash.Hex())
+	}
+}
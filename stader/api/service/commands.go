@@ -70,6 +70,25 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 
 				},
 			},
+
+			{
+				Name:      "get-fee-estimate",
+				Aliases:   []string{"f"},
+				Usage:     "Estimates the current max fees and priority fees from the Execution client's fee history",
+				UsageText: "stader-cli api service get-fee-estimate",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getFeeEstimate(c))
+					return nil
+
+				},
+			},
 		},
 	})
 }
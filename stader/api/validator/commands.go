@@ -100,6 +100,96 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 
 				},
 			},
+			{
+				Name:      "can-import-keys",
+				Usage:     "Check whether the node can deposit a set of externally generated validator keystores",
+				UsageText: "stader-cli api validator can-import-keys amount password keystore-json...",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateMinArgCount(c, 3); err != nil {
+						return err
+					}
+					amountWei, err := cliutils.ValidateWeiAmount("deposit amount", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(canImportValidatorKeys(c, amountWei, c.Args().Get(1), c.Args()[2:]))
+					return nil
+
+				},
+			},
+			{
+				Name:      "import-keys",
+				Usage:     "Import a set of externally generated validator keystores and deposit them",
+				UsageText: "stader-cli api validator import-keys amount reload-keys password keystore-json...",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateMinArgCount(c, 4); err != nil {
+						return err
+					}
+					amountWei, err := cliutils.ValidateWeiAmount("deposit amount", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					reloadKeys, err := cliutils.ValidateBool("reload-keys", c.Args().Get(1))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(importValidatorKeys(c, amountWei, reloadKeys, c.Args().Get(2), c.Args()[3:]))
+					return nil
+
+				},
+			},
+			{
+				Name:      "export-deposit-request",
+				Usage:     "Do the chain reads for a deposit whose validator keys will be created and signed on an offline machine",
+				UsageText: "stader-cli api validator export-deposit-request amount num-validators",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 2); err != nil {
+						return err
+					}
+					amountWei, err := cliutils.ValidateWeiAmount("deposit amount", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					numValidators, err := cliutils.ValidateBigInt("num-validators", c.Args().Get(1))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(exportDepositRequest(c, amountWei, numValidators))
+					return nil
+
+				},
+			},
+			{
+				Name:      "sign-deposit-request",
+				Usage:     "Create the validator keys for an exported deposit request and sign the deposit transaction",
+				UsageText: "stader-cli api validator sign-deposit-request deposit-request-json",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(signDepositRequest(c, c.Args().Get(0)))
+					return nil
+
+				},
+			},
 			{
 				Name:      "can-exit-validator",
 				Usage:     "Can validator exit",
@@ -142,6 +232,48 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 
 				},
 			},
+			{
+				Name:      "can-exit-validators",
+				Usage:     "Check whether each of a comma-separated list of validators can exit",
+				UsageText: "stader-cli api validator can-exit-validators validator-pub-keys",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					validatorPubKeys, err := cliutils.ValidatePubkeys("validator-pub-keys", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					api.PrintResponse(canExitValidators(c, validatorPubKeys))
+					return nil
+
+				},
+			},
+			{
+				Name:      "exit-validators",
+				Usage:     "Exit each of a comma-separated list of validators",
+				UsageText: "stader-cli api validator exit-validators validator-pub-keys",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					validatorPubKeys, err := cliutils.ValidatePubkeys("validator-pub-keys", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					api.PrintResponse(exitValidators(c, validatorPubKeys))
+					return nil
+
+				},
+			},
 			{
 				Name:      "can-send-cl-rewards",
 				Usage:     "Can send cl rewards of a validator to the operator claim vault",
@@ -174,6 +306,120 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 
 				},
 			},
+			{
+				Name:      "can-send-cl-rewards-batch",
+				Usage:     "Check whether the cl rewards of each of a comma-separated list of validators can be sent to the operator claim vault",
+				UsageText: "stader-cli api validator can-send-cl-rewards-batch validator-pub-keys",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					validatorPubKeys, err := cliutils.ValidatePubkeys("validator-pub-keys", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					api.PrintResponse(CanSendClRewardsBatch(c, validatorPubKeys))
+					return nil
+
+				},
+			},
+			{
+				Name:      "send-cl-rewards-batch",
+				Usage:     "Send the cl rewards of each of a comma-separated list of validators to the operator claim vault, using sequential nonces",
+				UsageText: "stader-cli api validator send-cl-rewards-batch validator-pub-keys",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					validatorPubKeys, err := cliutils.ValidatePubkeys("validator-pub-keys", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					api.PrintResponse(SendClRewardsBatch(c, validatorPubKeys))
+					return nil
+
+				},
+			},
+			{
+				Name:      "can-settle-exit-funds",
+				Usage:     "Check the withdraw vault of an exited validator, which the node registry settles once the oracle reports the validator as withdrawn",
+				UsageText: "stader-cli api validator can-settle-exit-funds validator-pub-key",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					validatorPubKey, err := cliutils.ValidatePubkey("validator-pub-key", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					api.PrintResponse(canSettleExitFunds(c, validatorPubKey))
+					return nil
+
+				},
+			},
+			{
+				Name:      "presign-status",
+				Usage:     "Get the history of presigned exit messages sent to the Stader backend",
+				UsageText: "stader-cli api validator presign-status",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getPresignStatus(c))
+					return nil
+
+				},
+			},
+			{
+				Name:      "doppelganger-status",
+				Usage:     "Get the validator keys held back from the validator client by the doppelganger watch",
+				UsageText: "stader-cli api validator doppelganger-status",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getDoppelgangerStatus(c))
+					return nil
+
+				},
+			},
+			{
+				Name:      "doppelganger-retry",
+				Usage:     "Watch the validator keys a doppelganger was seen for again",
+				UsageText: "stader-cli api validator doppelganger-retry",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(retryDoppelgangerWatch(c))
+					return nil
+
+				},
+			},
 		},
 	})
 }
@@ -1,9 +1,12 @@
 package validator
 
 import (
+	"context"
 	"fmt"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stader-labs/stader-node/stader-lib/node"
 	sd_collateral "github.com/stader-labs/stader-node/stader-lib/sd-collateral"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
 	"github.com/stader-labs/stader-node/stader-lib/tokens"
 	stadertypes "github.com/stader-labs/stader-node/stader-lib/types"
 	"github.com/urfave/cli"
@@ -11,8 +14,11 @@ import (
 	"math/big"
 
 	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
 	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/types/eth2"
 	"github.com/stader-labs/stader-node/shared/utils/eth1"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
 	"github.com/stader-labs/stader-node/shared/utils/validator"
 )
 
@@ -48,7 +54,7 @@ func canNodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int,
 	}
 
 	// Get eth2 config
-	eth2Config, err := bc.GetEth2Config()
+	eth2Config, err := bc.GetEth2Config(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -70,64 +76,17 @@ func canNodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int,
 
 	canNodeDepositResponse := api.CanNodeDepositResponse{}
 
-	userBalance, err := tokens.GetEthBalance(prn.Client, nodeAccount.Address, nil)
+	checks, operatorId, err := checkAddValidatorKeys(prn, sdc, nodeAccount.Address, amountToSend, numValidators)
 	if err != nil {
 		return nil, err
 	}
-	if userBalance.Cmp(amountToSend) < 0 {
-		canNodeDepositResponse.InsufficientBalance = true
-	}
-
-	isPermissionlessNodeRegistryPaused, err := node.IsPermissionlessNodeRegistryPaused(prn, nil)
-	if err != nil {
-		return nil, err
-	}
-	if isPermissionlessNodeRegistryPaused {
-		canNodeDepositResponse.DepositPaused = true
-		return &canNodeDepositResponse, nil
-	}
-
-	operatorId, err := node.GetOperatorId(prn, nodeAccount.Address, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	totalValidatorKeys, err := node.GetTotalValidatorKeys(prn, operatorId, nil)
-	if err != nil {
-		return nil, err
-	}
-	totalValidatorNonTerminalKeys, err := node.GetTotalNonTerminalValidatorKeys(prn, nodeAccount.Address, totalValidatorKeys, nil)
-	if err != nil {
-		return nil, err
-	}
-	maxKeysPerOperator, err := node.GetMaxValidatorKeysPerOperator(prn, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	totalValidatorsPostAddition := totalValidatorNonTerminalKeys + numValidators.Uint64()
-
-	hasEnoughSdCollateral, err := sd_collateral.HasEnoughSdCollateral(sdc, nodeAccount.Address, 1, big.NewInt(int64(totalValidatorsPostAddition)), nil)
-	if err != nil {
-		return nil, err
-	}
-	if !hasEnoughSdCollateral {
-		canNodeDepositResponse.NotEnoughSdCollateral = true
-		return &canNodeDepositResponse, nil
-	}
-
-	inputKeyLimitCount, err := node.GetInputKeyLimitCount(prn, nil)
-	if err != nil {
-		return nil, err
-	}
-	if numValidators.Cmp(big.NewInt(int64(inputKeyLimitCount))) > 0 {
-		canNodeDepositResponse.InputKeyLimitReached = true
-		canNodeDepositResponse.InputKeyLimit = inputKeyLimitCount
-		return &canNodeDepositResponse, nil
-	}
-
-	if totalValidatorsPostAddition > maxKeysPerOperator {
-		canNodeDepositResponse.MaxValidatorLimitReached = true
+	canNodeDepositResponse.InsufficientBalance = checks.InsufficientBalance
+	canNodeDepositResponse.DepositPaused = checks.DepositPaused
+	canNodeDepositResponse.NotEnoughSdCollateral = checks.NotEnoughSdCollateral
+	canNodeDepositResponse.InputKeyLimitReached = checks.InputKeyLimitReached
+	canNodeDepositResponse.InputKeyLimit = checks.InputKeyLimit
+	canNodeDepositResponse.MaxValidatorLimitReached = checks.MaxValidatorLimitReached
+	if checks.blocked() {
 		return &canNodeDepositResponse, nil
 	}
 
@@ -147,14 +106,20 @@ func canNodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int,
 		return nil, err
 	}
 
-	for i := int64(0); i < numValidators.Int64(); i++ {
-		// Create and save a new validator key
-		validatorKey, err := w.GetValidatorKeyAt(walletIndex)
+	// With a remote signer, the signer's unused keys are deposited instead of new wallet keys
+	remoteSigner, err := getRemoteSignerIfEnabled(c)
+	if err != nil {
+		return nil, err
+	}
+	var remotePubkeys []stadertypes.ValidatorPubkey
+	if remoteSigner != nil {
+		remotePubkeys, err = getUnusedRemoteSignerKeys(remoteSigner, prn, bc, numValidators.Int64())
 		if err != nil {
 			return nil, err
 		}
-		walletIndex++
+	}
 
+	for i := int64(0); i < numValidators.Int64(); i++ {
 		rewardWithdrawVault, err := node.ComputeWithdrawVaultAddress(vfc, 1, operatorId, newValidatorKey, nil)
 		if err != nil {
 			return nil, err
@@ -165,17 +130,32 @@ func canNodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int,
 			return nil, err
 		}
 
-		// Get validator deposit data for 1 eth
-		preDepositData, _, err := validator.GetDepositData(validatorKey, withdrawCredentials, eth2Config, 1000000000)
-		if err != nil {
-			return nil, err
+		var preDepositData, depositData eth2.DepositData
+		if remoteSigner != nil {
+			preDepositData, depositData, err = getRemoteSignerDepositData(remoteSigner, remotePubkeys[i], withdrawCredentials, eth2Config)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// Get the next validator key without saving it
+			validatorKey, err := w.GetValidatorKeyAt(walletIndex)
+			if err != nil {
+				return nil, err
+			}
+			walletIndex++
+
+			// Get validator deposit data for 1 eth
+			preDepositData, _, err = validator.GetDepositData(validatorKey, withdrawCredentials, eth2Config, 1000000000)
+			if err != nil {
+				return nil, err
+			}
+
+			depositData, _, err = validator.GetDepositData(validatorKey, withdrawCredentials, eth2Config, 31000000000)
+			if err != nil {
+				return nil, err
+			}
 		}
 		preDepositSignature := stadertypes.BytesToValidatorSignature(preDepositData.Signature)
-
-		depositData, _, err := validator.GetDepositData(validatorKey, withdrawCredentials, eth2Config, 31000000000)
-		if err != nil {
-			return nil, err
-		}
 		depositSignature := stadertypes.BytesToValidatorSignature(depositData.Signature)
 
 		pubKey := stadertypes.BytesToValidatorPubkey(preDepositData.PublicKey)
@@ -228,7 +208,7 @@ func nodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int, rel
 	}
 
 	// Get eth2 config
-	eth2Config, err := bc.GetEth2Config()
+	eth2Config, err := bc.GetEth2Config(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +242,7 @@ func nodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int, rel
 	pubKeys := make([][]byte, numValidators.Int64())
 	preDepositSignatures := make([][]byte, numValidators.Int64())
 	depositSignatures := make([][]byte, numValidators.Int64())
+	withdrawalCredentials := make([]common.Hash, numValidators.Int64())
 
 	amountToSend := amountWei.Mul(amountWei, numValidators)
 	opts.Value = amountToSend
@@ -273,44 +254,73 @@ func nodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int, rel
 
 	newValidatorKey := validatorKeyCount
 
-	for i := int64(0); i < numValidators.Int64(); i++ {
-		// Create and save a new validator key
-		validatorKey, err := w.CreateValidatorKey()
+	// With a remote signer, the signer's unused keys are deposited instead of new wallet keys
+	remoteSigner, err := getRemoteSignerIfEnabled(c)
+	if err != nil {
+		return nil, err
+	}
+	var remotePubkeys []stadertypes.ValidatorPubkey
+	var newKeys []wallet.ValidatorKey
+	if remoteSigner != nil {
+		remotePubkeys, err = getUnusedRemoteSignerKeys(remoteSigner, prn, bc, numValidators.Int64())
 		if err != nil {
 			return nil, err
 		}
-
-		rewardWithdrawVault, err := node.ComputeWithdrawVaultAddress(srcf, 1, operatorId, newValidatorKey, nil)
+	} else {
+		// Derive the new wallet keys, but only save them once they've passed the checks below
+		nextKeyIndex, err := w.GetValidatorKeyCount()
 		if err != nil {
 			return nil, err
 		}
-
-		withdrawCredentials, err := node.GetValidatorWithdrawalCredential(srcf, rewardWithdrawVault, nil)
+		newKeys, err = w.GetValidatorKeys(nextKeyIndex, uint(numValidators.Int64()))
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		// Get validator deposit data for 1 eth
-		preDepositData, _, err := validator.GetDepositData(validatorKey, withdrawCredentials, eth2Config, 1000000000)
+	for i := int64(0); i < numValidators.Int64(); i++ {
+		rewardWithdrawVault, err := node.ComputeWithdrawVaultAddress(srcf, 1, operatorId, newValidatorKey, nil)
 		if err != nil {
 			return nil, err
 		}
-		preDepositSignature := stadertypes.BytesToValidatorSignature(preDepositData.Signature)
-
-		pubKey := stadertypes.BytesToValidatorPubkey(preDepositData.PublicKey)
 
-		depositData, _, err := validator.GetDepositData(validatorKey, withdrawCredentials, eth2Config, 31000000000)
+		withdrawCredentials, err := node.GetValidatorWithdrawalCredential(srcf, rewardWithdrawVault, nil)
 		if err != nil {
 			return nil, err
 		}
+
+		var preDepositData, depositData eth2.DepositData
+		if remoteSigner != nil {
+			preDepositData, depositData, err = getRemoteSignerDepositData(remoteSigner, remotePubkeys[i], withdrawCredentials, eth2Config)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			validatorKey := newKeys[i].PrivateKey
+
+			// Get validator deposit data for 1 eth
+			preDepositData, _, err = validator.GetDepositData(validatorKey, withdrawCredentials, eth2Config, 1000000000)
+			if err != nil {
+				return nil, err
+			}
+
+			depositData, _, err = validator.GetDepositData(validatorKey, withdrawCredentials, eth2Config, 31000000000)
+			if err != nil {
+				return nil, err
+			}
+		}
+		preDepositSignature := stadertypes.BytesToValidatorSignature(preDepositData.Signature)
 		depositSignature := stadertypes.BytesToValidatorSignature(depositData.Signature)
 
+		pubKey := stadertypes.BytesToValidatorPubkey(preDepositData.PublicKey)
+
 		pubKeys[i] = pubKey[:]
 		preDepositSignatures[i] = preDepositSignature[:]
 		depositSignatures[i] = depositSignature[:]
+		withdrawalCredentials[i] = withdrawCredentials
 
 		// Make sure a validator with this pubkey doesn't already exist
-		status, err := bc.GetValidatorStatus(pubKey, nil)
+		status, err := bc.GetValidatorStatus(context.Background(), pubKey, nil)
 		if err != nil {
 			return nil, fmt.Errorf("Error checking for existing validator status: %w\nYour funds have not been deposited for your own safety.", err)
 		}
@@ -326,14 +336,46 @@ func nodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int, rel
 		newValidatorKey = validatorKeyCount.Add(validatorKeyCount, big.NewInt(1))
 	}
 
+	// Make sure the keys won't be marked as invalid or front-run once they're added
+	if err := checkKeysBeforeSubmission(c, eth2Config, pubKeys, withdrawalCredentials, preDepositSignatures, depositSignatures); err != nil {
+		return nil, err
+	}
+
+	// Save the new keys now that they're safe to add
+	for _, newKey := range newKeys {
+		if err := w.SaveValidatorKey(newKey); err != nil {
+			return nil, err
+		}
+	}
+
+	// Let the validator client pick up the newly deposited remote keys along with the operator's existing ones
+	if remoteSigner != nil {
+		_, registeredPubkeys, err := stdr.GetAllValidatorsRegisteredWithOperator(prn, operatorId, nodeAccount.Address, nil)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := validator.UpdateRemoteSignerKeys(cfg, remoteSigner, append(registeredPubkeys, remotePubkeys...)); err != nil {
+			return nil, err
+		}
+	}
+
 	if reloadKeys {
 		d, err := services.GetDocker(c)
 		if err != nil {
 			return nil, err
 		}
 
-		// Restart the validator container when a new key have been saved
-		err = validator.RestartValidator(cfg, bc, nil, d)
+		watch, err := services.GetDoppelgangerWatch(c)
+		if err != nil {
+			return nil, err
+		}
+		newPubkeys := make([]stadertypes.ValidatorPubkey, len(pubKeys))
+		for i, pubKey := range pubKeys {
+			newPubkeys[i] = stadertypes.BytesToValidatorPubkey(pubKey)
+		}
+
+		// Restart the validator container when a new key have been saved, unless the keys have to be watched for doppelgangers first
+		response.DoppelgangerWatch, err = validator.ActivateValidatorKeys(cfg, bc, d, w, watch, newPubkeys, "deposit", nil)
 		if err != nil {
 			return nil, err
 		}
@@ -361,3 +403,87 @@ func nodeDeposit(c *cli.Context, amountWei *big.Int, numValidators *big.Int, rel
 	return &response, nil
 
 }
+
+// Node-level conditions for adding a batch of validator keys to the permissionless registry
+type addValidatorKeysChecks struct {
+	InsufficientBalance      bool
+	DepositPaused            bool
+	NotEnoughSdCollateral    bool
+	MaxValidatorLimitReached bool
+	InputKeyLimitReached     bool
+	InputKeyLimit            uint16
+}
+
+// Check if any of the conditions stop the keys from being added; an insufficient balance is reported but left to the caller
+func (checks addValidatorKeysChecks) blocked() bool {
+	return checks.DepositPaused || checks.NotEnoughSdCollateral || checks.MaxValidatorLimitReached || checks.InputKeyLimitReached
+}
+
+// Check whether the node can add numValidators validator keys, stopping at the first condition that blocks it.
+// Also returns the node's operator id, which is nil if the registry is paused.
+func checkAddValidatorKeys(prn *stader.PermissionlessNodeRegistryContractManager, sdc *stader.SdCollateralContractManager, nodeAddress common.Address, amountToSend *big.Int, numValidators *big.Int) (addValidatorKeysChecks, *big.Int, error) {
+	checks := addValidatorKeysChecks{}
+
+	userBalance, err := tokens.GetEthBalance(prn.Client, nodeAddress, nil)
+	if err != nil {
+		return checks, nil, err
+	}
+	if userBalance.Cmp(amountToSend) < 0 {
+		checks.InsufficientBalance = true
+	}
+
+	isPermissionlessNodeRegistryPaused, err := node.IsPermissionlessNodeRegistryPaused(prn, nil)
+	if err != nil {
+		return checks, nil, err
+	}
+	if isPermissionlessNodeRegistryPaused {
+		checks.DepositPaused = true
+		return checks, nil, nil
+	}
+
+	operatorId, err := node.GetOperatorId(prn, nodeAddress, nil)
+	if err != nil {
+		return checks, nil, err
+	}
+
+	totalValidatorKeys, err := node.GetTotalValidatorKeys(prn, operatorId, nil)
+	if err != nil {
+		return checks, nil, err
+	}
+	totalValidatorNonTerminalKeys, err := node.GetTotalNonTerminalValidatorKeys(prn, nodeAddress, totalValidatorKeys, nil)
+	if err != nil {
+		return checks, nil, err
+	}
+	maxKeysPerOperator, err := node.GetMaxValidatorKeysPerOperator(prn, nil)
+	if err != nil {
+		return checks, nil, err
+	}
+
+	totalValidatorsPostAddition := totalValidatorNonTerminalKeys + numValidators.Uint64()
+
+	hasEnoughSdCollateral, err := sd_collateral.HasEnoughSdCollateral(sdc, nodeAddress, 1, big.NewInt(int64(totalValidatorsPostAddition)), nil)
+	if err != nil {
+		return checks, nil, err
+	}
+	if !hasEnoughSdCollateral {
+		checks.NotEnoughSdCollateral = true
+		return checks, operatorId, nil
+	}
+
+	inputKeyLimitCount, err := node.GetInputKeyLimitCount(prn, nil)
+	if err != nil {
+		return checks, nil, err
+	}
+	if numValidators.Cmp(big.NewInt(int64(inputKeyLimitCount))) > 0 {
+		checks.InputKeyLimitReached = true
+		checks.InputKeyLimit = inputKeyLimitCount
+		return checks, operatorId, nil
+	}
+
+	if totalValidatorsPostAddition > maxKeysPerOperator {
+		checks.MaxValidatorLimitReached = true
+		return checks, operatorId, nil
+	}
+
+	return checks, operatorId, nil
+}
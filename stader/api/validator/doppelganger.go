@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/doppelganger"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+func getDoppelgangerStatus(c *cli.Context) (*api.DoppelgangerStatusResponse, error) {
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	watch, err := services.GetDoppelgangerWatch(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.DoppelgangerStatusResponse{}
+	response.WatchEpochs = cfg.StaderNode.DoppelgangerWatchEpochs.Value.(uint64)
+	response.Keys, err = watch.GetKeys()
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Watch the keys a doppelganger was seen for again, once the user has shut the other machine down
+func retryDoppelgangerWatch(c *cli.Context) (*api.RetryDoppelgangerWatchResponse, error) {
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	watch, err := services.GetDoppelgangerWatch(c)
+	if err != nil {
+		return nil, err
+	}
+
+	watchEpochs := cfg.StaderNode.DoppelgangerWatchEpochs.Value.(uint64)
+	if watchEpochs == 0 {
+		// The user turned the watch off, but a key that was seen signing elsewhere still has to clear a short watch
+		watchEpochs = 1
+	}
+	head, err := bc.GetBeaconHead(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error getting beacon head: %w", err)
+	}
+
+	response := api.RetryDoppelgangerWatchResponse{
+		Pubkeys: []types.ValidatorPubkey{},
+	}
+	err = watch.Update(func(watched map[types.ValidatorPubkey]doppelganger.WatchedKey) error {
+		for pubkey, key := range watched {
+			if key.Status != doppelganger.KeyStatus_Detected {
+				continue
+			}
+			watched[pubkey] = doppelganger.NewWatchedKey(pubkey, key.Index, key.Reason, head.Epoch, watchEpochs)
+			response.Pubkeys = append(response.Pubkeys, pubkey)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.EndEpoch = head.Epoch + watchEpochs
+	return &response, nil
+}
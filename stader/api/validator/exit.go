@@ -1,7 +1,13 @@
 package validator
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
 	"github.com/stader-labs/stader-node/shared/types/api"
 	"github.com/stader-labs/stader-node/shared/utils/eth2"
 	"github.com/stader-labs/stader-node/shared/utils/validator"
@@ -27,16 +33,40 @@ func canExitValidator(c *cli.Context, validatorPubKey types.ValidatorPubkey) (*a
 	if err != nil {
 		return nil, err
 	}
+	remoteSigner, err := getRemoteSignerIfEnabled(c)
+	if err != nil {
+		return nil, err
+	}
+	beaconHead, err := bc.GetBeaconHead(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return getCanExitValidator(bc, w, remoteSigner, validatorPubKey, beaconHead.Epoch)
+}
+
+// Run the exit checks for a single validator
+func getCanExitValidator(bc beacon.Client, w *wallet.Wallet, remoteSigner *web3signer.Client, validatorPubKey types.ValidatorPubkey, currentEpoch uint64) (*api.CanExitValidatorResponse, error) {
 	// Response
 	response := api.CanExitValidatorResponse{}
 
 	// check if the validator is key is available to sign the exit message
-	_, err = w.GetValidatorKeyByPubkey(validatorPubKey)
-	if err != nil {
-		return nil, err
+	if remoteSigner != nil {
+		hasKey, err := remoteSigner.HasPublicKey(validatorPubKey)
+		if err != nil {
+			return nil, err
+		}
+		if !hasKey {
+			return nil, fmt.Errorf("Validator %s key not found in the remote signer", validatorPubKey.Hex())
+		}
+	} else {
+		_, err := w.GetValidatorKeyByPubkey(validatorPubKey)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	res, err := bc.GetValidatorStatus(validatorPubKey, nil)
+	res, err := bc.GetValidatorStatus(context.Background(), validatorPubKey, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -54,15 +84,57 @@ func canExitValidator(c *cli.Context, validatorPubKey types.ValidatorPubkey) (*a
 		return &response, nil
 	}
 
-	beaconHead, err := bc.GetBeaconHead()
+	if res.ActivationEpoch+256 > currentEpoch {
+		response.ValidatorTooYoung = true
+		return &response, nil
+	}
+
+	return &response, nil
+}
+
+func canExitValidators(c *cli.Context, validatorPubKeys []types.ValidatorPubkey) (*api.CanExitValidatorsResponse, error) {
+
+	// Get services
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	if err := services.RequireNodeRegistered(c); err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
 	if err != nil {
 		return nil, err
 	}
-	currentEpoch := beaconHead.Epoch
 
-	if res.ActivationEpoch+256 > currentEpoch {
-		response.ValidatorTooYoung = true
-		return &response, nil
+	remoteSigner, err := getRemoteSignerIfEnabled(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.CanExitValidatorsResponse{}
+
+	beaconHead, err := bc.GetBeaconHead(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	response.Validators = make([]api.CanExitValidatorResult, len(validatorPubKeys))
+	for i, validatorPubKey := range validatorPubKeys {
+		result := api.CanExitValidatorResult{
+			Pubkey: validatorPubKey,
+		}
+		canExit, err := getCanExitValidator(bc, w, remoteSigner, validatorPubKey, beaconHead.Epoch)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.CanExit = *canExit
+		}
+		response.Validators[i] = result
 	}
 
 	return &response, nil
@@ -82,47 +154,122 @@ func exitValidator(c *cli.Context, validatorPubKey types.ValidatorPubkey) (*api.
 	if err != nil {
 		return nil, err
 	}
+	remoteSigner, err := getRemoteSignerIfEnabled(c)
+	if err != nil {
+		return nil, err
+	}
 
 	// Response
 	response := api.ExitValidatorResponse{}
 
 	// Get beacon head
-	head, err := bc.GetBeaconHead()
+	head, err := bc.GetBeaconHead(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
 	// Get voluntary exit signature domain
-	signatureDomain, err := bc.GetDomainData(eth2types.DomainVoluntaryExit[:], head.Epoch, false)
+	signatureDomain, err := bc.GetDomainData(context.Background(), eth2types.DomainVoluntaryExit[:], head.Epoch, false)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get validator index
-	validatorIndex, err := bc.GetValidatorIndex(validatorPubKey)
-	if err != nil {
+	if err := signAndBroadcastExit(bc, w, remoteSigner, validatorPubKey, head.Epoch, signatureDomain); err != nil {
 		return nil, err
 	}
 
-	validatorKey, err := w.GetValidatorKeyByPubkey(validatorPubKey)
+	response.BeaconChainUrl = cfg.StaderNode.GetBeaconChainUrl()
+
+	// Return response
+	return &response, nil
+
+}
+
+func exitValidators(c *cli.Context, validatorPubKeys []types.ValidatorPubkey) (*api.ExitValidatorsResponse, error) {
+
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	remoteSigner, err := getRemoteSignerIfEnabled(c)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get signed voluntary exit message
-	signature, _, err := validator.GetSignedExitMessage(validatorKey, validatorIndex, head.Epoch, signatureDomain)
+	// Response
+	response := api.ExitValidatorsResponse{}
+
+	// Get beacon head
+	head, err := bc.GetBeaconHead(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	// Broadcast voluntary exit message
-	if err := bc.ExitValidator(validatorIndex, head.Epoch, signature); err != nil {
+	// Get voluntary exit signature domain
+	signatureDomain, err := bc.GetDomainData(context.Background(), eth2types.DomainVoluntaryExit[:], head.Epoch, false)
+	if err != nil {
 		return nil, err
 	}
 
+	// Sign and broadcast each exit on its own so one failure doesn't stop the rest
+	response.Results = make([]api.BatchValidatorResult, len(validatorPubKeys))
+	for i, validatorPubKey := range validatorPubKeys {
+		result := api.BatchValidatorResult{
+			Pubkey: validatorPubKey,
+		}
+		if err := signAndBroadcastExit(bc, w, remoteSigner, validatorPubKey, head.Epoch, signatureDomain); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+		response.Results[i] = result
+	}
+
 	response.BeaconChainUrl = cfg.StaderNode.GetBeaconChainUrl()
 
 	// Return response
 	return &response, nil
 
 }
+
+// Sign a voluntary exit for a validator and broadcast it to the beacon node
+func signAndBroadcastExit(bc beacon.Client, w *wallet.Wallet, remoteSigner *web3signer.Client, validatorPubKey types.ValidatorPubkey, epoch uint64, signatureDomain []byte) error {
+	// Get validator index
+	validatorIndex, err := bc.GetValidatorIndex(context.Background(), validatorPubKey)
+	if err != nil {
+		return err
+	}
+
+	// Get signed voluntary exit message
+	var signature types.ValidatorSignature
+	if remoteSigner != nil {
+		forkInfo, err := bc.GetForkInfo(context.Background())
+		if err != nil {
+			return err
+		}
+		signature, _, err = validator.GetSignedExitMessageFromRemoteSigner(remoteSigner, validatorPubKey, validatorIndex, epoch, signatureDomain, forkInfo)
+		if err != nil {
+			return err
+		}
+	} else {
+		validatorKey, err := w.GetValidatorKeyByPubkey(validatorPubKey)
+		if err != nil {
+			return err
+		}
+		signature, _, err = validator.GetSignedExitMessage(validatorKey, validatorIndex, epoch, signatureDomain)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Broadcast voluntary exit message
+	return bc.ExitValidator(context.Background(), validatorIndex, epoch, signature)
+}
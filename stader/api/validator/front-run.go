@@ -0,0 +1,32 @@
+package validator
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+)
+
+// Make sure the keys won't be marked as invalid or front-run once they're added to the registry
+func checkKeysBeforeSubmission(c *cli.Context, eth2Config beacon.Eth2Config, pubKeys [][]byte, withdrawalCredentials []common.Hash, preDepositSignatures [][]byte, depositSignatures [][]byte) error {
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return err
+	}
+	eventLogInterval, err := cfg.GetEventLogInterval()
+	if err != nil {
+		return err
+	}
+
+	return validator.CheckKeysBeforeSubmission(ec, bc, uint64(eventLogInterval), eth2Config, pubKeys, withdrawalCredentials, preDepositSignatures, depositSignatures)
+}
@@ -0,0 +1,359 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/eth1"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/urfave/cli"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// A validator key decrypted from an imported keystore
+type importedKey struct {
+	pubkey         types.ValidatorPubkey
+	key            *eth2types.BLSPrivateKey
+	derivationPath string
+}
+
+func canImportValidatorKeys(c *cli.Context, amountWei *big.Int, password string, keystores []string) (*api.CanImportValidatorKeysResponse, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	if err := services.RequireNodeRegistered(c); err != nil {
+		return nil, err
+	}
+	if err := services.RequireNodeActive(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	prn, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	vfc, err := services.GetVaultFactory(c)
+	if err != nil {
+		return nil, err
+	}
+	sdc, err := services.GetSdCollateralContract(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.CanImportValidatorKeysResponse{}
+
+	keys, err := decryptImportedKeys(c, password, keystores)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		response.Pubkeys = append(response.Pubkeys, key.pubkey)
+	}
+
+	// Keys that are already in use can't be deposited again
+	response.ExistingOnBeaconChain, response.RegisteredWithStader, err = getUsedImportedKeys(prn, bc, response.Pubkeys)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.ExistingOnBeaconChain) > 0 || len(response.RegisteredWithStader) > 0 {
+		return &response, nil
+	}
+
+	// Get eth2 config
+	eth2Config, err := bc.GetEth2Config(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// Get node account
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	numValidators := big.NewInt(int64(len(keys)))
+	amountToSend := amountWei.Mul(amountWei, numValidators)
+
+	opts, err := w.GetNodeAccountTransactor()
+	if err != nil {
+		return nil, err
+	}
+	opts.Value = amountToSend
+
+	checks, operatorId, err := checkAddValidatorKeys(prn, sdc, nodeAccount.Address, amountToSend, numValidators)
+	if err != nil {
+		return nil, err
+	}
+	response.InsufficientBalance = checks.InsufficientBalance
+	response.DepositPaused = checks.DepositPaused
+	response.NotEnoughSdCollateral = checks.NotEnoughSdCollateral
+	response.InputKeyLimitReached = checks.InputKeyLimitReached
+	response.InputKeyLimit = checks.InputKeyLimit
+	response.MaxValidatorLimitReached = checks.MaxValidatorLimitReached
+	if checks.blocked() {
+		return &response, nil
+	}
+
+	pubKeys, preDepositSignatures, depositSignatures, _, err := getImportedKeysDepositData(prn, vfc, operatorId, keys, eth2Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Override the provided pending TX if requested
+	err = eth1.CheckForNonceOverride(c, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for nonce override: %w", err)
+	}
+
+	gasInfo, err := node.EstimateAddValidatorKeys(prn, pubKeys, preDepositSignatures, depositSignatures, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	response.CanImport = true
+	response.GasInfo = gasInfo
+
+	return &response, nil
+}
+
+func importValidatorKeys(c *cli.Context, amountWei *big.Int, reloadKeys bool, password string, keystores []string) (*api.ImportValidatorKeysResponse, error) {
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	prn, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	vfc, err := services.GetVaultFactory(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.ImportValidatorKeysResponse{}
+
+	keys, err := decryptImportedKeys(c, password, keystores)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		response.Pubkeys = append(response.Pubkeys, key.pubkey)
+	}
+
+	// Make sure none of the keys are already in use
+	existingOnBeaconChain, registeredWithStader, err := getUsedImportedKeys(prn, bc, response.Pubkeys)
+	if err != nil {
+		return nil, fmt.Errorf("Error checking for existing validators: %w\nYour funds have not been deposited for your own safety.", err)
+	}
+	if len(existingOnBeaconChain) > 0 {
+		return nil, fmt.Errorf("Validator key %s is already in use on the Beacon chain; Stader will not deposit it so you do not get slashed.", existingOnBeaconChain[0].Hex())
+	}
+	if len(registeredWithStader) > 0 {
+		return nil, fmt.Errorf("Validator key %s is already registered with Stader.", registeredWithStader[0].Hex())
+	}
+
+	// Get eth2 config
+	eth2Config, err := bc.GetEth2Config(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// Get node account
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	operatorId, err := node.GetOperatorId(prn, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get transactor
+	opts, err := w.GetNodeAccountTransactor()
+	if err != nil {
+		return nil, err
+	}
+	opts.Value = amountWei.Mul(amountWei, big.NewInt(int64(len(keys))))
+
+	pubKeys, preDepositSignatures, depositSignatures, withdrawalCredentials, err := getImportedKeysDepositData(prn, vfc, operatorId, keys, eth2Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the keys won't be marked as invalid or front-run once they're added
+	if err := checkKeysBeforeSubmission(c, eth2Config, pubKeys, withdrawalCredentials, preDepositSignatures, depositSignatures); err != nil {
+		return nil, err
+	}
+
+	// Save the keys to every validator client keystore
+	for _, key := range keys {
+		if err := w.ImportValidatorKey(key.key, key.derivationPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if reloadKeys {
+		d, err := services.GetDocker(c)
+		if err != nil {
+			return nil, err
+		}
+
+		watch, err := services.GetDoppelgangerWatch(c)
+		if err != nil {
+			return nil, err
+		}
+
+		// Restart the validator container so it loads the imported keys, unless the keys have to be watched for doppelgangers first
+		response.DoppelgangerWatch, err = validator.ActivateValidatorKeys(cfg, bc, d, w, watch, response.Pubkeys, "import", nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Override the provided pending TX if requested
+	err = eth1.CheckForNonceOverride(c, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for nonce override: %w", err)
+	}
+
+	tx, err := node.AddValidatorKeys(prn, pubKeys, preDepositSignatures, depositSignatures, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	response.TxHash = tx.Hash()
+
+	// Return response
+	return &response, nil
+}
+
+// Decrypt the keystores being imported, rejecting duplicates
+func decryptImportedKeys(c *cli.Context, password string, keystores []string) ([]importedKey, error) {
+	remoteSigner, err := getRemoteSignerIfEnabled(c)
+	if err != nil {
+		return nil, err
+	}
+	if remoteSigner != nil {
+		return nil, fmt.Errorf("Keystores can't be imported into the node while the remote signer is enabled; import them into the remote signer instead.")
+	}
+
+	keys := make([]importedKey, 0, len(keystores))
+	seen := map[types.ValidatorPubkey]bool{}
+	for i, keystore := range keystores {
+		key, derivationPath, err := validator.DecryptKeystore([]byte(keystore), password)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading keystore %d: %w", i+1, err)
+		}
+		pubkey := types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+		if seen[pubkey] {
+			return nil, fmt.Errorf("Validator key %s was given more than once", pubkey.Hex())
+		}
+		seen[pubkey] = true
+		keys = append(keys, importedKey{
+			pubkey:         pubkey,
+			key:            key,
+			derivationPath: derivationPath,
+		})
+	}
+
+	return keys, nil
+}
+
+// Get the imported keys that already exist on the beacon chain and the ones already registered with Stader
+func getUsedImportedKeys(prn *stader.PermissionlessNodeRegistryContractManager, bc beacon.Client, pubkeys []types.ValidatorPubkey) ([]types.ValidatorPubkey, []types.ValidatorPubkey, error) {
+	statuses, err := bc.GetValidatorStatuses(context.Background(), pubkeys, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error checking for existing validator statuses: %w", err)
+	}
+
+	existingOnBeaconChain := []types.ValidatorPubkey{}
+	registeredWithStader := []types.ValidatorPubkey{}
+	for _, pubkey := range pubkeys {
+		if status, ok := statuses[pubkey]; ok && status.Exists {
+			existingOnBeaconChain = append(existingOnBeaconChain, pubkey)
+		}
+		validatorId, err := node.GetValidatorIdByPubKey(prn, pubkey.Bytes(), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if validatorId.Cmp(big.NewInt(0)) != 0 {
+			registeredWithStader = append(registeredWithStader, pubkey)
+		}
+	}
+
+	return existingOnBeaconChain, registeredWithStader, nil
+}
+
+// Sign the 1 ETH pre-deposit and the 31 ETH deposit for each imported key, against the withdraw vault it will be assigned.
+// Also returns the withdrawal credentials of each vault.
+func getImportedKeysDepositData(prn *stader.PermissionlessNodeRegistryContractManager, vfc *stader.VaultFactoryContractManager, operatorId *big.Int, keys []importedKey, eth2Config beacon.Eth2Config) ([][]byte, [][]byte, [][]byte, []common.Hash, error) {
+	pubKeys := make([][]byte, len(keys))
+	preDepositSignatures := make([][]byte, len(keys))
+	depositSignatures := make([][]byte, len(keys))
+	withdrawalCredentials := make([]common.Hash, len(keys))
+
+	validatorKeyCount, err := node.GetTotalValidatorKeys(prn, operatorId, nil)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	for i, key := range keys {
+		newValidatorKey := new(big.Int).Add(validatorKeyCount, big.NewInt(int64(i)))
+
+		rewardWithdrawVault, err := node.ComputeWithdrawVaultAddress(vfc, 1, operatorId, newValidatorKey, nil)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+
+		withdrawCredentials, err := node.GetValidatorWithdrawalCredential(vfc, rewardWithdrawVault, nil)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+
+		// Get validator deposit data for 1 eth
+		preDepositData, _, err := validator.GetDepositData(key.key, withdrawCredentials, eth2Config, 1000000000)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+
+		depositData, _, err := validator.GetDepositData(key.key, withdrawCredentials, eth2Config, 31000000000)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+
+		preDepositSignature := types.BytesToValidatorSignature(preDepositData.Signature)
+		depositSignature := types.BytesToValidatorSignature(depositData.Signature)
+
+		pubKeys[i] = key.pubkey.Bytes()
+		preDepositSignatures[i] = preDepositSignature[:]
+		depositSignatures[i] = depositSignature[:]
+		withdrawalCredentials[i] = withdrawCredentials
+	}
+
+	return pubKeys, preDepositSignatures, depositSignatures, withdrawalCredentials, nil
+}
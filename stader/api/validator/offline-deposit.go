@@ -0,0 +1,272 @@
+package validator
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/offline"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	stadertypes "github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Do the chain reads for a deposit whose keys will be created and signed on an offline machine
+func exportDepositRequest(c *cli.Context, amountWei *big.Int, numValidators *big.Int) (*api.ExportDepositRequestResponse, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	if err := services.RequireNodeRegistered(c); err != nil {
+		return nil, err
+	}
+	if err := services.RequireNodeActive(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	prn, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	vfc, err := services.GetVaultFactory(c)
+	if err != nil {
+		return nil, err
+	}
+	sdc, err := services.GetSdCollateralContract(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.ExportDepositRequestResponse{}
+
+	// Get eth2 config
+	eth2Config, err := bc.GetEth2Config(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// Get node account
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	amountToSend := amountWei.Mul(amountWei, numValidators)
+
+	checks, operatorId, err := checkAddValidatorKeys(prn, sdc, nodeAccount.Address, amountToSend, numValidators)
+	if err != nil {
+		return nil, err
+	}
+	response.InsufficientBalance = checks.InsufficientBalance
+	response.DepositPaused = checks.DepositPaused
+	response.NotEnoughSdCollateral = checks.NotEnoughSdCollateral
+	response.InputKeyLimitReached = checks.InputKeyLimitReached
+	response.InputKeyLimit = checks.InputKeyLimit
+	response.MaxValidatorLimitReached = checks.MaxValidatorLimitReached
+	if checks.blocked() {
+		return &response, nil
+	}
+
+	request := offline.DepositRequest{
+		NodeAddress:          nodeAccount.Address,
+		OperatorId:           operatorId.Uint64(),
+		GenesisForkVersion:   eth2Config.GenesisForkVersion,
+		PreDepositAmountGwei: validator.PreDepositAmountGwei,
+		DepositAmountGwei:    validator.DepositAmountGwei,
+	}
+
+	// Get the withdrawal credentials each new key will be deposited against
+	operatorKeyCount, err := node.GetTotalValidatorKeys(prn, operatorId, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := int64(0); i < numValidators.Int64(); i++ {
+		operatorKeyIndex := new(big.Int).Add(operatorKeyCount, big.NewInt(i))
+
+		rewardWithdrawVault, err := node.ComputeWithdrawVaultAddress(vfc, 1, operatorId, operatorKeyIndex, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		withdrawCredentials, err := node.GetValidatorWithdrawalCredential(vfc, rewardWithdrawVault, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		request.Validators = append(request.Validators, offline.DepositRequestValidator{
+			OperatorKeyIndex:      operatorKeyIndex.Uint64(),
+			WithdrawVault:         rewardWithdrawVault,
+			WithdrawalCredentials: withdrawCredentials,
+		})
+	}
+
+	// Build the transaction with placeholder keys to get its nonce, fees and gas limit; the offline machine fills in the real call data
+	opts, err := w.GetNodeAccountTemplateTransactor()
+	if err != nil {
+		return nil, err
+	}
+	opts.Value = amountToSend
+
+	pubKeys, preDepositSignatures, depositSignatures := getPlaceholderValidatorKeys(numValidators.Int64())
+	gasInfo, err := node.EstimateAddValidatorKeys(prn, pubKeys, preDepositSignatures, depositSignatures, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.GasLimit = gasInfo.SafeGasLimit
+
+	tx, err := node.AddValidatorKeys(prn, pubKeys, preDepositSignatures, depositSignatures, opts)
+	if err != nil {
+		return nil, err
+	}
+	request.Transaction, err = offline.NewUnsignedTransaction(nodeAccount.Address, tx)
+	if err != nil {
+		return nil, err
+	}
+	request.Transaction.Data = nil
+
+	response.Request = &request
+
+	return &response, nil
+}
+
+// Create the validator keys for a deposit request, sign their deposit data and sign the deposit transaction.
+// This runs on the offline machine, so everything it needs from the chain has to be in the request.
+func signDepositRequest(c *cli.Context, depositRequest string) (*api.SignTransactionsResponse, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	if w.IsWatchOnly() {
+		return nil, fmt.Errorf("The node wallet is watch-only; deposits must be signed on the machine that holds the wallet")
+	}
+
+	response := api.SignTransactionsResponse{}
+
+	// Decode the request
+	var request offline.DepositRequest
+	if err := json.Unmarshal([]byte(depositRequest), &request); err != nil {
+		return nil, fmt.Errorf("Error decoding deposit request: %w", err)
+	}
+	if len(request.Validators) == 0 {
+		return nil, fmt.Errorf("The deposit request has no validators")
+	}
+
+	// Get node account
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	if request.NodeAddress != nodeAccount.Address {
+		return nil, fmt.Errorf("The deposit request is for node %s, not this wallet's node %s", request.NodeAddress.Hex(), nodeAccount.Address.Hex())
+	}
+
+	eth2Config := beacon.Eth2Config{
+		GenesisForkVersion: request.GenesisForkVersion,
+	}
+
+	pubKeys := make([][]byte, len(request.Validators))
+	preDepositSignatures := make([][]byte, len(request.Validators))
+	depositSignatures := make([][]byte, len(request.Validators))
+
+	// Derive the new keys, but only save them once their deposit data has been checked
+	nextKeyIndex, err := w.GetValidatorKeyCount()
+	if err != nil {
+		return nil, err
+	}
+	newKeys, err := w.GetValidatorKeys(nextKeyIndex, uint(len(request.Validators)))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, requestValidator := range request.Validators {
+		validatorKey := newKeys[i].PrivateKey
+
+		// Get validator deposit data for 1 eth
+		preDepositData, _, err := validator.GetDepositData(validatorKey, requestValidator.WithdrawalCredentials, eth2Config, request.PreDepositAmountGwei)
+		if err != nil {
+			return nil, err
+		}
+
+		depositData, _, err := validator.GetDepositData(validatorKey, requestValidator.WithdrawalCredentials, eth2Config, request.DepositAmountGwei)
+		if err != nil {
+			return nil, err
+		}
+
+		pubKey := stadertypes.BytesToValidatorPubkey(preDepositData.PublicKey)
+		preDepositSignature := stadertypes.BytesToValidatorSignature(preDepositData.Signature)
+		depositSignature := stadertypes.BytesToValidatorSignature(depositData.Signature)
+
+		// The chain can't be searched for earlier deposits from here, so that part of the check runs when the transaction is broadcast
+		err = validator.VerifyStaderDepositSignatures(pubKey, requestValidator.WithdrawalCredentials, eth2Config, preDepositSignature, depositSignature)
+		if err != nil {
+			return nil, fmt.Errorf("%w\nThe key would be marked as having an invalid signature and the bond lost, so the deposit has not been signed.", err)
+		}
+
+		pubKeys[i] = pubKey[:]
+		preDepositSignatures[i] = preDepositSignature[:]
+		depositSignatures[i] = depositSignature[:]
+		response.SignedTransactions.ValidatorPubkeys = append(response.SignedTransactions.ValidatorPubkeys, pubKey.Hex())
+	}
+
+	// Save the new keys now that they're safe to add
+	for _, newKey := range newKeys {
+		if err := w.SaveValidatorKey(newKey); err != nil {
+			return nil, err
+		}
+	}
+
+	// Build the addValidatorKeys call
+	prnAbi, err := contracts.PermissionlessNodeRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("Error loading the permissionless node registry ABI: %w", err)
+	}
+	request.Transaction.Data, err = prnAbi.Pack("addValidatorKeys", pubKeys, preDepositSignatures, depositSignatures)
+	if err != nil {
+		return nil, fmt.Errorf("Error encoding the addValidatorKeys call: %w", err)
+	}
+
+	signedTx, err := offline.SignTransaction(w.Sign, nodeAccount.Address, w.GetChainID(), request.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	response.SignedTransactions.Transactions = []offline.SignedTransaction{signedTx}
+
+	// To save the validator index update
+	if err := w.Save(); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// Get distinct placeholder keys and signatures, only used to estimate the gas of an addValidatorKeys call
+func getPlaceholderValidatorKeys(count int64) ([][]byte, [][]byte, [][]byte) {
+	pubKeys := make([][]byte, count)
+	preDepositSignatures := make([][]byte, count)
+	depositSignatures := make([][]byte, count)
+	for i := int64(0); i < count; i++ {
+		pubKey := make([]byte, stadertypes.ValidatorPubkeyLength)
+		binary.BigEndian.PutUint64(pubKey[len(pubKey)-8:], uint64(i+1))
+		pubKeys[i] = pubKey
+		preDepositSignatures[i] = make([]byte, stadertypes.ValidatorSignatureLength)
+		depositSignatures[i] = make([]byte, stadertypes.ValidatorSignatureLength)
+	}
+	return pubKeys, preDepositSignatures, depositSignatures
+}
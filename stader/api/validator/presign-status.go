@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/presign"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/urfave/cli"
+)
+
+func getPresignStatus(c *cli.Context) (*api.PresignStatusResponse, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	if err := services.RequireNodeRegistered(c); err != nil {
+		return nil, err
+	}
+
+	// Get services
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	presignDb, err := services.GetPresignDatabase(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.PresignStatusResponse{}
+
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	operatorId, err := node.GetOperatorId(pnr, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+	registeredValidators, validatorPubKeys, err := stdr.GetAllValidatorsRegisteredWithOperator(pnr, operatorId, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := presignDb.GetRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	// Report every validator that still needs a presigned exit, including ones that were never submitted
+	response.Validators = []presign.ValidatorRecord{}
+	for _, validatorPubKey := range validatorPubKeys {
+		if stdr.IsValidatorTerminal(registeredValidators[validatorPubKey]) {
+			continue
+		}
+		record, ok := records[validatorPubKey]
+		if !ok {
+			record = presign.ValidatorRecord{Pubkey: validatorPubKey}
+		}
+		response.Validators = append(response.Validators, record)
+	}
+
+	return &response, nil
+}
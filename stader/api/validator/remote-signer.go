@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
+	"github.com/stader-labs/stader-node/shared/types/eth2"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/urfave/cli"
+)
+
+// Get the remote signer if it's enabled, or nil if the validator keys come from the node wallet
+func getRemoteSignerIfEnabled(c *cli.Context) (*web3signer.Client, error) {
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.EnableRemoteSigner.Value != true {
+		return nil, nil
+	}
+	return services.GetRemoteSigner(c)
+}
+
+// Get validator keys held by the remote signer that are neither registered with Stader nor known to the beacon chain yet
+func getUnusedRemoteSignerKeys(signer *web3signer.Client, prn *stader.PermissionlessNodeRegistryContractManager, bc beacon.Client, count int64) ([]types.ValidatorPubkey, error) {
+	signerPubkeys, err := signer.GetPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	beaconStatuses, err := bc.GetValidatorStatuses(context.Background(), signerPubkeys, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error checking remote signer keys on the beacon chain: %w", err)
+	}
+
+	unusedPubkeys := []types.ValidatorPubkey{}
+	for _, pubkey := range signerPubkeys {
+		if int64(len(unusedPubkeys)) == count {
+			break
+		}
+		if status, ok := beaconStatuses[pubkey]; ok && status.Exists {
+			continue
+		}
+		validatorId, err := node.GetValidatorIdByPubKey(prn, pubkey.Bytes(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if validatorId.Int64() != 0 {
+			continue
+		}
+		unusedPubkeys = append(unusedPubkeys, pubkey)
+	}
+
+	if int64(len(unusedPubkeys)) < count {
+		return nil, fmt.Errorf("The remote signer only has %d unused validator keys but %d are needed. Please add more keys to the signer.", len(unusedPubkeys), count)
+	}
+
+	return unusedPubkeys, nil
+}
+
+// Get the 1 ETH pre-deposit and the 31 ETH deposit data for a validator key held by the remote signer
+func getRemoteSignerDepositData(signer *web3signer.Client, pubkey types.ValidatorPubkey, withdrawCredentials common.Hash, eth2Config beacon.Eth2Config) (eth2.DepositData, eth2.DepositData, error) {
+	preDepositData, _, err := validator.GetDepositDataFromRemoteSigner(signer, pubkey, withdrawCredentials, eth2Config, 1000000000)
+	if err != nil {
+		return eth2.DepositData{}, eth2.DepositData{}, err
+	}
+
+	depositData, _, err := validator.GetDepositDataFromRemoteSigner(signer, pubkey, withdrawCredentials, eth2Config, 31000000000)
+	if err != nil {
+		return eth2.DepositData{}, eth2.DepositData{}, err
+	}
+
+	return preDepositData, depositData, nil
+}
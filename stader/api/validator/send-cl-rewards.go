@@ -1,10 +1,18 @@
 package validator
 
 import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stader-labs/stader-node/shared/services"
 	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/eth1"
 	"github.com/stader-labs/stader-node/stader-lib/node"
 	pool_utils "github.com/stader-labs/stader-node/stader-lib/pool-utils"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
 	stader_config "github.com/stader-labs/stader-node/stader-lib/stader-config"
 	"github.com/stader-labs/stader-node/stader-lib/tokens"
 	"github.com/stader-labs/stader-node/stader-lib/types"
@@ -36,6 +44,21 @@ func CanSendClRewards(c *cli.Context, validatorPubKey types.ValidatorPubkey) (*a
 		return nil, err
 	}
 
+	opts, err := w.GetNodeAccountTransactor()
+	if err != nil {
+		return nil, err
+	}
+
+	rewardsThreshold, err := stader_config.GetRewardsThreshold(sdcfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return getCanSendClRewards(pnr, putils, rewardsThreshold, validatorPubKey, opts)
+}
+
+// Run the send-cl-rewards checks for a single validator
+func getCanSendClRewards(pnr *stader.PermissionlessNodeRegistryContractManager, putils *stader.PoolUtilsContractManager, rewardsThreshold *big.Int, validatorPubKey types.ValidatorPubkey, opts *bind.TransactOpts) (*api.CanSendClRewardsResponse, error) {
 	// Response
 	response := api.CanSendClRewardsResponse{}
 
@@ -58,7 +81,6 @@ func CanSendClRewards(c *cli.Context, validatorPubKey types.ValidatorPubkey) (*a
 		return &response, nil
 	}
 
-	//fmt.Println("getting validator eth balance")
 	withdrawVaultBalance, err := tokens.GetEthBalance(pnr.Client, validatorContractInfo.WithdrawVaultAddress, nil)
 	if err != nil {
 		return nil, err
@@ -68,31 +90,78 @@ func CanSendClRewards(c *cli.Context, validatorPubKey types.ValidatorPubkey) (*a
 	if err != nil {
 		return nil, err
 	}
+	response.ClRewardsAmount = withdrawVaultRewardShares.OperatorShare
 	if withdrawVaultRewardShares.OperatorShare.Int64() == 0 {
 		response.NoClRewards = true
 		return &response, nil
 	}
 
-	rewardsThreshold, err := stader_config.GetRewardsThreshold(sdcfg, nil)
-	if err != nil {
-		return nil, err
-	}
-
 	if withdrawVaultRewardShares.OperatorShare.Cmp(rewardsThreshold) > 0 {
 		response.TooManyClRewards = true
 		return &response, nil
 	}
 
+	gasInfo, err := node.EstimateDistributeRewards(pnr.Client, validatorContractInfo.WithdrawVaultAddress, opts)
+	if err != nil {
+		return nil, err
+	}
+	response.GasInfo = gasInfo
+
+	return &response, nil
+}
+
+func CanSendClRewardsBatch(c *cli.Context, validatorPubKeys []types.ValidatorPubkey) (*api.CanSendClRewardsBatchResponse, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	if err := services.RequireNodeRegistered(c); err != nil {
+		return nil, err
+	}
+	// Get services
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	sdcfg, err := services.GetStaderConfigContract(c)
+	if err != nil {
+		return nil, err
+	}
+	putils, err := services.GetPoolUtilsContract(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
 	opts, err := w.GetNodeAccountTransactor()
 	if err != nil {
 		return nil, err
 	}
 
-	gasInfo, err := node.EstimateDistributeRewards(pnr.Client, validatorContractInfo.WithdrawVaultAddress, opts)
+	// Response
+	response := api.CanSendClRewardsBatchResponse{}
+
+	rewardsThreshold, err := stader_config.GetRewardsThreshold(sdcfg, nil)
 	if err != nil {
 		return nil, err
 	}
-	response.GasInfo = gasInfo
+
+	response.Validators = make([]api.CanSendClRewardsResult, len(validatorPubKeys))
+	for i, validatorPubKey := range validatorPubKeys {
+		result := api.CanSendClRewardsResult{
+			Pubkey: validatorPubKey,
+		}
+		canSend, err := getCanSendClRewards(pnr, putils, rewardsThreshold, validatorPubKey, opts)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.CanSend = *canSend
+			response.TotalGasInfo.EstGasLimit += canSend.GasInfo.EstGasLimit
+			response.TotalGasInfo.SafeGasLimit += canSend.GasInfo.SafeGasLimit
+		}
+		response.Validators[i] = result
+	}
 
 	return &response, nil
 }
@@ -130,33 +199,113 @@ func SendClRewards(c *cli.Context, validatorPubKey types.ValidatorPubkey) (*api.
 		return nil, err
 	}
 
-	validatorId, err := node.GetValidatorIdByPubKey(pnr, validatorPubKey.Bytes(), nil)
+	clRewardsAmount, tx, err := sendClRewards(pnr, putils, validatorPubKey, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	validatorContractInfo, err := node.GetValidatorInfo(pnr, validatorId, nil)
+	response.ClRewardsAmount = clRewardsAmount
+	response.OperatorRewardAddress = operatorInfo.OperatorRewardAddress
+
+	response.TxHash = tx.Hash()
+
+	return &response, nil
+}
+
+func SendClRewardsBatch(c *cli.Context, validatorPubKeys []types.ValidatorPubkey) (*api.SendClRewardsBatchResponse, error) {
+	w, err := services.GetWallet(c)
 	if err != nil {
 		return nil, err
 	}
-	withdrawVaultBalance, err := tokens.GetEthBalance(pnr.Client, validatorContractInfo.WithdrawVaultAddress, nil)
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
 	if err != nil {
 		return nil, err
 	}
-	withdrawVaultRewardShares, err := pool_utils.CalculateRewardShare(putils, 1, withdrawVaultBalance, nil)
+	putils, err := services.GetPoolUtilsContract(c)
+	if err != nil {
+		return nil, err
+	}
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := w.GetNodeAccountTransactor()
 	if err != nil {
 		return nil, err
 	}
 
-	response.ClRewardsAmount = withdrawVaultRewardShares.OperatorShare
-	response.OperatorRewardAddress = operatorInfo.OperatorRewardAddress
+	response := api.SendClRewardsBatchResponse{}
 
-	tx, err := node.DistributeRewards(pnr.Client, validatorContractInfo.WithdrawVaultAddress, opts)
+	operatorId, err := node.GetOperatorId(pnr, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+	operatorInfo, err := node.GetOperatorInfo(pnr, operatorId, nil)
 	if err != nil {
 		return nil, err
 	}
+	response.OperatorRewardAddress = operatorInfo.OperatorRewardAddress
 
-	response.TxHash = tx.Hash()
+	// Override the provided pending TX if requested
+	err = eth1.CheckForNonceOverride(c, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Error checking for nonce override: %w", err)
+	}
+
+	// Give every transaction in the batch its own nonce up front
+	if opts.Nonce == nil {
+		nextNonce, err := pnr.Client.PendingNonceAt(context.Background(), opts.From)
+		if err != nil {
+			return nil, fmt.Errorf("Could not get next available nonce: %w", err)
+		}
+		opts.Nonce = big.NewInt(0).SetUint64(nextNonce)
+	}
+
+	response.Results = make([]api.BatchValidatorResult, len(validatorPubKeys))
+	for i, validatorPubKey := range validatorPubKeys {
+		result := api.BatchValidatorResult{
+			Pubkey: validatorPubKey,
+		}
+
+		amount, tx, err := sendClRewards(pnr, putils, validatorPubKey, opts)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			result.Amount = amount
+			result.TxHash = tx.Hash()
+			opts.Nonce = big.NewInt(0).Add(opts.Nonce, big.NewInt(1))
+		}
+		response.Results[i] = result
+	}
 
 	return &response, nil
 }
+
+// Send the CL rewards of a single validator's withdraw vault to the operator
+func sendClRewards(pnr *stader.PermissionlessNodeRegistryContractManager, putils *stader.PoolUtilsContractManager, validatorPubKey types.ValidatorPubkey, opts *bind.TransactOpts) (*big.Int, *ethtypes.Transaction, error) {
+	validatorId, err := node.GetValidatorIdByPubKey(pnr, validatorPubKey.Bytes(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validatorContractInfo, err := node.GetValidatorInfo(pnr, validatorId, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	withdrawVaultBalance, err := tokens.GetEthBalance(pnr.Client, validatorContractInfo.WithdrawVaultAddress, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	withdrawVaultRewardShares, err := pool_utils.CalculateRewardShare(putils, 1, withdrawVaultBalance, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := node.DistributeRewards(pnr.Client, validatorContractInfo.WithdrawVaultAddress, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return withdrawVaultRewardShares.OperatorShare, tx, nil
+}
@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"context"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/stader-labs/stader-node/stader-lib/tokens"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/urfave/cli"
+)
+
+func canSettleExitFunds(c *cli.Context, validatorPubKey types.ValidatorPubkey) (*api.CanSettleExitFunds, error) {
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	if err := services.RequireNodeRegistered(c); err != nil {
+		return nil, err
+	}
+	// Get services
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.CanSettleExitFunds{}
+
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	operatorId, err := node.GetOperatorId(pnr, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	validatorId, err := node.GetValidatorIdByPubKey(pnr, validatorPubKey.Bytes(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if validatorId.Int64() == 0 {
+		response.ValidatorNotRegistered = true
+		return &response, nil
+	}
+
+	validatorContractInfo, err := node.GetValidatorInfo(pnr, validatorId, nil)
+	if err != nil {
+		return nil, err
+	}
+	response.WithdrawVaultAddress = validatorContractInfo.WithdrawVaultAddress
+
+	// Only the validator's own operator can settle its vault
+	if validatorContractInfo.OperatorId == nil || validatorContractInfo.OperatorId.Cmp(operatorId) != 0 {
+		response.ValidatorNotOwned = true
+		return &response, nil
+	}
+
+	if validatorContractInfo.Status == 5 {
+		response.VaultAlreadySettled = true
+		return &response, nil
+	}
+
+	// The vault can only be settled once the beacon chain has paid out the full balance
+	validatorStatus, err := bc.GetValidatorStatus(context.Background(), validatorPubKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !validatorStatus.Exists {
+		response.ValidatorNotRegistered = true
+		return &response, nil
+	}
+	if validatorStatus.Status != beacon.ValidatorState_WithdrawalDone {
+		response.ValidatorNotWithdrawn = true
+		return &response, nil
+	}
+
+	withdrawVaultBalance, err := tokens.GetEthBalance(pnr.Client, validatorContractInfo.WithdrawVaultAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	response.WithdrawVaultBalance = withdrawVaultBalance
+	if withdrawVaultBalance.Sign() == 0 {
+		response.NoEthToWithdraw = true
+		return &response, nil
+	}
+
+	// The vault only accepts settleFunds from the node registry, which calls it once the oracle reports the validator
+	// as withdrawn, so there's nothing for the operator to send; report what the operator will be paid instead
+	withdrawShares, err := node.CalculateValidatorWithdrawVaultWithdrawShare(pnr.Client, validatorContractInfo.WithdrawVaultAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	response.OperatorShare = withdrawShares.OperatorShare
+
+	return &response, nil
+}
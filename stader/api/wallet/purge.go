@@ -23,8 +23,10 @@ import (
 	"fmt"
 
 	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/doppelganger"
 	"github.com/stader-labs/stader-node/shared/types/api"
 	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/types"
 	"github.com/urfave/cli"
 )
 
@@ -69,6 +71,21 @@ func purge(c *cli.Context) (*api.PurgeResponse, error) {
 		return nil, fmt.Errorf("error deleting validator storage: %w", err)
 	}
 
+	// The held keys are gone as well, so there's nothing left to watch
+	watch, err := services.GetDoppelgangerWatch(c)
+	if err != nil {
+		return nil, err
+	}
+	err = watch.Update(func(watched map[types.ValidatorPubkey]doppelganger.WatchedKey) error {
+		for pubkey := range watched {
+			delete(watched, pubkey)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error clearing the doppelganger watch: %w", err)
+	}
+
 	// Delete the wallet and password
 	err = w.Delete()
 	if err != nil {
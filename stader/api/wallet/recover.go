@@ -29,7 +29,9 @@ import (
 	"github.com/stader-labs/stader-node/shared/services"
 	"github.com/stader-labs/stader-node/shared/services/wallet"
 	"github.com/stader-labs/stader-node/shared/types/api"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
 	walletutils "github.com/stader-labs/stader-node/shared/utils/wallet"
+	"github.com/stader-labs/stader-node/stader-lib/types"
 )
 
 const (
@@ -121,11 +123,39 @@ func recoverWallet(c *cli.Context, mnemonic string) (*api.RecoverWalletResponse,
 		return nil, err
 	}
 
+	// The recovered keys may still be running on the machine they came from, so check them before the validator client loads them
+	if len(response.ValidatorKeys) > 0 {
+		response.DoppelgangerWatch, err = activateRecoveredKeys(c, w, response.ValidatorKeys)
+		if err != nil {
+			return nil, fmt.Errorf("the wallet was recovered, but its validator keys could not be checked for doppelgangers: %w", err)
+		}
+	}
+
 	// Return response
 	return &response, nil
 
 }
 
+func activateRecoveredKeys(c *cli.Context, w *wallet.Wallet, pubkeys []types.ValidatorPubkey) (bool, error) {
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return false, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return false, err
+	}
+	d, err := services.GetDocker(c)
+	if err != nil {
+		return false, err
+	}
+	watch, err := services.GetDoppelgangerWatch(c)
+	if err != nil {
+		return false, err
+	}
+	return validator.ActivateValidatorKeys(cfg, bc, d, w, watch, pubkeys, "recovery", nil)
+}
+
 func searchAndRecoverWallet(c *cli.Context, mnemonic string, address common.Address) (*api.SearchAndRecoverWalletResponse, error) {
 
 	// Get services
@@ -21,6 +21,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package collector
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/stader-labs/stader-node/stader-lib/stader"
@@ -110,7 +111,7 @@ func (collector *BeaconCollector) Collect(channel chan<- prometheus.Metric) {
 		}
 	}
 
-	head, err := collector.bc.GetBeaconHead()
+	head, err := collector.bc.GetBeaconHead(context.Background())
 	if err != nil {
 		collector.logError(fmt.Errorf("error getting Beacon chain head: %w", err))
 		return
@@ -118,7 +119,7 @@ func (collector *BeaconCollector) Collect(channel chan<- prometheus.Metric) {
 
 	wg.Go(func() error {
 		// Get current duties
-		duties, err := collector.bc.GetValidatorSyncDuties(validatorIndices, head.Epoch)
+		duties, err := collector.bc.GetValidatorSyncDuties(context.Background(), validatorIndices, head.Epoch)
 		if err != nil {
 			return fmt.Errorf("Error getting sync duties: %w", err)
 		}
@@ -137,7 +138,7 @@ func (collector *BeaconCollector) Collect(channel chan<- prometheus.Metric) {
 		config := state.BeaconConfig
 
 		// Get upcoming duties
-		duties, err := collector.bc.GetValidatorSyncDuties(validatorIndices, head.Epoch+config.EpochsPerSyncCommitteePeriod)
+		duties, err := collector.bc.GetValidatorSyncDuties(context.Background(), validatorIndices, head.Epoch+config.EpochsPerSyncCommitteePeriod)
 		if err != nil {
 			return fmt.Errorf("Error getting sync duties: %w", err)
 		}
@@ -153,7 +154,7 @@ func (collector *BeaconCollector) Collect(channel chan<- prometheus.Metric) {
 
 	wg.Go(func() error {
 		// Get proposals in this epoch
-		duties, err := collector.bc.GetValidatorProposerDuties(validatorIndices, head.Epoch)
+		duties, err := collector.bc.GetValidatorProposerDuties(context.Background(), validatorIndices, head.Epoch)
 		if err != nil {
 			return fmt.Errorf("Error getting proposer duties: %w", err)
 		}
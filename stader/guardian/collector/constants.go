@@ -15,6 +15,8 @@ const InitializedValidators = "initialized_validators"            //GetValidator
 const InvalidSignatureValidators = "invalid_signature_validators" //GetValidatorStatus
 const FrontRunValidators = "front_run_validators"                 //GetValidatorStatus
 const FundsSettledValidators = "funds_settled_validators"         //GetValidatorStatus
+const PresignPendingValidators = "presign_pending_validators"     //presign database
+const QuarantinedMerkleProofs = "quarantined_merkle_proofs"       //merkle proof quarantine folder
 
 const TotalETHBonded = "total_eth_bonded"
 const TotalSDBonded = "total_sd_bonded"
@@ -30,6 +32,32 @@ const UnclaimedSocializingPoolSdRewards = "unclaimed_socializing_pool_sd_rewards
 const UnclaimedCLRewards = "unclaimed_cl_rewards"
 const NextRewardCycleTime = "next_reward_cycle_time"
 
+// Validator duty performance => stader_performance + key, labelled per validator
+const PerformanceSub = "performance"
+
+const AttestationsAssigned = "attestations_assigned_total"
+const AttestationsMissed = "attestations_missed_total"
+const AttestationsHeadCorrect = "attestations_head_correct_total"
+const AttestationsTargetCorrect = "attestations_target_correct_total"
+const AttestationInclusionDelay = "attestation_inclusion_delay"
+const ProposalsAssigned = "proposals_assigned_total"
+const ProposalsMissed = "proposals_missed_total"
+const SyncCommitteeAssigned = "sync_committee_assigned_total"
+const SyncCommitteeMissed = "sync_committee_missed_total"
+const LastProcessedEpoch = "last_processed_epoch"
+
+// Validator penalties => stader_penalty + key, labelled per validator
+const PenaltySub = "penalty"
+
+const ValidatorTotalPenalty = "total"
+const ValidatorMevTheftPenalty = "mev_theft"
+const ValidatorMissedAttestationPenalty = "missed_attestation"
+const ValidatorAdditionalPenalty = "additional"
+const ValidatorPenaltyThresholdRatio = "exit_threshold_ratio"
+const ExitPenaltyThreshold = "exit_threshold"
+const ForceExitBlock = "force_exit_block"
+const ForceExitedValidators = "force_exited_validators"
+
 // Node Health => stader_node_health+ key
 const NodeSub = "node_health"
 const CPUUsage = "cpu_usage"
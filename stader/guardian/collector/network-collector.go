@@ -58,6 +58,12 @@ type NetworkCollector struct {
 	// The min amount of sd value that can be staked to get rewards
 	MinEthThreshold *prometheus.Desc
 
+	// The EL block all of the cached contract figures were read at
+	StateElBlock *prometheus.Desc
+
+	// The beacon slot all of the cached validator figures were read at
+	StateBeaconSlot *prometheus.Desc
+
 	// The beacon client
 	bc beacon.Client
 
@@ -138,6 +144,14 @@ func NewNetworkCollector(bc beacon.Client, ec stader.ExecutionClient, nodeAddres
 			"The maximum amount of sd value that can be staked to get rewards",
 			nil, nil,
 		),
+		StateElBlock: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "state_el_block"),
+			"The EL block the current metrics were read at",
+			nil, nil,
+		),
+		StateBeaconSlot: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "state_beacon_slot"),
+			"The beacon slot the current metrics were read at",
+			nil, nil,
+		),
 		bc:          bc,
 		ec:          ec,
 		nodeAddress: nodeAddress,
@@ -163,6 +177,8 @@ func (collector *NetworkCollector) Describe(channel chan<- *prometheus.Desc) {
 	channel <- collector.CollateralRatioInSd
 	channel <- collector.MinEthThreshold
 	channel <- collector.MaxEthThreshold
+	channel <- collector.StateElBlock
+	channel <- collector.StateBeaconSlot
 }
 
 // Collect the latest metric values and pass them to Prometheus
@@ -202,6 +218,10 @@ func (collector *NetworkCollector) Collect(channel chan<- prometheus.Metric) {
 		collector.MinEthThreshold, prometheus.GaugeValue, state.StaderNetworkDetails.MinEthThreshold)
 	channel <- prometheus.MustNewConstMetric(
 		collector.MaxEthThreshold, prometheus.GaugeValue, state.StaderNetworkDetails.MaxEthThreshold)
+	channel <- prometheus.MustNewConstMetric(
+		collector.StateElBlock, prometheus.GaugeValue, float64(state.ElBlockNumber))
+	channel <- prometheus.MustNewConstMetric(
+		collector.StateBeaconSlot, prometheus.GaugeValue, float64(state.BeaconSlotNumber))
 }
 
 // Log error messages
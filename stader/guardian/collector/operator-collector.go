@@ -23,6 +23,8 @@ type OperatorCollector struct {
 	InvalidSignatureValidators           *prometheus.Desc
 	IntializedValidators                 *prometheus.Desc
 	FundsSettledValidators               *prometheus.Desc
+	PresignPendingValidators             *prometheus.Desc
+	QuarantinedMerkleProofs              *prometheus.Desc
 	UnclaimedClRewards                   *prometheus.Desc
 	UnclaimedNonSocializingPoolElRewards *prometheus.Desc
 	CumulativePenalty                    *prometheus.Desc
@@ -88,6 +90,12 @@ func NewOperatorCollector(
 		FundsSettledValidators: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, OperatorSub, FundsSettledValidators), "", nil, nil,
 		),
+		PresignPendingValidators: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, OperatorSub, PresignPendingValidators), "", nil, nil,
+		),
+		QuarantinedMerkleProofs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, OperatorSub, QuarantinedMerkleProofs), "", nil, nil,
+		),
 		UnclaimedClRewards: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, OperatorSub, UnclaimedCLRewards), "", nil, nil,
 		),
@@ -132,6 +140,8 @@ func (collector *OperatorCollector) Describe(channel chan<- *prometheus.Desc) {
 	channel <- collector.InvalidSignatureValidators
 	channel <- collector.WithdrawnValidators
 	channel <- collector.FundsSettledValidators
+	channel <- collector.PresignPendingValidators
+	channel <- collector.QuarantinedMerkleProofs
 	channel <- collector.UnclaimedClRewards
 	channel <- collector.UnclaimedNonSocializingPoolElRewards
 	channel <- collector.CumulativePenalty
@@ -159,6 +169,8 @@ func (collector *OperatorCollector) Collect(channel chan<- prometheus.Metric) {
 	channel <- prometheus.MustNewConstMetric(collector.IntializedValidators, prometheus.GaugeValue, float64(state.StaderNetworkDetails.InitializedValidators.Int64()))
 	channel <- prometheus.MustNewConstMetric(collector.FrontRunValidators, prometheus.GaugeValue, float64(state.StaderNetworkDetails.FrontRunValidators.Int64()))
 	channel <- prometheus.MustNewConstMetric(collector.FundsSettledValidators, prometheus.GaugeValue, float64(state.StaderNetworkDetails.FundsSettledValidators.Int64()))
+	channel <- prometheus.MustNewConstMetric(collector.PresignPendingValidators, prometheus.GaugeValue, float64(state.StaderNetworkDetails.PresignPendingValidators.Int64()))
+	channel <- prometheus.MustNewConstMetric(collector.QuarantinedMerkleProofs, prometheus.GaugeValue, float64(state.StaderNetworkDetails.QuarantinedMerkleProofs.Int64()))
 	channel <- prometheus.MustNewConstMetric(collector.UnclaimedClRewards, prometheus.GaugeValue, state.StaderNetworkDetails.UnclaimedClRewards)
 	channel <- prometheus.MustNewConstMetric(collector.CumulativePenalty, prometheus.GaugeValue, state.StaderNetworkDetails.CumulativePenalty)
 	channel <- prometheus.MustNewConstMetric(collector.UnclaimedNonSocializingPoolElRewards, prometheus.GaugeValue, state.StaderNetworkDetails.UnclaimedNonSocializingPoolElRewards)
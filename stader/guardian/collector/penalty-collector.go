@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+// Represents the collector for the penalties of the node's validators
+type PenaltyCollector struct {
+	TotalPenalty             *prometheus.Desc
+	MevTheftPenalty          *prometheus.Desc
+	MissedAttestationPenalty *prometheus.Desc
+	AdditionalPenalty        *prometheus.Desc
+	ThresholdRatio           *prometheus.Desc
+	ExitPenaltyThreshold     *prometheus.Desc
+	ForceExitBlock           *prometheus.Desc
+	ForceExitedValidators    *prometheus.Desc
+
+	// The thread-safe locker for the network state
+	stateLocker *MetricsCacheContainer
+}
+
+// Create a new PenaltyCollector instance
+func NewPenaltyCollector(stateLocker *MetricsCacheContainer) *PenaltyCollector {
+	validatorLabels := []string{"validator", "pubkey"}
+	return &PenaltyCollector{
+		TotalPenalty: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PenaltySub, ValidatorTotalPenalty), "The validator's total penalty in ETH as last recorded on-chain", validatorLabels, nil,
+		),
+		MevTheftPenalty: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PenaltySub, ValidatorMevTheftPenalty), "The validator's MEV theft penalty in ETH", validatorLabels, nil,
+		),
+		MissedAttestationPenalty: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PenaltySub, ValidatorMissedAttestationPenalty), "The validator's missed attestation penalty in ETH", validatorLabels, nil,
+		),
+		AdditionalPenalty: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PenaltySub, ValidatorAdditionalPenalty), "The additional penalty in ETH set on the validator by Stader", validatorLabels, nil,
+		),
+		ThresholdRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PenaltySub, ValidatorPenaltyThresholdRatio), "The fraction of the exit threshold the validator's total penalty has reached, it is force exited at 1", validatorLabels, nil,
+		),
+		ExitPenaltyThreshold: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PenaltySub, ExitPenaltyThreshold), "The total penalty in ETH at which a validator is force exited", nil, nil,
+		),
+		ForceExitBlock: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PenaltySub, ForceExitBlock), "The block the validator was force exited in", validatorLabels, nil,
+		),
+		ForceExitedValidators: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PenaltySub, ForceExitedValidators), "The number of the node's validators that have been force exited", nil, nil,
+		),
+		stateLocker: stateLocker,
+	}
+}
+
+// Write metric descriptions to the Prometheus channel
+func (collector *PenaltyCollector) Describe(channel chan<- *prometheus.Desc) {
+	channel <- collector.TotalPenalty
+	channel <- collector.MevTheftPenalty
+	channel <- collector.MissedAttestationPenalty
+	channel <- collector.AdditionalPenalty
+	channel <- collector.ThresholdRatio
+	channel <- collector.ExitPenaltyThreshold
+	channel <- collector.ForceExitBlock
+	channel <- collector.ForceExitedValidators
+}
+
+// Collect the latest metric values and pass them to Prometheus
+func (collector *PenaltyCollector) Collect(channel chan<- prometheus.Metric) {
+	// Get the latest state
+	state := collector.stateLocker.GetMetricsContainer()
+	details := state.StaderNetworkDetails
+
+	exitPenaltyThresholdSet := false
+	for pubkey, penalty := range details.ValidatorPenalties {
+		if !exitPenaltyThresholdSet && penalty.ExitPenaltyThreshold != nil {
+			channel <- prometheus.MustNewConstMetric(collector.ExitPenaltyThreshold, prometheus.GaugeValue, eth.WeiToEth(penalty.ExitPenaltyThreshold))
+			exitPenaltyThresholdSet = true
+		}
+
+		labels := collector.getLabels(details.ValidatorStatusMap[pubkey], pubkey)
+		channel <- prometheus.MustNewConstMetric(collector.TotalPenalty, prometheus.GaugeValue, eth.WeiToEth(penalty.TotalPenalty), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.MevTheftPenalty, prometheus.GaugeValue, eth.WeiToEth(penalty.MevTheftPenalty), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.MissedAttestationPenalty, prometheus.GaugeValue, eth.WeiToEth(penalty.MissedAttestationPenalty), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.AdditionalPenalty, prometheus.GaugeValue, eth.WeiToEth(penalty.AdditionalPenalty), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.ThresholdRatio, prometheus.GaugeValue, penalty.ThresholdRatio(), labels...)
+	}
+
+	for pubkey, forceExit := range details.ForceExits {
+		labels := collector.getLabels(details.ValidatorStatusMap[pubkey], pubkey)
+		channel <- prometheus.MustNewConstMetric(collector.ForceExitBlock, prometheus.GaugeValue, float64(forceExit.BlockNumber), labels...)
+	}
+	channel <- prometheus.MustNewConstMetric(collector.ForceExitedValidators, prometheus.GaugeValue, float64(len(details.ForceExits)))
+}
+
+// Validators that aren't on the Beacon chain yet don't have an index
+func (collector *PenaltyCollector) getLabels(status beacon.ValidatorStatus, pubkey types.ValidatorPubkey) []string {
+	index := ""
+	if status.Exists {
+		index = strconv.FormatUint(status.Index, 10)
+	}
+	return []string{index, pubkey.Hex()}
+}
@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stader-labs/stader-node/shared/services/performance"
+)
+
+// Represents the collector for the duty performance of the node's validators
+type PerformanceCollector struct {
+	AttestationsAssigned      *prometheus.Desc
+	AttestationsMissed        *prometheus.Desc
+	AttestationsHeadCorrect   *prometheus.Desc
+	AttestationsTargetCorrect *prometheus.Desc
+	AttestationInclusionDelay *prometheus.Desc
+	ProposalsAssigned         *prometheus.Desc
+	ProposalsMissed           *prometheus.Desc
+	SyncCommitteeAssigned     *prometheus.Desc
+	SyncCommitteeMissed       *prometheus.Desc
+	LastProcessedEpoch        *prometheus.Desc
+
+	// The duty performance database
+	db *performance.Database
+
+	// Prefix for logging
+	logPrefix string
+}
+
+// Create a new PerformanceCollector instance
+func NewPerformanceCollector(db *performance.Database) *PerformanceCollector {
+	validatorLabels := []string{"validator", "pubkey"}
+	return &PerformanceCollector{
+		AttestationsAssigned: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, AttestationsAssigned), "The number of attestations the validator was due to make", validatorLabels, nil,
+		),
+		AttestationsMissed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, AttestationsMissed), "The number of attestations that weren't included in time", validatorLabels, nil,
+		),
+		AttestationsHeadCorrect: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, AttestationsHeadCorrect), "The number of included attestations that voted for the canonical head", validatorLabels, nil,
+		),
+		AttestationsTargetCorrect: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, AttestationsTargetCorrect), "The number of included attestations that voted for the canonical target", validatorLabels, nil,
+		),
+		AttestationInclusionDelay: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, AttestationInclusionDelay), "The inclusion delay in slots of the validator's attestation in the last processed epoch, 0 if it was missed", validatorLabels, nil,
+		),
+		ProposalsAssigned: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, ProposalsAssigned), "The number of blocks the validator was due to propose", validatorLabels, nil,
+		),
+		ProposalsMissed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, ProposalsMissed), "The number of blocks the validator didn't propose", validatorLabels, nil,
+		),
+		SyncCommitteeAssigned: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, SyncCommitteeAssigned), "The number of sync committee signatures the validator was due to make", validatorLabels, nil,
+		),
+		SyncCommitteeMissed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, SyncCommitteeMissed), "The number of sync committee signatures missing from blocks", validatorLabels, nil,
+		),
+		LastProcessedEpoch: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, PerformanceSub, LastProcessedEpoch), "The last epoch whose duties were checked", nil, nil,
+		),
+		db:        db,
+		logPrefix: "Performance Collector",
+	}
+}
+
+// Write metric descriptions to the Prometheus channel
+func (collector *PerformanceCollector) Describe(channel chan<- *prometheus.Desc) {
+	channel <- collector.AttestationsAssigned
+	channel <- collector.AttestationsMissed
+	channel <- collector.AttestationsHeadCorrect
+	channel <- collector.AttestationsTargetCorrect
+	channel <- collector.AttestationInclusionDelay
+	channel <- collector.ProposalsAssigned
+	channel <- collector.ProposalsMissed
+	channel <- collector.SyncCommitteeAssigned
+	channel <- collector.SyncCommitteeMissed
+	channel <- collector.LastProcessedEpoch
+}
+
+// Collect the latest metric values and pass them to Prometheus
+func (collector *PerformanceCollector) Collect(channel chan<- prometheus.Metric) {
+	lastEpoch, found, err := collector.db.GetLastEpoch()
+	if err != nil {
+		collector.logError(err)
+		return
+	}
+	if !found {
+		return
+	}
+	totals, err := collector.db.GetTotals()
+	if err != nil {
+		collector.logError(err)
+		return
+	}
+
+	channel <- prometheus.MustNewConstMetric(collector.LastProcessedEpoch, prometheus.GaugeValue, float64(lastEpoch))
+	for index, validatorTotals := range totals {
+		labels := []string{strconv.FormatUint(index, 10), validatorTotals.Pubkey.Hex()}
+		channel <- prometheus.MustNewConstMetric(collector.AttestationsAssigned, prometheus.CounterValue, float64(validatorTotals.AttestationsAssigned), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.AttestationsMissed, prometheus.CounterValue, float64(validatorTotals.AttestationsMissed), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.AttestationsHeadCorrect, prometheus.CounterValue, float64(validatorTotals.HeadCorrect), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.AttestationsTargetCorrect, prometheus.CounterValue, float64(validatorTotals.TargetCorrect), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.AttestationInclusionDelay, prometheus.GaugeValue, float64(validatorTotals.Latest.InclusionDelay), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.ProposalsAssigned, prometheus.CounterValue, float64(validatorTotals.ProposalsAssigned), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.ProposalsMissed, prometheus.CounterValue, float64(validatorTotals.ProposalsMissed), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.SyncCommitteeAssigned, prometheus.CounterValue, float64(validatorTotals.SyncAssigned), labels...)
+		channel <- prometheus.MustNewConstMetric(collector.SyncCommitteeMissed, prometheus.CounterValue, float64(validatorTotals.SyncMissed), labels...)
+	}
+}
+
+// Log error messages
+func (collector *PerformanceCollector) logError(err error) {
+	fmt.Printf("[%s] %s\n", collector.logPrefix, err.Error())
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stader-labs/stader-node/shared/services/beacon"
 	"github.com/stader-labs/stader-node/shared/services/state"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
 	"github.com/stader-labs/stader-node/stader-lib/types"
 )
 
@@ -37,6 +38,8 @@ func NewMetricsCacheContainer() *MetricsCacheContainer {
 				FrontRunValidators:                   big.NewInt(0),
 				InvalidSignatureValidators:           big.NewInt(0),
 				FundsSettledValidators:               big.NewInt(0),
+				PresignPendingValidators:             big.NewInt(0),
+				QuarantinedMerkleProofs:              big.NewInt(0),
 				CumulativePenalty:                    0,
 				UnclaimedClRewards:                   0,
 				UnclaimedNonSocializingPoolElRewards: 0,
@@ -55,6 +58,8 @@ func NewMetricsCacheContainer() *MetricsCacheContainer {
 				},
 				ValidatorStatusMap:  make(map[types.ValidatorPubkey]beacon.ValidatorStatus),
 				ValidatorInfoMap:    make(map[types.ValidatorPubkey]contracts.Validator),
+				ValidatorPenalties:  make(map[types.ValidatorPubkey]stdr.ValidatorPenalty),
+				ForceExits:          make(map[types.ValidatorPubkey]stdr.ForceExitEvent),
 				CollateralRatio:     0,
 				CollateralRatioInSd: 0,
 			},
@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package guardian
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sync"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
 	"github.com/stader-labs/stader-node/shared/services/state"
 	"github.com/stader-labs/stader-node/stader/guardian/collector"
 
@@ -43,9 +45,10 @@ var taskCooldown, _ = time.ParseDuration("10s")
 const (
 	MaxConcurrentEth1Requests = 200
 
-	ErrorColor   = color.FgRed
-	UpdateColor  = color.FgBlue
-	MetricsColor = color.FgHiYellow
+	ErrorColor       = color.FgRed
+	UpdateColor      = color.FgBlue
+	MetricsColor     = color.FgHiYellow
+	PerformanceColor = color.FgHiCyan
 )
 
 // Register guardian command
@@ -94,8 +97,12 @@ func run(c *cli.Context) error {
 		return err
 	}
 
+	// Refresh the metrics as soon as the chain finalizes or reorgs instead of waiting for the next interval
+	refresh := make(chan struct{}, 1)
+	go watchBeaconEvents(bc, updateLog, errorLog, refresh)
+
 	wg := new(sync.WaitGroup)
-	wg.Add(2)
+	wg.Add(3)
 
 	// Run metrics loop
 	go func() {
@@ -128,12 +135,27 @@ func run(c *cli.Context) error {
 				continue
 			}
 			metricsCache.UpdateMetricsContainer(networkStateCache)
-			time.Sleep(tasksInterval)
+
+			select {
+			case <-refresh:
+			case <-time.After(tasksInterval):
+			}
 		}
 
 		wg.Done()
 	}()
 
+	// Run performance tracker
+	go func() {
+		tracker, err := newPerformanceTracker(c, log.NewColorLogger(PerformanceColor), errorLog, metricsCache)
+		if err != nil {
+			errorLog.Println(err)
+		} else {
+			tracker.run()
+		}
+		wg.Done()
+	}()
+
 	go func() {
 		err := runMetricsServer(c, log.NewColorLogger(MetricsColor), metricsCache)
 		if err != nil {
@@ -156,6 +178,31 @@ func configureHTTP() {
 	http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost = MaxConcurrentEth1Requests
 }
 
+// Request a metrics refresh whenever a finalized checkpoint or a reorg changes the chain state
+func watchBeaconEvents(bc beacon.Client, updateLog log.ColorLogger, errorLog log.ColorLogger, refresh chan<- struct{}) {
+	topics := []beacon.EventTopic{beacon.EventTopic_FinalizedCheckpoint, beacon.EventTopic_ChainReorg}
+	events := beacon.SubscribeEvents(context.Background(), bc, topics, func(err error) {
+		errorLog.Printlnf("Beacon event stream failed, reconnecting: %s", err.Error())
+	})
+
+	for event := range events {
+		switch event.Topic {
+		case beacon.EventTopic_FinalizedCheckpoint:
+			updateLog.Printlnf("Epoch %d was finalized, refreshing metrics", event.FinalizedCheckpoint.Epoch)
+		case beacon.EventTopic_ChainReorg:
+			updateLog.Printlnf("Beacon chain reorg of depth %d at slot %d, refreshing metrics", event.ChainReorg.Depth, event.ChainReorg.Slot)
+		default:
+			continue
+		}
+
+		// Several events before the loop gets to it only refresh once
+		select {
+		case refresh <- struct{}{}:
+		default:
+		}
+	}
+}
+
 func updateMetricsCache(m *state.MetricsCacheManager, nodeAddress common.Address) (*state.MetricsCache, error) {
 	// Get the networkStateCache of the network
 	metricsCache, err := m.GetHeadStateForNode(nodeAddress)
@@ -69,15 +69,24 @@ func runMetricsServer(c *cli.Context, logger log.ColorLogger, stateLocker *colle
 	if err != nil {
 		return err
 	}
+	performanceDb, err := services.GetPerformanceDatabase(c)
+	if err != nil {
+		return err
+	}
+
 	nodeAccountAddr := nodeAccount.Address
 	beaconCollector := collector.NewBeaconCollector(bc, ec, nodeAccountAddr, stateLocker)
 	networkCollector := collector.NewNetworkCollector(bc, ec, nodeAccountAddr, stateLocker)
 	operatorCollector := collector.NewOperatorCollector(bc, ec, nodeAccountAddr, stateLocker)
+	performanceCollector := collector.NewPerformanceCollector(performanceDb)
+	penaltyCollector := collector.NewPenaltyCollector(stateLocker)
 	// Set up Prometheus
 	registry := prometheus.NewRegistry()
 	registry.MustRegister(beaconCollector)
 	registry.MustRegister(networkCollector)
 	registry.MustRegister(operatorCollector)
+	registry.MustRegister(performanceCollector)
+	registry.MustRegister(penaltyCollector)
 
 	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
 
@@ -0,0 +1,160 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package guardian
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/performance"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/stader-labs/stader-node/stader/guardian/collector"
+)
+
+// Config
+const (
+	// When starting without history, only go back this many epochs
+	maxPerformanceCatchUpEpochs = 8
+)
+
+// Track the duty performance of the node's validators, one epoch at a time
+type performanceTracker struct {
+	log          log.ColorLogger
+	errLog       log.ColorLogger
+	bc           beacon.Client
+	db           *performance.Database
+	tracker      *performance.Tracker
+	metricsCache *collector.MetricsCacheContainer
+	epochTime    time.Duration
+}
+
+// Create a new performance tracker
+func newPerformanceTracker(c *cli.Context, logger log.ColorLogger, errorLogger log.ColorLogger, metricsCache *collector.MetricsCacheContainer) (*performanceTracker, error) {
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	db, err := services.GetPerformanceDatabase(c)
+	if err != nil {
+		return nil, err
+	}
+	eth2Config, err := bc.GetEth2Config(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &performanceTracker{
+		log:          logger,
+		errLog:       errorLogger,
+		bc:           bc,
+		db:           db,
+		tracker:      performance.NewTracker(bc, eth2Config.SlotsPerEpoch),
+		metricsCache: metricsCache,
+		epochTime:    time.Duration(eth2Config.SecondsPerEpoch) * time.Second,
+	}, nil
+}
+
+// Process each complete epoch as it comes
+func (t *performanceTracker) run() {
+	for {
+		if err := t.processNewEpochs(); err != nil {
+			t.errLog.Println(err)
+			time.Sleep(taskCooldown)
+			continue
+		}
+		time.Sleep(t.epochTime)
+	}
+}
+
+// Process every complete epoch since the last recorded one
+func (t *performanceTracker) processNewEpochs() error {
+
+	// Wait until the metrics loop has loaded the validators
+	state := t.metricsCache.GetMetricsContainer()
+	if state.BeaconSlotNumber == 0 {
+		return nil
+	}
+
+	head, err := t.bc.GetBeaconHead(context.Background())
+	if err != nil {
+		return fmt.Errorf("Could not get the beacon head: %w", err)
+	}
+	lastCompleteEpoch, ok := performance.GetLastCompleteEpoch(head.Epoch)
+	if !ok {
+		return nil
+	}
+
+	// Pick up where the last run stopped, without going too far back
+	startEpoch := uint64(0)
+	lastEpoch, found, err := t.db.GetLastEpoch()
+	if err != nil {
+		return err
+	}
+	if found {
+		startEpoch = lastEpoch + 1
+	}
+	if lastCompleteEpoch >= maxPerformanceCatchUpEpochs && startEpoch < lastCompleteEpoch-maxPerformanceCatchUpEpochs+1 {
+		startEpoch = lastCompleteEpoch - maxPerformanceCatchUpEpochs + 1
+	}
+
+	for epoch := startEpoch; epoch <= lastCompleteEpoch; epoch++ {
+		record, err := t.tracker.ProcessEpoch(context.Background(), epoch, getActiveValidators(state.ValidatorDetails, epoch))
+		if err != nil {
+			return fmt.Errorf("Could not process duties for epoch %d: %w", epoch, err)
+		}
+		if err := t.db.RecordEpoch(record); err != nil {
+			return fmt.Errorf("Could not record duties for epoch %d: %w", epoch, err)
+		}
+		t.logMisses(record)
+	}
+	return nil
+}
+
+// Log the duties the validators missed in an epoch
+func (t *performanceTracker) logMisses(record performance.EpochRecord) {
+	for _, duties := range record.Validators {
+		if duties.AttestationAssigned && !duties.AttestationIncluded {
+			t.log.Printlnf("Validator %d missed its attestation in epoch %d", duties.Index, record.Epoch)
+		}
+		if duties.ProposalsMissed > 0 {
+			t.log.Printlnf("Validator %d missed %d block proposal(s) in epoch %d", duties.Index, duties.ProposalsMissed, record.Epoch)
+		}
+		if duties.SyncMissed > 0 {
+			t.log.Printlnf("Validator %d missed %d of %d sync committee signatures in epoch %d", duties.Index, duties.SyncMissed, duties.SyncAssigned, record.Epoch)
+		}
+	}
+}
+
+// Get the validators that were active in an epoch, keyed by index
+func getActiveValidators(statuses map[types.ValidatorPubkey]beacon.ValidatorStatus, epoch uint64) map[uint64]types.ValidatorPubkey {
+	validators := map[uint64]types.ValidatorPubkey{}
+	for pubkey, status := range statuses {
+		if status.Exists && status.ActivationEpoch <= epoch && epoch < status.ExitEpoch {
+			validators[status.Index] = pubkey
+		}
+	}
+	return validators
+}
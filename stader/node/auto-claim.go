@@ -0,0 +1,455 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	stader_utils "github.com/stader-labs/stader-node/shared/utils/stader"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	pool_utils "github.com/stader-labs/stader-node/stader-lib/pool-utils"
+	socializing_pool "github.com/stader-labs/stader-node/stader-lib/socializing-pool"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	stader_config "github.com/stader-labs/stader-node/stader-lib/stader-config"
+	"github.com/stader-labs/stader-node/stader-lib/tokens"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+// The reward types the daemon can claim
+const (
+	spRewardsClaim       = "socializing-pool"
+	operatorRewardsClaim = "operator-rewards"
+	clRewardsClaim       = "cl-rewards"
+)
+
+// A claim the daemon made, or would have made in dry-run mode
+type autoClaimRecord struct {
+	Time      time.Time             `json:"time"`
+	Type      string                `json:"type"`
+	AmountEth *big.Int              `json:"amountEth"`
+	AmountSd  *big.Int              `json:"amountSd,omitempty"`
+	Cycles    []int64               `json:"cycles,omitempty"`
+	Validator types.ValidatorPubkey `json:"validator,omitempty"`
+	TxHash    common.Hash           `json:"txHash,omitempty"`
+	DryRun    bool                  `json:"dryRun"`
+}
+
+// Auto-claim task
+type autoClaim struct {
+	c      *cli.Context
+	log    log.ColorLogger
+	cfg    *config.StaderConfig
+	w      *wallet.Wallet
+	pnr    *stader.PermissionlessNodeRegistryContractManager
+	sp     *stader.SocializingPoolContractManager
+	orc    *stader.OperatorRewardsCollectorContractManager
+	putils *stader.PoolUtilsContractManager
+	sdcfg  *stader.StaderConfigContractManager
+	tx     *txSender
+
+	// The claims already logged in dry-run mode, which stay claimable on every run until they're made
+	dryRunClaims map[string]bool
+}
+
+// Create auto-claim task
+func newAutoClaim(c *cli.Context, logger log.ColorLogger, tx *txSender) (*autoClaim, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := services.GetSocializingPoolContract(c)
+	if err != nil {
+		return nil, err
+	}
+	orc, err := services.GetOperatorRewardsCollectorContract(c)
+	if err != nil {
+		return nil, err
+	}
+	putils, err := services.GetPoolUtilsContract(c)
+	if err != nil {
+		return nil, err
+	}
+	sdcfg, err := services.GetStaderConfigContract(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &autoClaim{
+		c:      c,
+		log:    logger,
+		cfg:    cfg,
+		w:      w,
+		pnr:    pnr,
+		sp:     sp,
+		orc:    orc,
+		putils: putils,
+		sdcfg:  sdcfg,
+		tx:     tx,
+
+		dryRunClaims: map[string]bool{},
+	}, nil
+
+}
+
+// Run each enabled claim policy; one failing doesn't stop the others
+func (a *autoClaim) run() error {
+
+	policies := []struct {
+		name    string
+		enabled bool
+		claim   func(nodeAddress common.Address) error
+	}{
+		{spRewardsClaim, a.cfg.AutoClaim.EnableSpRewards.Value == true, a.claimSpRewards},
+		{operatorRewardsClaim, a.cfg.AutoClaim.EnableOperatorRewards.Value == true, a.claimOperatorRewards},
+		{clRewardsClaim, a.cfg.AutoClaim.EnableClRewards.Value == true, a.sendClRewards},
+	}
+
+	nodeAccount, err := a.w.GetNodeAccount()
+	if err != nil {
+		return err
+	}
+
+	failures := []string{}
+	for _, policy := range policies {
+		if !policy.enabled {
+			continue
+		}
+		err := policy.claim(nodeAccount.Address)
+		if errors.Is(err, errFeeTooHigh) {
+			a.log.Printlnf("Postponing the %s claim: %s", policy.name, err.Error())
+			continue
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("error running the %s claim: %s", policy.name, err.Error()))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+
+	return nil
+
+}
+
+// Claim the unclaimed socializing pool cycles the node has verified proofs for, in batches
+func (a *autoClaim) claimSpRewards(nodeAddress common.Address) error {
+
+	isPaused, err := socializing_pool.IsSocializingPoolPaused(a.sp, nil)
+	if err != nil {
+		return err
+	}
+	if isPaused {
+		return nil
+	}
+
+	rewardDetails, err := socializing_pool.GetRewardDetails(a.sp, nil)
+	if err != nil {
+		return err
+	}
+	quarantinedCycles, err := a.cfg.StaderNode.GetQuarantinedCycles(true)
+	if err != nil {
+		return err
+	}
+	quarantined := map[int64]bool{}
+	for _, cycle := range quarantinedCycles {
+		quarantined[cycle] = true
+	}
+
+	cycles := []*big.Int{}
+	totalEth := big.NewInt(0)
+	for i := int64(1); i < rewardDetails.CurrentIndex.Int64(); i++ {
+		if quarantined[i] {
+			continue
+		}
+		cycle := big.NewInt(i)
+		isClaimed, err := socializing_pool.HasClaimedRewards(a.sp, nodeAddress, cycle, nil)
+		if err != nil {
+			return err
+		}
+		if isClaimed {
+			continue
+		}
+		cycleMerkleProof, exists, err := a.cfg.StaderNode.ReadCycleCache(i)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// The merkle proofs downloader hasn't got to it yet
+			continue
+		}
+		if err := stader_utils.VerifyCycleMerkleProof(a.sp, nodeAddress, &cycleMerkleProof); err != nil {
+			a.log.Printlnf("WARNING: skipping cycle %d: %s", i, err.Error())
+			continue
+		}
+		_, amountEth, _, err := cycleMerkleProof.Decode()
+		if err != nil {
+			return err
+		}
+		cycles = append(cycles, cycle)
+		totalEth.Add(totalEth, amountEth)
+	}
+	if len(cycles) == 0 {
+		return nil
+	}
+
+	threshold := eth.EthToWei(a.cfg.AutoClaim.SpRewardsThreshold.Value.(float64))
+	if totalEth.Cmp(threshold) < 0 {
+		return nil
+	}
+	if err := a.checkBaseFee(a.cfg.AutoClaim.SpRewardsMaxBaseFee.Value.(float64)); err != nil {
+		return err
+	}
+
+	maxCycles := int(a.cfg.AutoClaim.SpRewardsMaxCycles.Value.(uint64))
+	for start := 0; start < len(cycles); start += maxCycles {
+		end := start + maxCycles
+		if end > len(cycles) {
+			end = len(cycles)
+		}
+		batch := cycles[start:end]
+
+		amountSd, amountEth, merkleProofs, err := a.cfg.StaderNode.GetClaimData(batch)
+		if err != nil {
+			return err
+		}
+		record := autoClaimRecord{
+			Type:      spRewardsClaim,
+			AmountEth: sumAmounts(amountEth),
+			AmountSd:  sumAmounts(amountSd),
+		}
+		for _, cycle := range batch {
+			record.Cycles = append(record.Cycles, cycle.Int64())
+		}
+
+		description := fmt.Sprintf("the socializing pool claim for cycles %v", record.Cycles)
+		err = a.claim(record, description, func(opts *bind.TransactOpts) (stader.GasInfo, error) {
+			return socializing_pool.EstimateClaimRewards(a.sp, batch, amountSd, amountEth, merkleProofs, opts)
+		}, func(opts *bind.TransactOpts) (common.Hash, error) {
+			tx, err := socializing_pool.ClaimRewards(a.sp, batch, amountSd, amountEth, merkleProofs, opts)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			return tx.Hash(), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+// Claim the rewards the operator rewards collector holds for the node
+func (a *autoClaim) claimOperatorRewards(nodeAddress common.Address) error {
+
+	balance, err := node.GetOperatorRewardsCollectorBalance(a.orc, nodeAddress, nil)
+	if err != nil {
+		return err
+	}
+	threshold := eth.EthToWei(a.cfg.AutoClaim.OperatorRewardsThreshold.Value.(float64))
+	if balance.Sign() == 0 || balance.Cmp(threshold) < 0 {
+		return nil
+	}
+	if err := a.checkBaseFee(a.cfg.AutoClaim.OperatorRewardsMaxBaseFee.Value.(float64)); err != nil {
+		return err
+	}
+
+	record := autoClaimRecord{
+		Type:      operatorRewardsClaim,
+		AmountEth: balance,
+	}
+	return a.claim(record, "the operator rewards claim", func(opts *bind.TransactOpts) (stader.GasInfo, error) {
+		return node.EstimateClaimOperatorRewards(a.orc, opts)
+	}, func(opts *bind.TransactOpts) (common.Hash, error) {
+		tx, err := node.ClaimOperatorRewards(a.orc, opts)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return tx.Hash(), nil
+	})
+
+}
+
+// Send the CL rewards of every withdraw vault whose operator share has reached the threshold
+func (a *autoClaim) sendClRewards(nodeAddress common.Address) error {
+
+	operatorId, err := node.GetOperatorId(a.pnr, nodeAddress, nil)
+	if err != nil {
+		return err
+	}
+	validators, pubkeys, err := stdr.GetAllValidatorsRegisteredWithOperator(a.pnr, operatorId, nodeAddress, nil)
+	if err != nil {
+		return err
+	}
+	rewardsThreshold, err := stader_config.GetRewardsThreshold(a.sdcfg, nil)
+	if err != nil {
+		return err
+	}
+	threshold := eth.EthToWei(a.cfg.AutoClaim.ClRewardsThreshold.Value.(float64))
+
+	baseFeeChecked := false
+	for _, pubkey := range pubkeys {
+		validator := validators[pubkey]
+		// Settled vaults have nothing left, and vaults above the rewards threshold hold the exited stake
+		if validator.Status == 5 {
+			continue
+		}
+		vaultBalance, err := tokens.GetEthBalance(a.pnr.Client, validator.WithdrawVaultAddress, nil)
+		if err != nil {
+			return err
+		}
+		rewardShares, err := pool_utils.CalculateRewardShare(a.putils, 1, vaultBalance, nil)
+		if err != nil {
+			return err
+		}
+		operatorShare := rewardShares.OperatorShare
+		if operatorShare.Sign() == 0 || operatorShare.Cmp(threshold) < 0 || operatorShare.Cmp(rewardsThreshold) > 0 {
+			continue
+		}
+
+		if !baseFeeChecked {
+			if err := a.checkBaseFee(a.cfg.AutoClaim.ClRewardsMaxBaseFee.Value.(float64)); err != nil {
+				return err
+			}
+			baseFeeChecked = true
+		}
+
+		vault := validator.WithdrawVaultAddress
+		record := autoClaimRecord{
+			Type:      clRewardsClaim,
+			AmountEth: operatorShare,
+			Validator: pubkey,
+		}
+		description := fmt.Sprintf("the CL rewards of validator %s", pubkey.Hex())
+		err = a.claim(record, description, func(opts *bind.TransactOpts) (stader.GasInfo, error) {
+			return node.EstimateDistributeRewards(a.pnr.Client, vault, opts)
+		}, func(opts *bind.TransactOpts) (common.Hash, error) {
+			tx, err := node.DistributeRewards(a.pnr.Client, vault, opts)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			return tx.Hash(), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+// Make sure the base fee is below the policy's limit
+func (a *autoClaim) checkBaseFee(maxBaseFeeGwei float64) error {
+	baseFee, err := a.tx.getBaseFee()
+	if err != nil {
+		return err
+	}
+	if baseFee.Cmp(eth.GweiToWei(maxBaseFeeGwei)) > 0 {
+		return fmt.Errorf("%w: the current base fee is %.2f gwei, the limit is %.2f gwei", errFeeTooHigh, eth.WeiToGwei(baseFee), maxBaseFeeGwei)
+	}
+	return nil
+}
+
+// Send the claim, or only log it in dry-run mode, and record it
+func (a *autoClaim) claim(record autoClaimRecord, description string, estimate func(opts *bind.TransactOpts) (stader.GasInfo, error), submit func(opts *bind.TransactOpts) (common.Hash, error)) error {
+
+	if a.cfg.AutoClaim.DryRun.Value == true {
+		// The amount can grow between runs, so the claim is identified by what it claims for
+		key := fmt.Sprintf("%s %v %s", record.Type, record.Cycles, record.Validator.Hex())
+		if a.dryRunClaims[key] {
+			return nil
+		}
+		a.dryRunClaims[key] = true
+		a.log.Printlnf("Dry run: would send %s for %.6f ETH", description, eth.WeiToEth(record.AmountEth))
+		record.DryRun = true
+	} else {
+		receipt, err := a.tx.send(description, 0, estimate, submit)
+		if err != nil {
+			return err
+		}
+		record.TxHash = receipt.TxHash
+		a.log.Printlnf("Claimed %.6f ETH with %s", eth.WeiToEth(record.AmountEth), description)
+	}
+
+	record.Time = time.Now()
+	if err := a.appendRecord(record); err != nil {
+		return fmt.Errorf("%s was sent but could not be recorded: %w", description, err)
+	}
+	return nil
+
+}
+
+// Add a record to the auto-claim log, one JSON object per line
+func (a *autoClaim) appendRecord(record autoClaimRecord) error {
+
+	path := a.cfg.StaderNode.GetAutoClaimLogPath()
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(bytes, '\n'))
+	return err
+
+}
+
+func sumAmounts(amounts []*big.Int) *big.Int {
+	total := big.NewInt(0)
+	for _, amount := range amounts {
+		total.Add(total, amount)
+	}
+	return total
+}
@@ -0,0 +1,63 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+func TestDryRunClaimsAreLoggedOnce(t *testing.T) {
+	dataPath := t.TempDir()
+	cfg := config.NewStaderConfig(dataPath, true)
+	cfg.StaderNode.DataPath.Value = dataPath
+	cfg.AutoClaim.DryRun.Value = true
+	a := &autoClaim{
+		log:          log.NewColorLogger(AutoClaimColor),
+		cfg:          cfg,
+		dryRunClaims: map[string]bool{},
+	}
+
+	first := types.BytesToValidatorPubkey(bytes.Repeat([]byte{1}, types.ValidatorPubkeyLength))
+	second := types.BytesToValidatorPubkey(bytes.Repeat([]byte{2}, types.ValidatorPubkeyLength))
+	for run := 1; run <= 3; run++ {
+		// The rewards keep growing between runs
+		for _, validator := range []types.ValidatorPubkey{first, second} {
+			record := autoClaimRecord{Type: clRewardsClaim, AmountEth: big.NewInt(int64(run)), Validator: validator}
+			if err := a.claim(record, "a CL rewards transaction", nil, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	logBytes, err := ioutil.ReadFile(cfg.StaderNode.GetAutoClaimLogPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(logBytes, []byte("\n")); lines != 2 {
+		t.Errorf("logged %d dry-run claims for 2 validators over 3 runs, want 2", lines)
+	}
+}
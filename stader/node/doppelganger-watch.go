@@ -0,0 +1,252 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/client"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/doppelganger"
+	"github.com/stader-labs/stader-node/shared/services/performance"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// The outcome of checking a watched key, applied to the watch once all epochs are checked
+type watchResult struct {
+	startEpoch    uint64
+	nextEpoch     uint64
+	detectedEpoch uint64
+	activity      string
+}
+
+// Doppelganger watch task
+type doppelgangerWatch struct {
+	c       *cli.Context
+	log     log.ColorLogger
+	errLog  log.ColorLogger
+	cfg     *config.StaderConfig
+	bc      beacon.Client
+	d       *client.Client
+	w       *wallet.Wallet
+	watch   *doppelganger.Watch
+	tracker *performance.Tracker
+}
+
+// Create doppelganger watch task
+func newDoppelgangerWatch(c *cli.Context, logger log.ColorLogger, errorLogger log.ColorLogger) (*doppelgangerWatch, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	d, err := services.GetDocker(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	watch, err := services.GetDoppelgangerWatch(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return task
+	return &doppelgangerWatch{
+		c:      c,
+		log:    logger,
+		errLog: errorLogger,
+		cfg:    cfg,
+		bc:     bc,
+		d:      d,
+		w:      w,
+		watch:  watch,
+	}, nil
+
+}
+
+// Check the watched keys for activity in the epochs that have completed since the last run,
+// and hand each key to the validator client once it has stayed quiet for its whole window
+func (t *doppelgangerWatch) run() error {
+
+	keys, err := t.watch.GetKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if t.tracker == nil {
+		eth2Config, err := t.bc.GetEth2Config(context.Background())
+		if err != nil {
+			return fmt.Errorf("error getting the Beacon chain config: %w", err)
+		}
+		t.tracker = performance.NewTracker(t.bc, eth2Config.SlotsPerEpoch)
+	}
+	head, err := t.bc.GetBeaconHead(context.Background())
+	if err != nil {
+		return fmt.Errorf("error getting beacon head: %w", err)
+	}
+	lastCompleteEpoch, ok := performance.GetLastCompleteEpoch(head.Epoch)
+	if !ok {
+		return nil
+	}
+
+	// Check every complete epoch that falls in at least one key's window
+	results := map[types.ValidatorPubkey]watchResult{}
+	fromEpoch, toEpoch := uint64(0), uint64(0)
+	for _, key := range keys {
+		if key.Status != doppelganger.KeyStatus_Watching || key.NextEpoch > key.EndEpoch {
+			continue
+		}
+		results[key.Pubkey] = watchResult{startEpoch: key.StartEpoch, nextEpoch: key.NextEpoch}
+		if len(results) == 1 || key.NextEpoch < fromEpoch {
+			fromEpoch = key.NextEpoch
+		}
+		if key.EndEpoch > toEpoch {
+			toEpoch = key.EndEpoch
+		}
+	}
+	if toEpoch > lastCompleteEpoch {
+		toEpoch = lastCompleteEpoch
+	}
+
+	var checkErr error
+	if len(results) > 0 {
+		for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+			validators := map[uint64]types.ValidatorPubkey{}
+			for _, key := range keys {
+				result, watching := results[key.Pubkey]
+				if !watching || result.detectedEpoch > 0 || epoch < result.nextEpoch || epoch > key.EndEpoch {
+					continue
+				}
+				validators[key.Index] = key.Pubkey
+			}
+			if len(validators) == 0 {
+				continue
+			}
+
+			activity, err := doppelganger.CheckEpoch(context.Background(), t.tracker, epoch, validators)
+			if err != nil {
+				// Keep the progress made so far
+				checkErr = err
+				break
+			}
+			for index, pubkey := range validators {
+				result := results[pubkey]
+				result.nextEpoch = epoch + 1
+				if seen, exists := activity[index]; exists {
+					result.detectedEpoch = epoch
+					result.activity = seen
+				}
+				results[pubkey] = result
+			}
+		}
+	}
+
+	// Apply the results to the keys that haven't been re-added while they were checked, and release the cleared ones
+	newlyDetected := []doppelganger.WatchedKey{}
+	detected := []doppelganger.WatchedKey{}
+	released := []doppelganger.WatchedKey{}
+	var releaseErr error
+	err = t.watch.Update(func(watched map[types.ValidatorPubkey]doppelganger.WatchedKey) error {
+		for pubkey, result := range results {
+			key, exists := watched[pubkey]
+			if !exists || key.Status != doppelganger.KeyStatus_Watching || key.StartEpoch != result.startEpoch {
+				continue
+			}
+			key.NextEpoch = result.nextEpoch
+			if result.detectedEpoch > 0 {
+				key.Status = doppelganger.KeyStatus_Detected
+				key.DetectedEpoch = result.detectedEpoch
+				key.Activity = result.activity
+				newlyDetected = append(newlyDetected, key)
+			}
+			watched[pubkey] = key
+		}
+
+		for pubkey, key := range watched {
+			switch {
+			case key.Status == doppelganger.KeyStatus_Detected:
+				detected = append(detected, key)
+			case key.NextEpoch > key.EndEpoch:
+				// The key only stops being watched once it's back in the validator client's keystores
+				if err := t.w.ReleaseValidatorKey(pubkey); err != nil {
+					releaseErr = fmt.Errorf("error releasing validator key %s to the validator client: %w", pubkey.Hex(), err)
+					continue
+				}
+				delete(watched, pubkey)
+				released = append(released, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(newlyDetected) > 0 {
+		t.errLog.Println("***DOPPELGANGER DETECTED***")
+		for _, key := range newlyDetected {
+			t.errLog.Printlnf("Validator %d (%s) was seen on chain in epoch %d while this node wasn't running it: %s.", key.Index, key.Pubkey.Hex(), key.DetectedEpoch, key.Activity)
+		}
+		t.errLog.Println("These keys are running on another machine. Running them here as well will get them slashed, so they are kept out of the validator client.")
+	} else if len(detected) > 0 {
+		t.errLog.Printlnf("%d validator key(s) are kept out of the validator client because they are running on another machine. Shut that machine down and run `stader-cli validator doppelganger-retry` to watch them again.", len(detected))
+	}
+
+	if len(released) > 0 {
+		for _, key := range released {
+			t.log.Printlnf("No doppelgangers were seen for validator %d (%s) in epochs %d to %d.", key.Index, key.Pubkey.Hex(), key.StartEpoch, key.EndEpoch)
+		}
+		t.log.Printlnf("Restarting the validator client to load %d released validator key(s)...", len(released))
+		if err := validator.RestartValidator(t.cfg, t.bc, &t.log, t.d); err != nil {
+			return fmt.Errorf("error restarting validator client: %w", err)
+		}
+	}
+
+	if releaseErr != nil {
+		return releaseErr
+	}
+	if checkErr != nil {
+		return checkErr
+	}
+	if len(results) > len(released)+len(newlyDetected) && fromEpoch <= toEpoch {
+		t.log.Printlnf("No doppelgangers seen for %d watched validator key(s) in epochs %d to %d.", len(results)-len(released)-len(newlyDetected), fromEpoch, toEpoch)
+	}
+	return nil
+
+}
@@ -0,0 +1,473 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	stader_node "github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Config
+const (
+	// Don't scan more than this many blocks in one log query
+	maxEventBlockRange = 10000
+	eventLogBufferSize = 64
+)
+
+// Wakes a daemon task early when an event it cares about arrives
+type taskTrigger struct {
+	ch chan struct{}
+}
+
+// Create a new task trigger
+func newTaskTrigger() *taskTrigger {
+	return &taskTrigger{
+		ch: make(chan struct{}, 1),
+	}
+}
+
+// Wake the task; several events before the task runs only wake it once
+func (t *taskTrigger) fire() {
+	select {
+	case t.ch <- struct{}{}:
+	default:
+	}
+}
+
+// Wait for the trigger to fire or for the timeout to pass, whichever comes first
+func (t *taskTrigger) wait(timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-t.ch:
+	case <-timer.C:
+	}
+}
+
+// Watch contract events and the beacon head, and wake the tasks they affect
+type eventWatcher struct {
+	log    log.ColorLogger
+	errLog log.ColorLogger
+	ec     stader.ExecutionClient
+	bc     beacon.Client
+	pnr    *stader.PermissionlessNodeRegistryContractManager
+
+	pnrAddress  common.Address
+	spAddress   common.Address
+	pnrFilterer *contracts.PermissionlessNodeRegistryFilterer
+	spFilterer  *contracts.SocializingPoolFilterer
+
+	addedValidatorKeyId             common.Hash
+	validatorMarkedReadyToDepositId common.Hash
+	updatedSocializingPoolStateId   common.Hash
+	operatorRewardsUpdatedId        common.Hash
+	markedAsFrontRunnedId           common.Hash
+	markedAsInvalidSignatureId      common.Hash
+
+	nodeAddress  common.Address
+	operatorId   *big.Int
+	slotDuration time.Duration
+
+	presignTrigger      *taskTrigger
+	feeRecipientTrigger *taskTrigger
+	merkleProofsTrigger *taskTrigger
+	frontRunTrigger     *taskTrigger
+
+	// The last block whose logs have been handled
+	lastBlock uint64
+	// Our validators that are waiting to show up on the beacon chain
+	pendingPresign map[types.ValidatorPubkey]bool
+}
+
+// Create a new event watcher
+func newEventWatcher(c *cli.Context, logger log.ColorLogger, errorLogger log.ColorLogger, nodeAddress common.Address, presignTrigger, feeRecipientTrigger, merkleProofsTrigger, frontRunTrigger *taskTrigger) (*eventWatcher, error) {
+
+	// Get services
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	pnrAddress, err := services.GetPermissionlessNodeRegistryAddress(c)
+	if err != nil {
+		return nil, err
+	}
+	spAddress, err := services.GetSocializingPoolAddress(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the event bindings
+	pnrFilterer, err := contracts.NewPermissionlessNodeRegistryFilterer(pnrAddress, ec)
+	if err != nil {
+		return nil, err
+	}
+	spFilterer, err := contracts.NewSocializingPoolFilterer(spAddress, ec)
+	if err != nil {
+		return nil, err
+	}
+	pnrAbi, err := contracts.PermissionlessNodeRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	spAbi, err := contracts.SocializingPoolMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the node's operator ID and the slot time
+	operatorId, err := stader_node.GetOperatorId(pnr, nodeAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get the operator ID of node %s: %w", nodeAddress.Hex(), err)
+	}
+	eth2Config, err := bc.GetEth2Config(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// Return watcher
+	return &eventWatcher{
+		log:                             logger,
+		errLog:                          errorLogger,
+		ec:                              ec,
+		bc:                              bc,
+		pnr:                             pnr,
+		pnrAddress:                      pnrAddress,
+		spAddress:                       spAddress,
+		pnrFilterer:                     pnrFilterer,
+		spFilterer:                      spFilterer,
+		addedValidatorKeyId:             pnrAbi.Events["AddedValidatorKey"].ID,
+		validatorMarkedReadyToDepositId: pnrAbi.Events["ValidatorMarkedReadyToDeposit"].ID,
+		updatedSocializingPoolStateId:   pnrAbi.Events["UpdatedSocializingPoolState"].ID,
+		operatorRewardsUpdatedId:        spAbi.Events["OperatorRewardsUpdated"].ID,
+		markedAsFrontRunnedId:           pnrAbi.Events["ValidatorMarkedAsFrontRunned"].ID,
+		markedAsInvalidSignatureId:      pnrAbi.Events["ValidatorStatusMarkedAsInvalidSignature"].ID,
+		nodeAddress:                     nodeAddress,
+		operatorId:                      operatorId,
+		slotDuration:                    time.Duration(eth2Config.SecondsPerSlot) * time.Second,
+		presignTrigger:                  presignTrigger,
+		feeRecipientTrigger:             feeRecipientTrigger,
+		merkleProofsTrigger:             merkleProofsTrigger,
+		frontRunTrigger:                 frontRunTrigger,
+		pendingPresign:                  map[types.ValidatorPubkey]bool{},
+	}, nil
+
+}
+
+// Watch events until the daemon stops.
+// Logs are streamed if the execution client supports subscriptions, and polled once per slot otherwise.
+func (w *eventWatcher) run() {
+
+	// Start from the current head; anything older is picked up by the tasks' regular intervals
+	latestBlock, err := w.ec.BlockNumber(context.Background())
+	if err != nil {
+		w.errLog.Printlnf("Could not get the latest block, contract events will be ignored until it is available: %s", err.Error())
+	}
+	w.lastBlock = latestBlock
+
+	// Follow the beacon head so the loop runs as each block arrives; the slot timer covers gaps in the stream
+	beaconEvents := beacon.SubscribeEvents(context.Background(), w.bc, []beacon.EventTopic{beacon.EventTopic_Head, beacon.EventTopic_ChainReorg}, func(err error) {
+		w.errLog.Printlnf("Beacon event stream failed, reconnecting: %s", err.Error())
+	})
+
+	logs := make(chan ethtypes.Log, eventLogBufferSize)
+	var sub ethereum.Subscription
+	subscribeFailed := false
+	for {
+
+		// (Re)subscribe to logs; the gap since the last handled block is caught up below
+		if sub == nil {
+			sub, err = w.ec.SubscribeFilterLogs(context.Background(), w.getFilterQuery(nil, nil), logs)
+			if err != nil {
+				sub = nil
+				if !subscribeFailed {
+					w.log.Printlnf("Execution client does not support log subscriptions (%s), polling for contract events every %s instead", err.Error(), w.slotDuration)
+					subscribeFailed = true
+				}
+			} else {
+				subscribeFailed = false
+				if err := w.catchUp(); err != nil {
+					w.errLog.Println(err)
+				}
+			}
+		}
+
+		// Poll for logs when there's no subscription
+		if sub == nil {
+			if err := w.catchUp(); err != nil {
+				w.errLog.Println(err)
+			}
+		}
+
+		// Check if any new validators have reached the beacon chain
+		if err := w.checkPendingPresigns(); err != nil {
+			w.errLog.Println(err)
+		}
+
+		// Handle streamed logs until the next block or slot
+		w.waitForSlot(&sub, logs, beaconEvents)
+
+	}
+
+}
+
+// Handle streamed logs until a new beacon block arrives or the slot passes, dropping the subscription if it fails
+func (w *eventWatcher) waitForSlot(sub *ethereum.Subscription, logs chan ethtypes.Log, beaconEvents <-chan beacon.Event) {
+	var subErr <-chan error
+	if *sub != nil {
+		subErr = (*sub).Err()
+	}
+
+	timer := time.NewTimer(w.slotDuration)
+	defer timer.Stop()
+	for {
+		select {
+		case eventLog := <-logs:
+			w.handleLog(eventLog)
+			if eventLog.BlockNumber > w.lastBlock {
+				w.lastBlock = eventLog.BlockNumber
+			}
+		case err := <-subErr:
+			if err != nil {
+				w.errLog.Printlnf("Contract event subscription failed: %s", err.Error())
+			}
+			(*sub).Unsubscribe()
+			*sub = nil
+			return
+		case event := <-beaconEvents:
+			switch event.Topic {
+			case beacon.EventTopic_Head:
+				return
+			case beacon.EventTopic_ChainReorg:
+				w.handleReorg(event.ChainReorg)
+				return
+			}
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+// Rescan the blocks a reorg replaced, since the new chain may have different contract events.
+// Streamed logs don't need this because the subscription resends them, but polling does.
+func (w *eventWatcher) handleReorg(reorg *beacon.ChainReorgEvent) {
+	w.log.Printlnf("Beacon chain reorg of depth %d at slot %d, new head is %s", reorg.Depth, reorg.Slot, reorg.NewHeadBlock.Hex())
+
+	// There's at most one block per slot, so the depth bounds how many execution blocks were replaced
+	if w.lastBlock > reorg.Depth {
+		w.lastBlock -= reorg.Depth
+	}
+}
+
+// Get the log filter for the events the daemon reacts to
+func (w *eventWatcher) getFilterQuery(fromBlock, toBlock *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []common.Address{w.pnrAddress, w.spAddress},
+		Topics: [][]common.Hash{{
+			w.addedValidatorKeyId,
+			w.validatorMarkedReadyToDepositId,
+			w.updatedSocializingPoolStateId,
+			w.operatorRewardsUpdatedId,
+			w.markedAsFrontRunnedId,
+			w.markedAsInvalidSignatureId,
+		}},
+	}
+}
+
+// Handle the logs of every block since the last handled one
+func (w *eventWatcher) catchUp() error {
+	latestBlock, err := w.ec.BlockNumber(context.Background())
+	if err != nil {
+		return fmt.Errorf("Could not get the latest block: %w", err)
+	}
+
+	// Nothing was handled yet because the head wasn't available at startup
+	if w.lastBlock == 0 {
+		w.lastBlock = latestBlock
+		return nil
+	}
+
+	for w.lastBlock < latestBlock {
+		toBlock := w.lastBlock + maxEventBlockRange
+		if toBlock > latestBlock {
+			toBlock = latestBlock
+		}
+		eventLogs, err := w.ec.FilterLogs(context.Background(), w.getFilterQuery(big.NewInt(int64(w.lastBlock+1)), big.NewInt(int64(toBlock))))
+		if err != nil {
+			return fmt.Errorf("Could not get contract events for blocks %d to %d: %w", w.lastBlock+1, toBlock, err)
+		}
+		for _, eventLog := range eventLogs {
+			w.handleLog(eventLog)
+		}
+		w.lastBlock = toBlock
+	}
+	return nil
+}
+
+// Handle a single contract event
+func (w *eventWatcher) handleLog(eventLog ethtypes.Log) {
+
+	// Ignore logs that were reorged out
+	if eventLog.Removed || len(eventLog.Topics) == 0 {
+		return
+	}
+
+	switch eventLog.Topics[0] {
+	case w.addedValidatorKeyId:
+		event, err := w.pnrFilterer.ParseAddedValidatorKey(eventLog)
+		if err != nil {
+			w.errLog.Printlnf("Could not decode AddedValidatorKey event: %s", err.Error())
+			return
+		}
+		if event.NodeOperator != w.nodeAddress {
+			return
+		}
+		pubkey := types.BytesToValidatorPubkey(event.Pubkey)
+		w.log.Printlnf("Validator %s was added, it will be presigned once it is on the beacon chain", pubkey.Hex())
+		w.pendingPresign[pubkey] = true
+		// Its deposits are watched for front-running until Stader deposits the rest of its stake
+		w.frontRunTrigger.fire()
+
+	case w.validatorMarkedReadyToDepositId:
+		event, err := w.pnrFilterer.ParseValidatorMarkedReadyToDeposit(eventLog)
+		if err != nil {
+			w.errLog.Printlnf("Could not decode ValidatorMarkedReadyToDeposit event: %s", err.Error())
+			return
+		}
+		pubkey := types.BytesToValidatorPubkey(event.Pubkey)
+		if w.pendingPresign[pubkey] {
+			return
+		}
+		// The key may have been added before the daemon started, so check who it belongs to
+		validatorInfo, err := stader_node.GetValidatorInfo(w.pnr, event.ValidatorId, nil)
+		if err != nil {
+			w.errLog.Printlnf("Could not get info for validator %s: %s", pubkey.Hex(), err.Error())
+			return
+		}
+		if validatorInfo.OperatorId == nil || validatorInfo.OperatorId.Cmp(w.operatorId) != 0 {
+			return
+		}
+		w.log.Printlnf("Validator %s is ready to deposit, it will be presigned once it is on the beacon chain", pubkey.Hex())
+		w.pendingPresign[pubkey] = true
+
+	case w.updatedSocializingPoolStateId:
+		event, err := w.pnrFilterer.ParseUpdatedSocializingPoolState(eventLog)
+		if err != nil {
+			w.errLog.Printlnf("Could not decode UpdatedSocializingPoolState event: %s", err.Error())
+			return
+		}
+		if event.OperatorId == nil || event.OperatorId.Cmp(w.operatorId) != 0 {
+			return
+		}
+		w.log.Printlnf("Socializing pool opt-in changed to %t, updating the fee recipient", event.OptedForSocializingPool)
+		w.feeRecipientTrigger.fire()
+
+	case w.operatorRewardsUpdatedId:
+		if _, err := w.spFilterer.ParseOperatorRewardsUpdated(eventLog); err != nil {
+			w.errLog.Printlnf("Could not decode OperatorRewardsUpdated event: %s", err.Error())
+			return
+		}
+		w.log.Println("Socializing pool rewards were updated, checking for new merkle proofs")
+		w.merkleProofsTrigger.fire()
+
+	case w.markedAsFrontRunnedId:
+		event, err := w.pnrFilterer.ParseValidatorMarkedAsFrontRunned(eventLog)
+		if err != nil {
+			w.errLog.Printlnf("Could not decode ValidatorMarkedAsFrontRunned event: %s", err.Error())
+			return
+		}
+		w.dropPendingPresign(types.BytesToValidatorPubkey(event.Pubkey), "front-run")
+		// These are emitted for every operator's keys; the front-run watch checks whether any of them are ours
+		w.frontRunTrigger.fire()
+
+	case w.markedAsInvalidSignatureId:
+		event, err := w.pnrFilterer.ParseValidatorStatusMarkedAsInvalidSignature(eventLog)
+		if err != nil {
+			w.errLog.Printlnf("Could not decode ValidatorStatusMarkedAsInvalidSignature event: %s", err.Error())
+			return
+		}
+		w.dropPendingPresign(types.BytesToValidatorPubkey(event.Pubkey), "invalid signature")
+		w.frontRunTrigger.fire()
+	}
+
+}
+
+// Stop waiting for a validator the registry has given up on, since it will never be presigned
+func (w *eventWatcher) dropPendingPresign(pubkey types.ValidatorPubkey, reason string) {
+	if !w.pendingPresign[pubkey] {
+		return
+	}
+	w.log.Printlnf("Validator %s was marked as %s, it will not be presigned", pubkey.Hex(), reason)
+	delete(w.pendingPresign, pubkey)
+
+}
+
+// Wake the presign task as soon as one of the pending validators is on the beacon chain
+func (w *eventWatcher) checkPendingPresigns() error {
+	if len(w.pendingPresign) == 0 {
+		return nil
+	}
+
+	pubkeys := make([]types.ValidatorPubkey, 0, len(w.pendingPresign))
+	for pubkey := range w.pendingPresign {
+		pubkeys = append(pubkeys, pubkey)
+	}
+	statuses, err := w.bc.GetValidatorStatuses(context.Background(), pubkeys, nil)
+	if err != nil {
+		return fmt.Errorf("Could not get the beacon status of pending validators: %w", err)
+	}
+
+	found := false
+	for _, pubkey := range pubkeys {
+		if status, ok := statuses[pubkey]; ok && status.Exists {
+			w.log.Printlnf("Validator %s is on the beacon chain with index %d", pubkey.Hex(), status.Index)
+			delete(w.pendingPresign, pubkey)
+			found = true
+		}
+	}
+	if found {
+		w.presignTrigger.fire()
+	}
+	return nil
+}
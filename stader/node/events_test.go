@@ -0,0 +1,95 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Build a watcher that only decodes registry events, without any clients
+func newTestEventWatcher(t *testing.T) *eventWatcher {
+	pnrFilterer, err := contracts.NewPermissionlessNodeRegistryFilterer(common.Address{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pnrAbi, err := contracts.PermissionlessNodeRegistryMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &eventWatcher{
+		log:                        log.NewColorLogger(EventWatcherColor),
+		errLog:                     log.NewColorLogger(ErrorColor),
+		pnrFilterer:                pnrFilterer,
+		markedAsFrontRunnedId:      pnrAbi.Events["ValidatorMarkedAsFrontRunned"].ID,
+		markedAsInvalidSignatureId: pnrAbi.Events["ValidatorStatusMarkedAsInvalidSignature"].ID,
+		frontRunTrigger:            newTaskTrigger(),
+		pendingPresign:             map[types.ValidatorPubkey]bool{},
+	}
+}
+
+// Encode a registry event about a validator
+func newValidatorEventLog(t *testing.T, eventName string, pubkey types.ValidatorPubkey) ethtypes.Log {
+	pnrAbi, err := contracts.PermissionlessNodeRegistryMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := pnrAbi.Events[eventName]
+	data, err := event.Inputs.NonIndexed().Pack(pubkey.Bytes(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ethtypes.Log{Topics: []common.Hash{event.ID}, Data: data}
+}
+
+func TestHandleLogDropsRejectedPendingPresigns(t *testing.T) {
+	w := newTestEventWatcher(t)
+	frontRun := types.BytesToValidatorPubkey(common.LeftPadBytes([]byte{1}, types.ValidatorPubkeyLength))
+	invalid := types.BytesToValidatorPubkey(common.LeftPadBytes([]byte{2}, types.ValidatorPubkeyLength))
+	waiting := types.BytesToValidatorPubkey(common.LeftPadBytes([]byte{3}, types.ValidatorPubkeyLength))
+	for _, pubkey := range []types.ValidatorPubkey{frontRun, invalid, waiting} {
+		w.pendingPresign[pubkey] = true
+	}
+
+	w.handleLog(newValidatorEventLog(t, "ValidatorMarkedAsFrontRunned", frontRun))
+	w.handleLog(newValidatorEventLog(t, "ValidatorStatusMarkedAsInvalidSignature", invalid))
+
+	if w.pendingPresign[frontRun] {
+		t.Error("a front-run validator is still waiting to be presigned")
+	}
+	if w.pendingPresign[invalid] {
+		t.Error("a validator with an invalid signature is still waiting to be presigned")
+	}
+	if !w.pendingPresign[waiting] {
+		t.Error("a validator that wasn't marked stopped waiting to be presigned")
+	}
+	select {
+	case <-w.frontRunTrigger.ch:
+	default:
+		t.Error("the front-run watch wasn't woken")
+	}
+}
@@ -0,0 +1,294 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/contracts"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Registry statuses of keys that have lost their bond
+const (
+	invalidSignatureStatus uint8 = 1
+	frontRunStatus         uint8 = 2
+)
+
+// Registry statuses of keys that Stader hasn't deposited the rest of the stake for yet
+const (
+	initializedStatus  uint8 = 0
+	preDepositedStatus uint8 = 3
+)
+
+// A key whose deposits are watched, with the withdrawal credentials Stader deposits it to
+type watchedKey struct {
+	withdrawalCredentials common.Hash
+	// Set once Stader has made the pre-deposit, which fixes the key's withdrawal credentials
+	preDeposited    bool
+	preDepositBlock uint64
+}
+
+// Front-run watch task
+type frontRunWatch struct {
+	c      *cli.Context
+	log    log.ColorLogger
+	errLog log.ColorLogger
+	cfg    *config.StaderConfig
+	w      *wallet.Wallet
+	ec     *services.ExecutionClientManager
+	bc     beacon.Client
+	pnr    *stader.PermissionlessNodeRegistryContractManager
+	vfc    *stader.VaultFactoryContractManager
+
+	// Set up on the first run, since the Beacon client may not be synced when the daemon starts
+	depositContract *contracts.BeaconDeposit
+	eth2Config      beacon.Eth2Config
+	nextBlock       uint64
+
+	// Each problem is only reported once per daemon run
+	alertedStatuses   map[types.ValidatorPubkey]bool
+	alertedDeposits   map[common.Hash]bool
+	checkedSignatures map[types.ValidatorPubkey]bool
+}
+
+// Create front-run watch task
+func newFrontRunWatch(c *cli.Context, logger log.ColorLogger, errorLogger log.ColorLogger) (*frontRunWatch, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	vfc, err := services.GetVaultFactory(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return task
+	return &frontRunWatch{
+		c:                 c,
+		log:               logger,
+		errLog:            errorLogger,
+		cfg:               cfg,
+		w:                 w,
+		ec:                ec,
+		bc:                bc,
+		pnr:               pnr,
+		vfc:               vfc,
+		alertedStatuses:   map[types.ValidatorPubkey]bool{},
+		alertedDeposits:   map[common.Hash]bool{},
+		checkedSignatures: map[types.ValidatorPubkey]bool{},
+	}, nil
+
+}
+
+// Check the node's keys for a front-run or invalid signature status, and search the blocks added since the last run
+// for deposits of the keys Stader hasn't deposited yet that would send the validator to someone else's withdrawal credentials
+func (t *frontRunWatch) run() error {
+
+	nodeAccount, err := t.w.GetNodeAccount()
+	if err != nil {
+		return err
+	}
+	operatorId, err := node.GetOperatorId(t.pnr, nodeAccount.Address, nil)
+	if err != nil {
+		return err
+	}
+	if operatorId.Int64() == 0 {
+		return nil
+	}
+
+	if t.depositContract == nil {
+		if err := t.setup(); err != nil {
+			return err
+		}
+	}
+
+	latestBlock, err := t.ec.BlockNumber(context.Background())
+	if err != nil {
+		return err
+	}
+	if t.nextBlock == 0 {
+		t.nextBlock = validator.GetDepositLookbackStart(latestBlock)
+	}
+
+	validators, pubkeys, err := stdr.GetAllValidatorsRegisteredWithOperator(t.pnr, operatorId, nodeAccount.Address, nil)
+	if err != nil {
+		return err
+	}
+
+	pending := map[types.ValidatorPubkey]watchedKey{}
+	for _, pubkey := range pubkeys {
+		validatorInfo := validators[pubkey]
+		switch {
+		case validatorInfo.Status == invalidSignatureStatus || validatorInfo.Status == frontRunStatus:
+			if !t.alertedStatuses[pubkey] {
+				t.alertedStatuses[pubkey] = true
+				t.errLog.Printlnf("***VALIDATOR BOND LOST*** Stader has marked validator %s as \"%s\" and it will not be deposited.", pubkey.Hex(), stdr.ValidatorState[validatorInfo.Status])
+			}
+
+		case validatorInfo.Status == initializedStatus || validatorInfo.Status == preDepositedStatus:
+			withdrawalCredentials, err := node.GetValidatorWithdrawalCredential(t.vfc, validatorInfo.WithdrawVaultAddress, nil)
+			if err != nil {
+				return fmt.Errorf("error getting the withdrawal credentials of validator %s: %w", pubkey.Hex(), err)
+			}
+			key := watchedKey{
+				withdrawalCredentials: withdrawalCredentials,
+				preDeposited:          validatorInfo.Status == preDepositedStatus,
+			}
+			if validatorInfo.DepositBlock != nil {
+				key.preDepositBlock = validatorInfo.DepositBlock.Uint64()
+			}
+			pending[pubkey] = key
+			t.checkSignatures(pubkey, withdrawalCredentials, validatorInfo.PreDepositSignature, validatorInfo.DepositSignature)
+		}
+	}
+
+	if t.nextBlock > latestBlock {
+		return nil
+	}
+	// Keys added later are checked for earlier deposits before they're added, so the skipped blocks don't need scanning
+	if len(pending) == 0 {
+		t.nextBlock = latestBlock + 1
+		return nil
+	}
+
+	eventLogInterval, err := t.cfg.GetEventLogInterval()
+	if err != nil {
+		return err
+	}
+	watched := make(map[types.ValidatorPubkey]bool, len(pending))
+	for pubkey := range pending {
+		watched[pubkey] = true
+	}
+	deposits := map[types.ValidatorPubkey][]validator.BeaconDeposit{}
+	nextBlock, err := validator.ScanBeaconDeposits(t.depositContract, watched, t.nextBlock, latestBlock, uint64(eventLogInterval), deposits)
+	t.nextBlock = nextBlock
+	t.reportDeposits(pending, deposits)
+	if err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+// Get the Beacon deposit contract and the chain config the deposit signatures are checked against
+func (t *frontRunWatch) setup() error {
+	eth2Config, err := t.bc.GetEth2Config(context.Background())
+	if err != nil {
+		return fmt.Errorf("error getting the Beacon chain config: %w", err)
+	}
+	depositContract, err := t.bc.GetEth2DepositContract(context.Background())
+	if err != nil {
+		return fmt.Errorf("error getting the Beacon deposit contract: %w", err)
+	}
+	dc, err := contracts.NewBeaconDeposit(depositContract.Address, t.ec)
+	if err != nil {
+		return err
+	}
+	t.eth2Config = eth2Config
+	t.depositContract = dc
+	return nil
+}
+
+// The signatures of a key don't change once it's registered, so each key is only checked once
+func (t *frontRunWatch) checkSignatures(pubkey types.ValidatorPubkey, withdrawalCredentials common.Hash, preDepositSignature []byte, depositSignature []byte) {
+	if t.checkedSignatures[pubkey] {
+		return
+	}
+	t.checkedSignatures[pubkey] = true
+	err := validator.VerifyStaderDepositSignatures(pubkey, withdrawalCredentials, t.eth2Config, types.BytesToValidatorSignature(preDepositSignature), types.BytesToValidatorSignature(depositSignature))
+	if err != nil {
+		t.errLog.Println("***INVALID DEPOSIT SIGNATURE***")
+		t.errLog.Printlnf("%s. Stader will mark the key as having an invalid signature and the bond will be lost.", err.Error())
+	}
+}
+
+func (t *frontRunWatch) reportDeposits(pending map[types.ValidatorPubkey]watchedKey, deposits map[types.ValidatorPubkey][]validator.BeaconDeposit) {
+	for pubkey, keyDeposits := range deposits {
+		key := pending[pubkey]
+		withdrawalCredentials := key.withdrawalCredentials
+
+		// Once the pre-deposit is made the key's withdrawal credentials are set, so later deposits only top it up.
+		// If the registry doesn't have the pre-deposit block, it's the first deposit to Stader's withdrawal credentials;
+		// when that isn't in the scanned blocks, the pre-deposit came before them.
+		preDepositBlock := key.preDepositBlock
+		if key.preDeposited && preDepositBlock == 0 {
+			for _, deposit := range keyDeposits {
+				if deposit.WithdrawalCredentials == withdrawalCredentials {
+					preDepositBlock = deposit.BlockNumber
+					break
+				}
+			}
+			if preDepositBlock == 0 {
+				continue
+			}
+		}
+
+		for _, deposit := range keyDeposits {
+			if t.alertedDeposits[deposit.TxHash] || deposit.WithdrawalCredentials == withdrawalCredentials {
+				continue
+			}
+			if key.preDeposited && deposit.BlockNumber >= preDepositBlock {
+				continue
+			}
+			t.alertedDeposits[deposit.TxHash] = true
+
+			if deposit.IsFrontRun(withdrawalCredentials, t.eth2Config) {
+				t.errLog.Println("***FRONT-RUN DEPOSIT DETECTED***")
+				t.errLog.Printlnf("Validator %s was deposited with %.9f ETH in block %d (transaction %s) to withdrawal credentials %s instead of %s.", pubkey.Hex(), float64(deposit.AmountGwei)/1e9, deposit.BlockNumber, deposit.TxHash.Hex(), deposit.WithdrawalCredentials.Hex(), withdrawalCredentials.Hex())
+				t.errLog.Println("Stader will mark the key as front-run and the bond will be lost. Someone else has access to this key. PLEASE REPORT THIS TO THE STADER DEVELOPERS.")
+				continue
+			}
+			t.log.Printlnf("Validator %s was deposited to withdrawal credentials %s in transaction %s, but the deposit signature is invalid so the Beacon chain will ignore it.", pubkey.Hex(), deposit.WithdrawalCredentials.Hex(), deposit.TxHash.Hex())
+		}
+	}
+}
@@ -0,0 +1,320 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	sd_collateral "github.com/stader-labs/stader-node/stader-lib/sd-collateral"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/tokens"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+// An SD deposit the daemon made on its own
+type sdTopUp struct {
+	Time   time.Time   `json:"time"`
+	Amount *big.Int    `json:"amount"`
+	TxHash common.Hash `json:"txHash"`
+}
+
+// Manage SD collateral task
+type manageSdCollateral struct {
+	c   *cli.Context
+	log log.ColorLogger
+	cfg *config.StaderConfig
+	w   *wallet.Wallet
+	pnr *stader.PermissionlessNodeRegistryContractManager
+	sdc *stader.SdCollateralContractManager
+	sdt *stader.Erc20TokenContractManager
+	tx  *txSender
+}
+
+// Create manage SD collateral task
+func newManageSdCollateral(c *cli.Context, logger log.ColorLogger, tx *txSender) (*manageSdCollateral, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	sdc, err := services.GetSdCollateralContract(c)
+	if err != nil {
+		return nil, err
+	}
+	sdt, err := services.GetSdTokenContract(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &manageSdCollateral{
+		c:   c,
+		log: logger,
+		cfg: cfg,
+		w:   w,
+		pnr: pnr,
+		sdc: sdc,
+		sdt: sdt,
+		tx:  tx,
+	}, nil
+
+}
+
+// Check the node's SD collateral and top it up if it's enabled and needed
+func (m *manageSdCollateral) run() error {
+
+	nodeAccount, err := m.w.GetNodeAccount()
+	if err != nil {
+		return err
+	}
+	operatorId, err := node.GetOperatorId(m.pnr, nodeAccount.Address, nil)
+	if err != nil {
+		return err
+	}
+	totalKeys, err := node.GetTotalValidatorKeys(m.pnr, operatorId, nil)
+	if err != nil {
+		return err
+	}
+	nonTerminalKeys, err := node.GetTotalNonTerminalValidatorKeys(m.pnr, nodeAccount.Address, totalKeys, nil)
+	if err != nil {
+		return err
+	}
+	if nonTerminalKeys == 0 {
+		// No validators need collateral
+		return nil
+	}
+
+	// Get the collateral and the thresholds it's measured against, in ETH
+	sdCollateral, err := sd_collateral.GetOperatorSdBalance(m.sdc, nodeAccount.Address, nil)
+	if err != nil {
+		return err
+	}
+	sdCollateralInEth, err := sd_collateral.ConvertSdToEth(m.sdc, sdCollateral, nil)
+	if err != nil {
+		return err
+	}
+	poolThreshold, err := sd_collateral.GetPoolThreshold(m.sdc, 1, nil)
+	if err != nil {
+		return err
+	}
+	keys := big.NewInt(int64(nonTerminalKeys))
+	minCollateral := new(big.Int).Mul(poolThreshold.MinThreshold, keys)
+	maxCollateral := new(big.Int).Mul(poolThreshold.MaxThreshold, keys)
+	if minCollateral.Sign() == 0 {
+		return nil
+	}
+
+	ratio := eth.WeiToEth(sdCollateralInEth) / eth.WeiToEth(minCollateral)
+	warningRatio := m.cfg.SdCollateral.WarningRatio.Value.(float64)
+	if ratio >= warningRatio {
+		return nil
+	}
+	if ratio < 1 {
+		m.log.Printlnf("WARNING: the node's SD collateral is worth %.6f ETH, which is below the minimum of %.6f ETH for its %d validators. No new validators can be added until it is topped up.", eth.WeiToEth(sdCollateralInEth), eth.WeiToEth(minCollateral), nonTerminalKeys)
+	} else {
+		m.log.Printlnf("WARNING: the node's SD collateral is worth %.6f ETH, only %.2fx the minimum of %.6f ETH for its %d validators.", eth.WeiToEth(sdCollateralInEth), ratio, eth.WeiToEth(minCollateral), nonTerminalKeys)
+	}
+
+	if m.cfg.SdCollateral.EnableAutoTopUp.Value != true {
+		return nil
+	}
+
+	// Bring the collateral back up to the target, but never past the maximum the pool counts
+	targetRatio := m.cfg.SdCollateral.TargetRatio.Value.(float64)
+	targetCollateral := eth.EthToWei(eth.WeiToEth(minCollateral) * targetRatio)
+	if targetCollateral.Cmp(maxCollateral) > 0 {
+		targetCollateral = maxCollateral
+	}
+	if targetCollateral.Cmp(sdCollateralInEth) <= 0 {
+		return nil
+	}
+	topUpInEth := new(big.Int).Sub(targetCollateral, sdCollateralInEth)
+	topUp, err := sd_collateral.ConvertEthToSd(m.sdc, topUpInEth, nil)
+	if err != nil {
+		return err
+	}
+
+	// Stay within the daily budget and what the wallet holds
+	history, err := m.loadTopUpHistory()
+	if err != nil {
+		return err
+	}
+	remainingBudget := eth.EthToWei(m.cfg.SdCollateral.DailyBudget.Value.(float64))
+	for _, entry := range history {
+		if time.Since(entry.Time) < 24*time.Hour {
+			remainingBudget.Sub(remainingBudget, entry.Amount)
+		}
+	}
+	if remainingBudget.Sign() <= 0 {
+		m.log.Printlnf("The daily SD collateral top-up budget of %.6f SD has been used up, the node will not top up the collateral again until it frees up.", m.cfg.SdCollateral.DailyBudget.Value.(float64))
+		return nil
+	}
+	if topUp.Cmp(remainingBudget) > 0 {
+		topUp = remainingBudget
+	}
+	walletBalance, err := tokens.BalanceOf(m.sdt, nodeAccount.Address, nil)
+	if err != nil {
+		return err
+	}
+	if topUp.Cmp(walletBalance) > 0 {
+		topUp = walletBalance
+	}
+	if topUp.Sign() == 0 {
+		m.log.Printlnf("The node wallet has no SD to top up the collateral with.")
+		return nil
+	}
+
+	m.log.Printlnf("Topping up the SD collateral with %.6f SD.", eth.WeiToEth(topUp))
+	hash, err := m.depositSd(nodeAccount.Address, topUp)
+	if errors.Is(err, errFeeTooHigh) {
+		m.log.Printlnf("Postponing the SD collateral top-up: %s", err.Error())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The deposit counts against the budget as soon as it's sent, so a deposit that is slow to be mined isn't repeated
+	history = append(history, sdTopUp{
+		Time:   time.Now(),
+		Amount: topUp,
+		TxHash: hash,
+	})
+	if err := m.saveTopUpHistory(history); err != nil {
+		return fmt.Errorf("the SD collateral deposit was sent but could not be recorded: %w", err)
+	}
+
+	if _, err := m.tx.wait("the SD collateral deposit", hash); err != nil {
+		return err
+	}
+	m.log.Printlnf("Deposited %.6f SD as collateral.", eth.WeiToEth(topUp))
+
+	return nil
+
+}
+
+// Approve the SD collateral contract if needed, then send the deposit without waiting for it.
+// Both transactions together have to stay within the transaction fee cap.
+func (m *manageSdCollateral) depositSd(nodeAddress common.Address, amount *big.Int) (common.Hash, error) {
+
+	maxFee := m.cfg.SdCollateral.MaxFee.Value.(float64)
+	spender := *m.sdc.SdCollateralContract.Address
+
+	allowance, err := tokens.Allowance(m.sdt, nodeAddress, spender, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	committed := big.NewInt(0)
+	if allowance.Cmp(amount) < 0 {
+		hash, approvalCost, err := m.tx.submit("the SD collateral approval", maxFee, nil, func(opts *bind.TransactOpts) (stader.GasInfo, error) {
+			return tokens.EstimateApproveGas(m.sdt, spender, amount, opts)
+		}, func(opts *bind.TransactOpts) (common.Hash, error) {
+			return tokens.Approve(m.sdt, spender, amount, opts)
+		})
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if _, err := m.tx.wait("the SD collateral approval", hash); err != nil {
+			return common.Hash{}, err
+		}
+		committed = approvalCost
+	}
+
+	hash, _, err := m.tx.submit("the SD collateral deposit", maxFee, committed, func(opts *bind.TransactOpts) (stader.GasInfo, error) {
+		return sd_collateral.EstimateDepositSdAsCollateral(m.sdc, amount, opts)
+	}, func(opts *bind.TransactOpts) (common.Hash, error) {
+		tx, err := sd_collateral.DepositSdAsCollateral(m.sdc, amount, opts)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return tx.Hash(), nil
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return hash, nil
+
+}
+
+// Load the top-ups made in the last day
+func (m *manageSdCollateral) loadTopUpHistory() ([]sdTopUp, error) {
+
+	path := m.cfg.StaderNode.GetSdTopUpHistoryPath()
+	bytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []sdTopUp{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the SD top-up history from %s: %w", path, err)
+	}
+
+	history := []sdTopUp{}
+	if err := json.Unmarshal(bytes, &history); err != nil {
+		return nil, fmt.Errorf("could not parse the SD top-up history at %s: %w", path, err)
+	}
+
+	recent := []sdTopUp{}
+	for _, entry := range history {
+		if entry.Amount != nil && time.Since(entry.Time) < 24*time.Hour {
+			recent = append(recent, entry)
+		}
+	}
+	return recent, nil
+
+}
+
+func (m *manageSdCollateral) saveTopUpHistory(history []sdTopUp) error {
+
+	path := m.cfg.StaderNode.GetSdTopUpHistoryPath()
+	bytes, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bytes, 0644)
+
+}
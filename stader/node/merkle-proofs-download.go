@@ -1,14 +1,13 @@
 package node
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/mitchellh/go-homedir"
+	"errors"
 	"github.com/stader-labs/stader-node/shared/services"
 	"github.com/stader-labs/stader-node/shared/services/config"
 	"github.com/stader-labs/stader-node/shared/services/wallet"
 	"github.com/stader-labs/stader-node/shared/utils/log"
 	"github.com/stader-labs/stader-node/shared/utils/stader"
+	stader_lib "github.com/stader-labs/stader-node/stader-lib/stader"
 	"github.com/urfave/cli"
 	"os"
 )
@@ -18,6 +17,7 @@ type MerkleProofsDownloader struct {
 	log log.ColorLogger
 	cfg *config.StaderConfig
 	w   *wallet.Wallet
+	sp  *stader_lib.SocializingPoolContractManager
 }
 
 func NewMerkleProofsDownloader(c *cli.Context, logger log.ColorLogger) (*MerkleProofsDownloader, error) {
@@ -29,12 +29,17 @@ func NewMerkleProofsDownloader(c *cli.Context, logger log.ColorLogger) (*MerkleP
 	if err != nil {
 		return nil, err
 	}
+	sp, err := services.GetSocializingPoolContract(c)
+	if err != nil {
+		return nil, err
+	}
 
 	return &MerkleProofsDownloader{
 		c:   c,
 		log: logger,
 		cfg: cfg,
 		w:   w,
+		sp:  sp,
 	}, nil
 }
 
@@ -55,13 +60,10 @@ func (m *MerkleProofsDownloader) run() error {
 	}
 
 	downloadedCycles := []int64{}
+	quarantinedCycles := []int64{}
 
 	for _, cycleMerkleProof := range allMerkleProofs {
 		cycleMerkleProofFile := m.cfg.StaderNode.GetSpRewardCyclePath(cycleMerkleProof.Cycle, true)
-		absolutePathOfProofFile, err := homedir.Expand(cycleMerkleProofFile)
-		if err != nil {
-			return err
-		}
 
 		_, err = os.Stat(cycleMerkleProofFile)
 		if !os.IsNotExist(err) && err != nil {
@@ -73,20 +75,23 @@ func (m *MerkleProofsDownloader) run() error {
 		}
 
 		m.log.Printlnf("Downloading merkle proof for cycle %d", cycleMerkleProof.Cycle)
-		file, err := os.Create(absolutePathOfProofFile)
-		if err != nil {
-			return err
+		err = stader.SaveCycleMerkleProof(m.cfg, m.sp, nodeAccount.Address, cycleMerkleProof)
+		var invalidProofErr *stader.InvalidMerkleProofError
+		if errors.As(err, &invalidProofErr) {
+			m.log.Printlnf("WARNING: %s; it has been quarantined and will not be claimed", invalidProofErr.Error())
+			quarantinedCycles = append(quarantinedCycles, cycleMerkleProof.Cycle)
+			continue
 		}
-
-		encoder := json.NewEncoder(file)
-		err = encoder.Encode(cycleMerkleProof)
 		if err != nil {
-			return fmt.Errorf("error encoding JSON: %v", err)
+			return err
 		}
 
 		downloadedCycles = append(downloadedCycles, cycleMerkleProof.Cycle)
 	}
 
+	if len(quarantinedCycles) != 0 {
+		m.log.Printlnf("Quarantined merkle proofs for cycles: %v", quarantinedCycles)
+	}
 	if len(downloadedCycles) == 0 {
 		m.log.Printlnf("No merkle proofs to download")
 		return nil
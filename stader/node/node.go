@@ -26,37 +26,45 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
 	"sync"
 	"time"
 
-	stader_backend "github.com/stader-labs/stader-node/shared/types/stader-backend"
-	"github.com/stader-labs/stader-node/shared/utils/crypto"
-	"github.com/stader-labs/stader-node/shared/utils/eth2"
-	"github.com/stader-labs/stader-node/shared/utils/stader"
-	"github.com/stader-labs/stader-node/shared/utils/stdr"
 	"github.com/stader-labs/stader-node/shared/utils/validator"
-	"github.com/stader-labs/stader-node/stader-lib/node"
-	eth2types "github.com/wealdtech/go-eth2-types/v2"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
 
 	"github.com/stader-labs/stader-node/shared/services"
 	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
 	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/stader-labs/stader-node/stader-lib/types"
 )
 
 // Config
 var preSignedCooldown, _ = time.ParseDuration("1h")
+var preSignedMinCooldown, _ = time.ParseDuration("1m")
 var feeRecepientPollingInterval, _ = time.ParseDuration("5m")
 var taskCooldown, _ = time.ParseDuration("10s")
+var txWaitTimeout, _ = time.ParseDuration("15m")
 var merkleProofsDownloadInterval, _ = time.ParseDuration("3h")
+var sdCollateralCheckInterval, _ = time.ParseDuration("15m")
+var autoClaimInterval, _ = time.ParseDuration("1h")
+var doppelgangerWatchInterval, _ = time.ParseDuration("1m")
+var frontRunWatchInterval, _ = time.ParseDuration("5m")
 
 const (
 	MaxConcurrentEth1Requests   = 200
 	ManageFeeRecipientColor     = color.FgHiCyan
 	MerkleProofsDownloaderColor = color.FgHiBlue
+	EventWatcherColor           = color.FgHiMagenta
+	ManageSdCollateralColor     = color.FgHiYellow
+	AutoClaimColor              = color.FgYellow
+	DoppelgangerWatchColor      = color.FgHiWhite
+	FrontRunWatchColor          = color.FgWhite
 	ErrorColor                  = color.FgRed
 	InfoColor                   = color.FgHiGreen
 )
@@ -103,13 +111,18 @@ func run(c *cli.Context) error {
 		return err
 	}
 
-	bc, err := services.GetBeaconClient(c)
+	cfg, err := services.GetConfig(c)
 	if err != nil {
 		return err
 	}
-	pnr, err := services.GetPermissionlessNodeRegistry(c)
-	if err != nil {
-		return err
+
+	// Validator keys are held by a remote signer instead of the node wallet
+	var remoteSigner *web3signer.Client
+	if cfg.EnableRemoteSigner.Value == true {
+		remoteSigner, err = services.GetRemoteSigner(c)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Initialize tasks
@@ -121,262 +134,165 @@ func run(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	submitPresignedExits, err := newSubmitPresignedExits(c, log.NewColorLogger(InfoColor), log.NewColorLogger(ErrorColor))
+	if err != nil {
+		return err
+	}
+	tx, err := newTxSender(c, log.NewColorLogger(InfoColor))
+	if err != nil {
+		return err
+	}
+	manageSdCollateral, err := newManageSdCollateral(c, log.NewColorLogger(ManageSdCollateralColor), tx)
+	if err != nil {
+		return err
+	}
+	autoClaim, err := newAutoClaim(c, log.NewColorLogger(AutoClaimColor), tx)
+	if err != nil {
+		return err
+	}
+	doppelgangerWatch, err := newDoppelgangerWatch(c, log.NewColorLogger(DoppelgangerWatchColor), log.NewColorLogger(ErrorColor))
+	if err != nil {
+		return err
+	}
+
+	frontRunWatch, err := newFrontRunWatch(c, log.NewColorLogger(FrontRunWatchColor), log.NewColorLogger(ErrorColor))
+	if err != nil {
+		return err
+	}
 
 	// Initialize loggers
 	errorLog := log.NewColorLogger(ErrorColor)
 	infoLog := log.NewColorLogger(InfoColor)
 
-	publicKey, err := stader.GetPublicKey(c)
+	// Make sure the validator client knows about the operator's keys the remote signer holds
+	if remoteSigner != nil {
+		pubkeys, err := updateRemoteSignerKeys(c, cfg, remoteSigner, nodeAccount.Address)
+		if err != nil {
+			errorLog.Printf("Could not update the validator client's remote signer keys: %s\n", err.Error())
+		} else {
+			infoLog.Printlnf("Remote signer at %s holds %d of the operator's validator keys", remoteSigner.GetUrl(), len(pubkeys))
+		}
+	}
+
+	// Tasks are woken by the events they care about; their intervals are only a safety net
+	presignTrigger := newTaskTrigger()
+	feeRecipientTrigger := newTaskTrigger()
+	merkleProofsTrigger := newTaskTrigger()
+	sdCollateralTrigger := newTaskTrigger()
+	autoClaimTrigger := newTaskTrigger()
+	doppelgangerTrigger := newTaskTrigger()
+	frontRunTrigger := newTaskTrigger()
+	eventWatcher, err := newEventWatcher(c, log.NewColorLogger(EventWatcherColor), errorLog, nodeAccount.Address, presignTrigger, feeRecipientTrigger, merkleProofsTrigger, frontRunTrigger)
 	if err != nil {
 		return err
 	}
 
 	// Wait group to handle the various threads
 	wg := new(sync.WaitGroup)
-	wg.Add(3)
+	wg.Add(8)
 
-	// validator presigned loop
+	// Contract and beacon event loop
 	go func() {
-		for {
-			// Check the EC status
-			err := services.WaitEthClientSynced(c, false) // Force refresh the primary / fallback EC status
-			if err != nil {
-				errorLog.Println(err)
-				continue
-			} else {
-				// Check the BC status
-				err := services.WaitBeaconClientSynced(c, false) // Force refresh the primary / fallback BC status
-				if err != nil {
-					errorLog.Println(err)
-					continue
-				}
-			}
-
-			operatorId, err := node.GetOperatorId(pnr, nodeAccount.Address, nil)
-			if err != nil {
-				errorLog.Printf("Failed to get operator id: %s\n", err.Error())
-				continue
-			}
-
-			// make a map of all validators actually registered with stader
-			// user might just move the validator keys to the directory. we don't wanna send the presigned msg of them
-			infoLog.Println("Building a map of user validators registered with stader")
-			registeredValidators, validatorPubKeys, err := stdr.GetAllValidatorsRegisteredWithOperator(pnr, operatorId, nodeAccount.Address, nil)
-			if err != nil {
-				errorLog.Printf("Could not get all validators registered with operator %s with error %s\n", operatorId, err.Error())
-				continue
-			}
-
-			infoLog.Printlnf("Found %d validators registered with operator %s", len(registeredValidators), operatorId)
-			infoLog.Println("Starting a pass of the presign daemon!")
-
-			currentHead, err := bc.GetBeaconHead()
-			if err != nil {
-				errorLog.Printf("Could not get beacon head with error %s\n", err.Error())
-				continue
-			}
+		eventWatcher.run()
+		wg.Done()
+	}()
 
-			err = w.Reload()
-			if err != nil {
-				errorLog.Printf("Could not reload wallet: %s\n", err.Error())
-				continue
-			}
+	// validator presigned loop
+	go func() {
+		runTask(c, errorLog, presignTrigger, submitPresignedExits.run, submitPresignedExits.getCooldown, taskCooldown)
+		wg.Done()
+	}()
 
-			preSignRegisteredMap, err := stader.BulkIsPresignedKeyRegistered(c, validatorPubKeys)
-			if err != nil {
-				errorLog.Printf("Could not bulk check presigned keys with error %s\n", err.Error())
-				continue
-			}
+	// Fee recipient loop
+	go func() {
+		runTask(c, errorLog, feeRecipientTrigger, manageFeeRecipient.run, func() time.Duration {
+			return feeRecepientPollingInterval
+		}, feeRecepientPollingInterval)
+		wg.Done()
+	}()
 
-			pageNumber := 0
-			pageSize := 5
-			for {
-				startIndex := pageNumber * pageSize
-				if startIndex > len(validatorPubKeys) {
-					break
-				}
-				endIndex := (pageNumber + 1) * pageSize
-				if endIndex > len(validatorPubKeys) {
-					endIndex = len(validatorPubKeys)
-				}
-				infoLog.Printf("Starting index: %d, End index: %d\n", startIndex, endIndex)
-
-				validatorKeyBatch := validatorPubKeys[startIndex:endIndex]
-				infoLog.Printf("Checking %d validator keys\n", len(validatorKeyBatch))
-
-				preSignSendMessages := []stader_backend.PreSignSendApiRequestType{}
-
-				for _, validatorPubKey := range validatorKeyBatch {
-					infoLog.Printf("Checking validator pubkey %s\n", validatorPubKey.String())
-					validatorKeyPair, err := w.GetValidatorKeyByPubkey(validatorPubKey)
-					// log the errors and continue. dont need to sleep post an error
-					if err != nil {
-						errorLog.Printf("Could not find validator private key for %s with err: %s\n", validatorPubKey, err.Error())
-						continue
-					}
-
-					validatorInfo, ok := registeredValidators[validatorPubKey]
-					if !ok {
-						errorLog.Printf("Validator pub key: %s not found in stader contracts\n", validatorPubKey)
-						continue
-					}
-					if stdr.IsValidatorTerminal(validatorInfo) {
-						errorLog.Printf("Validator pub key: %s is in terminal state in the stader contracts\n", validatorPubKey)
-						continue
-					}
-
-					registeredPresign, ok := preSignRegisteredMap[validatorPubKey.String()]
-					if !ok {
-						errorLog.Printf("Could not query presign api to check if validator: %s is registered\n", validatorPubKey)
-						continue
-					}
-					if registeredPresign {
-						infoLog.Printf("Validator pub key: %s pre signed key already registered\n", validatorPubKey)
-						continue
-					} else {
-						infoLog.Printf("Validator pub key: %s pre signed key not registered. Creating presigned message\n", validatorPubKey)
-					}
-
-					// check if validator has not yet been registered on beacon chain
-					validatorStatus, err := bc.GetValidatorStatus(validatorPubKey, nil)
-					if err != nil {
-						errorLog.Printf("Error finding validator status for validator: %s with err: %s\n", validatorPubKey, err.Error())
-						continue
-					}
-					if !validatorStatus.Exists {
-						errorLog.Printf("Validator pub key: %s not found on beacon chain\n", validatorPubKey)
-						continue
-					}
-
-					// check if validator is already in an exiting phase, then no point sending a pre-signed message
-					if eth2.IsValidatorExiting(validatorStatus) {
-						errorLog.Printf("Validator pub key: %s already exiting or exited with status %s", validatorPubKey, validatorStatus.Status)
-						continue
-					}
-
-					exitEpoch := currentHead.Epoch
-
-					signatureDomain, err := bc.GetDomainData(eth2types.DomainVoluntaryExit[:], exitEpoch, false)
-					if err != nil {
-						errorLog.Printf("Failed to get the signature domain from beacon chain with err: %s\n", err.Error())
-						continue
-					}
-
-					// get the presigned msg
-					exitSignature, _, err := validator.GetSignedExitMessage(validatorKeyPair, validatorStatus.Index, exitEpoch, signatureDomain)
-					if err != nil {
-						errorLog.Printf("Failed to generate the SignedExitMessage for validator with beacon chain index: %d with err: %s\n", validatorStatus.Index, err.Error())
-						continue
-					}
-
-					// encrypt the signature and srHash
-					exitSignatureEncrypted, err := crypto.EncryptUsingPublicKey([]byte(exitSignature.String()), publicKey)
-					if err != nil {
-						errorLog.Printf("Failed to encrypt exit signature for validator: %s with err: %s\n", validatorPubKey, err.Error())
-						continue
-					}
-					exitSignatureEncryptedString := crypto.EncodeBase64(exitSignatureEncrypted)
-
-					// send it to the presigned api
-					preSignSendMessages = append(preSignSendMessages, stader_backend.PreSignSendApiRequestType{
-						Message: struct {
-							Epoch          string `json:"epoch"`
-							ValidatorIndex string `json:"validator_index"`
-						}{
-							Epoch:          strconv.FormatUint(exitEpoch, 10),
-							ValidatorIndex: strconv.FormatUint(validatorStatus.Index, 10),
-						},
-						Signature:          exitSignatureEncryptedString,
-						ValidatorPublicKey: validatorPubKey.String(),
-					})
-				}
-
-				//fmt.Printf("Sending %d presigned messages to stader backend\n", len(preSignSendMessages))
-				fmt.Printf("Sending %d presigned messages to stader backend\n", len(preSignSendMessages))
-				//fmt.Printf("Pre-sign messages being sent are %v\n", preSignSendMessages)
-				if len(preSignSendMessages) > 0 {
-					res, err := stader.SendBulkPresignedMessageToStaderBackend(c, preSignSendMessages)
-					if err != nil {
-						errorLog.Printf("Sending bulk presigned message failed with %v\n", err.Error())
-					} else {
-						for pubKey, response := range *res {
-							if response.Success {
-								infoLog.Printf("Successfully sent the presigned message for validator: %s\n", pubKey)
-							} else {
-								errorLog.Printf("Failed to send the presigned api for validator: %s with err: %s\n", pubKey, response.Error)
-							}
-						}
-					}
-				}
-
-				pageNumber += 1
+	// Merkle proofs loop
+	go func() {
+		runTask(c, errorLog, merkleProofsTrigger, func() error {
+			infoLog.Printlnf("Checking if there are any available merkle proofs to download")
+			if err := merkleProofsDownloader.run(); err != nil {
+				return err
 			}
+			infoLog.Printlnf("Done checking for merkle proofs to download")
+			// Newly downloaded proofs may be claimable
+			autoClaimTrigger.fire()
+			return nil
+		}, func() time.Duration {
+			return merkleProofsDownloadInterval
+		}, merkleProofsDownloadInterval)
+		wg.Done()
+	}()
 
-			infoLog.Printf("Done with the pass of presign daemon")
-			// run loop every 12 hours
-			time.Sleep(preSignedCooldown)
-		}
+	// SD collateral loop
+	go func() {
+		runTask(c, errorLog, sdCollateralTrigger, manageSdCollateral.run, func() time.Duration {
+			return sdCollateralCheckInterval
+		}, sdCollateralCheckInterval)
+		wg.Done()
+	}()
 
+	// Auto-claim loop
+	go func() {
+		runTask(c, errorLog, autoClaimTrigger, autoClaim.run, func() time.Duration {
+			return autoClaimInterval
+		}, autoClaimInterval)
 		wg.Done()
 	}()
 
-	// Run task loop
+	// Doppelganger watch loop
 	go func() {
-		for {
-			// Check the EC status
-			err := services.WaitEthClientSynced(c, false) // Force refresh the primary / fallback EC status
-			if err != nil {
-				errorLog.Println(err)
-			} else {
-				// Check the BC status
-				err := services.WaitBeaconClientSynced(c, false) // Force refresh the primary / fallback BC status
-				if err != nil {
-					errorLog.Println(err)
-				} else {
-					// Manage the fee recipient for the node
-					if err := manageFeeRecipient.run(); err != nil {
-						errorLog.Println(err)
-					}
-					time.Sleep(taskCooldown)
-				}
-			}
-			time.Sleep(feeRecepientPollingInterval)
-		}
+		runTask(c, errorLog, doppelgangerTrigger, doppelgangerWatch.run, func() time.Duration {
+			return doppelgangerWatchInterval
+		}, doppelgangerWatchInterval)
 		wg.Done()
 	}()
 
+	// Front-run watch loop
 	go func() {
-		for {
-			infoLog.Printlnf("Checking if there are any available merkle proofs to download")
-			// Check the EC status
-			err := services.WaitEthClientSynced(c, false) // Force refresh the primary / fallback EC status
-			if err != nil {
-				errorLog.Println(err)
-			} else {
-				// Check the BC status
-				err := services.WaitBeaconClientSynced(c, false) // Force refresh the primary / fallback BC status
-				if err != nil {
-					errorLog.Println(err)
-				} else {
-					// Manage the fee recipient for the node
-					if err := merkleProofsDownloader.run(); err != nil {
-						errorLog.Println(err)
-					}
-					time.Sleep(taskCooldown)
-				}
-			}
-			infoLog.Printlnf("Done checking for merkle proofs to download")
-			time.Sleep(merkleProofsDownloadInterval)
-		}
+		runTask(c, errorLog, frontRunTrigger, frontRunWatch.run, func() time.Duration {
+			return frontRunWatchInterval
+		}, frontRunWatchInterval)
 		wg.Done()
 	}()
 
-	// Wait for both threads to stop
+	// Wait for all threads to stop
 	wg.Wait()
 	return nil
 
 }
 
+// Run a task whenever its trigger fires, or after its interval if nothing fired it first
+func runTask(c *cli.Context, errorLog log.ColorLogger, trigger *taskTrigger, task func() error, interval func() time.Duration, retryInterval time.Duration) {
+	for {
+		// Check the EC status
+		err := services.WaitEthClientSynced(c, false) // Force refresh the primary / fallback EC status
+		if err != nil {
+			errorLog.Println(err)
+			trigger.wait(taskCooldown)
+			continue
+		}
+		// Check the BC status
+		err = services.WaitBeaconClientSynced(c, false) // Force refresh the primary / fallback BC status
+		if err != nil {
+			errorLog.Println(err)
+			trigger.wait(taskCooldown)
+			continue
+		}
+
+		if err := task(); err != nil {
+			errorLog.Println(err)
+			trigger.wait(retryInterval)
+			continue
+		}
+		trigger.wait(interval())
+	}
+}
+
 // Configure HTTP transport settings
 func configureHTTP() {
 
@@ -425,3 +341,20 @@ func deployDefaultFeeRecipientFile(c *cli.Context) error {
 	return nil
 
 }
+
+// Make the validator client aware of the operator's registered keys the remote signer holds
+func updateRemoteSignerKeys(c *cli.Context, cfg *config.StaderConfig, signer *web3signer.Client, nodeAddress common.Address) ([]types.ValidatorPubkey, error) {
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	operatorId, err := node.GetOperatorId(pnr, nodeAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, registeredPubkeys, err := stdr.GetAllValidatorsRegisteredWithOperator(pnr, operatorId, nodeAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	return validator.UpdateRemoteSignerKeys(cfg, signer, registeredPubkeys)
+}
@@ -0,0 +1,418 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/presign"
+	"github.com/stader-labs/stader-node/shared/services/web3signer"
+	stader_backend "github.com/stader-labs/stader-node/shared/types/stader-backend"
+	"github.com/stader-labs/stader-node/shared/utils/crypto"
+	"github.com/stader-labs/stader-node/shared/utils/eth2"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/shared/utils/stdr"
+	"github.com/stader-labs/stader-node/shared/utils/validator"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/node"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// Number of presigned messages sent to the backend in one request
+const presignBatchSize = 5
+
+// The node wallet lookups the presign pass needs
+type presignWallet interface {
+	Reload() error
+	GetValidatorKeyByPubkey(pubkey types.ValidatorPubkey) (*eth2types.BLSPrivateKey, error)
+}
+
+// The permissionless registry lookups the presign pass needs
+type presignRegistry interface {
+	GetOperatorId(nodeAddress common.Address) (*big.Int, error)
+	GetAllValidatorsRegisteredWithOperator(operatorId *big.Int, nodeAddress common.Address) (map[types.ValidatorPubkey]contracts.Validator, []types.ValidatorPubkey, error)
+}
+
+// The beacon queries the presign pass needs
+type presignBeaconClient interface {
+	GetBeaconHead(ctx context.Context) (beacon.BeaconHead, error)
+	GetForkInfo(ctx context.Context) (beacon.ForkInfo, error)
+	GetValidatorStatus(ctx context.Context, pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error)
+	GetDomainData(ctx context.Context, domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error)
+}
+
+// Reads the presign pass' registry lookups from the permissionless node registry contract
+type contractPresignRegistry struct {
+	pnr *stader.PermissionlessNodeRegistryContractManager
+}
+
+func (r contractPresignRegistry) GetOperatorId(nodeAddress common.Address) (*big.Int, error) {
+	return node.GetOperatorId(r.pnr, nodeAddress, nil)
+}
+
+func (r contractPresignRegistry) GetAllValidatorsRegisteredWithOperator(operatorId *big.Int, nodeAddress common.Address) (map[types.ValidatorPubkey]contracts.Validator, []types.ValidatorPubkey, error) {
+	return stdr.GetAllValidatorsRegisteredWithOperator(r.pnr, operatorId, nodeAddress, nil)
+}
+
+// Submit presigned exits task
+type submitPresignedExits struct {
+	log          log.ColorLogger
+	errLog       log.ColorLogger
+	w            presignWallet
+	registry     presignRegistry
+	bc           presignBeaconClient
+	backend      presign.PresignBackend
+	presignDb    *presign.Database
+	remoteSigner *web3signer.Client
+	nodeAddress  common.Address
+}
+
+// Create submit presigned exits task
+func newSubmitPresignedExits(c *cli.Context, logger log.ColorLogger, errorLogger log.ColorLogger) (*submitPresignedExits, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	pnr, err := services.GetPermissionlessNodeRegistry(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := services.GetPresignBackend(c)
+	if err != nil {
+		return nil, err
+	}
+	presignDb, err := services.GetPresignDatabase(c)
+	if err != nil {
+		return nil, err
+	}
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validator keys are held by a remote signer instead of the node wallet
+	var remoteSigner *web3signer.Client
+	if cfg.EnableRemoteSigner.Value == true {
+		remoteSigner, err = services.GetRemoteSigner(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Return task
+	return &submitPresignedExits{
+		log:          logger,
+		errLog:       errorLogger,
+		w:            w,
+		registry:     contractPresignRegistry{pnr: pnr},
+		bc:           bc,
+		backend:      backend,
+		presignDb:    presignDb,
+		remoteSigner: remoteSigner,
+		nodeAddress:  nodeAccount.Address,
+	}, nil
+
+}
+
+// Send presigned exit messages for every registered validator the backend doesn't have one for yet
+func (t *submitPresignedExits) run() error {
+
+	// Get the key to encrypt exit signatures with, checking the backend still uses the pinned one
+	publicKey, err := t.backend.GetPublicKey()
+	if err != nil {
+		return err
+	}
+
+	operatorId, err := t.registry.GetOperatorId(t.nodeAddress)
+	if err != nil {
+		return fmt.Errorf("Failed to get operator id: %w", err)
+	}
+
+	// make a map of all validators actually registered with stader
+	// user might just move the validator keys to the directory. we don't wanna send the presigned msg of them
+	t.log.Println("Building a map of user validators registered with stader")
+	registeredValidators, validatorPubKeys, err := t.registry.GetAllValidatorsRegisteredWithOperator(operatorId, t.nodeAddress)
+	if err != nil {
+		return fmt.Errorf("Could not get all validators registered with operator %s: %w", operatorId, err)
+	}
+
+	t.log.Printlnf("Found %d validators registered with operator %s", len(registeredValidators), operatorId)
+	t.log.Println("Starting a pass of the presign daemon!")
+
+	currentHead, err := t.bc.GetBeaconHead(context.Background())
+	if err != nil {
+		return fmt.Errorf("Could not get beacon head: %w", err)
+	}
+
+	err = t.w.Reload()
+	if err != nil {
+		return fmt.Errorf("Could not reload wallet: %w", err)
+	}
+
+	// With a remote signer, look up the keys it holds and the fork it signs against once per pass
+	var remoteSignerKeys map[types.ValidatorPubkey]bool
+	var forkInfo beacon.ForkInfo
+	if t.remoteSigner != nil {
+		signerPubkeys, err := t.remoteSigner.GetPublicKeys()
+		if err != nil {
+			return fmt.Errorf("Could not get the remote signer keys: %w", err)
+		}
+		remoteSignerKeys = make(map[types.ValidatorPubkey]bool, len(signerPubkeys))
+		for _, signerPubkey := range signerPubkeys {
+			remoteSignerKeys[signerPubkey] = true
+		}
+
+		forkInfo, err = t.bc.GetForkInfo(context.Background())
+		if err != nil {
+			return fmt.Errorf("Could not get the beacon fork info: %w", err)
+		}
+	}
+
+	preSignRegisteredMap, err := t.backend.BulkIsPresignedKeyRegistered(validatorPubKeys)
+	if err != nil {
+		return err
+	}
+
+	// Load the presign history, and note keys the backend already has that aren't recorded as accepted yet
+	presignRecords, err := t.presignDb.GetRecords()
+	if err != nil {
+		t.errLog.Printf("Could not read the presign database with error %s\n", err.Error())
+		presignRecords = map[types.ValidatorPubkey]presign.ValidatorRecord{}
+	}
+	newlyAccepted := []types.ValidatorPubkey{}
+	for _, validatorPubKey := range validatorPubKeys {
+		if preSignRegisteredMap[validatorPubKey.String()] && !presignRecords[validatorPubKey].Accepted {
+			newlyAccepted = append(newlyAccepted, validatorPubKey)
+		}
+	}
+	if len(newlyAccepted) > 0 {
+		if err := t.presignDb.MarkAccepted(newlyAccepted); err != nil {
+			t.errLog.Printf("Could not update the presign database with error %s\n", err.Error())
+		}
+	}
+
+	for startIndex := 0; startIndex < len(validatorPubKeys); startIndex += presignBatchSize {
+		endIndex := startIndex + presignBatchSize
+		if endIndex > len(validatorPubKeys) {
+			endIndex = len(validatorPubKeys)
+		}
+		t.log.Printf("Starting index: %d, End index: %d\n", startIndex, endIndex)
+
+		validatorKeyBatch := validatorPubKeys[startIndex:endIndex]
+		t.log.Printf("Checking %d validator keys\n", len(validatorKeyBatch))
+
+		preSignSendMessages := []stader_backend.PreSignSendApiRequestType{}
+		preSignSubmissions := map[types.ValidatorPubkey]presign.Submission{}
+
+		for _, validatorPubKey := range validatorKeyBatch {
+			t.log.Printf("Checking validator pubkey %s\n", validatorPubKey.String())
+			var validatorKeyPair *eth2types.BLSPrivateKey
+			if t.remoteSigner != nil {
+				if !remoteSignerKeys[validatorPubKey] {
+					t.errLog.Printf("Could not find validator key for %s in the remote signer\n", validatorPubKey)
+					continue
+				}
+			} else {
+				validatorKeyPair, err = t.w.GetValidatorKeyByPubkey(validatorPubKey)
+				// log the errors and continue. dont need to sleep post an error
+				if err != nil {
+					t.errLog.Printf("Could not find validator private key for %s with err: %s\n", validatorPubKey, err.Error())
+					continue
+				}
+			}
+
+			validatorInfo, ok := registeredValidators[validatorPubKey]
+			if !ok {
+				t.errLog.Printf("Validator pub key: %s not found in stader contracts\n", validatorPubKey)
+				continue
+			}
+			if stdr.IsValidatorTerminal(validatorInfo) {
+				t.errLog.Printf("Validator pub key: %s is in terminal state in the stader contracts\n", validatorPubKey)
+				continue
+			}
+
+			registeredPresign, ok := preSignRegisteredMap[validatorPubKey.String()]
+			if !ok {
+				t.errLog.Printf("Could not query presign api to check if validator: %s is registered\n", validatorPubKey)
+				continue
+			}
+			if registeredPresign {
+				t.log.Printf("Validator pub key: %s pre signed key already registered\n", validatorPubKey)
+				continue
+			}
+			if record, ok := presignRecords[validatorPubKey]; ok && !record.CanRetry(time.Now()) {
+				t.log.Printf("Validator pub key: %s pre signed key was not accepted after %d attempts, retrying after %s\n", validatorPubKey, record.Retries, record.NextAttempt.Format(time.RFC822))
+				continue
+			}
+			t.log.Printf("Validator pub key: %s pre signed key not registered. Creating presigned message\n", validatorPubKey)
+
+			// check if validator has not yet been registered on beacon chain
+			validatorStatus, err := t.bc.GetValidatorStatus(context.Background(), validatorPubKey, nil)
+			if err != nil {
+				t.errLog.Printf("Error finding validator status for validator: %s with err: %s\n", validatorPubKey, err.Error())
+				continue
+			}
+			if !validatorStatus.Exists {
+				t.errLog.Printf("Validator pub key: %s not found on beacon chain\n", validatorPubKey)
+				continue
+			}
+
+			// check if validator is already in an exiting phase, then no point sending a pre-signed message
+			if eth2.IsValidatorExiting(validatorStatus) {
+				t.errLog.Printf("Validator pub key: %s already exiting or exited with status %s", validatorPubKey, validatorStatus.Status)
+				continue
+			}
+
+			exitEpoch := currentHead.Epoch
+
+			signatureDomain, err := t.bc.GetDomainData(context.Background(), eth2types.DomainVoluntaryExit[:], exitEpoch, false)
+			if err != nil {
+				t.errLog.Printf("Failed to get the signature domain from beacon chain with err: %s\n", err.Error())
+				continue
+			}
+
+			// get the presigned msg
+			var exitSignature types.ValidatorSignature
+			if t.remoteSigner != nil {
+				exitSignature, _, err = validator.GetSignedExitMessageFromRemoteSigner(t.remoteSigner, validatorPubKey, validatorStatus.Index, exitEpoch, signatureDomain, forkInfo)
+			} else {
+				exitSignature, _, err = validator.GetSignedExitMessage(validatorKeyPair, validatorStatus.Index, exitEpoch, signatureDomain)
+			}
+			if err != nil {
+				t.errLog.Printf("Failed to generate the SignedExitMessage for validator with beacon chain index: %d with err: %s\n", validatorStatus.Index, err.Error())
+				continue
+			}
+
+			// encrypt the signature and srHash
+			exitSignatureEncrypted, err := crypto.EncryptUsingPublicKey([]byte(exitSignature.String()), publicKey)
+			if err != nil {
+				t.errLog.Printf("Failed to encrypt exit signature for validator: %s with err: %s\n", validatorPubKey, err.Error())
+				continue
+			}
+			exitSignatureEncryptedString := crypto.EncodeBase64(exitSignatureEncrypted)
+
+			// send it to the presigned api
+			preSignSendMessages = append(preSignSendMessages, stader_backend.PreSignSendApiRequestType{
+				Message: struct {
+					Epoch          string `json:"epoch"`
+					ValidatorIndex string `json:"validator_index"`
+				}{
+					Epoch:          strconv.FormatUint(exitEpoch, 10),
+					ValidatorIndex: strconv.FormatUint(validatorStatus.Index, 10),
+				},
+				Signature:          exitSignatureEncryptedString,
+				ValidatorPublicKey: validatorPubKey.String(),
+			})
+			preSignSubmissions[validatorPubKey] = presign.Submission{
+				ExitEpoch:      exitEpoch,
+				ValidatorIndex: validatorStatus.Index,
+			}
+		}
+
+		t.log.Printf("Sending %d presigned messages to stader backend\n", len(preSignSendMessages))
+		if len(preSignSendMessages) > 0 {
+			t.sendPresignedMessages(preSignSendMessages, preSignSubmissions)
+		}
+	}
+
+	t.log.Printf("Done with the pass of presign daemon")
+	return nil
+
+}
+
+// Send a batch of presigned messages and record the outcome of each one so failed keys are retried with backoff
+func (t *submitPresignedExits) sendPresignedMessages(preSignSendMessages []stader_backend.PreSignSendApiRequestType, preSignSubmissions map[types.ValidatorPubkey]presign.Submission) {
+	res, err := t.backend.SendBulkPresignedMessages(preSignSendMessages)
+	submittedAt := time.Now()
+	if err != nil {
+		t.errLog.Printf("Sending bulk presigned message failed with %v\n", err.Error())
+	} else {
+		for pubKey, response := range res {
+			if response.Success {
+				t.log.Printf("Successfully sent the presigned message for validator: %s\n", pubKey)
+			} else {
+				t.errLog.Printf("Failed to send the presigned api for validator: %s with err: %s\n", pubKey, response.Error)
+			}
+		}
+	}
+
+	for validatorPubKey, submission := range preSignSubmissions {
+		submission.SubmittedAt = submittedAt
+		if err != nil {
+			submission.Response = err.Error()
+		} else if response, ok := res[validatorPubKey.String()]; !ok {
+			submission.Response = "no response from the backend"
+		} else if response.Success {
+			submission.Accepted = true
+			submission.Response = "accepted"
+		} else {
+			submission.Response = response.Error
+		}
+		preSignSubmissions[validatorPubKey] = submission
+	}
+	if err := t.presignDb.RecordSubmissions(preSignSubmissions); err != nil {
+		t.errLog.Printf("Could not update the presign database with error %s\n", err.Error())
+	}
+}
+
+// Get the time until the next pass, which is sooner than the regular cooldown when a rejected key is due for a retry
+func (t *submitPresignedExits) getCooldown() time.Duration {
+	records, err := t.presignDb.GetRecords()
+	if err != nil {
+		t.errLog.Printf("Could not read the presign database with error %s\n", err.Error())
+		return preSignedCooldown
+	}
+
+	cooldown := preSignedCooldown
+	now := time.Now()
+	for _, record := range records {
+		if record.Accepted {
+			continue
+		}
+		if untilRetry := record.NextAttempt.Sub(now); untilRetry < cooldown {
+			cooldown = untilRetry
+		}
+	}
+	if cooldown < preSignedMinCooldown {
+		cooldown = preSignedMinCooldown
+	}
+	return cooldown
+}
@@ -0,0 +1,210 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fatih/color"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/stader-labs/stader-node/shared/services/beacon"
+	"github.com/stader-labs/stader-node/shared/services/presign"
+	"github.com/stader-labs/stader-node/shared/services/presign/presigntest"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/contracts"
+	"github.com/stader-labs/stader-node/stader-lib/types"
+)
+
+// In-memory node wallet
+type testPresignWallet struct {
+	keys map[types.ValidatorPubkey]*eth2types.BLSPrivateKey
+}
+
+func (w *testPresignWallet) Reload() error {
+	return nil
+}
+
+func (w *testPresignWallet) GetValidatorKeyByPubkey(pubkey types.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
+	key, ok := w.keys[pubkey]
+	if !ok {
+		return nil, fmt.Errorf("validator %s key not found", pubkey.Hex())
+	}
+	return key, nil
+}
+
+// In-memory permissionless node registry
+type testPresignRegistry struct {
+	operatorId *big.Int
+	validators map[types.ValidatorPubkey]contracts.Validator
+	pubkeys    []types.ValidatorPubkey
+}
+
+func (r *testPresignRegistry) GetOperatorId(nodeAddress common.Address) (*big.Int, error) {
+	return r.operatorId, nil
+}
+
+func (r *testPresignRegistry) GetAllValidatorsRegisteredWithOperator(operatorId *big.Int, nodeAddress common.Address) (map[types.ValidatorPubkey]contracts.Validator, []types.ValidatorPubkey, error) {
+	return r.validators, r.pubkeys, nil
+}
+
+// In-memory beacon chain
+type testPresignBeaconClient struct {
+	epoch    uint64
+	statuses []beacon.ValidatorStatus
+}
+
+func (bc *testPresignBeaconClient) GetBeaconHead(ctx context.Context) (beacon.BeaconHead, error) {
+	return beacon.BeaconHead{Epoch: bc.epoch}, nil
+}
+
+func (bc *testPresignBeaconClient) GetForkInfo(ctx context.Context) (beacon.ForkInfo, error) {
+	return beacon.ForkInfo{}, errors.New("not supported")
+}
+
+func (bc *testPresignBeaconClient) GetValidatorStatus(ctx context.Context, pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+	for _, status := range bc.statuses {
+		if status.Pubkey == pubkey {
+			return status, nil
+		}
+	}
+	return beacon.ValidatorStatus{}, nil
+}
+
+func (bc *testPresignBeaconClient) GetValidatorStatusByIndex(ctx context.Context, index string, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+	i, err := strconv.ParseUint(index, 10, 64)
+	if err != nil || i >= uint64(len(bc.statuses)) {
+		return beacon.ValidatorStatus{}, nil
+	}
+	return bc.statuses[i], nil
+}
+
+func (bc *testPresignBeaconClient) GetDomainData(ctx context.Context, domainType []byte, epoch uint64, useGenesisFork bool) ([]byte, error) {
+	domain := make([]byte, 32)
+	copy(domain, domainType)
+	domain[31] = 0x01
+	return domain, nil
+}
+
+func TestSubmitPresignedExits(t *testing.T) {
+	if err := eth2types.InitBLS(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Four registered validators: two that can be presigned, one front-run and one whose key isn't in the wallet
+	w := &testPresignWallet{keys: map[types.ValidatorPubkey]*eth2types.BLSPrivateKey{}}
+	registry := &testPresignRegistry{operatorId: big.NewInt(7), validators: map[types.ValidatorPubkey]contracts.Validator{}}
+	bc := &testPresignBeaconClient{epoch: 1000}
+	validatorStatuses := []uint8{4, 4, 2, 4}
+	for i, validatorStatus := range validatorStatuses {
+		key, err := eth2types.GenerateBLSPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubkey := types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+		if i != 3 {
+			w.keys[pubkey] = key
+		}
+		registry.validators[pubkey] = contracts.Validator{Status: validatorStatus, Pubkey: pubkey.Bytes()}
+		registry.pubkeys = append(registry.pubkeys, pubkey)
+		bc.statuses = append(bc.statuses, beacon.ValidatorStatus{
+			Pubkey: pubkey,
+			Index:  uint64(i),
+			Status: beacon.ValidatorState_ActiveOngoing,
+			Exists: true,
+		})
+	}
+
+	// Serve the backend locally
+	privateKey, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := presigntest.NewMockServer(privateKey, bc)
+	url := server.Start()
+	defer server.Close()
+
+	task := &submitPresignedExits{
+		log:         log.NewColorLogger(color.FgWhite),
+		errLog:      log.NewColorLogger(color.FgRed),
+		w:           w,
+		registry:    registry,
+		bc:          bc,
+		backend:     presign.NewHttpBackend(url, &privateKey.PublicKey, time.Minute),
+		presignDb:   presign.NewDatabase(filepath.Join(t.TempDir(), "presign.db")),
+		nodeAddress: common.HexToAddress("0x1"),
+	}
+	if err := task.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the active validators with local keys are presigned, and the backend accepted their signatures
+	records, err := task.presignDb.GetRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, pubkey := range registry.pubkeys {
+		_, registered := server.GetExitSignature(pubkey)
+		record, recorded := records[pubkey]
+		expected := i < 2
+		if registered != expected {
+			t.Errorf("validator %d: expected registered %t, got %t", i, expected, registered)
+		}
+		if recorded != expected {
+			t.Errorf("validator %d: expected recorded %t, got %t", i, expected, recorded)
+			continue
+		}
+		if !expected {
+			continue
+		}
+		if !record.Accepted || len(record.Submissions) != 1 {
+			t.Errorf("validator %d: expected one accepted submission, got %+v", i, record)
+			continue
+		}
+		submission := record.Submissions[0]
+		if submission.ExitEpoch != bc.epoch || submission.ValidatorIndex != uint64(i) {
+			t.Errorf("validator %d: unexpected submission %+v", i, submission)
+		}
+	}
+
+	// A second pass finds every key already registered and sends nothing
+	if err := task.run(); err != nil {
+		t.Fatal(err)
+	}
+	records, err = task.presignDb.GetRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, pubkey := range registry.pubkeys[:2] {
+		if len(records[pubkey].Submissions) != 1 {
+			t.Errorf("validator %d: expected no new submission, got %d", i, len(records[pubkey].Submissions))
+		}
+	}
+}
@@ -0,0 +1,254 @@
+/*
+This work is licensed and released under GNU GPL v3 or any other later versions.
+The full text of the license is below/ found at <http://www.gnu.org/licenses/>
+
+(c) 2023 Stakeinfra Technologies Inc.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli"
+
+	"github.com/stader-labs/stader-node/shared/services"
+	"github.com/stader-labs/stader-node/shared/services/config"
+	"github.com/stader-labs/stader-node/shared/services/gas/feehistory"
+	"github.com/stader-labs/stader-node/shared/services/wallet"
+	"github.com/stader-labs/stader-node/shared/utils/log"
+	"github.com/stader-labs/stader-node/stader-lib/stader"
+	"github.com/stader-labs/stader-node/stader-lib/utils"
+	"github.com/stader-labs/stader-node/stader-lib/utils/eth"
+)
+
+// Returned when the network's fees are above what the task is willing to pay; the task should try again later
+var errFeeTooHigh = errors.New("network fees are above the max fee")
+
+// Sends the daemon's own transactions one at a time so they can't race each other for the node account's nonce
+type txSender struct {
+	c   *cli.Context
+	log log.ColorLogger
+	cfg *config.StaderConfig
+	w   *wallet.Wallet
+	ec  *services.ExecutionClientManager
+
+	lock sync.Mutex
+}
+
+// Create the daemon transaction sender
+func newTxSender(c *cli.Context, logger log.ColorLogger) (*txSender, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txSender{
+		c:   c,
+		log: logger,
+		cfg: cfg,
+		w:   w,
+		ec:  ec,
+	}, nil
+
+}
+
+// Send a transaction from the node account and wait for it to be mined.
+// maxFeeGwei caps the max fee (including the priority fee) the transaction may pay; errFeeTooHigh is returned if the
+// network currently needs more than that. estimate and submit are given a transactor with the fees already set.
+func (s *txSender) send(description string, maxFeeGwei float64, estimate func(opts *bind.TransactOpts) (stader.GasInfo, error), submit func(opts *bind.TransactOpts) (common.Hash, error)) (*types.Receipt, error) {
+
+	hash, _, err := s.submit(description, maxFeeGwei, nil, estimate, submit)
+	if err != nil {
+		return nil, err
+	}
+	return s.wait(description, hash)
+
+}
+
+// Send a transaction from the node account without waiting for it, returning its hash and the most it can cost.
+// committedWei is what earlier transactions of the same operation can cost; it counts against the transaction fee cap.
+// Only one transaction is submitted at a time so they can't race each other for the nonce.
+func (s *txSender) submit(description string, maxFeeGwei float64, committedWei *big.Int, estimate func(opts *bind.TransactOpts) (stader.GasInfo, error), submit func(opts *bind.TransactOpts) (common.Hash, error)) (common.Hash, *big.Int, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	nodeAccount, err := s.w.GetNodeAccount()
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	// Don't queue up behind a transaction that hasn't been mined yet, it may be stuck
+	pendingNonce, err := s.ec.PendingNonceAt(context.Background(), nodeAccount.Address)
+	if err != nil {
+		return common.Hash{}, nil, fmt.Errorf("error getting the node account's pending nonce: %w", err)
+	}
+	latestNonce, err := s.ec.NonceAt(context.Background(), nodeAccount.Address, nil)
+	if err != nil {
+		return common.Hash{}, nil, fmt.Errorf("error getting the node account's nonce: %w", err)
+	}
+	if pendingNonce > latestNonce {
+		return common.Hash{}, nil, fmt.Errorf("the node account has %d pending transaction(s), not sending %s until they are mined", pendingNonce-latestNonce, description)
+	}
+
+	// Get the fees
+	maxFee, priorityFee, err := s.getFees(maxFeeGwei)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	opts, err := s.w.GetNodeAccountTransactor()
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	opts.GasFeeCap = maxFee
+	opts.GasTipCap = priorityFee
+	opts.Nonce = new(big.Int).SetUint64(pendingNonce)
+
+	// Get the gas limit
+	gasInfo, err := estimate(opts)
+	if err != nil {
+		return common.Hash{}, nil, fmt.Errorf("error estimating the gas of %s: %w", description, err)
+	}
+	opts.GasLimit = gasInfo.SafeGasLimit
+
+	// Make sure the transaction can't cost more than the configured cap or the node account's balance
+	maxCost := new(big.Int).Mul(maxFee, new(big.Int).SetUint64(opts.GasLimit))
+	totalCost := new(big.Int).Set(maxCost)
+	if committedWei != nil {
+		totalCost.Add(totalCost, committedWei)
+	}
+	txFeeCap := eth.EthToWei(s.cfg.StaderNode.TxFeeCap.Value.(float64))
+	if totalCost.Cmp(txFeeCap) > 0 {
+		if committedWei != nil && committedWei.Sign() > 0 {
+			return common.Hash{}, nil, fmt.Errorf("%s could cost up to %.6f ETH on top of the %.6f ETH already committed to, which is above the transaction fee cap of %.6f ETH", description, eth.WeiToEth(maxCost), eth.WeiToEth(committedWei), eth.WeiToEth(txFeeCap))
+		}
+		return common.Hash{}, nil, fmt.Errorf("%s could cost up to %.6f ETH, which is above the transaction fee cap of %.6f ETH", description, eth.WeiToEth(maxCost), eth.WeiToEth(txFeeCap))
+	}
+	balance, err := s.ec.BalanceAt(context.Background(), nodeAccount.Address, nil)
+	if err != nil {
+		return common.Hash{}, nil, fmt.Errorf("error getting the node account's ETH balance: %w", err)
+	}
+	if maxCost.Cmp(balance) > 0 {
+		return common.Hash{}, nil, fmt.Errorf("%s could cost up to %.6f ETH but the node account only has %.6f ETH", description, eth.WeiToEth(maxCost), eth.WeiToEth(balance))
+	}
+
+	// Send it
+	hash, err := submit(opts)
+	if err != nil {
+		return common.Hash{}, nil, fmt.Errorf("error sending %s: %w", description, err)
+	}
+	s.printTransactionHash(description, hash)
+
+	return hash, maxCost, nil
+
+}
+
+// Wait for a submitted transaction to be mined. Gives up after txWaitTimeout so a stuck transaction doesn't block the
+// task; the next transaction isn't sent until it's mined or replaced anyway.
+func (s *txSender) wait(description string, hash common.Hash) (*types.Receipt, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), txWaitTimeout)
+	defer cancel()
+
+	receipt, err := utils.WaitForTransactionWithContext(ctx, s.ec, hash)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s (%s) was not mined within %s; it may be stuck, check it with `stader-cli node tx list`", description, hash.Hex(), txWaitTimeout)
+	}
+	if err != nil {
+		return receipt, fmt.Errorf("%s (%s) failed: %w", description, hash.Hex(), err)
+	}
+	s.log.Printlnf("%s (%s) was mined in block %s", description, hash.Hex(), receipt.BlockNumber.String())
+
+	return receipt, nil
+
+}
+
+// Get the max fee and priority fee to use, in wei
+func (s *txSender) getFees(maxFeeGwei float64) (*big.Int, *big.Int, error) {
+
+	estimate, err := feehistory.GetFeeEstimate(s.ec)
+	if err != nil {
+		return nil, nil, err
+	}
+	baseFee := estimate.PredictedBaseFeesWei[0]
+
+	priorityFee := eth.GweiToWei(s.cfg.StaderNode.PriorityFee.Value.(float64))
+
+	// Leave room for the base fee to keep rising while the transaction waits, unless a max fee is set manually
+	var maxFee *big.Int
+	manualMaxFee := s.cfg.StaderNode.ManualMaxFee.Value.(float64)
+	if manualMaxFee > 0 {
+		maxFee = eth.GweiToWei(manualMaxFee)
+	} else {
+		maxFee = new(big.Int).Add(estimate.Standard.MaxBaseFeeWei, priorityFee)
+	}
+
+	if maxFeeGwei > 0 {
+		feeCap := eth.GweiToWei(maxFeeGwei)
+		minFee := new(big.Int).Add(baseFee, priorityFee)
+		if minFee.Cmp(feeCap) > 0 {
+			return nil, nil, fmt.Errorf("%w: the current base fee is %.2f gwei plus a %.2f gwei priority fee, the max fee is %.2f gwei", errFeeTooHigh, eth.WeiToGwei(baseFee), eth.WeiToGwei(priorityFee), maxFeeGwei)
+		}
+		if maxFee.Cmp(feeCap) > 0 {
+			maxFee = feeCap
+		}
+	}
+	if priorityFee.Cmp(maxFee) > 0 {
+		priorityFee = maxFee
+	}
+
+	return maxFee, priorityFee, nil
+
+}
+
+// Get the base fee of the latest block, in wei
+func (s *txSender) getBaseFee() (*big.Int, error) {
+	header, err := s.ec.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the latest block header: %w", err)
+	}
+	if header.BaseFee == nil {
+		return nil, fmt.Errorf("the latest block has no base fee")
+	}
+	return header.BaseFee, nil
+}
+
+func (s *txSender) printTransactionHash(description string, hash common.Hash) {
+	txWatchUrl := s.cfg.StaderNode.GetTxWatchUrl()
+	if txWatchUrl != "" {
+		s.log.Printlnf("Sent %s, you may follow its progress at %s/%s", description, txWatchUrl, hash.Hex())
+	} else {
+		s.log.Printlnf("Sent %s with hash %s", description, hash.Hex())
+	}
+}
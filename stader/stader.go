@@ -795,6 +795,14 @@ Public License instead of this License. But first, please read
 			Name:  "ignore-sync-check",
 			Usage: "Set this to true if you already checked the sync status of the execution client(s) and don't need to re-check it for this command",
 		},
+		cli.StringFlag{
+			Name:  "node-address",
+			Usage: "Use this node `address` without loading the wallet, for nodes whose wallet is kept on an offline machine",
+		},
+		cli.BoolFlag{
+			Name:  "export-unsigned-tx",
+			Usage: "Build transactions without signing or sending them, and report them in the API response so they can be signed offline",
+		},
 		cli.BoolFlag{
 			Name:  "force-fallbacks",
 			Usage: "Set this to true if you know the primary EC or CC is offline and want to bypass its health checks, and just use the fallback EC and CC instead",